@@ -7,23 +7,38 @@ import (
 	"reflect"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"unsafe"
 )
 
-const fieldTagName = "clone"
-const fieldTagValueSkip = "skip"
-const fieldTagValueSkipAlias = "-"
-const fieldTagValueShadowCopy = "shadowcopy"
+const fieldTagName = TagName
+const fieldTagValueSkip = TagSkip
+const fieldTagValueSkipAlias = TagSkipAlias
+const fieldTagValueZero = TagZero
+const fieldTagValueShadowCopy = TagShadowCopy
+const fieldTagValueOpaque = TagOpaque
+const fieldTagValueRebind = TagRebind
+const fieldTagValueParent = TagParent
+const fieldTagValueGeneration = TagGeneration
+const fieldTagValueDeep = TagDeep
+const fieldTagValueInitPrefix = TagInit + "="
+const fieldTagValueFuncPrefix = TagFunc + "="
 
 var typeOfAllocator = reflect.TypeOf(Allocator{})
 
 // defaultAllocator is the default allocator and allocates memory from heap.
-var defaultAllocator = &Allocator{
-	new:       heapNew,
-	makeSlice: heapMakeSlice,
-	makeMap:   heapMakeMap,
-	makeChan:  heapMakeChan,
-	isScalar:  IsScalar,
+var defaultAllocator = newDefaultAllocator()
+
+func newDefaultAllocator() *Allocator {
+	a := &Allocator{
+		new:       heapNew,
+		makeSlice: heapMakeSlice,
+		makeMap:   heapMakeMap,
+		makeChan:  heapMakeChan,
+		isScalar:  IsScalar,
+	}
+	a.config = unsafe.Pointer(newConfig(nil, a.isScalar))
+	return a
 }
 
 // Allocator is a utility type for memory allocation.
@@ -35,13 +50,34 @@ type Allocator struct {
 	makeSlice func(pool unsafe.Pointer, t reflect.Type, len, cap int) reflect.Value
 	makeMap   func(pool unsafe.Pointer, t reflect.Type, n int) reflect.Value
 	makeChan  func(pool unsafe.Pointer, t reflect.Type, buffer int) reflect.Value
+	free      func(pool unsafe.Pointer, v reflect.Value)
+	reset     func(pool unsafe.Pointer)
+	release   func(pool unsafe.Pointer)
 	isScalar  func(t reflect.Kind) bool
 
-	cachedStructTypes     sync.Map
-	cachedPointerTypes    sync.Map
-	cachedCustomFuncTypes sync.Map
+	// fallback is the allocator set by AllocatorMethods.Fallback,
+	// and primary holds methods of this allocator before falling back to fallback.
+	fallback *Allocator
+	primary  *Allocator
+
+	// tx is the transaction tracking memory allocated by this allocator or nil.
+	tx *transaction
+
+	counters unsafe.Pointer // The *allocCounters or nil if allocation stats are disabled.
+
+	mu     sync.Mutex     // Guards config updates.
+	config unsafe.Pointer // The *config snapshot. It's immutable once published.
+	frozen int32
 }
 
+// Values of a boolean option in allocator.
+// An unset option inherits the value from parent.
+const (
+	optionUnset int32 = iota
+	optionEnabled
+	optionDisabled
+)
+
 // FromHeap creates an allocator which allocate memory from heap.
 func FromHeap() *Allocator {
 	return NewAllocator(nil, nil)
@@ -59,7 +95,15 @@ func NewAllocator(pool unsafe.Pointer, methods *AllocatorMethods) (allocator *Al
 	new := methods.new(parent, pool)
 
 	// Allocate the allocator from the pool.
-	val := new(pool, typeOfAllocator)
+	var val reflect.Value
+	fallback := methods.fallback()
+
+	if fallback == nil {
+		val = new(pool, typeOfAllocator)
+	} else if val = tryAllocate(func() reflect.Value { return new(pool, typeOfAllocator) }); !val.IsValid() {
+		val = fallback.New(typeOfAllocator)
+	}
+
 	allocator = (*Allocator)(unsafe.Pointer(val.Pointer()))
 	runtime.KeepAlive(val)
 
@@ -68,34 +112,74 @@ func NewAllocator(pool unsafe.Pointer, methods *AllocatorMethods) (allocator *Al
 	allocator.makeSlice = methods.makeSlice(parent, pool)
 	allocator.makeMap = methods.makeMap(parent, pool)
 	allocator.makeChan = methods.makeChan(parent, pool)
+	allocator.free = methods.free(parent, pool)
+	allocator.reset = methods.reset(parent, pool)
+	allocator.release = methods.release(parent, pool)
 	allocator.isScalar = methods.isScalar(parent)
 
+	if fallback != nil {
+		allocator.withFallback(fallback)
+	}
+
 	if parent == nil {
 		parent = defaultAllocator
 	}
 
 	allocator.parent = parent
+
+	// An allocator derived from a frozen parent shares parent's config and caches
+	// until it's customized.
+	if parent.IsFrozen() && (methods == nil || methods.IsScalar == nil) {
+		allocator.config = atomic.LoadPointer(&parent.config)
+	} else {
+		allocator.config = unsafe.Pointer(newConfig(parent.loadConfig(), allocator.isScalar))
+	}
+
 	return
 }
 
 // New returns a new zero value of t.
 func (a *Allocator) New(t reflect.Type) reflect.Value {
-	return a.new(a.pool, t)
+	v := a.new(a.pool, t)
+
+	if counters := a.loadCounters(); counters != nil {
+		counters.new.count(v, int(t.Size()))
+	}
+
+	return v
 }
 
 // MakeSlice creates a new zero-initialized slice value of t with len and cap.
 func (a *Allocator) MakeSlice(t reflect.Type, len, cap int) reflect.Value {
-	return a.makeSlice(a.pool, t, len, cap)
+	v := a.makeSlice(a.pool, t, len, cap)
+
+	if counters := a.loadCounters(); counters != nil {
+		counters.makeSlice.count(v, int(t.Elem().Size())*cap)
+	}
+
+	return v
 }
 
 // MakeMap creates a new map with minimum size n.
 func (a *Allocator) MakeMap(t reflect.Type, n int) reflect.Value {
-	return a.makeMap(a.pool, t, n)
+	v := a.makeMap(a.pool, t, n)
+
+	if counters := a.loadCounters(); counters != nil {
+		counters.makeMap.count(v, int(t.Key().Size()+t.Elem().Size())*n)
+	}
+
+	return v
 }
 
 // MakeChan creates a new chan with buffer.
 func (a *Allocator) MakeChan(t reflect.Type, buffer int) reflect.Value {
-	return a.makeChan(a.pool, t, buffer)
+	v := a.makeChan(a.pool, t, buffer)
+
+	if counters := a.loadCounters(); counters != nil {
+		counters.makeChan.count(v, int(t.Elem().Size())*buffer)
+	}
+
+	return v
 }
 
 // Clone recursively deep clone val to a new value with memory allocated from a.
@@ -108,15 +192,120 @@ func (a *Allocator) clone(val reflect.Value, inCustomFunc bool) reflect.Value {
 		return val
 	}
 
-	state := &cloneState{
-		allocator: a,
+	state := &cloneState{}
+	a.initCloneState(state, false)
+
+	if inCustomFunc {
+		state.skipCustomFuncValue = val
+	}
+
+	return state.cloneRoot(val)
+}
+
+// initCloneState initializes state with current config snapshot of a.
+// If slowly is true, all cloned values are marked to handle cycle pointers.
+//
+// The state is initialized in place rather than returned by a constructor,
+// so that it can be allocated on stack.
+func (a *Allocator) initCloneState(state *cloneState, slowly bool) {
+	cfg := a.loadConfig()
+	opts := cfg.options()
+	*state = opts.state
+	state.allocator = a
+
+	if a.tx == nil && opts.transactional {
+		state.tx = &transaction{}
+		state.allocator = a.withTransaction(state.tx)
 	}
 
+	if slowly {
+		state.visited = visitMap{}
+	}
+
+	if state.strict {
+		cfg.checkConflicts()
+	}
+}
+
+// cloneInto deep clones val into dst with memory allocated from a.
+// The dst must be settable and its type must be val's type.
+//
+// Structs and arrays are cloned into dst in place,
+// so that no memory is allocated for the root value.
+func (a *Allocator) cloneInto(val, dst reflect.Value, slowly, inCustomFunc bool) {
+	state := &cloneState{}
+	a.initCloneState(state, slowly)
+
 	if inCustomFunc {
 		state.skipCustomFuncValue = val
 	}
 
-	return state.clone(val)
+	if labels := state.labels; labels != nil {
+		labels.do(val.Type(), func() {
+			state.cloneInto(val, dst)
+		})
+		return
+	}
+
+	state.cloneInto(val, dst)
+}
+
+// cloneInto deep clones val into dst.
+func (state *cloneState) cloneInto(val, dst reflect.Value) {
+	if tx := state.tx; tx != nil {
+		state.tx = nil
+		defer tx.end()
+		state.cloneInto(val, dst)
+		tx.commit()
+		return
+	}
+
+	if state.strict {
+		state.config.checkInvalidValues(val)
+	}
+
+	if state.readOnly {
+		defer state.checkSource(val, fingerprint(val))
+	}
+
+	switch val.Kind() {
+	case reflect.Struct:
+		dst.Set(reflect.Zero(dst.Type()))
+		state.copyStruct(val, dst.Addr())
+	case reflect.Array:
+		dst.Set(reflect.Zero(dst.Type()))
+		state.copyArray(val, dst.Addr())
+	default:
+		dst.Set(state.clone(val))
+	}
+
+	state.drain()
+	state.fix(dst)
+	state.rebind(dst)
+}
+
+// CanShadowCopy returns true if a clone of any value of t made by a is a shadow copy of the value,
+// e.g. t is a struct of numbers without any custom func.
+// In strict mode, CanShadowCopy always returns false, as cloned values must be validated.
+//
+// It's designed for fast paths which copy values of t directly instead of calling clone methods.
+func (a *Allocator) CanShadowCopy(t reflect.Type) bool {
+	cfg := a.loadConfig()
+
+	if cfg.isStrictMode() {
+		return false
+	}
+
+	if cfg.isScalarType(t) {
+		return true
+	}
+
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	st := cfg.loadStructType(t)
+	return st.CanShadowCopy()
 }
 
 // CloneSlowly recursively deep clone val to a new value with memory allocated from a.
@@ -130,136 +319,119 @@ func (a *Allocator) cloneSlowly(val reflect.Value, inCustomFunc bool) reflect.Va
 		return val
 	}
 
-	state := &cloneState{
-		allocator: a,
-		visited:   visitMap{},
-		invalid:   invalidPointers{},
-	}
+	state := &cloneState{}
+	a.initCloneState(state, true)
 
 	if inCustomFunc {
 		state.skipCustomFuncValue = val
 	}
 
-	cloned := state.clone(val)
-	state.fix(cloned)
-	return cloned
+	return state.cloneRoot(val)
 }
 
-func (a *Allocator) loadStructType(t reflect.Type) (st structType) {
-	st, ok := a.lookupStructType(t)
+// loadConfig returns current config snapshot of a.
+// If any parent config is changed, a's config is rebuilt on top of the latest parent config.
+func (a *Allocator) loadConfig() *config {
+	cfg := (*config)(atomic.LoadPointer(&a.config))
 
-	if ok {
-		return
+	if a.parent == nil {
+		return cfg
 	}
 
-	num := t.NumField()
-	zeroFeilds := make([]structFieldSize, 0, num)
-	pointerFields := make([]structFieldType, 0, num)
+	if a.IsFrozen() {
+		return cfg
+	}
 
-	// Find pointer fields in depth-first order.
-	for i := 0; i < num; i++ {
-		field := t.Field(i)
-		ft := field.Type
-		k := ft.Kind()
-		tag := field.Tag.Get(fieldTagName)
+	parent := a.parent.loadConfig()
 
-		if tag == fieldTagValueSkip || tag == fieldTagValueSkipAlias {
-			zeroFeilds = append(zeroFeilds, structFieldSize{
-				Offset: field.Offset,
-				Size:   uintptr(ft.Size()),
-			})
-			continue
-		}
+	if cfg == parent || cfg.parent == parent {
+		return cfg
+	}
 
-		if tag == fieldTagValueShadowCopy || a.isScalar(k) {
-			continue
-		}
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
-		switch k {
-		case reflect.Array:
-			if ft.Len() == 0 {
-				continue
-			}
-
-			elem := ft.Elem()
-
-			if a.isScalar(elem.Kind()) {
-				continue
-			}
-
-			if elem.Kind() == reflect.Struct {
-				if fst := a.loadStructType(elem); fst.CanShadowCopy() {
-					continue
-				}
-			}
-		case reflect.Struct:
-			if fst := a.loadStructType(ft); fst.CanShadowCopy() {
-				continue
-			}
-		}
+	cfg = (*config)(atomic.LoadPointer(&a.config))
 
-		pointerFields = append(pointerFields, structFieldType{
-			Offset: field.Offset,
-			Index:  i,
-		})
+	if cfg.parent != parent {
+		cfg = cfg.copy()
+		cfg.parent = parent
+		atomic.StorePointer(&a.config, unsafe.Pointer(cfg))
 	}
 
-	st = structType{}
+	return cfg
+}
 
-	if len(zeroFeilds) != 0 {
-		st.ZeroFields = append(st.ZeroFields, zeroFeilds...)
-	}
+// updateConfig publishes a new config returned by fn.
+// The fn must not modify the config passed to it.
+func (a *Allocator) updateConfig(fn func(cfg *config) *config) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
-	if len(pointerFields) != 0 {
-		st.PointerFields = append(st.PointerFields, pointerFields...)
+	if a.IsFrozen() {
+		panic("go-clone: cannot customize a frozen allocator")
 	}
 
-	// Load custom function.
-	current := a
-
-	for current != nil {
-		if fn, ok := current.cachedCustomFuncTypes.Load(t); ok {
-			st.fn = fn.(Func)
-			break
-		}
+	cfg := (*config)(atomic.LoadPointer(&a.config))
 
-		current = current.parent
+	// The config is shared with a frozen parent. Derive a new one from it.
+	if a.parent != nil && unsafe.Pointer(cfg) == atomic.LoadPointer(&a.parent.config) {
+		cfg = newConfig(cfg, a.isScalar)
 	}
 
-	a.cachedStructTypes.LoadOrStore(t, st)
-	return
+	atomic.StorePointer(&a.config, unsafe.Pointer(fn(cfg)))
 }
 
-func (a *Allocator) lookupStructType(t reflect.Type) (st structType, ok bool) {
-	var v interface{}
-	current := a
+// Freeze makes a read-only.
+// All customizations in a and a's parents are merged into a single config,
+// which is used by a from now on.
+// Customizations made in a's parents after Freeze don't affect a,
+// and any attempt to customize a panics.
+//
+// Freeze is designed for a shared base allocator.
+// Allocators created by NewAllocator with a frozen parent share parent's config
+// and type caches until they are customized,
+// so that it's cheap to create a lot of short-lived allocators,
+// e.g. one allocator per request with its own memory pool.
+// If AllocatorMethods.IsScalar is set, the new allocator has to build its own caches.
+func (a *Allocator) Freeze() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.IsFrozen() {
+		return
+	}
 
-	for current != nil {
-		v, ok = current.cachedStructTypes.Load(t)
+	cfg := (*config)(atomic.LoadPointer(&a.config))
 
-		if ok {
-			st = v.(structType)
-			return
+	if a.parent != nil {
+		if parent := a.parent.loadConfig(); cfg != parent && cfg.parent != parent {
+			cfg = cfg.copy()
+			cfg.parent = parent
 		}
-
-		current = current.parent
 	}
 
-	return
+	atomic.StorePointer(&a.config, unsafe.Pointer(cfg.flatten()))
+	atomic.StoreInt32(&a.frozen, 1)
 }
 
-func (a *Allocator) isOpaquePointer(t reflect.Type) (ok bool) {
-	current := a
+// IsFrozen returns true if a is frozen by Freeze.
+func (a *Allocator) IsFrozen() bool {
+	return atomic.LoadInt32(&a.frozen) != 0
+}
 
-	for current != nil {
-		if _, ok = current.cachedPointerTypes.Load(t); ok {
-			return
-		}
+func (a *Allocator) updateTypeConfig(t reflect.Type, fn func(tc *typeConfig)) {
+	a.updateConfig(func(cfg *config) *config {
+		return cfg.update(t, fn)
+	})
+}
 
-		current = current.parent
-	}
+func (a *Allocator) loadStructType(t reflect.Type) *structType {
+	return a.loadConfig().loadStructType(t)
+}
 
-	return
+func (a *Allocator) isOpaquePointer(t reflect.Type) bool {
+	return a.loadConfig().isOpaquePointer(t)
 }
 
 // MarkAsScalar marks t as a scalar type so that all clone methods will copy t by value.
@@ -280,7 +452,9 @@ func (a *Allocator) MarkAsScalar(t reflect.Type) {
 		return
 	}
 
-	a.cachedStructTypes.Store(t, zeroStructType)
+	a.updateTypeConfig(t, func(tc *typeConfig) {
+		tc.scalar = true
+	})
 }
 
 // MarkAsOpaquePointer marks t as an opaque pointer so that all clone methods will copy t by value.
@@ -294,19 +468,98 @@ func (a *Allocator) MarkAsOpaquePointer(t reflect.Type) {
 		return
 	}
 
-	a.cachedPointerTypes.Store(t, struct{}{})
+	a.updateTypeConfig(t, func(tc *typeConfig) {
+		tc.opaque = true
+	})
+}
+
+// UnmarkAsScalar removes the scalar mark of t set by MarkAsScalar in a.
+// If t is a pointer to struct, its elem type is used.
+// Marks in a's parents are not affected and still apply to a.
+func (a *Allocator) UnmarkAsScalar(t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	a.updateTypeConfig(t, func(tc *typeConfig) {
+		tc.scalar = false
+	})
+}
+
+// UnmarkAsOpaquePointer removes the opaque pointer mark of t set by MarkAsOpaquePointer in a.
+// Marks in a's parents are not affected and still apply to a.
+func (a *Allocator) UnmarkAsOpaquePointer(t reflect.Type) {
+	if t.Kind() != reflect.Ptr {
+		return
+	}
+
+	a.updateTypeConfig(t, func(tc *typeConfig) {
+		tc.opaque = false
+	})
+}
+
+// IsMarkedAsScalar returns true if struct type t is marked as scalar in a or a's parents
+// and the mark is not overridden by a custom func according to precedence.
+// If t is a pointer to struct, its elem type is checked.
+func (a *Allocator) IsMarkedAsScalar(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	_, scalar := a.loadConfig().lookupScalar(t)
+	return scalar
+}
+
+// IsOpaquePointer returns true if pointer type t is marked as an opaque pointer in a or a's parents.
+func (a *Allocator) IsOpaquePointer(t reflect.Type) bool {
+	return a.isOpaquePointer(t)
 }
 
 // SetCustomFunc sets a custom clone function for type t.
-// If t is not struct or pointer to struct, SetCustomFunc ignores t.
+// The t can be a struct type, a pointer to struct or a named non-struct type,
+// e.g. `type Duration int64` or `type Set map[string]struct{}`.
+// If t is neither of them or is an interface type, SetCustomFunc ignores t.
 //
 // If fn is nil, remove the custom clone function for type t.
 func (a *Allocator) SetCustomFunc(t reflect.Type, fn Func) {
-	if fn == nil {
-		a.cachedCustomFuncTypes.Delete(t)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() == reflect.Struct {
+		a.updateTypeConfig(t, func(tc *typeConfig) {
+			tc.fn = fn
+		})
+		return
+	}
+
+	if t.Name() == "" || t.Kind() == reflect.Interface {
 		return
 	}
 
+	a.updateConfig(func(cfg *config) *config {
+		updated := cfg.update(t, func(tc *typeConfig) {
+			tc.fn = fn
+		})
+		updated.namedFuncs = updated.namedFuncs || fn != nil
+		return updated
+	})
+}
+
+// SetRebindFunc sets a rebind function for struct type t.
+// If t is not struct or pointer to struct, SetRebindFunc ignores t.
+//
+// The fn is called with the cloned root value and every cloned value of t
+// after the whole value is cloned.
+// Fields tagged with `clone:"rebind"` are left zero during cloning and
+// fn is responsible to populate them.
+//
+// If fn is nil, remove the rebind function for type t.
+func (a *Allocator) SetRebindFunc(t reflect.Type, fn RebindFunc) {
 	for t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
@@ -315,7 +568,31 @@ func (a *Allocator) SetCustomFunc(t reflect.Type, fn Func) {
 		return
 	}
 
-	a.cachedCustomFuncTypes.Store(t, fn)
+	a.updateTypeConfig(t, func(tc *typeConfig) {
+		tc.rebind = fn
+	})
+}
+
+// SetStrictMode enables or disables strict mode in a.
+// If strict mode is not set, a inherits it from parent allocator.
+// Strict mode is disabled in the default allocator.
+//
+// In strict mode, cloned values are checked by validators registered by RegisterValidator
+// and any failure panics with an error.
+// Source values containing invalid reflect.Values are rejected with an *InvalidValueError
+// listing paths to all of them.
+func (a *Allocator) SetStrictMode(strict bool) {
+	option := optionDisabled
+
+	if strict {
+		option = optionEnabled
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.strictMode = option
+		return copied
+	})
 }
 
 func heapNew(pool unsafe.Pointer, t reflect.Type) reflect.Value {
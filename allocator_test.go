@@ -5,6 +5,7 @@ package clone
 
 import (
 	"reflect"
+	"sync"
 	"testing"
 	"unsafe"
 
@@ -84,3 +85,280 @@ func TestAllocatorCloneSlowly(t *testing.T) {
 	//     - data.Next.Next
 	a.Equal(cnt, 4)
 }
+
+func TestAllocatorSetRebindFunc(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+
+	type manager struct {
+		sessions []interface{}
+	}
+	type session struct {
+		ID      int
+		manager *manager `clone:"rebind"`
+	}
+	type app struct {
+		Sessions []*session
+		Main     session
+	}
+
+	mgr := &manager{}
+	called := 0
+	allocator.SetRebindFunc(reflect.TypeOf(session{}), func(root, owner reflect.Value) {
+		called++
+		s := owner.Addr().Interface().(*session)
+		s.manager = mgr
+		mgr.sessions = append(mgr.sessions, root.Interface())
+	})
+
+	orig := &app{
+		Sessions: []*session{{ID: 1}, {ID: 2}},
+		Main:     session{ID: 3},
+	}
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*app)
+
+	a.Equal(called, 3)
+	a.Assert(cloned.Sessions[0].manager == mgr)
+	a.Assert(cloned.Sessions[1].manager == mgr)
+	a.Assert(cloned.Main.manager == mgr)
+	a.Assert(mgr.sessions[0].(*app) == cloned)
+
+	// Rebind fields are left zero without rebind func.
+	allocator.SetRebindFunc(reflect.TypeOf(session{}), nil)
+	other := FromHeap()
+	cloned = other.Clone(reflect.ValueOf(orig)).Interface().(*app)
+	a.Equal(called, 3)
+	a.Assert(cloned.Sessions[0].manager == nil)
+	a.Equal(cloned.Sessions[0].ID, 1)
+}
+
+func TestSkipFieldsInStructWithoutPointers(t *testing.T) {
+	a := assert.New(t)
+
+	type inner struct {
+		Foo int
+		Bar int `clone:"skip"`
+	}
+	type outer struct {
+		Inner inner
+		Baz   int
+	}
+
+	cloned := Clone(&outer{
+		Inner: inner{Foo: 1, Bar: 2},
+		Baz:   3,
+	}).(*outer)
+	a.Equal(cloned, &outer{
+		Inner: inner{Foo: 1},
+		Baz:   3,
+	})
+}
+
+func TestAllocatorConfigSnapshot(t *testing.T) {
+	a := assert.New(t)
+	parent := FromHeap()
+	allocator := NewAllocator(nil, &AllocatorMethods{
+		Parent: parent,
+	})
+
+	type data struct {
+		Values []int
+	}
+	type trigger struct {
+		Foo int
+	}
+	type pair struct {
+		Trigger *trigger
+		Data    *data
+	}
+
+	// Registrations made during cloning don't affect current traversal.
+	allocator.SetCustomFunc(reflect.TypeOf(trigger{}), func(allocator *Allocator, old, new reflect.Value) {
+		allocator.MarkAsScalar(reflect.TypeOf(data{}))
+		new.Set(old)
+	})
+	orig := &pair{
+		Trigger: &trigger{Foo: 1},
+		Data:    &data{Values: []int{1, 2, 3}},
+	}
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*pair)
+	a.Equal(cloned, orig)
+	a.Assert(&cloned.Data.Values[0] != &orig.Data.Values[0])
+
+	// Registrations take effect in next clone, even if the type has been cloned before.
+	cloned = allocator.Clone(reflect.ValueOf(orig)).Interface().(*pair)
+	a.Assert(&cloned.Data.Values[0] == &orig.Data.Values[0])
+
+	// Registrations in parent take effect in child as well.
+	allocator.SetCustomFunc(reflect.TypeOf(trigger{}), nil)
+	parent.SetCustomFunc(reflect.TypeOf(trigger{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.FieldByName("Foo").SetInt(2)
+	})
+	cloned = allocator.Clone(reflect.ValueOf(orig)).Interface().(*pair)
+	a.Equal(cloned.Trigger.Foo, 2)
+}
+
+func TestAllocatorConcurrentRegistration(t *testing.T) {
+	allocator := FromHeap()
+
+	type data struct {
+		Values []int
+	}
+
+	orig := &data{Values: []int{1, 2, 3}}
+	typ := reflect.TypeOf(orig)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 1000; i++ {
+			allocator.SetCustomFunc(typ, func(allocator *Allocator, old, new reflect.Value) {
+				new.Set(old)
+			})
+			allocator.MarkAsScalar(typ)
+			allocator.SetCustomFunc(typ, nil)
+		}
+
+		close(done)
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*data)
+
+			if len(cloned.Values) != 3 {
+				t.Errorf("invalid cloned value: %v", cloned)
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+}
+
+func TestAllocatorFreeze(t *testing.T) {
+	a := assert.New(t)
+	root := FromHeap()
+	base := NewAllocator(nil, &AllocatorMethods{
+		Parent: root,
+	})
+
+	type scalar struct {
+		Values []int
+	}
+	type custom struct {
+		Foo int
+	}
+	type data struct {
+		Scalar *scalar
+		Custom *custom
+	}
+
+	root.MarkAsScalar(reflect.TypeOf(scalar{}))
+	base.SetCustomFunc(reflect.TypeOf(custom{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.FieldByName("Foo").SetInt(old.FieldByName("Foo").Int() + 1)
+	})
+	base.Freeze()
+	a.Assert(base.IsFrozen())
+	a.Assert(!root.IsFrozen())
+
+	// Customizing a frozen allocator panics.
+	a.Assert(func() (ok bool) {
+		defer func() {
+			ok = recover() != nil
+		}()
+		base.MarkAsScalar(reflect.TypeOf(custom{}))
+		return
+	}())
+
+	// Customizations in parent after Freeze don't affect the frozen allocator.
+	root.MarkAsScalar(reflect.TypeOf(custom{}))
+
+	orig := &data{
+		Scalar: &scalar{Values: []int{1, 2}},
+		Custom: &custom{Foo: 1},
+	}
+	cloned := base.Clone(reflect.ValueOf(orig)).Interface().(*data)
+	a.Assert(&cloned.Scalar.Values[0] == &orig.Scalar.Values[0])
+	a.Equal(cloned.Custom.Foo, 2)
+
+	// Derived allocators share base's config until they are customized.
+	derived := NewAllocator(nil, &AllocatorMethods{
+		Parent: base,
+	})
+	a.Assert(derived.config == base.config)
+	cloned = derived.Clone(reflect.ValueOf(orig)).Interface().(*data)
+	a.Equal(cloned.Custom.Foo, 2)
+
+	derived.MarkAsScalar(reflect.TypeOf(custom{}))
+	a.Assert(derived.config != base.config)
+	cloned = derived.Clone(reflect.ValueOf(orig)).Interface().(*data)
+	a.Equal(cloned.Custom.Foo, 1)
+	a.Assert(&cloned.Scalar.Values[0] == &orig.Scalar.Values[0])
+
+	cloned = base.Clone(reflect.ValueOf(orig)).Interface().(*data)
+	a.Equal(cloned.Custom.Foo, 2)
+
+	// A derived allocator with its own IsScalar builds its own config.
+	other := NewAllocator(nil, &AllocatorMethods{
+		Parent:   base,
+		IsScalar: IsScalar,
+	})
+	a.Assert(other.config != base.config)
+	cloned = other.Clone(reflect.ValueOf(orig)).Interface().(*data)
+	a.Equal(cloned.Custom.Foo, 2)
+}
+
+func TestAllocatorRegistrationQueries(t *testing.T) {
+	a := assert.New(t)
+
+	type scalar struct {
+		P *int
+	}
+	type opaque struct {
+		P *int
+	}
+
+	parent := FromHeap()
+	parent.MarkAsScalar(reflect.TypeOf(scalar{}))
+	parent.MarkAsOpaquePointer(reflect.TypeOf(&opaque{}))
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: parent,
+	})
+
+	a.Assert(child.IsMarkedAsScalar(reflect.TypeOf(scalar{})))
+	a.Assert(child.IsMarkedAsScalar(reflect.TypeOf(&scalar{})))
+	a.Assert(!child.IsMarkedAsScalar(reflect.TypeOf(opaque{})))
+	a.Assert(child.IsOpaquePointer(reflect.TypeOf(&opaque{})))
+	a.Assert(!child.IsOpaquePointer(reflect.TypeOf(&scalar{})))
+
+	// Custom func in child overrides scalar mark in parent.
+	child.SetCustomFunc(reflect.TypeOf(scalar{}), func(allocator *Allocator, old, new reflect.Value) {})
+	a.Assert(!child.IsMarkedAsScalar(reflect.TypeOf(scalar{})))
+	a.Assert(parent.IsMarkedAsScalar(reflect.TypeOf(scalar{})))
+
+	// Values can be shadow copied if there is nothing to clone in depth.
+	type point struct {
+		X, Y int
+	}
+	a.Assert(parent.CanShadowCopy(reflect.TypeOf(0)))
+	a.Assert(parent.CanShadowCopy(reflect.TypeOf(point{})))
+	a.Assert(parent.CanShadowCopy(reflect.TypeOf(scalar{})))
+	a.Assert(!parent.CanShadowCopy(reflect.TypeOf(opaque{})))
+	a.Assert(!parent.CanShadowCopy(reflect.TypeOf([]int{})))
+	a.Assert(!child.CanShadowCopy(reflect.TypeOf(scalar{})))
+
+	child.SetStrictMode(true)
+	a.Assert(!child.CanShadowCopy(reflect.TypeOf(point{})))
+}
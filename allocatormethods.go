@@ -4,10 +4,17 @@
 package clone
 
 import (
+	"errors"
 	"reflect"
+	"sync/atomic"
 	"unsafe"
 )
 
+// ErrExhausted is the error to signal that an allocator runs out of memory in its pool.
+// Allocator methods can panic with an error wrapping ErrExhausted,
+// or return an invalid reflect.Value, to allocate memory from AllocatorMethods.Fallback.
+var ErrExhausted = errors.New("go-clone: allocator is exhausted")
+
 // AllocatorMethods defines all methods required by allocator.
 // If any of these methods is nil, allocator will use default method which allocates memory from heap.
 type AllocatorMethods struct {
@@ -15,11 +22,35 @@ type AllocatorMethods struct {
 	// If it's nil, it will be the default allocator.
 	Parent *Allocator
 
+	// Fallback is the allocator to allocate memory when any method fails to allocate memory,
+	// i.e. returns an invalid reflect.Value or panics with ErrExhausted.
+	// It's designed for allocators with limited memory, e.g. fixed-size arenas or quota pools,
+	// so that clones degrade to fallback allocator instead of panicking with a half-built clone.
+	// Every fallback is reported by ReportFallback.
+	// If it's nil, clones panic when methods fail.
+	Fallback *Allocator
+
 	New       func(pool unsafe.Pointer, t reflect.Type) reflect.Value
 	MakeSlice func(pool unsafe.Pointer, t reflect.Type, len, cap int) reflect.Value
 	MakeMap   func(pool unsafe.Pointer, t reflect.Type, n int) reflect.Value
 	MakeChan  func(pool unsafe.Pointer, t reflect.Type, buffer int) reflect.Value
 	IsScalar  func(k reflect.Kind) bool
+
+	// Free returns v to the pool.
+	// The v is a value returned by New, MakeSlice, MakeMap or MakeChan.
+	// It's optional and called in transactional mode or by Allocator.Free.
+	// See Allocator.SetTransactional and Allocator.Free for details.
+	// If it's nil, memory is never freed except by GC.
+	Free func(pool unsafe.Pointer, v reflect.Value)
+
+	// Reset returns all memory allocated from the pool at once, so that the pool can be reused.
+	// It must keep the memory of the allocator itself, which is allocated by New in NewAllocator.
+	// It's called by Allocator.Reset. If it's nil, Allocator.Reset does nothing.
+	Reset func(pool unsafe.Pointer)
+
+	// Release returns all memory of the pool, including the memory of the allocator itself.
+	// It's called by Allocator.Release. If it's nil, Allocator.Release does nothing.
+	Release func(pool unsafe.Pointer)
 }
 
 func (am *AllocatorMethods) parent() *Allocator {
@@ -30,6 +61,14 @@ func (am *AllocatorMethods) parent() *Allocator {
 	return nil
 }
 
+func (am *AllocatorMethods) fallback() *Allocator {
+	if am != nil && am.Fallback != nil {
+		return am.Fallback
+	}
+
+	return nil
+}
+
 func (am *AllocatorMethods) new(parent *Allocator, pool unsafe.Pointer) func(pool unsafe.Pointer, t reflect.Type) reflect.Value {
 	if am != nil && am.New != nil {
 		return am.New
@@ -102,6 +141,24 @@ func (am *AllocatorMethods) makeChan(parent *Allocator, pool unsafe.Pointer) fun
 	return defaultAllocator.makeChan
 }
 
+func (am *AllocatorMethods) free(parent *Allocator, pool unsafe.Pointer) func(pool unsafe.Pointer, v reflect.Value) {
+	if am != nil && am.Free != nil {
+		return am.Free
+	}
+
+	if parent != nil && parent.free != nil {
+		if parent.pool == pool {
+			return parent.free
+		} else {
+			return func(pool unsafe.Pointer, v reflect.Value) {
+				parent.free(parent.pool, v)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (am *AllocatorMethods) isScalar(parent *Allocator) func(t reflect.Kind) bool {
 	if am != nil && am.IsScalar != nil {
 		return am.IsScalar
@@ -113,3 +170,101 @@ func (am *AllocatorMethods) isScalar(parent *Allocator) func(t reflect.Kind) boo
 
 	return defaultAllocator.isScalar
 }
+
+// withFallback makes all methods of a fall back to fallback when they fail to allocate memory.
+func (a *Allocator) withFallback(fallback *Allocator) {
+	const reason = "allocator is exhausted"
+	a.fallback = fallback
+	a.primary = &Allocator{
+		pool:      a.pool,
+		new:       a.new,
+		makeSlice: a.makeSlice,
+		makeMap:   a.makeMap,
+		makeChan:  a.makeChan,
+		free:      a.free,
+	}
+	new := a.new
+	makeSlice := a.makeSlice
+	makeMap := a.makeMap
+	makeChan := a.makeChan
+
+	a.new = func(pool unsafe.Pointer, t reflect.Type) reflect.Value {
+		if v := tryAllocate(func() reflect.Value { return new(pool, t) }); v.IsValid() {
+			return v
+		}
+
+		a.ReportFallback(t, reason)
+		return fallback.New(t)
+	}
+	a.makeSlice = func(pool unsafe.Pointer, t reflect.Type, len, cap int) reflect.Value {
+		if v := tryAllocate(func() reflect.Value { return makeSlice(pool, t, len, cap) }); v.IsValid() {
+			return v
+		}
+
+		a.ReportFallback(t, reason)
+		return fallback.MakeSlice(t, len, cap)
+	}
+	a.makeMap = func(pool unsafe.Pointer, t reflect.Type, n int) reflect.Value {
+		if v := tryAllocate(func() reflect.Value { return makeMap(pool, t, n) }); v.IsValid() {
+			return v
+		}
+
+		a.ReportFallback(t, reason)
+		return fallback.MakeMap(t, n)
+	}
+	a.makeChan = func(pool unsafe.Pointer, t reflect.Type, buffer int) reflect.Value {
+		if v := tryAllocate(func() reflect.Value { return makeChan(pool, t, buffer) }); v.IsValid() {
+			return v
+		}
+
+		a.ReportFallback(t, reason)
+		return fallback.MakeChan(t, buffer)
+	}
+}
+
+// tryAllocate calls alloc and returns an invalid value if alloc panics with ErrExhausted.
+func tryAllocate(alloc func() reflect.Value) (v reflect.Value) {
+	defer func() {
+		if r := recover(); r != nil {
+			if err, ok := r.(error); ok && errors.Is(err, ErrExhausted) {
+				v = reflect.Value{}
+				return
+			}
+
+			panic(r)
+		}
+	}()
+
+	return alloc()
+}
+
+// derive returns a frozen allocator which shares pool and config with a.
+// The wrap sets methods of the derived allocator by wrapping methods of the primary allocator.
+// If a falls back to another allocator, the fallback allocator is derived by wrap as well.
+func (a *Allocator) derive(wrap func(primary, derived *Allocator)) *Allocator {
+	primary := a
+
+	if a.primary != nil {
+		primary = a.primary
+	}
+
+	derived := &Allocator{
+		parent:   a.parent,
+		pool:     a.pool,
+		free:     a.free,
+		reset:    a.reset,
+		release:  a.release,
+		isScalar: a.isScalar,
+		tx:       a.tx,
+		counters: atomic.LoadPointer(&a.counters),
+		config:   unsafe.Pointer(a.loadConfig()),
+		frozen:   1,
+	}
+	wrap(primary, derived)
+
+	if a.fallback != nil {
+		derived.withFallback(a.fallback.derive(wrap))
+	}
+
+	return derived
+}
@@ -4,6 +4,7 @@
 package clone
 
 import (
+	"fmt"
 	"reflect"
 	"sync"
 	"testing"
@@ -119,3 +120,108 @@ func TestAllocatorMethodsPool(t *testing.T) {
 	// 1 for MakeChan.
 	a.Equal(pool2Called, 1)
 }
+
+type quotaPool struct {
+	quota     int
+	exhausted func() reflect.Value
+}
+
+func (qp *quotaPool) allocate(alloc func() reflect.Value) reflect.Value {
+	if qp.quota <= 0 {
+		return qp.exhausted()
+	}
+
+	qp.quota--
+	return alloc()
+}
+
+func newQuotaAllocator(qp *quotaPool, fallback *Allocator) *Allocator {
+	return NewAllocator(unsafe.Pointer(qp), &AllocatorMethods{
+		Fallback: fallback,
+		New: func(pool unsafe.Pointer, t reflect.Type) reflect.Value {
+			return (*quotaPool)(pool).allocate(func() reflect.Value {
+				return reflect.New(t)
+			})
+		},
+		MakeSlice: func(pool unsafe.Pointer, t reflect.Type, len, cap int) reflect.Value {
+			return (*quotaPool)(pool).allocate(func() reflect.Value {
+				return reflect.MakeSlice(t, len, cap)
+			})
+		},
+		MakeMap: func(pool unsafe.Pointer, t reflect.Type, n int) reflect.Value {
+			return (*quotaPool)(pool).allocate(func() reflect.Value {
+				return reflect.MakeMapWithSize(t, n)
+			})
+		},
+	})
+}
+
+func TestAllocatorMethodsFallback(t *testing.T) {
+	a := assert.New(t)
+	type T struct {
+		Name  *string
+		List  []int
+		Attrs map[string]int
+		Next  *T
+	}
+	name := "foo"
+	orig := &T{
+		Name:  &name,
+		List:  []int{1, 2, 3},
+		Attrs: map[string]int{"a": 1},
+		Next: &T{
+			List: []int{4},
+		},
+	}
+
+	cases := []func() reflect.Value{
+		func() reflect.Value {
+			return reflect.Value{}
+		},
+		func() reflect.Value {
+			panic(fmt.Errorf("quota pool: %w", ErrExhausted))
+		},
+	}
+
+	for _, exhausted := range cases {
+		qp := &quotaPool{
+			quota:     3,
+			exhausted: exhausted,
+		}
+		var fallbacks []string
+		allocator := newQuotaAllocator(qp, FromHeap())
+		allocator.SetFallbackFunc(func(t reflect.Type, reason string) {
+			fallbacks = append(fallbacks, t.String()+": "+reason)
+		})
+
+		cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*T)
+		a.Equal(cloned, orig)
+		a.Assert(cloned.Next != orig.Next)
+		a.Equal(qp.quota, 0)
+		a.Equal(fallbacks, []string{
+			"[]int: allocator is exhausted",
+			"map[string]int: allocator is exhausted",
+			"clone.T: allocator is exhausted",
+			"[]int: allocator is exhausted",
+		})
+	}
+
+	// The allocator itself is allocated by fallback.
+	qp := &quotaPool{
+		exhausted: cases[0],
+	}
+	allocator := newQuotaAllocator(qp, FromHeap())
+	a.Equal(allocator.Clone(reflect.ValueOf(orig)).Interface(), orig)
+
+	// Other panics are not recovered.
+	qp = &quotaPool{
+		quota: 1,
+		exhausted: func() reflect.Value {
+			panic("unexpected")
+		},
+	}
+	allocator = newQuotaAllocator(qp, FromHeap())
+	_, err := allocator.TryClone(reflect.ValueOf(orig))
+	a.Assert(err != nil)
+	a.Equal(err.(*PanicError).Value, "unexpected")
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"sync/atomic"
+	"unsafe"
+)
+
+// AllocationStats is the statistics of memory allocated by an allocator.
+// See Allocator.SetAllocationStats for details.
+type AllocationStats struct {
+	// Objects is the number of objects allocated by all methods.
+	Objects int64
+
+	// Bytes is the number of bytes requested by all methods.
+	// The internal data structure of maps is not counted,
+	// thus the bytes of a map is estimated as the size of n keys and n values.
+	Bytes int64
+
+	New       AllocationCount // Allocated by Allocator.New.
+	MakeSlice AllocationCount // Allocated by Allocator.MakeSlice.
+	MakeMap   AllocationCount // Allocated by Allocator.MakeMap.
+	MakeChan  AllocationCount // Allocated by Allocator.MakeChan.
+}
+
+// AllocationCount is the number of objects and bytes allocated by a kind of allocator method.
+type AllocationCount struct {
+	Objects int64
+	Bytes   int64
+}
+
+// allocCounters counts memory allocated by an allocator.
+// All fields are updated atomically.
+type allocCounters struct {
+	new       allocCounter
+	makeSlice allocCounter
+	makeMap   allocCounter
+	makeChan  allocCounter
+}
+
+type allocCounter struct {
+	objects int64
+	bytes   int64
+}
+
+func (c *allocCounter) count(v reflect.Value, bytes int) {
+	if !v.IsValid() {
+		return
+	}
+
+	atomic.AddInt64(&c.objects, 1)
+	atomic.AddInt64(&c.bytes, int64(bytes))
+}
+
+func (c *allocCounter) load() AllocationCount {
+	return AllocationCount{
+		Objects: atomic.LoadInt64(&c.objects),
+		Bytes:   atomic.LoadInt64(&c.bytes),
+	}
+}
+
+// SetAllocationStats enables or disables allocation stats in a.
+// Allocation stats are disabled by default.
+//
+// When enabled, a counts all objects and bytes allocated by its methods New, MakeSlice, MakeMap and MakeChan,
+// which includes all memory allocated in clones by a and memory allocated by fallback allocator.
+// Call Stats to read counters.
+// It's designed to attribute memory cost of clones, e.g. by creating an allocator for every request.
+//
+// Counters are updated atomically, so that a can be shared by goroutines.
+// Disabling allocation stats drops all counters.
+// Allocation stats are not inherited by child allocators.
+func (a *Allocator) SetAllocationStats(enabled bool) {
+	var counters unsafe.Pointer
+
+	if enabled {
+		if atomic.LoadPointer(&a.counters) != nil {
+			return
+		}
+
+		counters = unsafe.Pointer(&allocCounters{})
+	}
+
+	atomic.StorePointer(&a.counters, counters)
+}
+
+// Stats returns the allocation stats of a.
+// It returns zero stats if allocation stats are disabled.
+func (a *Allocator) Stats() (stats AllocationStats) {
+	counters := a.loadCounters()
+
+	if counters == nil {
+		return
+	}
+
+	stats.New = counters.new.load()
+	stats.MakeSlice = counters.makeSlice.load()
+	stats.MakeMap = counters.makeMap.load()
+	stats.MakeChan = counters.makeChan.load()
+	stats.Objects = stats.New.Objects + stats.MakeSlice.Objects + stats.MakeMap.Objects + stats.MakeChan.Objects
+	stats.Bytes = stats.New.Bytes + stats.MakeSlice.Bytes + stats.MakeMap.Bytes + stats.MakeChan.Bytes
+	return
+}
+
+func (a *Allocator) loadCounters() *allocCounters {
+	return (*allocCounters)(atomic.LoadPointer(&a.counters))
+}
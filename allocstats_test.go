@@ -0,0 +1,76 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestAllocationStats(t *testing.T) {
+	a := assert.New(t)
+	type T struct {
+		Name  *string
+		List  []int
+		Attrs map[string]int
+		Ch    chan int
+	}
+	name := "foo"
+	orig := &T{
+		Name:  &name,
+		List:  []int{1, 2, 3},
+		Attrs: map[string]int{"a": 1},
+		Ch:    make(chan int, 4),
+	}
+
+	allocator := FromHeap()
+	allocator.Clone(reflect.ValueOf(orig))
+	a.Equal(allocator.Stats(), AllocationStats{})
+
+	allocator.SetAllocationStats(true)
+	allocator.Clone(reflect.ValueOf(orig))
+	stats := allocator.Stats()
+	a.Equal(stats.New, AllocationCount{
+		Objects: 2,
+		Bytes:   int64(reflect.TypeOf(T{}).Size() + reflect.TypeOf("").Size()),
+	})
+	a.Equal(stats.MakeSlice, AllocationCount{Objects: 1, Bytes: int64(reflect.TypeOf(0).Size()) * 3})
+	a.Equal(stats.MakeMap, AllocationCount{Objects: 1, Bytes: int64(reflect.TypeOf("").Size()+reflect.TypeOf(0).Size()) * 1})
+	a.Equal(stats.MakeChan, AllocationCount{Objects: 1, Bytes: int64(reflect.TypeOf(0).Size()) * 4})
+	a.Equal(stats.Objects, int64(5))
+	a.Equal(stats.Bytes, stats.New.Bytes+stats.MakeSlice.Bytes+stats.MakeMap.Bytes+stats.MakeChan.Bytes)
+
+	// Counters are shared by goroutines and clones with stats.
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			allocator.CloneWithStats(reflect.ValueOf(orig))
+		}()
+	}
+
+	wg.Wait()
+	a.Equal(allocator.Stats().Objects, stats.Objects*11)
+
+	// Enabling stats again keeps counters.
+	allocator.SetAllocationStats(true)
+	a.Equal(allocator.Stats().Objects, stats.Objects*11)
+
+	// Child allocators don't inherit stats.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	child.Clone(reflect.ValueOf(orig))
+	a.Equal(child.Stats(), AllocationStats{})
+	a.Equal(allocator.Stats().Objects, stats.Objects*11)
+
+	// Disabling stats drops counters.
+	allocator.SetAllocationStats(false)
+	a.Equal(allocator.Stats(), AllocationStats{})
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Package anonymize clones values with sensitive data replaced by generated data.
+// It's designed to turn production values into fixtures which are safe to share.
+//
+// An Anonymizer deep clones a value and then replaces values in the clone
+// by generators registered for struct fields or types.
+// The original value is never modified.
+//
+//	an := anonymize.New(nil)
+//	an.SetTypeGenerator(reflect.TypeOf(""), anonymize.String(42))
+//	an.SetFieldGenerator(reflect.TypeOf(User{}), "Age", anonymize.Number(42))
+//	fixture := an.Anonymize(user).(*User)
+package anonymize
+
+import (
+	"reflect"
+
+	"github.com/huandu/go-clone"
+)
+
+// Generator returns a value to replace v.
+// The returned value must be assignable to v's type.
+// If the returned value is invalid, v is kept as is.
+type Generator func(v reflect.Value) reflect.Value
+
+// Anonymizer clones values and replaces data with generators.
+type Anonymizer struct {
+	allocator *clone.Allocator
+	fields    map[fieldKey]Generator
+	types     map[reflect.Type]Generator
+}
+
+type fieldKey struct {
+	t    reflect.Type
+	name string
+}
+
+// New creates a new Anonymizer which clones values with allocator.
+// If allocator is nil, the heap allocator is used.
+func New(allocator *clone.Allocator) *Anonymizer {
+	if allocator == nil {
+		allocator = clone.FromHeap()
+	}
+
+	return &Anonymizer{
+		allocator: allocator,
+		fields:    map[fieldKey]Generator{},
+		types:     map[reflect.Type]Generator{},
+	}
+}
+
+// SetFieldGenerator sets a generator for the field named name in struct type t.
+// If t is a pointer to struct, its elem type is used.
+// Field generators take precedence over type generators.
+//
+// If g is nil, remove the generator for the field.
+func (an *Anonymizer) SetFieldGenerator(t reflect.Type, name string, g Generator) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	key := fieldKey{
+		t:    t,
+		name: name,
+	}
+
+	if g == nil {
+		delete(an.fields, key)
+		return
+	}
+
+	an.fields[key] = g
+}
+
+// SetTypeGenerator sets a generator for all values of type t.
+//
+// If g is nil, remove the generator for type t.
+func (an *Anonymizer) SetTypeGenerator(t reflect.Type, g Generator) {
+	if g == nil {
+		delete(an.types, t)
+		return
+	}
+
+	an.types[t] = g
+}
+
+// Anonymize clones v in depth and replaces values in the clone with generators.
+// It can clone v with pointer cycles.
+//
+// Only exported struct fields, slice, array and map elements and interface values are replaced.
+// Map keys and unexported struct fields are kept as is in the clone,
+// so that types like time.Time are not broken by generators for basic types.
+// If a value is replaced, values inside the replaced value are not visited.
+func (an *Anonymizer) Anonymize(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	cloned := clone.MakeCloner(an.allocator).CloneSlowly(v)
+	val := reflect.New(reflect.TypeOf(cloned)).Elem()
+	val.Set(reflect.ValueOf(cloned))
+
+	w := &walker{
+		anonymizer: an,
+		visited:    map[visit]struct{}{},
+	}
+	w.walk(val, nil)
+	return val.Interface()
+}
+
+type visit struct {
+	p uintptr
+	t reflect.Type
+}
+
+type walker struct {
+	anonymizer *Anonymizer
+	visited    map[visit]struct{}
+}
+
+// walk replaces v or values inside v with generators.
+// The v must be settable.
+// The g is the field generator for v if v is a struct field.
+func (w *walker) walk(v reflect.Value, g Generator) {
+	if g == nil {
+		g = w.anonymizer.types[v.Type()]
+	}
+
+	if g != nil {
+		if nv := g(v); nv.IsValid() {
+			v.Set(nv)
+			return
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			w.walk(v.Index(i), nil)
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+
+		elem := v.Elem()
+		copied := reflect.New(elem.Type()).Elem()
+		copied.Set(elem)
+		w.walk(copied, nil)
+		v.Set(copied)
+	case reflect.Map:
+		if v.IsNil() || !w.visit(v) {
+			return
+		}
+
+		elem := reflect.New(v.Type().Elem()).Elem()
+
+		for _, key := range v.MapKeys() {
+			elem.Set(v.MapIndex(key))
+			w.walk(elem, nil)
+			v.SetMapIndex(key, elem)
+		}
+	case reflect.Ptr:
+		if v.IsNil() || !w.visit(v) {
+			return
+		}
+
+		w.walk(v.Elem(), nil)
+	case reflect.Slice:
+		if v.IsNil() || !w.visit(v) {
+			return
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			w.walk(v.Index(i), nil)
+		}
+	case reflect.Struct:
+		t := v.Type()
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+
+			if field.PkgPath != "" {
+				continue
+			}
+
+			w.walk(v.Field(i), w.anonymizer.fields[fieldKey{
+				t:    t,
+				name: field.Name,
+			}])
+		}
+	}
+}
+
+func (w *walker) visit(v reflect.Value) bool {
+	vst := visit{
+		p: v.Pointer(),
+		t: v.Type(),
+	}
+
+	if _, ok := w.visited[vst]; ok {
+		return false
+	}
+
+	w.visited[vst] = struct{}{}
+	return true
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package anonymize
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+type email string
+
+type user struct {
+	Name     string
+	Email    email
+	Age      int
+	Phones   []string
+	Labels   map[string]string
+	Extra    interface{}
+	Created  time.Time
+	Friend   *user
+	password string
+}
+
+func TestAnonymize(t *testing.T) {
+	a := assert.New(t)
+	an := New(nil)
+	an.SetTypeGenerator(reflect.TypeOf(""), String(1))
+	an.SetTypeGenerator(reflect.TypeOf(email("")), func(v reflect.Value) reflect.Value {
+		return reflect.ValueOf(email("user@example.com"))
+	})
+	an.SetFieldGenerator(reflect.TypeOf(&user{}), "Age", Number(1))
+
+	orig := &user{
+		Name:   "Alice Smith",
+		Email:  "alice@company.com",
+		Age:    42,
+		Phones: []string{"+1 (555) 123-4567"},
+		Labels: map[string]string{
+			"team": "Infra-01",
+		},
+		Extra:    "secret",
+		Created:  time.Now(),
+		password: "p@ssw0rd",
+	}
+	orig.Friend = orig
+	anonymized := an.Anonymize(orig).(*user)
+
+	a.Assert(anonymized.Friend == anonymized)
+	a.NotEqual(anonymized.Name, orig.Name)
+	a.Equal(len(anonymized.Name), len(orig.Name))
+	a.Equal(anonymized.Name[5], byte(' '))
+	a.Equal(anonymized.Email, email("user@example.com"))
+	a.Assert(anonymized.Age >= 10 && anonymized.Age <= 99)
+	a.Equal(len(anonymized.Phones[0]), len(orig.Phones[0]))
+	a.Equal(anonymized.Phones[0][0], byte('+'))
+	a.Equal(anonymized.Phones[0][3], byte('('))
+	a.Equal(anonymized.Phones[0][12], byte('-'))
+	a.NotEqual(anonymized.Labels["team"], "Infra-01")
+	a.Equal(len(anonymized.Labels["team"]), len("Infra-01"))
+	a.NotEqual(anonymized.Extra, "secret")
+	a.Equal(len(anonymized.Extra.(string)), len("secret"))
+	a.Assert(anonymized.Created.Equal(orig.Created))
+	a.Equal(anonymized.password, orig.password)
+
+	// Original value is not changed.
+	a.Equal(orig.Name, "Alice Smith")
+	a.Equal(orig.Phones[0], "+1 (555) 123-4567")
+	a.Equal(orig.Labels["team"], "Infra-01")
+
+	// Anonymized values are deterministic.
+	another := an.Anonymize(orig).(*user)
+	a.Equal(another.Name, anonymized.Name)
+	a.Equal(another.Age, anonymized.Age)
+}
+
+func TestNumber(t *testing.T) {
+	a := assert.New(t)
+	g := Number(7)
+
+	cases := []interface{}{
+		0, 7, -7, 12345, -12345,
+		int8(127), int8(-128), int64(math.MinInt64), int64(math.MaxInt64),
+		uint8(255), uint64(math.MaxUint64),
+		1.5, -0.0625, float32(3.25),
+	}
+
+	for _, c := range cases {
+		v := reflect.ValueOf(c)
+		nv := g(v)
+		a.Use(&c, &nv)
+
+		a.Equal(nv.Type(), v.Type())
+
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int64:
+			a.Equal(len(strconv.FormatInt(nv.Int(), 10)), len(strconv.FormatInt(v.Int(), 10)))
+		case reflect.Uint8, reflect.Uint64:
+			a.Equal(len(strconv.FormatUint(nv.Uint(), 10)), len(strconv.FormatUint(v.Uint(), 10)))
+		case reflect.Float32, reflect.Float64:
+			a.Assert((nv.Float() < 0) == (v.Float() < 0))
+		}
+	}
+
+	a.Assert(!g(reflect.ValueOf("str")).IsValid())
+}
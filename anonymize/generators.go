@@ -0,0 +1,146 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package anonymize
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+)
+
+// String returns a generator for values of any string kind.
+// It keeps the format of a string:
+// every ASCII letter is replaced by a letter in the same case,
+// every ASCII digit is replaced by a digit and other runes are kept as is,
+// so the length of a string is kept as well.
+//
+// Generated strings are deterministic.
+// The same string is always replaced by the same string with the same seed,
+// so that relations between values, e.g. equal IDs, are kept in fixtures.
+func String(seed int64) Generator {
+	return func(v reflect.Value) reflect.Value {
+		if v.Kind() != reflect.String {
+			return reflect.Value{}
+		}
+
+		s := v.String()
+		r := newRand(seed, s)
+		buf := []byte(s)
+
+		for i, c := range buf {
+			switch {
+			case c >= 'a' && c <= 'z':
+				buf[i] = byte('a' + r.Intn(26))
+			case c >= 'A' && c <= 'Z':
+				buf[i] = byte('A' + r.Intn(26))
+			case c >= '0' && c <= '9':
+				buf[i] = byte('0' + r.Intn(10))
+			}
+		}
+
+		return reflect.ValueOf(string(buf)).Convert(v.Type())
+	}
+}
+
+// Number returns a generator for values of any integer or float kind.
+// It keeps the sign and the number of decimal digits of a number.
+// A float keeps its length in the shortest decimal representation as well.
+//
+// Generated numbers are deterministic like String.
+func Number(seed int64) Generator {
+	return func(v reflect.Value) reflect.Value {
+		nv := reflect.New(v.Type()).Elem()
+
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n := v.Int()
+			r := newRand(seed, strconv.FormatInt(n, 10))
+			max := uint64(1)<<(uint(v.Type().Bits())-1) - 1
+
+			if n < 0 {
+				nv.SetInt(-int64(randDigits(r, uint64(-(n+1))+1, max+1)))
+			} else {
+				nv.SetInt(int64(randDigits(r, uint64(n), max)))
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			n := v.Uint()
+			r := newRand(seed, strconv.FormatUint(n, 10))
+			max := uint64(math.MaxUint64) >> (64 - uint(v.Type().Bits()))
+			nv.SetUint(randDigits(r, n, max))
+		case reflect.Float32, reflect.Float64:
+			f := v.Float()
+
+			if math.IsInf(f, 0) || math.IsNaN(f) {
+				return v
+			}
+
+			s := strconv.FormatFloat(f, 'f', -1, v.Type().Bits())
+			r := newRand(seed, s)
+			buf := []byte(s)
+			leading := true
+
+			for i, c := range buf {
+				if c < '0' || c > '9' {
+					continue
+				}
+
+				// Leading zeros are kept and the first significant digit must not be zero.
+				if leading {
+					if c == '0' {
+						continue
+					}
+
+					buf[i] = byte('1' + r.Intn(9))
+					leading = false
+					continue
+				}
+
+				buf[i] = byte('0' + r.Intn(10))
+			}
+
+			// It never fails as the format is kept.
+			f, _ = strconv.ParseFloat(string(buf), v.Type().Bits())
+			nv.SetFloat(f)
+		default:
+			return reflect.Value{}
+		}
+
+		return nv
+	}
+}
+
+// randDigits returns a random number with the same number of decimal digits as n.
+// The result is not greater than max.
+func randDigits(r *rand.Rand, n, max uint64) uint64 {
+	lo, hi := uint64(0), uint64(9)
+
+	for n > hi {
+		lo = hi + 1
+
+		if hi > math.MaxUint64/10 {
+			hi = math.MaxUint64
+			break
+		}
+
+		hi = hi*10 + 9
+	}
+
+	if hi > max {
+		hi = max
+	}
+
+	return lo + r.Uint64()%(hi-lo+1)
+}
+
+func newRand(seed int64, s string) *rand.Rand {
+	h := fnv.New64a()
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(seed))
+	h.Write(buf[:])
+	h.Write([]byte(s))
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
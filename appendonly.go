@@ -0,0 +1,87 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+)
+
+// MarkAsAppendOnly marks slice type t as append-only in heap allocator.
+//
+// See Allocator.MarkAsAppendOnly for more details.
+func MarkAsAppendOnly(t reflect.Type) {
+	defaultAllocator.MarkAsAppendOnly(t)
+}
+
+// UnmarkAsAppendOnly removes the append-only mark of t in heap allocator.
+//
+// See Allocator.UnmarkAsAppendOnly for more details.
+func UnmarkAsAppendOnly(t reflect.Type) {
+	defaultAllocator.UnmarkAsAppendOnly(t)
+}
+
+// MarkAsAppendOnly marks slice type t as append-only,
+// so that all clone methods share the backing array of a slice of t with the clone.
+// If t is not a slice type, MarkAsAppendOnly ignores t.
+//
+// The clone of an append-only slice has the same len and elements as the original slice,
+// and its cap is set to len.
+// Appending to either slice never changes the other one,
+// as the clone must allocate a new backing array to grow.
+// It makes cloning large append-only slices, e.g. event logs, as cheap as copying a slice header.
+//
+// Elements in append-only slices are never cloned.
+// By convention, callers must not modify any existing element in the original slice or the clone.
+func (a *Allocator) MarkAsAppendOnly(t reflect.Type) {
+	if t.Kind() != reflect.Slice {
+		return
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		updated := cfg.update(t, func(tc *typeConfig) {
+			tc.appendOnly = true
+		})
+		updated.appendOnly = true
+		return updated
+	})
+}
+
+// UnmarkAsAppendOnly removes the append-only mark of t set by MarkAsAppendOnly in a.
+// Marks in a's parents are not affected and still apply to a.
+func (a *Allocator) UnmarkAsAppendOnly(t reflect.Type) {
+	if t.Kind() != reflect.Slice {
+		return
+	}
+
+	a.updateTypeConfig(t, func(tc *typeConfig) {
+		tc.appendOnly = false
+	})
+}
+
+// hasAppendOnly returns true if any slice type is marked as append-only in cfg or parents.
+func (cfg *config) hasAppendOnly() bool {
+	for current := cfg; current != nil; current = current.parent {
+		if current.appendOnly {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (cfg *config) isAppendOnly(t reflect.Type) bool {
+	return cfg.lookup(t, func(tc *typeConfig) bool {
+		return tc.appendOnly
+	}) != nil
+}
+
+// shareSlice returns a slice sharing the backing array of v with cap set to len.
+func shareSlice(v reflect.Value) reflect.Value {
+	if !v.CanInterface() {
+		v = forceClearROFlag(v)
+	}
+
+	l := v.Len()
+	return v.Slice3(0, l, l)
+}
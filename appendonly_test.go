@@ -0,0 +1,61 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type appendOnlyEvent struct {
+	Name string
+	Args []int
+}
+
+type appendOnlyLog struct {
+	Events []*appendOnlyEvent
+	Tags   []string
+}
+
+func TestMarkAsAppendOnly(t *testing.T) {
+	a := assert.New(t)
+	typeOfEvents := reflect.TypeOf([]*appendOnlyEvent{})
+
+	events := make([]*appendOnlyEvent, 0, 10)
+	events = append(events, &appendOnlyEvent{Name: "foo", Args: []int{1}})
+	orig := &appendOnlyLog{
+		Events: events,
+		Tags:   []string{"tag"},
+	}
+
+	allocator := NewAllocator(nil, nil)
+	allocator.MarkAsAppendOnly(typeOfEvents)
+	allocator.MarkAsAppendOnly(reflect.TypeOf(0))
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*appendOnlyLog)
+	a.Equal(cloned, orig)
+	a.Assert(&cloned.Events[0] == &orig.Events[0])
+	a.Assert(cloned.Events[0] == orig.Events[0])
+	a.Equal(cap(cloned.Events), 1)
+	a.Assert(&cloned.Tags[0] != &orig.Tags[0])
+
+	// Future growth diverges.
+	orig.Events = append(orig.Events, &appendOnlyEvent{Name: "bar"})
+	cloned.Events = append(cloned.Events, &appendOnlyEvent{Name: "baz"})
+	a.Equal(orig.Events[1].Name, "bar")
+	a.Equal(cloned.Events[1].Name, "baz")
+
+	// Child allocators inherit the mark.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	cloned = child.CloneSlowly(reflect.ValueOf(orig)).Interface().(*appendOnlyLog)
+	a.Assert(&cloned.Events[0] == &orig.Events[0])
+
+	allocator.UnmarkAsAppendOnly(typeOfEvents)
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().(*appendOnlyLog)
+	a.Equal(cloned, orig)
+	a.Assert(cloned.Events[0] != orig.Events[0])
+}
@@ -6,4 +6,68 @@
 
 package clone
 
+import (
+	"arena"
+	"reflect"
+	"unsafe"
+)
+
 const arenaIsEnabled = true
+
+// FromArena creates an allocator using arena a to allocate memory.
+//
+// As there is no way to allocate map and chan in arena,
+// they are allocated in heap and reported by ReportFallback.
+func FromArena(a *arena.Arena) *Allocator {
+	var allocator *Allocator
+	methods := &AllocatorMethods{
+		New:       arenaNew,
+		MakeSlice: arenaMakeSlice,
+		MakeMap: func(pool unsafe.Pointer, t reflect.Type, n int) reflect.Value {
+			allocator.ReportFallback(t, "arena cannot allocate map")
+			return arenaMakeMap(pool, t, n)
+		},
+		MakeChan: func(pool unsafe.Pointer, t reflect.Type, buffer int) reflect.Value {
+			allocator.ReportFallback(t, "arena cannot allocate chan")
+			return arenaMakeChan(pool, t, buffer)
+		},
+	}
+	allocator = NewAllocator(unsafe.Pointer(a), methods)
+	return allocator
+}
+
+// ArenaClone recursively deep clones v to a new value in arena a.
+// It works in the same way as Clone, except it allocates all memory from arena.
+func ArenaClone(a *arena.Arena, v interface{}) interface{} {
+	return clone(FromArena(a), v)
+}
+
+// ArenaCloneSlowly recursively deep clones v to a new value in arena a.
+// It works in the same way as Slowly, except it allocates all memory from arena.
+func ArenaCloneSlowly(a *arena.Arena, v interface{}) interface{} {
+	return cloneSlowly(FromArena(a), v)
+}
+
+func arenaNew(pool unsafe.Pointer, t reflect.Type) reflect.Value {
+	return reflect.ArenaNew((*arena.Arena)(pool), t)
+}
+
+func arenaMakeSlice(pool unsafe.Pointer, t reflect.Type, len, cap int) reflect.Value {
+	// There is no reflect method to allocate slice in arena.
+	// Allocate an array of cap elements in arena instead and slice it,
+	// so that GC knows the pointers stored in elements.
+	arr := reflect.ArenaNew((*arena.Arena)(pool), reflect.ArrayOf(cap, t.Elem()))
+	return arr.Elem().Slice3(0, len, cap).Convert(t)
+}
+
+func arenaMakeMap(pool unsafe.Pointer, t reflect.Type, n int) reflect.Value {
+	// As of go1.20, there is no way to allocate map in arena.
+	// Fallback to heap allocation.
+	return reflect.MakeMapWithSize(t, n)
+}
+
+func arenaMakeChan(pool unsafe.Pointer, t reflect.Type, buffer int) reflect.Value {
+	// As of go1.20, there is no way to allocate chan in arena.
+	// Fallback to heap allocation.
+	return reflect.MakeChan(t, buffer)
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build go1.20 && goexperiment.arenas
+// +build go1.20,goexperiment.arenas
+
+package clone
+
+import (
+	"arena"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestArenaClone(t *testing.T) {
+	a := assert.New(t)
+
+	type foo struct {
+		A string
+		B []int
+		C *float64
+	}
+
+	f := 45.6
+	orig := &foo{
+		A: "hello",
+		B: []int{1, 2, 3},
+		C: &f,
+	}
+
+	ar := arena.NewArena()
+	cloned := ArenaClone(ar, orig).(*foo)
+	a.Equal(cloned, orig)
+
+	// If a pointer is not allocated by arena, arena.Clone() will return the pointer as it is.
+	a.Assert(arena.Clone(cloned) != cloned)
+	a.Assert(arena.Clone(cloned.C) != cloned.C)
+
+	cloned = ArenaCloneSlowly(ar, orig).(*foo)
+	a.Equal(cloned, orig)
+	a.Assert(arena.Clone(cloned) != cloned)
+
+	// Make sure ar is alive.
+	runtime.KeepAlive(ar)
+}
+
+func TestArenaFallback(t *testing.T) {
+	a := assert.New(t)
+	ar := arena.NewArena()
+	allocator := FromArena(ar)
+
+	var reasons []string
+	allocator.SetFallbackFunc(func(t reflect.Type, reason string) {
+		reasons = append(reasons, reason)
+	})
+
+	v := &struct {
+		M map[string]int
+		C chan int
+		S []int
+	}{
+		M: map[string]int{"foo": 1},
+		C: make(chan int, 1),
+		S: []int{1},
+	}
+	allocator.Clone(reflect.ValueOf(v))
+	a.Equal(reasons, []string{"arena cannot allocate map", "arena cannot allocate chan"})
+
+	runtime.KeepAlive(ar)
+}
@@ -0,0 +1,242 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// Priority is the priority of a clone job in AsyncCloner.
+type Priority int
+
+// All priorities of clone jobs.
+const (
+	PriorityNormal Priority = iota // Default priority.
+	PriorityHigh                   // Jobs with high priority are picked before any normal one.
+)
+
+// AsyncResult is the result of a clone job in AsyncCloner.
+type AsyncResult struct {
+	Value interface{} // The cloned value.
+	Err   error       // The error if cloning panics, e.g. a validator rejects the cloned value in strict mode.
+}
+
+// AsyncCloner clones values in a pool of background workers.
+// It's designed to move expensive clones off latency sensitive paths.
+type AsyncCloner struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	high   []asyncJob
+	normal []asyncJob
+	closed bool
+	wg     sync.WaitGroup
+}
+
+type asyncJob struct {
+	v         interface{}
+	allocator *Allocator
+	result    chan<- AsyncResult
+
+	// The struct pointed by v and the func to unlock its guard lock,
+	// which is held since the job is queued until it's done.
+	guarded reflect.Value
+	unlock  func()
+}
+
+var (
+	defaultAsyncCloner     *AsyncCloner
+	defaultAsyncClonerOnce sync.Once
+)
+
+// CloneAsync clones v with allocator in background workers with normal priority.
+// If allocator is nil, the heap allocator is used.
+//
+// Workers are shared by all CloneAsync calls and the number of workers is runtime.GOMAXPROCS(0).
+// See AsyncCloner.Clone for more details.
+func CloneAsync(v interface{}, allocator *Allocator) <-chan AsyncResult {
+	defaultAsyncClonerOnce.Do(func() {
+		defaultAsyncCloner = NewAsyncCloner(runtime.GOMAXPROCS(0))
+	})
+
+	return defaultAsyncCloner.Clone(v, allocator, PriorityNormal)
+}
+
+// NewAsyncCloner creates an AsyncCloner with workers background workers.
+// If workers is not positive, it's set to 1.
+func NewAsyncCloner(workers int) *AsyncCloner {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	ac := &AsyncCloner{}
+	ac.cond = sync.NewCond(&ac.mu)
+	ac.wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go ac.work()
+	}
+
+	return ac
+}
+
+// Clone queues a job to clone v with allocator and returns a chan to receive the result.
+// If allocator is nil, the heap allocator is used.
+// The chan receives exactly one result.
+//
+// If v is a pointer to a struct guarded by MarkAsGuardedBy in allocator,
+// Clone takes the guard lock before queuing the job and the worker unlocks it after cloning,
+// so that the cloned value is a consistent snapshot of v at the time Clone is called.
+// Clone only waits for the guard lock. Writers of v wait for the job instead.
+// Callers must not hold the guard lock when calling Clone,
+// and the lock must support being unlocked in another goroutine like sync.Mutex.
+//
+// Otherwise, the v is read by a worker when the job runs.
+// Callers must not modify v until the result is received,
+// or the cloned value may be inconsistent.
+//
+// Jobs are queued without any limit, so that Clone never blocks on a busy ac.
+// Callers queuing jobs faster than workers can clone must limit jobs in flight by themselves,
+// e.g. by a semaphore released on receiving results, or the queue grows without bound.
+//
+// Clone panics if ac is closed.
+func (ac *AsyncCloner) Clone(v interface{}, allocator *Allocator, priority Priority) <-chan AsyncResult {
+	if allocator == nil {
+		allocator = defaultAllocator
+	}
+
+	result := make(chan AsyncResult, 1)
+	job := asyncJob{
+		v:         v,
+		allocator: allocator,
+		result:    result,
+	}
+	job.lockGuard()
+
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if ac.closed {
+		if job.unlock != nil {
+			job.unlock()
+		}
+
+		panic("go-clone: AsyncCloner is closed")
+	}
+
+	if priority == PriorityHigh {
+		ac.high = append(ac.high, job)
+	} else {
+		ac.normal = append(ac.normal, job)
+	}
+
+	ac.cond.Signal()
+	return result
+}
+
+// Close stops all workers after all queued jobs are done.
+// It blocks until all workers exit.
+func (ac *AsyncCloner) Close() {
+	ac.mu.Lock()
+	ac.closed = true
+	ac.cond.Broadcast()
+	ac.mu.Unlock()
+
+	ac.wg.Wait()
+}
+
+func (ac *AsyncCloner) work() {
+	defer ac.wg.Done()
+
+	for {
+		job, ok := ac.next()
+
+		if !ok {
+			return
+		}
+
+		job.result <- job.run()
+	}
+}
+
+func (ac *AsyncCloner) next() (job asyncJob, ok bool) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	for len(ac.high) == 0 && len(ac.normal) == 0 {
+		if ac.closed {
+			return
+		}
+
+		ac.cond.Wait()
+	}
+
+	queue := &ac.normal
+
+	if len(ac.high) != 0 {
+		queue = &ac.high
+	}
+
+	job = (*queue)[0]
+	(*queue)[0] = asyncJob{}
+	*queue = (*queue)[1:]
+	ok = true
+	return
+}
+
+// lockGuard takes the guard lock of the struct pointed by job.v, if any.
+func (job *asyncJob) lockGuard() {
+	val := reflect.ValueOf(job.v)
+
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	// Values in read-only memory and scalar values are cloned without lock.
+	elem := val.Elem()
+	cfg := job.allocator.loadConfig()
+
+	if cfg.isReadOnlyMemory() || cfg.isScalarType(elem.Type()) {
+		return
+	}
+
+	st := job.allocator.loadStructType(elem.Type())
+
+	if st.Guard == nil {
+		return
+	}
+
+	if unlock := st.Guard.Lock(elem); unlock != nil {
+		job.guarded = elem
+		job.unlock = unlock
+	}
+}
+
+func (job asyncJob) run() (result AsyncResult) {
+	if job.unlock != nil {
+		defer job.unlock()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if err, ok := r.(error); ok {
+				result.Err = err
+			} else {
+				result.Err = fmt.Errorf("go-clone: %v", r)
+			}
+		}
+	}()
+
+	if job.v == nil {
+		return
+	}
+
+	state := &cloneState{}
+	job.allocator.initCloneState(state, false)
+	state.heldGuard = job.guarded
+	result.Value = state.cloneRoot(reflect.ValueOf(job.v)).Interface()
+	return
+}
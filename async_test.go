@@ -0,0 +1,166 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestCloneAsync(t *testing.T) {
+	a := assert.New(t)
+	orig := map[string][]int{
+		"foo": {1, 2, 3},
+	}
+
+	result := <-CloneAsync(orig, nil)
+	a.NilError(result.Err)
+	a.Equal(result.Value, orig)
+	a.Assert(&result.Value.(map[string][]int)["foo"][0] != &orig["foo"][0])
+
+	result = <-CloneAsync(nil, nil)
+	a.NilError(result.Err)
+	a.Equal(result.Value, nil)
+}
+
+func TestAsyncClonerPriority(t *testing.T) {
+	a := assert.New(t)
+	ac := NewAsyncCloner(1)
+	defer ac.Close()
+
+	type job struct {
+		ID int
+	}
+
+	// Block the only worker until all jobs are queued.
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	var order []int
+	allocator := FromHeap()
+	allocator.SetCustomFunc(reflect.TypeOf(job{}), func(allocator *Allocator, old, new reflect.Value) {
+		id := int(old.FieldByName("ID").Int())
+
+		if id == 0 {
+			close(started)
+			<-unblock
+		}
+
+		order = append(order, id)
+		new.Set(old)
+	})
+
+	first := ac.Clone(&job{ID: 0}, allocator, PriorityNormal)
+	<-started
+	normal := ac.Clone(&job{ID: 1}, allocator, PriorityNormal)
+	high := ac.Clone(&job{ID: 2}, allocator, PriorityHigh)
+	close(unblock)
+
+	a.Equal((<-first).Value.(*job).ID, 0)
+	a.Equal((<-normal).Value.(*job).ID, 1)
+	a.Equal((<-high).Value.(*job).ID, 2)
+	a.Equal(order, []int{0, 2, 1})
+}
+
+func TestAsyncClonerError(t *testing.T) {
+	a := assert.New(t)
+	ac := NewAsyncCloner(2)
+
+	type data struct {
+		Values []int
+	}
+
+	allocator := FromHeap()
+	allocator.SetStrictMode(true)
+	errInvalid := errors.New("invalid")
+	allocator.RegisterValidator(reflect.TypeOf(data{}), func(v reflect.Value) error {
+		return errInvalid
+	})
+
+	result := <-ac.Clone(&data{}, allocator, PriorityNormal)
+	a.Assert(result.Value == nil)
+	a.Assert(errors.Is(result.Err, errInvalid))
+
+	ac.Close()
+	a.Assert(func() (ok bool) {
+		defer func() {
+			ok = recover() != nil
+		}()
+		ac.Clone(&data{}, allocator, PriorityNormal)
+		return
+	}())
+}
+
+type asyncGuarded struct {
+	mu     *sync.Mutex
+	Values map[string]int
+}
+
+func TestAsyncClonerGuard(t *testing.T) {
+	a := assert.New(t)
+	ac := NewAsyncCloner(1)
+
+	type blocker struct {
+		ID int
+	}
+
+	// Block the only worker until v is modified.
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	allocator := FromHeap()
+	allocator.MarkAsGuardedBy(reflect.TypeOf(asyncGuarded{}), "mu")
+	allocator.SetCustomFunc(reflect.TypeOf(blocker{}), func(allocator *Allocator, old, new reflect.Value) {
+		close(started)
+		<-unblock
+		new.Set(old)
+	})
+
+	v := &asyncGuarded{
+		mu:     &sync.Mutex{},
+		Values: map[string]int{"foo": 1},
+	}
+	blocked := ac.Clone(&blocker{}, allocator, PriorityNormal)
+	<-started
+	result := ac.Clone(v, allocator, PriorityNormal)
+
+	// The writer waits for the job, so that the clone is a snapshot of v when it's queued.
+	written := make(chan struct{})
+	go func() {
+		v.mu.Lock()
+		v.Values["bar"] = 2
+		v.mu.Unlock()
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatalf("v is modified before the job is done")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(unblock)
+
+	<-blocked
+	r := <-result
+	a.NilError(r.Err)
+	a.Equal(r.Value.(*asyncGuarded).Values, map[string]int{"foo": 1})
+	<-written
+	a.Equal(v.Values, map[string]int{"foo": 1, "bar": 2})
+
+	// The lock is released if ac is closed.
+	ac.Close()
+	a.Assert(func() (ok bool) {
+		defer func() {
+			ok = recover() != nil
+		}()
+		ac.Clone(v, allocator, PriorityNormal)
+		return
+	}())
+	v.mu.Lock()
+	v.mu.Unlock()
+}
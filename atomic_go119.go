@@ -13,10 +13,6 @@ import (
 
 func init() {
 	SetCustomFunc(reflect.TypeOf(atomic.Bool{}), func(allocator *Allocator, old, new reflect.Value) {
-		if !old.CanAddr() {
-			return
-		}
-
 		// Clone value inside atomic.Bool.
 		oldValue := old.Addr().Interface().(*atomic.Bool)
 		newValue := new.Addr().Interface().(*atomic.Bool)
@@ -24,10 +20,6 @@ func init() {
 		newValue.Store(v)
 	})
 	SetCustomFunc(reflect.TypeOf(atomic.Int32{}), func(allocator *Allocator, old, new reflect.Value) {
-		if !old.CanAddr() {
-			return
-		}
-
 		// Clone value inside atomic.Int32.
 		oldValue := old.Addr().Interface().(*atomic.Int32)
 		newValue := new.Addr().Interface().(*atomic.Int32)
@@ -35,10 +27,6 @@ func init() {
 		newValue.Store(v)
 	})
 	SetCustomFunc(reflect.TypeOf(atomic.Int64{}), func(allocator *Allocator, old, new reflect.Value) {
-		if !old.CanAddr() {
-			return
-		}
-
 		// Clone value inside atomic.Int64.
 		oldValue := old.Addr().Interface().(*atomic.Int64)
 		newValue := new.Addr().Interface().(*atomic.Int64)
@@ -46,10 +34,6 @@ func init() {
 		newValue.Store(v)
 	})
 	SetCustomFunc(reflect.TypeOf(atomic.Uint32{}), func(allocator *Allocator, old, new reflect.Value) {
-		if !old.CanAddr() {
-			return
-		}
-
 		// Clone value inside atomic.Uint32.
 		oldValue := old.Addr().Interface().(*atomic.Uint32)
 		newValue := new.Addr().Interface().(*atomic.Uint32)
@@ -57,10 +41,6 @@ func init() {
 		newValue.Store(v)
 	})
 	SetCustomFunc(reflect.TypeOf(atomic.Uint64{}), func(allocator *Allocator, old, new reflect.Value) {
-		if !old.CanAddr() {
-			return
-		}
-
 		// Clone value inside atomic.Uint64.
 		oldValue := old.Addr().Interface().(*atomic.Uint64)
 		newValue := new.Addr().Interface().(*atomic.Uint64)
@@ -68,10 +48,6 @@ func init() {
 		newValue.Store(v)
 	})
 	SetCustomFunc(reflect.TypeOf(atomic.Uintptr{}), func(allocator *Allocator, old, new reflect.Value) {
-		if !old.CanAddr() {
-			return
-		}
-
 		// Clone value inside atomic.Uintptr.
 		oldValue := old.Addr().Interface().(*atomic.Uintptr)
 		newValue := new.Addr().Interface().(*atomic.Uintptr)
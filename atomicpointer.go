@@ -0,0 +1,54 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+)
+
+// RegisterAtomicPointerType registers a custom clone function for t in heap allocator,
+// which must be a type of `atomic.Pointer[T]` or a pointer to it.
+// If t is not such a type, RegisterAtomicPointerType ignores t.
+//
+// See Allocator.RegisterAtomicPointerType for more details.
+func RegisterAtomicPointerType(t reflect.Type) {
+	defaultAllocator.RegisterAtomicPointerType(t)
+}
+
+// RegisterAtomicPointerType registers a custom clone function for t,
+// which must be a type of `atomic.Pointer[T]` or a pointer to it.
+// If t is not such a type, RegisterAtomicPointerType ignores t.
+//
+// It's a reflect-based equivalent of `RegisterAtomicPointer[T]()` in the generic package
+// for code which doesn't import the generic package.
+// The pointer stored in `atomic.Pointer[T]` is loaded and stored atomically
+// and the pointed value is not cloned.
+func (a *Allocator) RegisterAtomicPointerType(t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || t.PkgPath() != "sync/atomic" || !strings.HasPrefix(t.Name(), "Pointer[") {
+		return
+	}
+
+	field, ok := t.FieldByName("v")
+
+	if !ok || field.Type.Kind() != reflect.UnsafePointer {
+		return
+	}
+
+	offset := field.Offset
+	a.SetCustomFunc(t, func(allocator *Allocator, old, new reflect.Value) {
+		// Clone value inside atomic.Pointer[T].
+		oldPtr := unsafe.Pointer(old.UnsafeAddr())
+		newPtr := unsafe.Pointer(new.UnsafeAddr())
+		src := (*unsafe.Pointer)(unsafe.Pointer(uintptr(oldPtr) + offset))
+		dst := (*unsafe.Pointer)(unsafe.Pointer(uintptr(newPtr) + offset))
+		atomic.StorePointer(dst, atomic.LoadPointer(src))
+	})
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build go1.19
+// +build go1.19
+
+package clone
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestRegisterAtomicPointerType(t *testing.T) {
+	a := assert.New(t)
+
+	type payload struct {
+		Value []int
+	}
+	type pointers struct {
+		P atomic.Pointer[payload]
+	}
+
+	allocator := FromHeap()
+	allocator.RegisterAtomicPointerType(reflect.TypeOf(&atomic.Pointer[payload]{}))
+
+	p := &payload{Value: []int{1, 2}}
+	orig := &pointers{}
+	orig.P.Store(p)
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*pointers)
+	a.Assert(cloned.P.Load() == p)
+	a.Assert(allocator.loadStructType(reflect.TypeOf(atomic.Pointer[payload]{})).fn != nil)
+
+	// Other types are ignored.
+	allocator.RegisterAtomicPointerType(reflect.TypeOf(atomic.Value{}))
+	allocator.RegisterAtomicPointerType(reflect.TypeOf(payload{}))
+	a.Equal(len(allocator.loadConfig().types), 1)
+}
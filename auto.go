@@ -0,0 +1,74 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+)
+
+// autoMaxDepth is the max depth of values cloned in fast mode by CloneAuto.
+// A value with cycles is infinitely deep in fast mode, so it always reaches this depth.
+const autoMaxDepth = 1000
+
+// autoFallback is the panic value to abort fast mode in CloneAuto.
+type autoFallback struct{}
+
+// CloneAuto clones v in heap like Clone and falls back to Slowly if v may contain cycles.
+//
+// See Allocator.CloneAuto for more details.
+func CloneAuto(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	return defaultAllocator.cloneAuto(reflect.ValueOf(v), false).Interface()
+}
+
+// CloneAuto clones val in fast mode like Clone and transparently falls back to CloneSlowly
+// if val may contain cycles, so that callers don't have to know whether val contains any cycle.
+//
+// A value with cycles is infinitely deep in fast mode.
+// Fast mode is aborted once it's deeper than an internal threshold,
+// and val is cloned by CloneSlowly from scratch.
+// Values which are deep enough, e.g. a long linked list, are cloned by CloneSlowly as well.
+//
+// As val may be cloned twice, custom funcs may be called twice on the same value,
+// and memory allocated in the aborted fast mode is not reused.
+func (a *Allocator) CloneAuto(val reflect.Value) reflect.Value {
+	return a.cloneAuto(val, true)
+}
+
+func (a *Allocator) cloneAuto(val reflect.Value, inCustomFunc bool) reflect.Value {
+	if !val.IsValid() {
+		return val
+	}
+
+	if cloned, ok := a.cloneAutoFast(val, inCustomFunc); ok {
+		return cloned
+	}
+
+	return a.cloneSlowly(val, inCustomFunc)
+}
+
+// cloneAutoFast clones val in fast mode.
+// It returns false if fast mode is aborted.
+func (a *Allocator) cloneAutoFast(val reflect.Value, inCustomFunc bool) (cloned reflect.Value, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, fallback := r.(autoFallback); !fallback {
+				panic(r)
+			}
+		}
+	}()
+
+	state := &cloneState{}
+	a.initCloneState(state, false)
+	state.autoDepth = autoMaxDepth
+
+	if inCustomFunc {
+		state.skipCustomFuncValue = val
+	}
+
+	return state.cloneRoot(val), true
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type autoNode struct {
+	Data int
+	Next *autoNode
+}
+
+func TestCloneAuto(t *testing.T) {
+	a := assert.New(t)
+
+	// Values without cycles are cloned in fast mode.
+	v := &autoNode{Data: 1, Next: &autoNode{Data: 2}}
+	cloned := CloneAuto(v).(*autoNode)
+	a.Equal(cloned, v)
+	a.Assert(cloned != v)
+	a.Assert(cloned.Next != v.Next)
+	a.Equal(CloneAuto(nil), nil)
+
+	// Cycles are cloned by Slowly.
+	node1 := &autoNode{Data: 1}
+	node2 := &autoNode{Data: 2, Next: node1}
+	node1.Next = node2
+	cloned = CloneAuto(node1).(*autoNode)
+	a.Assert(cloned != node1)
+	a.Assert(cloned.Next != node2)
+	a.Equal(cloned.Next.Data, 2)
+	a.Assert(cloned.Next.Next == cloned)
+
+	m := map[string]interface{}{"n": 1}
+	m["self"] = m
+	cm := CloneAuto(m).(map[string]interface{})
+	a.Equal(cm["n"], 1)
+	a.Assert(reflect.ValueOf(cm["self"]).Pointer() == reflect.ValueOf(cm).Pointer())
+	a.Assert(reflect.ValueOf(cm).Pointer() != reflect.ValueOf(m).Pointer())
+
+	// Long lists are cloned correctly.
+	var head *autoNode
+
+	for i := 0; i < 2*autoMaxDepth; i++ {
+		head = &autoNode{Data: i, Next: head}
+	}
+
+	cloned = FromHeap().CloneAuto(reflect.ValueOf(head)).Interface().(*autoNode)
+	a.Equal(cloned, head)
+
+	// Panics other than fallback are propagated.
+	allocator := FromHeap()
+	allocator.SetCustomFunc(reflect.TypeOf(autoNode{}), func(allocator *Allocator, old, new reflect.Value) {
+		panic("custom")
+	})
+
+	defer func() {
+		a.Equal(recover(), "custom")
+	}()
+	allocator.CloneAuto(reflect.ValueOf([]autoNode{{}}))
+}
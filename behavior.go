@@ -0,0 +1,192 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// BehaviorAction is how values of a kind or type are cloned.
+type BehaviorAction int
+
+// All behavior actions.
+const (
+	BehaviorDeep     BehaviorAction = iota + 1 // Values are cloned in depth.
+	BehaviorShadow                             // Values are copied by value.
+	BehaviorShare                              // Values are shared with the original values, e.g. opaque pointers.
+	BehaviorRecreate                           // Values are recreated instead of copied, e.g. new empty chans.
+	BehaviorCustom                             // Values are cloned by a custom func or a cloner method.
+	BehaviorZero                               // Values are set to zero.
+	BehaviorReject                             // Values are rejected with an error.
+)
+
+func (action BehaviorAction) String() string {
+	switch action {
+	case BehaviorDeep:
+		return "deep"
+	case BehaviorShadow:
+		return "shadow"
+	case BehaviorShare:
+		return "share"
+	case BehaviorRecreate:
+		return "recreate"
+	case BehaviorCustom:
+		return "custom"
+	case BehaviorZero:
+		return "zero"
+	case BehaviorReject:
+		return "reject"
+	default:
+		return fmt.Sprintf("BehaviorAction(%d)", int(action))
+	}
+}
+
+// Behavior describes how values of a kind or a type are cloned by an allocator.
+type Behavior struct {
+	Kind   reflect.Kind
+	Type   reflect.Type // The type of values or nil if it describes all values of Kind.
+	Action BehaviorAction
+	Reason string // Why values are cloned in this way, e.g. "marked as scalar".
+}
+
+func (b Behavior) String() string {
+	if b.Type == nil {
+		return fmt.Sprintf("%v: %v (%v)", b.Kind, b.Action, b.Reason)
+	}
+
+	return fmt.Sprintf("%v: %v (%v)", b.Type, b.Action, b.Reason)
+}
+
+// Behaviors returns behaviors of heap allocator.
+//
+// See Allocator.Behaviors for more details.
+func Behaviors() []Behavior {
+	return defaultAllocator.Behaviors()
+}
+
+// Behaviors returns how values of every reflect.Kind and every registered type are cloned by a.
+// Behaviors of kinds come first in the order of reflect.Kind,
+// followed by behaviors of types registered in a or a's parents sorted by type name.
+//
+// A registered type is listed if it's marked as scalar or opaque pointer, marked as append-only,
+// or has a custom func or an interface policy.
+// It's designed for tools and admin endpoints to display effective policies of an allocator.
+// Call BehaviorOf to query a type which is not registered.
+func (a *Allocator) Behaviors() []Behavior {
+	cfg := a.loadConfig()
+	behaviors := make([]Behavior, 0, int(reflect.UnsafePointer))
+
+	for k := reflect.Bool; k <= reflect.UnsafePointer; k++ {
+		action, reason := cfg.kindBehavior(k)
+		behaviors = append(behaviors, Behavior{
+			Kind:   k,
+			Action: action,
+			Reason: reason,
+		})
+	}
+
+	flattened := cfg.flatten()
+	types := make([]reflect.Type, 0, len(flattened.types))
+
+	for t, tc := range flattened.types {
+		if tc.scalar || tc.opaque || tc.fn != nil || tc.appendOnly || tc.interfacePolicy != 0 {
+			types = append(types, t)
+		}
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].String() < types[j].String()
+	})
+
+	for _, t := range types {
+		behaviors = append(behaviors, cfg.behaviorOf(t))
+	}
+
+	return behaviors
+}
+
+// BehaviorOf returns how values of t are cloned by a.
+// It only describes values of t themselves.
+// Elements and fields of t may be cloned in other ways according to their own behaviors.
+func (a *Allocator) BehaviorOf(t reflect.Type) Behavior {
+	return a.loadConfig().behaviorOf(t)
+}
+
+func (cfg *config) behaviorOf(t reflect.Type) Behavior {
+	k := t.Kind()
+	b := Behavior{
+		Kind: k,
+		Type: t,
+	}
+
+	switch {
+	case cfg.isScalarStruct(t):
+		b.Action, b.Reason = BehaviorShadow, "marked as scalar"
+	case cfg.lookup(t, func(tc *typeConfig) bool { return tc.fn != nil }) != nil:
+		b.Action, b.Reason = BehaviorCustom, "custom func"
+	case k == reflect.Struct && cfg.lookupFamilyFunc(t) != nil:
+		b.Action, b.Reason = BehaviorCustom, "family func"
+	case k == reflect.Struct && cfg.lookupShapeFunc(t) != nil:
+		b.Action, b.Reason = BehaviorCustom, "shape func"
+	case k == reflect.Ptr && cfg.isOpaquePointer(t):
+		b.Action, b.Reason = BehaviorShare, "opaque pointer"
+	case k == reflect.Slice && cfg.isAppendOnly(t):
+		b.Action, b.Reason = BehaviorShare, "append-only slice"
+	case cfg.isUsingCloner() && cfg.hasClonerMethod(t):
+		b.Action, b.Reason = BehaviorCustom, "cloner method"
+	case k == reflect.Interface && cfg.lookupInterfacePolicy(t) != InterfacePolicyClone:
+		const reason = "interface policy for unexported dynamic types"
+
+		switch cfg.lookupInterfacePolicy(t) {
+		case InterfacePolicyShare:
+			b.Action, b.Reason = BehaviorShare, reason
+		case InterfacePolicyZero:
+			b.Action, b.Reason = BehaviorZero, reason
+		case InterfacePolicyError:
+			b.Action, b.Reason = BehaviorReject, reason
+		}
+	default:
+		b.Action, b.Reason = cfg.kindBehavior(k)
+	}
+
+	return b
+}
+
+// isScalarStruct returns true if struct type t is marked as scalar and the mark wins.
+func (cfg *config) isScalarStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	_, scalar := cfg.lookupScalar(t)
+	return scalar
+}
+
+func (cfg *config) kindBehavior(k reflect.Kind) (action BehaviorAction, reason string) {
+	switch {
+	case k == reflect.Chan && cfg.lookupChanPolicy() == ChanShare:
+		return BehaviorShare, "chan policy"
+	case k == reflect.Chan && cfg.lookupChanPolicy() == ChanNil:
+		return BehaviorZero, "chan policy"
+	case k == reflect.Chan:
+		return BehaviorRecreate, "new empty chan"
+	case k == reflect.Func && cfg.lookupFuncStub() != nil:
+		return BehaviorRecreate, "func stub"
+	case cfg.isScalar(k):
+		return BehaviorShadow, "scalar kind"
+	default:
+		return BehaviorDeep, "default"
+	}
+}
+
+func (cfg *config) hasClonerMethod(t reflect.Type) bool {
+	if t.Kind() == reflect.Struct {
+		return cfg.clonerMethodFunc(t) != nil
+	}
+
+	_, ok := cfg.lookupClonerMethod(t)
+	return ok
+}
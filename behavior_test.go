@@ -0,0 +1,86 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+type behaviorCloner []int
+
+func (c behaviorCloner) Clone() behaviorCloner {
+	return c
+}
+
+func TestBehaviors(t *testing.T) {
+	a := assert.New(t)
+	allocator := NewAllocator(nil, nil)
+	behaviors := allocator.Behaviors()
+	kinds := map[reflect.Kind]Behavior{}
+	types := map[reflect.Type]Behavior{}
+
+	for _, b := range behaviors {
+		if b.Type == nil {
+			kinds[b.Kind] = b
+		} else {
+			types[b.Type] = b
+		}
+	}
+
+	a.Equal(len(kinds), int(reflect.UnsafePointer))
+	a.Equal(kinds[reflect.Int].Action, BehaviorShadow)
+	a.Equal(kinds[reflect.Ptr].Action, BehaviorDeep)
+	a.Equal(kinds[reflect.Map].Action, BehaviorDeep)
+	a.Equal(kinds[reflect.Chan].Action, BehaviorRecreate)
+	a.Equal(kinds[reflect.Func].Action, BehaviorShadow)
+	a.Equal(types[reflect.TypeOf(time.Time{})].Action, BehaviorShadow)
+	a.Equal(types[reflect.TypeOf(sync.Mutex{})].Action, BehaviorCustom)
+	a.Equal(types[reflect.TypeOf(reflect.TypeOf(0))].Action, BehaviorShare)
+	a.Equal(types[reflect.TypeOf(time.Time{})].String(), "time.Time: shadow (marked as scalar)")
+
+	// Registered types are sorted by name.
+	for i := int(reflect.UnsafePointer) + 1; i < len(behaviors); i++ {
+		a.Assert(behaviors[i-1].Type == nil || behaviors[i-1].Type.String() < behaviors[i].Type.String())
+	}
+
+	// Behaviors reflect registrations and options.
+	type T struct {
+		Name string
+	}
+	allocator.MarkAsScalar(reflect.TypeOf(T{}))
+	allocator.MarkAsAppendOnly(reflect.TypeOf([]*T{}))
+	allocator.SetInterfacePolicy(reflect.TypeOf((*io.Reader)(nil)).Elem(), InterfacePolicyZero)
+	allocator.SetChanPolicy(ChanShare)
+	allocator.SetFuncStubFactory(func(t reflect.Type) reflect.Value {
+		return reflect.Zero(t)
+	})
+	a.Equal(allocator.BehaviorOf(reflect.TypeOf(T{})).Action, BehaviorShadow)
+	a.Equal(allocator.BehaviorOf(reflect.TypeOf([]*T{})).Action, BehaviorShare)
+	a.Equal(allocator.BehaviorOf(reflect.TypeOf((*io.Reader)(nil)).Elem()).Action, BehaviorZero)
+	a.Equal(allocator.BehaviorOf(reflect.TypeOf(make(chan int))).Action, BehaviorShare)
+	a.Equal(allocator.BehaviorOf(reflect.TypeOf(func() {})).Action, BehaviorRecreate)
+	a.Equal(allocator.BehaviorOf(reflect.TypeOf(&T{})).Action, BehaviorDeep)
+	a.Equal(allocator.BehaviorOf(reflect.TypeOf(behaviorCloner{})).Action, BehaviorDeep)
+
+	allocator.UseClonerInterface(true)
+	a.Equal(allocator.BehaviorOf(reflect.TypeOf(behaviorCloner{})).Action, BehaviorCustom)
+
+	found := 0
+
+	for _, b := range allocator.Behaviors() {
+		switch b.Type {
+		case reflect.TypeOf(T{}), reflect.TypeOf([]*T{}), reflect.TypeOf((*io.Reader)(nil)).Elem():
+			found++
+		}
+	}
+
+	a.Equal(found, 3)
+	a.Equal(BehaviorAction(100).String(), "BehaviorAction(100)")
+}
@@ -0,0 +1,247 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"math"
+	"reflect"
+	"unsafe"
+)
+
+// Bits of canonical NaN used in canonical mode.
+const (
+	CanonicalNaN32 uint32 = 0x7fc00000
+	CanonicalNaN64 uint64 = 0x7ff8000000000000
+)
+
+// SetCanonical enables or disables canonical mode in heap allocator.
+//
+// See Allocator.SetCanonical for more details.
+func SetCanonical(canonical bool) {
+	defaultAllocator.SetCanonical(canonical)
+}
+
+// SetCanonical enables or disables canonical mode in a.
+// If canonical mode is not set, a inherits it from parent allocator.
+// Canonical mode is disabled in the default allocator.
+//
+// In canonical mode, equal values are cloned to the same bits,
+// so that serialized forms of clones are stable across architectures and runs,
+// e.g. for content-addressed storage.
+//
+//   - Negative zeros of floats and complex numbers are cloned to positive zeros.
+//   - NaNs with any sign or payload are cloned to CanonicalNaN32 or CanonicalNaN64.
+//   - Integers in [0, 256) of predeclared integer types in interfaces are boxed by Go runtime,
+//     so that they share the runtime's interned memory instead of the memory of source values.
+//
+// Values set by custom funcs or Clone methods are left as they are.
+// So are values shared with source, e.g. values pointed by opaque pointers.
+func (a *Allocator) SetCanonical(canonical bool) {
+	option := optionDisabled
+
+	if canonical {
+		option = optionEnabled
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.canonical = option
+		return copied
+	})
+}
+
+func (cfg *config) isCanonical() bool {
+	for current := cfg; current != nil; current = current.parent {
+		switch current.canonical {
+		case optionEnabled:
+			return true
+		case optionDisabled:
+			return false
+		}
+	}
+
+	return false
+}
+
+// isCanonicalFloat returns true if f is neither a negative zero nor a NaN.
+func isCanonicalFloat(f float64) bool {
+	return f == f && (f != 0 || !math.Signbit(f))
+}
+
+// canonicalScalar copies scalar value v and canonicalizes it if it's a float or complex number.
+func (cfg *config) canonicalScalar(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if isCanonicalFloat(v.Float()) {
+			return copyScalarValue(v)
+		}
+	case reflect.Complex64, reflect.Complex128:
+		if c := v.Complex(); isCanonicalFloat(real(c)) && isCanonicalFloat(imag(c)) {
+			return copyScalarValue(v)
+		}
+	default:
+		return copyScalarValue(v)
+	}
+
+	t := v.Type()
+	nv := reflect.New(t)
+	p := unsafe.Pointer(nv.Pointer())
+	shadowCopy(v, p)
+	cfg.canonicalizeMemory(t, p)
+	return nv.Elem()
+}
+
+// canonicalizeMemory canonicalizes all floats and complex numbers stored in the value of t at p.
+// Memory referenced by pointers in the value is not changed.
+func (cfg *config) canonicalizeMemory(t reflect.Type, p unsafe.Pointer) {
+	switch t.Kind() {
+	case reflect.Float32:
+		canonicalizeFloat32(p)
+	case reflect.Float64:
+		canonicalizeFloat64(p)
+	case reflect.Complex64:
+		canonicalizeFloat32(p)
+		canonicalizeFloat32(unsafe.Pointer(uintptr(p) + 4))
+	case reflect.Complex128:
+		canonicalizeFloat64(p)
+		canonicalizeFloat64(unsafe.Pointer(uintptr(p) + 8))
+	case reflect.Array:
+		elem := t.Elem()
+
+		if !cfg.hasInlineFloat(elem) {
+			return
+		}
+
+		sz := elem.Size()
+
+		for i := 0; i < t.Len(); i++ {
+			cfg.canonicalizeMemory(elem, unsafe.Pointer(uintptr(p)+uintptr(i)*sz))
+		}
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+
+			if cfg.hasInlineFloat(field.Type) {
+				cfg.canonicalizeMemory(field.Type, unsafe.Pointer(uintptr(p)+field.Offset))
+			}
+		}
+	}
+}
+
+func canonicalizeFloat32(p unsafe.Pointer) {
+	f := *(*float32)(p)
+
+	if f != f {
+		*(*uint32)(p) = CanonicalNaN32
+	} else if f == 0 {
+		*(*float32)(p) = 0
+	}
+}
+
+func canonicalizeFloat64(p unsafe.Pointer) {
+	f := *(*float64)(p)
+
+	if f != f {
+		*(*uint64)(p) = CanonicalNaN64
+	} else if f == 0 {
+		*(*float64)(p) = 0
+	}
+}
+
+// hasInlineFloat returns true if values of t store floats or complex numbers in place,
+// i.e. not through pointers.
+// The result is cached in the type cache of cfg.
+func (cfg *config) hasInlineFloat(t reflect.Type) bool {
+	cache := &cfg.cache().inlineFloats
+
+	if v, ok := cache.load(t); ok {
+		return v.(bool)
+	}
+
+	has := hasInlineFloat(t)
+	cache.store(t, has)
+	return has
+}
+
+func hasInlineFloat(t reflect.Type) bool {
+	has := false
+
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		has = true
+	case reflect.Array:
+		has = t.Len() != 0 && hasInlineFloat(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasInlineFloat(t.Field(i).Type) {
+				has = true
+				break
+			}
+		}
+	}
+
+	return has
+}
+
+// internSmallInt boxes v in an interface by Go runtime if v is an integer in [0, 256) of predeclared type.
+// Go runtime boxes such integers in interned memory without allocation.
+func internSmallInt(v reflect.Value) (boxed reflect.Value, ok bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n := v.Int(); n < 0 || n >= 256 {
+			return
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() >= 256 {
+			return
+		}
+	default:
+		return
+	}
+
+	var x interface{}
+
+	switch v.Type() {
+	case typeOfInt:
+		x = int(v.Int())
+	case typeOfInt8:
+		x = int8(v.Int())
+	case typeOfInt16:
+		x = int16(v.Int())
+	case typeOfInt32:
+		x = int32(v.Int())
+	case typeOfInt64:
+		x = v.Int()
+	case typeOfUint:
+		x = uint(v.Uint())
+	case typeOfUint8:
+		x = uint8(v.Uint())
+	case typeOfUint16:
+		x = uint16(v.Uint())
+	case typeOfUint32:
+		x = uint32(v.Uint())
+	case typeOfUint64:
+		x = v.Uint()
+	case typeOfUintptr:
+		x = uintptr(v.Uint())
+	default:
+		return
+	}
+
+	return reflect.ValueOf(x), true
+}
+
+var (
+	typeOfInt     = reflect.TypeOf(int(0))
+	typeOfInt8    = reflect.TypeOf(int8(0))
+	typeOfInt16   = reflect.TypeOf(int16(0))
+	typeOfInt32   = reflect.TypeOf(int32(0))
+	typeOfInt64   = reflect.TypeOf(int64(0))
+	typeOfUint    = reflect.TypeOf(uint(0))
+	typeOfUint8   = reflect.TypeOf(uint8(0))
+	typeOfUint16  = reflect.TypeOf(uint16(0))
+	typeOfUint32  = reflect.TypeOf(uint32(0))
+	typeOfUint64  = reflect.TypeOf(uint64(0))
+	typeOfUintptr = reflect.TypeOf(uintptr(0))
+)
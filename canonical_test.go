@@ -0,0 +1,144 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+type canonicalPoint struct {
+	X, Y float64
+	Z    [2]float32
+	C    complex128
+}
+
+type canonicalValue struct {
+	Point   canonicalPoint
+	Floats  []float64
+	Points  []canonicalPoint
+	Ptr     *float64
+	Any     interface{}
+	Map     map[float64]float32
+	private float64
+}
+
+func TestCanonical(t *testing.T) {
+	a := assert.New(t)
+	negZero := math.Copysign(0, -1)
+	nan64 := math.Float64frombits(0xfff8000000000123)
+	nan32 := math.Float32frombits(0xffc00123)
+	allocator := FromHeap()
+	allocator.SetCanonical(true)
+
+	v := &canonicalValue{
+		Point: canonicalPoint{
+			X: negZero,
+			Y: nan64,
+			Z: [2]float32{float32(negZero), nan32},
+			C: complex(negZero, nan64),
+		},
+		Floats: []float64{1, negZero, nan64},
+		Points: []canonicalPoint{{X: negZero, Y: 2}},
+		Ptr:    &nan64,
+		Any:    negZero,
+		Map: map[float64]float32{
+			negZero: nan32,
+		},
+		private: nan64,
+	}
+	cloned := allocator.Clone(reflect.ValueOf(v)).Interface().(*canonicalValue)
+
+	isCanonical64 := func(f float64) bool {
+		bits := math.Float64bits(f)
+		return bits == 0 || bits == CanonicalNaN64 || (f == f && f != 0)
+	}
+	isCanonical32 := func(f float32) bool {
+		bits := math.Float32bits(f)
+		return bits == 0 || bits == CanonicalNaN32 || (f == f && f != 0)
+	}
+
+	a.Equal(math.Float64bits(cloned.Point.X), uint64(0))
+	a.Equal(math.Float64bits(cloned.Point.Y), CanonicalNaN64)
+	a.Equal(math.Float32bits(cloned.Point.Z[0]), uint32(0))
+	a.Equal(math.Float32bits(cloned.Point.Z[1]), CanonicalNaN32)
+	a.Equal(math.Float64bits(real(cloned.Point.C)), uint64(0))
+	a.Equal(math.Float64bits(imag(cloned.Point.C)), CanonicalNaN64)
+	a.Equal(cloned.Floats[0], 1.0)
+	a.Assert(isCanonical64(cloned.Floats[1]) && isCanonical64(cloned.Floats[2]))
+	a.Equal(math.Float64bits(cloned.Floats[2]), CanonicalNaN64)
+	a.Equal(math.Float64bits(cloned.Points[0].X), uint64(0))
+	a.Equal(math.Float64bits(*cloned.Ptr), CanonicalNaN64)
+	a.Equal(math.Float64bits(cloned.Any.(float64)), uint64(0))
+	a.Equal(math.Float64bits(cloned.private), CanonicalNaN64)
+
+	for k, v := range cloned.Map {
+		a.Equal(math.Float64bits(k), uint64(0))
+		a.Assert(isCanonical32(v))
+		a.Equal(math.Float32bits(v), CanonicalNaN32)
+	}
+
+	// Source is not changed.
+	a.Assert(math.Signbit(v.Point.X))
+	a.Equal(math.Float64bits(v.Point.Y), uint64(0xfff8000000000123))
+	a.Equal(math.Float64bits(v.Floats[2]), uint64(0xfff8000000000123))
+
+	// Canonical mode is disabled by default.
+	cloned = Clone(v).(*canonicalValue)
+	a.Assert(math.Signbit(cloned.Point.X))
+	a.Equal(math.Float64bits(cloned.Floats[2]), uint64(0xfff8000000000123))
+}
+
+func TestCanonicalSmallInt(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.SetCanonical(true)
+	dataOf := func(v interface{}) unsafe.Pointer {
+		return (*[2]unsafe.Pointer)(unsafe.Pointer(&v))[1]
+	}
+
+	n, _ := strconv.Atoi("42") // Make sure n is not a constant.
+	large := 1000
+	values := []interface{}{Clone(n), Clone(uint8(n)), large}
+	cloned := allocator.Clone(reflect.ValueOf(values)).Interface().([]interface{})
+	a.Equal(cloned, values)
+
+	// Small integers share interned memory with values boxed by Go runtime.
+	a.Equal(dataOf(cloned[0]), dataOf(interface{}(n)))
+	a.Equal(dataOf(cloned[1]), dataOf(interface{}(uint8(n))))
+	a.Equal(cloned[2], large)
+
+	// Source values are boxed in other memory.
+	boxed := reflect.New(reflect.TypeOf(int64(0))).Elem()
+	boxed.SetInt(int64(n))
+	values = []interface{}{boxed.Interface()}
+	a.Assert(dataOf(values[0]) != dataOf(interface{}(int64(n))))
+	cloned = allocator.Clone(reflect.ValueOf(values)).Interface().([]interface{})
+	a.Equal(dataOf(cloned[0]), dataOf(interface{}(int64(n))))
+}
+
+func TestCanonicalCustomFunc(t *testing.T) {
+	a := assert.New(t)
+	negZero := math.Copysign(0, -1)
+	allocator := FromHeap()
+	allocator.SetCanonical(true)
+	allocator.SetCustomFunc(reflect.TypeOf(canonicalPoint{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.Set(old)
+	})
+
+	v := &struct {
+		Point canonicalPoint
+	}{
+		Point: canonicalPoint{X: negZero},
+	}
+	cloned := allocator.Clone(reflect.ValueOf(v)).Interface().(*struct {
+		Point canonicalPoint
+	})
+	a.Assert(math.Signbit(cloned.Point.X))
+}
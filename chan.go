@@ -0,0 +1,69 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import "reflect"
+
+// ChanPolicy decides how to clone channels.
+type ChanPolicy int
+
+// All chan policies.
+const (
+	ChanNewEmpty ChanPolicy = iota + 1 // Make a new empty chan with the same buffer size. It's the default policy.
+	ChanShare                          // Share the chan, so that the clone sends to and receives from the same chan.
+	ChanNil                            // Set the chan to nil, so that the clone is cut off from all goroutines using the chan.
+)
+
+// SetChanPolicy sets the chan policy in heap allocator.
+//
+// See Allocator.SetChanPolicy for more details.
+func SetChanPolicy(policy ChanPolicy) {
+	defaultAllocator.SetChanPolicy(policy)
+}
+
+// SetChanPolicy sets the policy to clone all channels in a.
+// If policy is not a valid policy, a inherits the policy from parent allocator.
+//
+// By default, a chan is cloned to a new empty chan with the same buffer size,
+// as values buffered in a chan cannot be read without receiving them.
+// Set policy to ChanShare to share channels between original values and clones,
+// e.g. to keep a snapshot sending events to the same chan as the original.
+// Set policy to ChanNil to drop channels in clones, e.g. to hand clones to untrusted code.
+func (a *Allocator) SetChanPolicy(policy ChanPolicy) {
+	if policy != ChanNewEmpty && policy != ChanShare && policy != ChanNil {
+		policy = 0
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.chanPolicy = policy
+		return copied
+	})
+}
+
+func (cfg *config) lookupChanPolicy() ChanPolicy {
+	for current := cfg; current != nil; current = current.parent {
+		if current.chanPolicy != 0 {
+			return current.chanPolicy
+		}
+	}
+
+	return ChanNewEmpty
+}
+
+func (state *cloneState) cloneChan(v reflect.Value) reflect.Value {
+	if state.chanPolicy == ChanNil {
+		return reflect.Zero(v.Type())
+	}
+
+	if state.chanPolicy == ChanShare {
+		if !v.CanInterface() {
+			v = forceClearROFlag(v)
+		}
+
+		return v
+	}
+
+	return state.allocator.MakeChan(v.Type(), v.Cap())
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestChanPolicy(t *testing.T) {
+	a := assert.New(t)
+	type T struct {
+		Events  chan int
+		private chan string
+	}
+	orig := &T{
+		Events:  make(chan int, 2),
+		private: make(chan string),
+	}
+
+	allocator := FromHeap()
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*T)
+	a.Assert(cloned.Events != orig.Events)
+	a.Equal(cap(cloned.Events), 2)
+	a.Assert(cloned.private != orig.private)
+
+	allocator.SetChanPolicy(ChanShare)
+	cloned = allocator.Clone(reflect.ValueOf(orig)).Interface().(*T)
+	a.Assert(cloned.Events == orig.Events)
+	a.Assert(cloned.private == orig.private)
+	a.Equal(allocator.EstimateSize(reflect.ValueOf(orig)), allocator.EstimateSize(reflect.ValueOf(&T{})))
+
+	cloned.Events <- 1
+	a.Equal(<-orig.Events, 1)
+
+	// Child allocators inherit the policy.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().(*T)
+	a.Assert(cloned.Events == orig.Events)
+
+	allocator.SetChanPolicy(ChanNil)
+	cloned = allocator.Clone(reflect.ValueOf(orig)).Interface().(*T)
+	a.Assert(cloned.Events == nil)
+	a.Assert(cloned.private == nil)
+	a.Equal(allocator.EstimateSize(reflect.ValueOf(orig)), allocator.EstimateSize(reflect.ValueOf(&T{})))
+
+	// Invalid policy resets the policy.
+	allocator.SetChanPolicy(0)
+	cloned = allocator.Clone(reflect.ValueOf(orig)).Interface().(*T)
+	a.Assert(cloned.Events != orig.Events)
+}
@@ -11,9 +11,6 @@ import (
 	"unsafe"
 )
 
-var heapCloneState = &cloneState{
-	allocator: defaultAllocator,
-}
 var cloner = MakeCloner(defaultAllocator)
 
 const zeroBytesCount = 256
@@ -71,12 +68,88 @@ func cloneSlowly(allocator *Allocator, v interface{}) interface{} {
 
 type cloneState struct {
 	allocator *Allocator
+	config    *config // The config snapshot captured when cloning starts.
 	visited   visitMap
 	invalid   invalidPointers
+	strict    bool
+	debug     bool
+	readOnly  bool         // True if sources must not be modified while cloning.
+	canonical bool         // True if floats and small integers in interfaces are canonicalized.
+	minCopy   bool         // True if immutable values which can be shadow copied are shared.
+	shareRefs bool         // True if pointers, slices and maps referencing values which can be shadow copied are shared.
+	useCloner bool         // True if methods like `Clone() T` are used to clone values.
+	yield     *yieldOption // Yield option or nil if clone should not yield.
+	ticks     int          // Number of values cloned.
+	stats     *Stats       // Stats of the clone or nil if stats is not required.
+	depth     int          // Current depth in stats or under max depth.
+	maxDepth  int          // Max depth of values to clone or 0 if unlimited.
+	shareDeep bool         // True if values beyond max depth are shared even in strict mode.
+	autoDepth int          // Max depth before falling back to Slowly in CloneAuto or 0 if not in auto mode.
+	nodeLimit int          // Max number of pointed values to clone or 0 if unlimited.
+	nodes     int          // Number of pointed values cloned under node limit.
+
+	// labels is the option of pprof labels or nil if clone is not labeled.
+	labels *labelsOption
+
+	// trace is the trace hooks or nil if clone is not traced.
+	// The path is the path of the value being cloned, which is set only if clone is traced.
+	trace *TraceHooks
+	path  string
+
+	// tx tracks memory allocated in a transactional clone or nil if clone is not transactional.
+	tx *transaction
+
+	chanPolicy   ChanPolicy
+	nanKeyPolicy NaNKeyPolicy
+	readOnlyMem  bool // True if sources may live in read-only memory.
+
+	// funcStub makes stub funcs to replace func values or nil if func values are copied.
+	funcStub FuncStubFactory
+
+	// Pending work of pointed values which are too deep to clone recursively.
+	// See workstack.go for details.
+	jobs     []cloneJob
+	ptrDepth int // Number of nested pointed values being cloned recursively.
+	guards   int // Number of guard locks held, in which no job is pushed to the work stack.
+
+	// The struct whose guard lock is held by callers during the whole clone, e.g. by AsyncCloner.Clone.
+	heldGuard reflect.Value
+
+	// namedFuncs is true if any custom func is set for a non-struct type.
+	namedFuncs bool
+
+	// appendOnly is true if any slice type is marked as append-only.
+	appendOnly bool
+
+	// The generation option and the generation stamped in this clone.
+	// The generation is generated on demand when the first generation field is cloned.
+	generation    *generationOption
+	stamp         uint64
+	stampAssigned bool
 
 	// The value that should not be cloned by custom func.
 	// It's useful to avoid infinite loop when custom func calls allocator.Clone().
 	skipCustomFuncValue reflect.Value
+
+	// All cloned structs which should be passed to rebind funcs after cloning.
+	rebinds []rebindValue
+
+	// All structs being cloned from current value to the root.
+	// It's used to fix fields tagged with `clone:"parent"`.
+	// It's a linked list so that jobs in the work stack can share it.
+	ancestors *ancestorValue
+}
+
+type ancestorValue struct {
+	p      uintptr
+	t      reflect.Type
+	nv     reflect.Value
+	parent *ancestorValue
+}
+
+type rebindValue struct {
+	owner reflect.Value
+	fn    RebindFunc
 }
 
 type visit struct {
@@ -86,18 +159,141 @@ type visit struct {
 }
 
 type visitMap map[visit]reflect.Value
-type invalidPointers map[visit]reflect.Value
+
+// invalidPointers is all invalid pointers found in CloneSlowly, indexed by pointer type.
+// The fix pass uses types to skip values which cannot contain any invalid pointer.
+type invalidPointers struct {
+	values map[visit]reflect.Value
+	types  map[reflect.Type]struct{}
+}
+
+func (ip *invalidPointers) add(vst visit, nv reflect.Value) {
+	if ip.values == nil {
+		ip.values = map[visit]reflect.Value{}
+		ip.types = map[reflect.Type]struct{}{}
+	}
+
+	ip.values[vst] = nv
+	ip.types[vst.t] = struct{}{}
+}
+
+func (ip *invalidPointers) lookup(vst visit) (nv reflect.Value, ok bool) {
+	if _, ok = ip.types[vst.t]; !ok {
+		return
+	}
+
+	nv, ok = ip.values[vst]
+	return
+}
+
+func (ip *invalidPointers) len() int {
+	return len(ip.values)
+}
+
+// cloneRoot clones the root value v and finishes all pending work after cloning.
+func (state *cloneState) cloneRoot(v reflect.Value) (cloned reflect.Value) {
+	if labels := state.labels; labels != nil {
+		state.labels = nil
+		labels.do(v.Type(), func() {
+			cloned = state.cloneRoot(v)
+		})
+		return
+	}
+
+	if tx := state.tx; tx != nil {
+		state.tx = nil
+		defer tx.end()
+		cloned = state.cloneRoot(v)
+		tx.commit()
+		return
+	}
+
+	if state.strict {
+		state.config.checkInvalidValues(v)
+	}
+
+	if state.readOnly {
+		defer state.checkSource(v, fingerprint(v))
+	}
+
+	cloned = state.clone(v)
+	state.drain()
+	state.fix(cloned)
+	state.rebind(cloned)
+	return cloned
+}
 
 func (state *cloneState) clone(v reflect.Value) reflect.Value {
-	if state.allocator.isScalar(v.Kind()) {
+	if state.yield != nil {
+		state.tick()
+	}
+
+	if state.trace != nil {
+		return state.cloneAndTrace(v)
+	}
+
+	if state.stats != nil || state.maxDepth > 0 || state.autoDepth > 0 {
+		return state.cloneAndCount(v)
+	}
+
+	return state.cloneValue(v)
+}
+
+// isScalarType is the same as config.isScalarType, but uses options resolved in state.
+func (state *cloneState) isScalarType(t reflect.Type) bool {
+	if t.Kind() == reflect.Func && state.funcStub != nil {
+		return false
+	}
+
+	return state.config.isScalar(t.Kind()) && (!state.namedFuncs || state.config.lookupNamedFunc(t) == nil)
+}
+
+func (state *cloneState) cloneValue(v reflect.Value) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+
+	if state.namedFuncs && v.Kind() != reflect.Struct {
+		if fn := state.config.lookupNamedFunc(v.Type()); fn != nil && state.skipCustomFuncValue != v {
+			return state.cloneByFunc(v, fn)
+		}
+	}
+
+	if state.funcStub != nil && v.Kind() == reflect.Func {
+		return stubFunc(state.funcStub, v)
+	}
+
+	if state.config.isScalar(v.Kind()) {
+		if state.canonical {
+			return state.config.canonicalScalar(v)
+		}
+
 		return copyScalarValue(v)
 	}
 
+	if state.useCloner && v.Kind() != reflect.Struct && state.skipCustomFuncValue != v {
+		if cloned, ok := state.cloneByMethod(v); ok {
+			return cloned
+		}
+	}
+
+	// Nil values of all kinds are cloned to nil values,
+	// so that clone methods of each kind never see nil values.
+	if isNilable(v.Kind()) && v.IsNil() {
+		return reflect.Zero(v.Type())
+	}
+
+	if state.minCopy || state.shareRefs {
+		if shared, ok := state.shareMinCopy(v); ok {
+			return shared
+		}
+	}
+
 	switch v.Kind() {
 	case reflect.Array:
 		return state.cloneArray(v)
 	case reflect.Chan:
-		return state.allocator.MakeChan(v.Type(), v.Cap())
+		return state.cloneChan(v)
 	case reflect.Interface:
 		return state.cloneInterface(v)
 	case reflect.Map:
@@ -111,10 +307,36 @@ func (state *cloneState) clone(v reflect.Value) reflect.Value {
 	case reflect.String:
 		return state.cloneString(v)
 	default:
-		panic(fmt.Errorf("go-clone: <bug> unsupported type `%v`", v.Type()))
+		panic(&UnsupportedTypeError{
+			Type: v.Type(),
+		})
 	}
 }
 
+// cloneByFunc clones a non-struct value v by custom func fn.
+func (state *cloneState) cloneByFunc(v reflect.Value, fn Func) reflect.Value {
+	if !v.CanInterface() {
+		v = forceClearROFlag(v)
+	}
+
+	if !v.CanAddr() {
+		tmp := reflect.New(v.Type()).Elem()
+		tmp.Set(v)
+		v = tmp
+	} else if state.readOnlyMem {
+		v = detachValue(v)
+	}
+
+	nv := state.allocator.New(v.Type()).Elem()
+	fn(state.allocator, v, nv)
+
+	if state.debug {
+		state.checkCustomFunc(v, nv)
+	}
+
+	return nv
+}
+
 func (state *cloneState) cloneArray(v reflect.Value) reflect.Value {
 	dst := state.allocator.New(v.Type())
 	state.copyArray(v, dst)
@@ -126,31 +348,71 @@ func (state *cloneState) copyArray(src, nv reflect.Value) {
 	dst := nv.Elem()
 	num := src.Len()
 
-	if state.allocator.isScalar(src.Type().Elem().Kind()) {
+	if state.isScalarType(src.Type().Elem()) {
 		shadowCopy(src, p)
+
+		if state.canonical {
+			state.config.canonicalizeMemory(src.Type(), p)
+		}
+
 		return
 	}
 
-	for i := 0; i < num; i++ {
-		dst.Index(i).Set(state.clone(src.Index(i)))
+	path := state.path
+
+	switch src.Type().Elem().Kind() {
+	case reflect.Struct:
+		for i := 0; i < num; i++ {
+			state.traceIndex(path, i)
+			state.copyStruct(src.Index(i), dst.Index(i).Addr())
+		}
+	case reflect.Array:
+		for i := 0; i < num; i++ {
+			state.traceIndex(path, i)
+			state.copyArray(src.Index(i), dst.Index(i).Addr())
+		}
+	default:
+		for i := 0; i < num; i++ {
+			state.traceIndex(path, i)
+			dst.Index(i).Set(state.clone(src.Index(i)))
+		}
 	}
+
+	state.path = path
 }
 
 func (state *cloneState) cloneInterface(v reflect.Value) reflect.Value {
-	if v.IsNil() {
-		return reflect.Zero(v.Type())
-	}
-
 	t := v.Type()
 	elem := v.Elem()
+
+	if !IsExportedType(elem.Type()) {
+		switch state.config.lookupInterfacePolicy(t) {
+		case InterfacePolicyShare:
+			if !v.CanInterface() {
+				v = forceClearROFlag(v)
+			}
+
+			return v
+		case InterfacePolicyZero:
+			return reflect.Zero(t)
+		case InterfacePolicyError:
+			panic(&InterfaceError{
+				Type:        t,
+				DynamicType: elem.Type(),
+			})
+		}
+	}
+
+	if state.canonical {
+		if boxed, ok := internSmallInt(elem); ok {
+			return boxed.Convert(t)
+		}
+	}
+
 	return state.clone(elem).Convert(elem.Type()).Convert(t)
 }
 
 func (state *cloneState) cloneMap(v reflect.Value) reflect.Value {
-	if v.IsNil() {
-		return reflect.Zero(v.Type())
-	}
-
 	t := v.Type()
 
 	if state.visited != nil {
@@ -174,23 +436,70 @@ func (state *cloneState) cloneMap(v reflect.Value) reflect.Value {
 		state.visited[vst] = nv
 	}
 
+	if t.Key().Kind() == reflect.String && state.stats == nil && state.isScalarType(t.Key()) {
+		state.cloneStringKeyMap(v, nv)
+		return nv
+	}
+
+	// Scalar keys are always copied by value.
+	// Don't look up key policy for them.
+	shareKeys := !state.config.isScalar(t.Key().Kind()) &&
+		state.config.lookupMapKeyPolicy(t) == MapKeyPolicyShare
+	checksNaNKeys := state.checksNaNKeys(t)
+	path := state.path
+
 	for iter := mapIter(v); iter.Next(); {
-		key := state.clone(iter.Key())
+		var key reflect.Value
+
+		if checksNaNKeys && state.skipNaNKey(t, iter.Key()) {
+			continue
+		}
+
+		state.traceKey(path, iter.Key())
+
+		if shareKeys {
+			key = iter.Key()
+
+			if !key.CanInterface() {
+				key = forceClearROFlag(key)
+			}
+		} else {
+			key = state.clone(iter.Key())
+		}
+
 		value := state.clone(iter.Value())
 		nv.SetMapIndex(key, value)
 	}
 
+	state.path = path
 	return nv
 }
 
-func (state *cloneState) clonePtr(v reflect.Value) reflect.Value {
-	if v.IsNil() {
-		return reflect.Zero(v.Type())
+// cloneStringKeyMap clones all entries in v to nv.
+// Strings are immutable, so that original keys are reused by nv.
+// A key value is reused for all entries to avoid allocating memory for each key.
+func (state *cloneState) cloneStringKeyMap(v, nv reflect.Value) {
+	if !v.CanInterface() {
+		v = forceClearROFlag(v)
 	}
 
+	key := reflect.New(v.Type().Key()).Elem()
+	path := state.path
+
+	for iter := mapIter(v); iter.Next(); {
+		setIterKey(key, iter)
+		state.traceKey(path, key)
+		value := state.clone(iter.Value())
+		nv.SetMapIndex(key, value)
+	}
+
+	state.path = path
+}
+
+func (state *cloneState) clonePtr(v reflect.Value) reflect.Value {
 	t := v.Type()
 
-	if state.allocator.isOpaquePointer(t) {
+	if state.config.isOpaquePointer(t) {
 		if v.CanInterface() {
 			return v
 		}
@@ -212,10 +521,12 @@ func (state *cloneState) clonePtr(v reflect.Value) reflect.Value {
 		}
 	}
 
+	if state.nodeLimit > 0 {
+		state.countNode(t)
+	}
+
 	src := v.Elem()
-	elemType := src.Type()
-	elemKind := src.Kind()
-	nv := state.allocator.New(elemType)
+	nv := state.allocator.New(src.Type())
 
 	if state.visited != nil {
 		vst := visit{
@@ -225,15 +536,17 @@ func (state *cloneState) clonePtr(v reflect.Value) reflect.Value {
 		state.visited[vst] = nv
 	}
 
-	switch elemKind {
-	case reflect.Struct:
-		state.copyStruct(src, nv)
-	case reflect.Array:
-		state.copyArray(src, nv)
-	default:
-		nv.Elem().Set(state.clone(src))
+	// The value is too deep to clone recursively.
+	// Clone it later in the work stack.
+	if state.ptrDepth >= maxPtrDepth && state.guards == 0 {
+		state.pushJob(src, nv)
+		return nv
 	}
 
+	state.ptrDepth++
+	state.copyElem(src, nv)
+	state.ptrDepth--
+
 	// If this pointer is the address of a struct field and it's a cycle pointer,
 	// it may be updated.
 	if state.visited != nil {
@@ -247,12 +560,25 @@ func (state *cloneState) clonePtr(v reflect.Value) reflect.Value {
 	return nv
 }
 
-func (state *cloneState) cloneSlice(v reflect.Value) reflect.Value {
-	if v.IsNil() {
-		return reflect.Zero(v.Type())
+// copyElem clones src to the value pointed by nv.
+func (state *cloneState) copyElem(src, nv reflect.Value) {
+	switch src.Kind() {
+	case reflect.Struct:
+		state.copyStruct(src, nv)
+	case reflect.Array:
+		state.copyArray(src, nv)
+	default:
+		nv.Elem().Set(state.clone(src))
 	}
+}
 
+func (state *cloneState) cloneSlice(v reflect.Value) reflect.Value {
 	t := v.Type()
+
+	if state.appendOnly && state.config.isAppendOnly(t) {
+		return shareSlice(v)
+	}
+
 	num := v.Len()
 
 	if state.visited != nil {
@@ -280,17 +606,43 @@ func (state *cloneState) cloneSlice(v reflect.Value) reflect.Value {
 	}
 
 	// For scalar slice, copy underlying values directly.
-	if state.allocator.isScalar(t.Elem().Kind()) {
+	if state.isScalarType(t.Elem()) {
 		src := unsafe.Pointer(v.Pointer())
 		dst := unsafe.Pointer(nv.Pointer())
 		sz := int(t.Elem().Size())
 		l := num * sz
 		cc := c * sz
 		copy((*[maxByteSize]byte)(dst)[:l:cc], (*[maxByteSize]byte)(src)[:l:cc])
+
+		if state.canonical && state.config.hasInlineFloat(t.Elem()) {
+			for i := 0; i < num; i++ {
+				state.config.canonicalizeMemory(t.Elem(), unsafe.Pointer(uintptr(dst)+uintptr(i*sz)))
+			}
+		}
 	} else {
-		for i := 0; i < num; i++ {
-			nv.Index(i).Set(state.clone(v.Index(i)))
+		// Clone struct and array elements in place like struct fields in copyStruct,
+		// so that the address of any cloned struct is the final address.
+		path := state.path
+
+		switch t.Elem().Kind() {
+		case reflect.Struct:
+			for i := 0; i < num; i++ {
+				state.traceIndex(path, i)
+				state.copyStruct(v.Index(i), nv.Index(i).Addr())
+			}
+		case reflect.Array:
+			for i := 0; i < num; i++ {
+				state.traceIndex(path, i)
+				state.copyArray(v.Index(i), nv.Index(i).Addr())
+			}
+		default:
+			for i := 0; i < num; i++ {
+				state.traceIndex(path, i)
+				nv.Index(i).Set(state.clone(v.Index(i)))
+			}
 		}
+
+		state.path = path
 	}
 
 	return nv
@@ -326,30 +678,100 @@ func (state *cloneState) cloneString(v reflect.Value) reflect.Value {
 
 func (state *cloneState) copyStruct(src, nv reflect.Value) {
 	t := src.Type()
-	st := state.allocator.loadStructType(t)
+	st := state.config.loadStructType(t)
 	ptr := unsafe.Pointer(nv.Pointer())
+	jobs := len(state.jobs)
+
+	// Values in read-only memory cannot be modified by others, so that locks are not necessary.
+	if st.Guard != nil && !state.readOnlyMem && !state.isGuardHeld(src) {
+		if unlock := st.Guard.Lock(src); unlock != nil {
+			// Values inside src must be cloned before unlocking src.
+			// They are cloned recursively instead of in the work stack, which runs after src is unlocked.
+			state.guards++
+			defer func() {
+				state.guards--
+				unlock()
+			}()
+		}
+	}
+
+	if st.rebind != nil {
+		state.rebinds = append(state.rebinds, rebindValue{
+			owner: nv.Elem(),
+			fn:    st.rebind,
+		})
+	}
+
+	noCustomFunc := state.skipCustomFuncValue == src
+
+	if state.readOnlyMem && st.fn != nil && !noCustomFunc {
+		src = detachValue(src)
+	}
 
-	if st.Init(state.allocator, src, nv, state.skipCustomFuncValue == src) {
+	done := st.Init(state.allocator, src, nv, noCustomFunc)
+
+	// Values set by custom funcs are left as they are.
+	if state.canonical && (st.fn == nil || noCustomFunc) {
+		state.config.canonicalizeMemory(t, ptr)
+	}
+
+	if done {
+		if state.debug && st.fn != nil && !noCustomFunc {
+			state.checkCustomFunc(src, nv.Elem())
+		}
+
+		if len(st.GenerationFields) != 0 {
+			state.stampGeneration(st, nv, ptr)
+		}
+
+		if state.strict {
+			state.validate(nv.Elem())
+		}
+
+		return
+	}
+
+	if st.plan != nil && state.usePlans() {
+		st.plan.exec(state, ptr)
 		return
 	}
 
 	for _, pf := range st.ZeroFields {
 		p := unsafe.Pointer(uintptr(ptr) + pf.Offset)
-		sz := pf.Size
+		zeroMemory(p, pf.Size)
+	}
 
-		for sz > zeroBytesCount {
-			copy((*[zeroBytesCount]byte)(p)[:zeroBytesCount:zeroBytesCount], zero)
-			sz -= zeroBytesCount
-			p = unsafe.Pointer(uintptr(p) + zeroBytesCount)
-		}
+	for _, pf := range st.ParentFields {
+		p := unsafe.Pointer(uintptr(ptr) + pf.Offset)
+		state.copyParentField(src.Field(int(pf.Index)), p)
+	}
 
-		copy((*[zeroBytesCount]byte)(p)[:sz:sz], zero)
+	for _, ff := range st.FuncFields {
+		p := unsafe.Pointer(uintptr(ptr) + ff.Offset)
+		shadowCopy(state.cloneByFunc(src.Field(ff.Index), ff.Func), p)
+	}
+
+	if st.TrackAncestors && src.CanAddr() {
+		state.ancestors = &ancestorValue{
+			p:      src.UnsafeAddr(),
+			t:      t,
+			nv:     nv,
+			parent: state.ancestors,
+		}
+		defer state.popAncestor()
 	}
 
+	path := state.path
+
 	for _, pf := range st.PointerFields {
-		i := int(pf.Index)
 		p := unsafe.Pointer(uintptr(ptr) + pf.Offset)
-		field := src.Field(i)
+		field := pf.field(src)
+		state.traceField(path, t, pf.Index)
+
+		if pf.Deep {
+			state.copyDeepField(field, p)
+			continue
+		}
 
 		// This field can be referenced by a pointer or interface inside itself.
 		// Put the pointer to this field to visited to avoid any error.
@@ -371,18 +793,103 @@ func (state *cloneState) copyStruct(src, nv reflect.Value) {
 			// Unfortunately, if the val was used by previous clone routines,
 			// there is no easy way to fix wrong values - all pointers must be traversed and fixed.
 			if val, ok := state.visited[vst]; ok {
-				state.invalid[visit{
+				state.invalid.add(visit{
 					p: val.Pointer(),
 					t: vst.t,
-				}] = nv
+				}, nv)
 			}
 
 			state.visited[vst] = nv
 		}
 
+		// Clone struct and array in place.
+		// It makes sure that the address of any cloned struct is the final address.
+		switch field.Kind() {
+		case reflect.Struct:
+			zeroMemory(p, field.Type().Size())
+			state.copyStruct(field, reflect.NewAt(field.Type(), p))
+			continue
+		case reflect.Array:
+			zeroMemory(p, field.Type().Size())
+			state.copyArray(field, reflect.NewAt(field.Type(), p))
+			continue
+		}
+
 		v := state.clone(field)
 		shadowCopy(v, p)
 	}
+
+	state.path = path
+
+	if len(st.GenerationFields) != 0 {
+		state.stampGeneration(st, nv, ptr)
+	}
+
+	if len(st.InitMethods) == 0 && !state.strict {
+		return
+	}
+
+	// Some values inside nv are still in the work stack.
+	// Finish nv after all of them are cloned.
+	if len(state.jobs) > jobs {
+		state.insertJob(jobs, cloneJob{
+			nv:          nv,
+			initMethods: st.InitMethods,
+			finish:      true,
+		})
+		return
+	}
+
+	state.finishStruct(nv, st.InitMethods)
+}
+
+// finishStruct calls init methods of the cloned struct nv and validates it in strict mode.
+func (state *cloneState) finishStruct(nv reflect.Value, initMethods []int) {
+	for _, i := range initMethods {
+		nv.Method(i).Call(nil)
+	}
+
+	if state.strict {
+		state.validate(nv.Elem())
+	}
+}
+
+// copyParentField sets the cloned parent to the field tagged with `clone:"parent"`.
+// If the parent is not found in ancestors, the field is shadow copied in Clone
+// or cloned as a normal pointer in Slowly.
+func (state *cloneState) copyParentField(field reflect.Value, p unsafe.Pointer) {
+	if field.IsNil() {
+		return
+	}
+
+	parent := field.Pointer()
+	t := field.Type().Elem()
+
+	for av := state.ancestors; av != nil; av = av.parent {
+		if av.p == parent && av.t == t {
+			shadowCopy(av.nv, p)
+			return
+		}
+	}
+
+	if state.visited != nil {
+		shadowCopy(state.clone(field), p)
+	}
+}
+
+func (state *cloneState) popAncestor() {
+	state.ancestors = state.ancestors.parent
+}
+
+// zeroMemory sets sz bytes starting from p to zero.
+func zeroMemory(p unsafe.Pointer, sz uintptr) {
+	for sz > zeroBytesCount {
+		copy((*[zeroBytesCount]byte)(p)[:zeroBytesCount:zeroBytesCount], zero)
+		sz -= zeroBytesCount
+		p = unsafe.Pointer(uintptr(p) + zeroBytesCount)
+	}
+
+	copy((*[zeroBytesCount]byte)(p)[:sz:sz], zero)
 }
 
 var typeOfString = reflect.TypeOf("")
@@ -494,24 +1001,35 @@ func shadowCopy(src reflect.Value, p unsafe.Pointer) {
 	}
 }
 
+// rebind calls all rebind funcs with cloned root value.
+func (state *cloneState) rebind(root reflect.Value) {
+	for _, rv := range state.rebinds {
+		rv.fn(root, rv.owner)
+	}
+}
+
 // fix tranverses v to update all pointer values in state.invalid.
 func (state *cloneState) fix(v reflect.Value) {
-	if state == nil || len(state.invalid) == 0 {
+	if state == nil || state.invalid.len() == 0 {
 		return
 	}
 
 	fix := &fixState{
 		allocator: state.allocator,
+		config:    state.config,
 		fixed:     fixMap{},
 		invalid:   state.invalid,
+		reachable: map[reflect.Type]bool{},
 	}
 	fix.fix(v)
 }
 
 type fixState struct {
 	allocator *Allocator
+	config    *config
 	fixed     fixMap
 	invalid   invalidPointers
+	reachable map[reflect.Type]bool // Cache of maybeInvalid.
 }
 
 type fixMap map[visit]struct{}
@@ -521,7 +1039,7 @@ func (fix *fixState) new(t reflect.Type) reflect.Value {
 }
 
 func (fix *fixState) fix(v reflect.Value) (copied reflect.Value, changed int) {
-	if fix.allocator.isScalar(v.Kind()) {
+	if fix.allocator.isScalar(v.Kind()) || !fix.maybeInvalid(v.Type()) {
 		return
 	}
 
@@ -545,8 +1063,57 @@ func (fix *fixState) fix(v reflect.Value) (copied reflect.Value, changed int) {
 		// Do nothing.
 		return
 	default:
-		panic(fmt.Errorf("go-clone: <bug> unsupported type `%v`", v.Type()))
+		panic(&UnsupportedTypeError{
+			Type: v.Type(),
+		})
+	}
+}
+
+// maybeInvalid returns true if values of t may contain any invalid pointer in depth.
+// The fix pass skips all values which cannot contain invalid pointers,
+// so that its cost depends on the part of the graph related to invalid pointers.
+func (fix *fixState) maybeInvalid(t reflect.Type) bool {
+	if reachable, ok := fix.reachable[t]; ok {
+		return reachable
 	}
+
+	reachable := fix.reachInvalid(t, map[reflect.Type]struct{}{})
+	fix.reachable[t] = reachable
+	return reachable
+}
+
+func (fix *fixState) reachInvalid(t reflect.Type, visiting map[reflect.Type]struct{}) bool {
+	if _, ok := visiting[t]; ok {
+		return false
+	}
+
+	visiting[t] = struct{}{}
+
+	switch t.Kind() {
+	case reflect.Array, reflect.Slice:
+		return fix.reachInvalid(t.Elem(), visiting)
+	case reflect.Interface:
+		// The dynamic type can be anything.
+		return true
+	case reflect.Map:
+		return fix.reachInvalid(t.Key(), visiting) || fix.reachInvalid(t.Elem(), visiting)
+	case reflect.Ptr:
+		if _, ok := fix.invalid.types[t]; ok {
+			return true
+		}
+
+		return fix.reachInvalid(t.Elem(), visiting)
+	case reflect.Struct:
+		st := fix.config.loadStructType(t)
+
+		for _, pf := range st.PointerFields {
+			if fix.reachInvalid(pf.fieldType(t), visiting) {
+				return true
+			}
+		}
+	}
+
+	return false
 }
 
 func (fix *fixState) fixArray(v reflect.Value) (copied reflect.Value, changed int) {
@@ -569,7 +1136,7 @@ func (fix *fixState) fixArray(v reflect.Value) (copied reflect.Value, changed in
 				t: et,
 			}
 
-			if nv, ok := fix.invalid[vst]; ok {
+			if nv, ok := fix.invalid.lookup(vst); ok {
 				// If elem cannot be set, v must be copied to make it settable.
 				// Don't do it unless there is no other choices.
 				if !elem.CanSet() {
@@ -620,7 +1187,7 @@ func (fix *fixState) fixInterface(v reflect.Value) (copied reflect.Value, change
 			t: t,
 		}
 
-		if nv, ok := fix.invalid[vst]; ok {
+		if nv, ok := fix.invalid.lookup(vst); ok {
 			copied = nv.Convert(v.Type())
 			changed++
 			return
@@ -676,7 +1243,7 @@ func (fix *fixState) fixMap(v reflect.Value) (copied reflect.Value, changed int)
 				t: et,
 			}
 
-			if nv, ok := fix.invalid[vst]; ok {
+			if nv, ok := fix.invalid.lookup(vst); ok {
 				fixed = nv
 				c++
 			} else {
@@ -701,7 +1268,7 @@ func (fix *fixState) fixMap(v reflect.Value) (copied reflect.Value, changed int)
 				t: kt,
 			}
 
-			if nv, ok := fix.invalid[vst]; ok {
+			if nv, ok := fix.invalid.lookup(vst); ok {
 				fixed = nv
 				c++
 			} else {
@@ -757,7 +1324,7 @@ func (fix *fixState) fixPtr(v reflect.Value) (copied reflect.Value, changed int)
 		t: v.Type(),
 	}
 
-	if _, ok := fix.invalid[vst]; ok {
+	if _, ok := fix.invalid.lookup(vst); ok {
 		panic(fmt.Errorf("go-clone: <bug> invalid pointers must have been fixed in other methods"))
 	}
 
@@ -810,7 +1377,7 @@ func (fix *fixState) fixSlice(v reflect.Value) (copied reflect.Value, changed in
 				t: et,
 			}
 
-			if nv, ok := fix.invalid[vst]; ok {
+			if nv, ok := fix.invalid.lookup(vst); ok {
 				fixed = nv
 			} else {
 				fixed, c = fix.fixPtr(elem)
@@ -833,16 +1400,14 @@ func (fix *fixState) fixSlice(v reflect.Value) (copied reflect.Value, changed in
 
 func (fix *fixState) fixStruct(v reflect.Value) (copied reflect.Value, changed int) {
 	t := v.Type()
-	st := fix.allocator.loadStructType(t)
+	st := fix.config.loadStructType(t)
 
 	if len(st.PointerFields) == 0 {
 		return
 	}
 
 	for _, pf := range st.PointerFields {
-		i := int(pf.Index)
-		field := v.Field(i)
-
+		field := pf.field(v)
 		ft := field.Type()
 
 		if ft.Kind() == reflect.Ptr {
@@ -851,7 +1416,7 @@ func (fix *fixState) fixStruct(v reflect.Value) (copied reflect.Value, changed i
 				t: ft,
 			}
 
-			if nv, ok := fix.invalid[vst]; ok {
+			if nv, ok := fix.invalid.lookup(vst); ok {
 				// If v is not addressable, a new struct must be allocated.
 				// Don't do it unless there is no other choices.
 				if !v.CanAddr() {
@@ -3,7 +3,10 @@
 
 package clone
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+)
 
 func BenchmarkSimpleClone(b *testing.B) {
 	orig := &testSimple{
@@ -17,6 +20,19 @@ func BenchmarkSimpleClone(b *testing.B) {
 	}
 }
 
+func BenchmarkSimpleCloneInto(b *testing.B) {
+	orig := &testSimple{
+		Foo: 123,
+		Bar: "abcd",
+	}
+	dst := &testSimple{}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		CloneInto(dst, orig)
+	}
+}
+
 func BenchmarkComplexClone(b *testing.B) {
 	m := map[string]*T{
 		"abc": {
@@ -38,3 +54,118 @@ func BenchmarkComplexClone(b *testing.B) {
 		Clone(m)
 	}
 }
+
+func BenchmarkPlannedStructClone(b *testing.B) {
+	score := 1.5
+	orig := &planNode{
+		Leaf: planLeaf{
+			ID:    1,
+			Score: &score,
+			Tags:  []int{1, 2, 3},
+		},
+		Next: &planLeaf{
+			ID:   2,
+			Tags: []int{4},
+		},
+	}
+
+	for i := 0; i < 16; i++ {
+		orig.Leaves = append(orig.Leaves, planLeaf{
+			ID:    i,
+			Score: &score,
+			Tags:  []int{i, i},
+		})
+	}
+
+	// Plans are not used in debug mode, so that values are cloned by reflection.
+	reflection := FromHeap()
+	reflection.SetDebugMode(true)
+
+	for _, c := range []struct {
+		name      string
+		allocator *Allocator
+	}{
+		{"Plan", defaultAllocator},
+		{"Reflection", reflection},
+	} {
+		cloner := MakeCloner(c.allocator)
+
+		b.Run(c.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				cloner.Clone(orig)
+			}
+		})
+	}
+}
+
+func BenchmarkNestedAllocatorClone(b *testing.B) {
+	allocator := FromHeap()
+
+	// Options are looked up in all parents.
+	for i := 0; i < 8; i++ {
+		allocator = NewAllocator(nil, &AllocatorMethods{
+			Parent: allocator,
+		})
+	}
+
+	orig := &testSimple{
+		Foo: 123,
+		Bar: "abcd",
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		clone(allocator, orig)
+	}
+}
+
+func BenchmarkDerivedAllocatorClone(b *testing.B) {
+	base := FromHeap()
+	base.Freeze()
+	orig := &testSimple{
+		Foo: 123,
+		Bar: "abcd",
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		allocator := NewAllocator(nil, &AllocatorMethods{
+			Parent: base,
+		})
+		clone(allocator, orig)
+	}
+}
+
+func BenchmarkSlowlyFixInvalidPointers(b *testing.B) {
+	const n = 10000
+	value := &elementGraph{
+		Nodes: make([]elementNode, n),
+	}
+
+	for i := range value.Nodes {
+		value.Nodes[i].Value.ID = i
+		value.Nodes[i].Name = "node"
+		value.Refs = append(value.Refs, &value.Nodes[i].Value)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Slowly(value)
+	}
+}
+
+func BenchmarkStringKeyMapClone(b *testing.B) {
+	m := make(map[string]struct{}, 1000)
+
+	for i := 0; i < 1000; i++ {
+		m[fmt.Sprint(i)] = struct{}{}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Clone(m)
+	}
+}
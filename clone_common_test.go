@@ -12,6 +12,7 @@ import (
 	"unsafe"
 
 	"github.com/huandu/go-assert"
+	"github.com/huandu/go-clone/diff"
 )
 
 var testFuncMap = map[string]func(t *testing.T, allocator *Allocator){
@@ -20,6 +21,7 @@ var testFuncMap = map[string]func(t *testing.T, allocator *Allocator){
 	"Slowly cycle linked list":           testSlowlyCycleLinkedList,
 	"Slowly fix invalid cycle pointers":  testSlowlyFixInvalidCyclePointers,
 	"Slowly fix invalid linked pointers": testSlowlyFixInvalidLinkedPointers,
+	"Slowly fix pointers to elements":    testSlowlyFixPointersToElements,
 	"Clone array":                        testCloneArray,
 	"Clone map":                          testCloneMap,
 	"Clone bytes buffer":                 testCloneBytesBuffer,
@@ -413,6 +415,42 @@ func testSlowlyFixInvalidLinkedPointers(t *testing.T, allocator *Allocator) {
 	a.Assert(cloned.refComplexMap == &cloned.complexMap)
 }
 
+type elementValue struct {
+	ID   int
+	Next *elementValue
+}
+
+type elementNode struct {
+	Value elementValue
+	Name  string
+}
+
+type elementGraph struct {
+	Refs  []*elementValue
+	Nodes []elementNode
+	Array [2][1]elementNode
+}
+
+func testSlowlyFixPointersToElements(t *testing.T, allocator *Allocator) {
+	a := assert.New(t)
+	value := &elementGraph{
+		Nodes: []elementNode{{Name: "foo"}, {Name: "bar"}},
+	}
+	value.Nodes[0].Value.Next = &value.Nodes[1].Value
+	value.Refs = []*elementValue{
+		&value.Nodes[0].Value,
+		&value.Nodes[1].Value,
+		&value.Array[1][0].Value,
+	}
+	cloned := cloneSlowly(allocator, value).(*elementGraph)
+
+	a.Assert(cloned.Refs[0] == &cloned.Nodes[0].Value)
+	a.Assert(cloned.Refs[1] == &cloned.Nodes[1].Value)
+	a.Assert(cloned.Refs[2] == &cloned.Array[1][0].Value)
+	a.Assert(cloned.Nodes[0].Value.Next == &cloned.Nodes[1].Value)
+	a.Equal(cloned.Nodes[1].Name, "bar")
+}
+
 func testCloneArray(t *testing.T, allocator *Allocator) {
 	a := assert.New(t)
 	arr := [2]*T{
@@ -629,6 +667,8 @@ func testCloneUnexportedFields(t *testing.T, allocator *Allocator) {
 
 	// Don't test this map in reflect.DeepEqual due to bug in Go.
 	// https://github.com/golang/go/issues/33907
+	// Package diff compares it correctly.
+	a.Assert(diff.Equal(unexported.m, cloned.m))
 	unexported.m["loop"] = nil
 	cloned.m["loop"] = nil
 
@@ -695,6 +735,8 @@ type skipFields struct {
 	privateTSliceSkip []*T `clone:"-"`
 	bytes             [testBytes]byte
 	bytesSkip         [testBytes]byte `clone:"-"`
+	cache             map[string]int  `clone:"zero"`
+	cacheSize         int             `clone:"zero"`
 }
 
 func testCloneSkipFields(t *testing.T, allocator *Allocator) {
@@ -769,6 +811,10 @@ func testCloneSkipFields(t *testing.T, allocator *Allocator) {
 				},
 			},
 		},
+		cache: map[string]int{
+			"abc": 123,
+		},
+		cacheSize: 1,
 	}
 
 	for i := 0; i < testBytes; i++ {
@@ -796,4 +842,6 @@ func testCloneSkipFields(t *testing.T, allocator *Allocator) {
 	a.Equal(to.privateTSliceSkip, ([]*T)(nil))
 	a.Equal(from.bytes, to.bytes)
 	a.Equal(to.bytesSkip, [testBytes]byte{})
+	a.Equal(to.cache, map[string]int(nil))
+	a.Equal(to.cacheSize, 0)
 }
@@ -0,0 +1,93 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package clone
+
+import (
+	"reflect"
+)
+
+// CloneOf recursively deep clones v to a new value in heap.
+// It works exactly the same as Clone without boxing v in an interface{}.
+//
+// If T contains scalar values only, e.g. a struct of numbers and strings,
+// CloneOf returns a copy of v without any memory allocation.
+func CloneOf[T any](v T) T {
+	if canShadowCopy[T]() {
+		return v
+	}
+
+	src := new(T)
+	*src = v
+	return *cloneNew(src)
+}
+
+// SlowlyOf recursively deep clones v to a new value in heap.
+// It works exactly the same as Slowly without boxing v in an interface{}.
+func SlowlyOf[T any](v T) T {
+	if canShadowCopy[T]() {
+		return v
+	}
+
+	src := new(T)
+	dst := new(T)
+	*src = v
+	SlowlyPtr(src, dst)
+	return *dst
+}
+
+// ClonePtr recursively deep clones *src into *dst with memory allocated from heap.
+// If src is nil, *dst is set to zero value.
+// It panics if dst is nil.
+//
+// ClonePtr never leaks dst, so that *dst can stay on stack.
+// If T contains scalar values only, ClonePtr copies *src to *dst without any memory allocation.
+// Otherwise, ClonePtr works like Clone(*src): the clone is made in heap and copied to *dst,
+// so values in *dst can never point to *dst itself, e.g. pointers set by rebind funcs.
+// Use SlowlyPtr if they must.
+func ClonePtr[T any](src, dst *T) {
+	if src == nil {
+		var zero T
+		*dst = zero
+		return
+	}
+
+	if canShadowCopy[T]() {
+		*dst = *src
+		return
+	}
+
+	*dst = *cloneNew(src)
+}
+
+// SlowlyPtr recursively deep clones *src into *dst with memory allocated from heap.
+// If src is nil, *dst is set to zero value.
+// It panics if dst is nil.
+//
+// SlowlyPtr clones *src into *dst in place,
+// so that pointers to *src or its fields inside *src are cloned to pointers to *dst or its fields.
+// As *dst is referenced by cloned values, it always escapes to heap.
+func SlowlyPtr[T any](src, dst *T) {
+	if src == nil {
+		var zero T
+		*dst = zero
+		return
+	}
+
+	defaultAllocator.cloneInto(reflect.ValueOf(src).Elem(), reflect.ValueOf(dst).Elem(), true, false)
+}
+
+func canShadowCopy[T any]() bool {
+	return defaultAllocator.CanShadowCopy(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// cloneNew clones *src to a new value in heap.
+// The dst is allocated in heap on purpose, as it escapes in clone methods.
+func cloneNew[T any](src *T) *T {
+	dst := new(T)
+	defaultAllocator.cloneInto(reflect.ValueOf(src).Elem(), reflect.ValueOf(dst).Elem(), false, false)
+	return dst
+}
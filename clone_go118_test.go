@@ -0,0 +1,154 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestCloneOf(t *testing.T) {
+	a := assert.New(t)
+
+	s := testSimple{
+		Foo: 123,
+		Bar: "abcd",
+	}
+	a.Equal(CloneOf(s), s)
+	a.Equal(SlowlyOf(s), s)
+
+	m := map[string][]int{"foo": {1, 2}}
+	cloned := CloneOf(m)
+	a.Equal(cloned, m)
+	a.Assert(&cloned["foo"][0] != &m["foo"][0])
+
+	var iface interface{} = &s
+	clonedIface := CloneOf(iface)
+	a.Equal(clonedIface, iface)
+	a.Assert(clonedIface.(*testSimple) != &s)
+	a.Equal(CloneOf[interface{}](nil), nil)
+
+	list := &cycleList{}
+	elem := &cycleElement{list: list}
+	list.elem = elem
+	clonedList := SlowlyOf(list)
+	a.Assert(clonedList != list)
+	a.Assert(clonedList.elem.list == clonedList)
+}
+
+func TestClonePtr(t *testing.T) {
+	a := assert.New(t)
+
+	src := T{
+		Foo: 123,
+		Bar: map[string]interface{}{
+			"abc": 321,
+		},
+	}
+	dst := T{
+		Foo: 456,
+		Bar: map[string]interface{}{
+			"def": 789,
+		},
+	}
+	ClonePtr(&src, &dst)
+	a.Equal(dst, src)
+	a.Assert(reflect.ValueOf(dst.Bar).Pointer() != reflect.ValueOf(src.Bar).Pointer())
+
+	ClonePtr(nil, &dst)
+	a.Equal(dst, T{})
+
+	arr := [2][]int{{1}, {2}}
+	var clonedArr [2][]int
+	ClonePtr(&arr, &clonedArr)
+	a.Equal(clonedArr, arr)
+	a.Assert(&clonedArr[0][0] != &arr[0][0])
+
+	// Back pointers to fields of the root value are fixed.
+	type node struct {
+		Value elementValue
+		Ref   *elementValue
+	}
+	var n node
+	n.Ref = &n.Value
+	n.Value.Next = &n.Value
+	var clonedNode node
+	SlowlyPtr(&n, &clonedNode)
+	a.Assert(clonedNode.Value.Next == &clonedNode.Value)
+	a.Assert(clonedNode.Ref == &clonedNode.Value)
+
+	SlowlyPtr(nil, &clonedNode)
+	a.Equal(clonedNode, node{})
+}
+
+func BenchmarkSimpleCloneOf(b *testing.B) {
+	orig := testSimple{
+		Foo: 123,
+		Bar: "abcd",
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		CloneOf(orig)
+	}
+}
+
+func BenchmarkSimpleClonePtr(b *testing.B) {
+	orig := testSimple{
+		Foo: 123,
+		Bar: "abcd",
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var cloned testSimple
+		ClonePtr(&orig, &cloned)
+	}
+}
+
+func BenchmarkSimpleCloneValue(b *testing.B) {
+	orig := testSimple{
+		Foo: 123,
+		Bar: "abcd",
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Clone(orig)
+	}
+}
+
+func BenchmarkComplexClonePtr(b *testing.B) {
+	orig := T{
+		Foo: 123,
+		Bar: map[string]interface{}{
+			"abc": 321,
+		},
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var cloned T
+		ClonePtr(&orig, &cloned)
+	}
+}
+
+func BenchmarkComplexCloneValue(b *testing.B) {
+	orig := T{
+		Foo: 123,
+		Bar: map[string]interface{}{
+			"abc": 321,
+		},
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Clone(orig)
+	}
+}
@@ -38,3 +38,47 @@ func TestIssue21(t *testing.T) {
 	a.Assert(dst.foo != src.foo)
 	a.Equal(dst, src)
 }
+
+func TestCloneParentTag(t *testing.T) {
+	a := assert.New(t)
+
+	type node struct {
+		Value    int
+		Parent   *node `clone:"parent"`
+		Children []*node
+	}
+	type tree struct {
+		Root  node
+		Owner *tree `clone:"parent"`
+	}
+
+	outside := &node{Value: -1}
+	root := &node{Value: 0, Parent: outside}
+	child1 := &node{Value: 1, Parent: root}
+	child2 := &node{Value: 2, Parent: root}
+	grandchild := &node{Value: 3, Parent: child2}
+	root.Children = []*node{child1, child2}
+	child2.Children = []*node{grandchild}
+
+	cloned := Clone(root).(*node)
+	a.Assert(cloned != root)
+	a.Assert(cloned.Parent == outside)
+	a.Assert(cloned.Children[0].Parent == cloned)
+	a.Assert(cloned.Children[1].Parent == cloned)
+	a.Assert(cloned.Children[1].Children[0].Parent == cloned.Children[1])
+	a.Equal(cloned.Children[1].Children[0].Value, 3)
+
+	// Parent can be a struct field.
+	tr := &tree{}
+	tr.Owner = tr
+	tr.Root.Children = []*node{{Value: 1, Parent: &tr.Root}}
+	clonedTree := Clone(tr).(*tree)
+	a.Assert(clonedTree.Owner == tr)
+	a.Assert(clonedTree.Root.Children[0].Parent == &clonedTree.Root)
+
+	// Slowly clones parent outside the value.
+	cloned = Slowly(root).(*node)
+	a.Assert(cloned.Parent != outside)
+	a.Equal(cloned.Parent.Value, -1)
+	a.Assert(cloned.Children[1].Children[0].Parent == cloned.Children[1])
+}
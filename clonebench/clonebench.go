@@ -0,0 +1,210 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Package clonebench provides standard workloads and a harness to measure clone performance.
+//
+// It's designed to compare allocators and options on real hardware
+// and report performance regressions with reproducible numbers.
+//
+//	for _, w := range clonebench.Workloads() {
+//	    fmt.Println(clonebench.Measure(allocator, w))
+//	}
+package clonebench
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/huandu/go-clone"
+)
+
+// Workload is a value to clone in benchmark.
+type Workload struct {
+	Name   string      // The name of the workload.
+	Value  interface{} // The value to clone.
+	Slowly bool        // Whether the value must be cloned by CloneSlowly, e.g. it has pointer cycles.
+}
+
+// Result is the result of measuring a workload.
+type Result struct {
+	Workload string
+	testing.BenchmarkResult
+}
+
+// String returns the result in the format of `go test -bench`.
+func (r Result) String() string {
+	return fmt.Sprintf("%s\t%s\t%s", r.Workload, r.BenchmarkResult.String(), r.MemString())
+}
+
+// Measure clones workload's value with allocator repeatedly and returns the result.
+// If allocator is nil, the heap allocator is used.
+//
+// Measure runs as long as a benchmark in `go test`.
+// The value is cloned once before measuring to warm up type caches.
+func Measure(allocator *clone.Allocator, workload Workload) Result {
+	if allocator == nil {
+		allocator = clone.FromHeap()
+	}
+
+	cloner := clone.MakeCloner(allocator)
+	fn := cloner.Clone
+
+	if workload.Slowly {
+		fn = cloner.CloneSlowly
+	}
+
+	fn(workload.Value)
+	result := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			fn(workload.Value)
+		}
+	})
+
+	return Result{
+		Workload:        workload.Name,
+		BenchmarkResult: result,
+	}
+}
+
+// Workloads returns all standard workloads in default size.
+func Workloads() []Workload {
+	return []Workload{
+		DeepTree(10, 2),
+		WideMap(1000),
+		CyclicList(1000),
+		StringConfig(100),
+	}
+}
+
+// Tree is a node of the tree in DeepTree workload.
+type Tree struct {
+	ID       int
+	Children []*Tree
+}
+
+// DeepTree returns a workload of a complete tree with depth levels and fanout children per node.
+func DeepTree(depth, fanout int) Workload {
+	id := 0
+	var build func(level int) *Tree
+	build = func(level int) *Tree {
+		id++
+		node := &Tree{
+			ID: id,
+		}
+
+		if level < depth {
+			node.Children = make([]*Tree, 0, fanout)
+
+			for i := 0; i < fanout; i++ {
+				node.Children = append(node.Children, build(level+1))
+			}
+		}
+
+		return node
+	}
+
+	return Workload{
+		Name:  "DeepTree/depth=" + strconv.Itoa(depth) + "/fanout=" + strconv.Itoa(fanout),
+		Value: build(1),
+	}
+}
+
+// Item is the value type in WideMap workload.
+type Item struct {
+	Name  string
+	Score float64
+	Tags  []string
+}
+
+// WideMap returns a workload of a map with n entries.
+func WideMap(n int) Workload {
+	m := make(map[string]*Item, n)
+
+	for i := 0; i < n; i++ {
+		key := "key-" + strconv.Itoa(i)
+		m[key] = &Item{
+			Name:  key,
+			Score: float64(i),
+			Tags:  []string{"tag", key},
+		}
+	}
+
+	return Workload{
+		Name:  "WideMap/n=" + strconv.Itoa(n),
+		Value: m,
+	}
+}
+
+// List is a node of the doubly linked list in CyclicList workload.
+type List struct {
+	Value int
+	Prev  *List
+	Next  *List
+}
+
+// CyclicList returns a workload of a circular doubly linked list with n nodes.
+// It must be cloned by CloneSlowly.
+func CyclicList(n int) Workload {
+	head := &List{}
+	head.Prev = head
+	head.Next = head
+
+	for i := 1; i < n; i++ {
+		node := &List{
+			Value: i,
+			Prev:  head.Prev,
+			Next:  head,
+		}
+		head.Prev.Next = node
+		head.Prev = node
+	}
+
+	return Workload{
+		Name:   "CyclicList/n=" + strconv.Itoa(n),
+		Value:  head,
+		Slowly: true,
+	}
+}
+
+// Config is the value type in StringConfig workload.
+type Config struct {
+	Name     string
+	Labels   map[string]string
+	Args     []string
+	Sections []Section
+}
+
+// Section is a section in Config.
+type Section struct {
+	Title string
+	Lines []string
+}
+
+// StringConfig returns a workload of a string-heavy config with n labels, args and sections.
+func StringConfig(n int) Workload {
+	config := &Config{
+		Name:     strings.Repeat("config", 10),
+		Labels:   make(map[string]string, n),
+		Args:     make([]string, 0, n),
+		Sections: make([]Section, 0, n),
+	}
+
+	for i := 0; i < n; i++ {
+		s := strconv.Itoa(i)
+		config.Labels["label-"+s] = "value-" + s
+		config.Args = append(config.Args, "--arg-"+s)
+		config.Sections = append(config.Sections, Section{
+			Title: "section-" + s,
+			Lines: []string{"line-1-" + s, "line-2-" + s},
+		})
+	}
+
+	return Workload{
+		Name:  "StringConfig/n=" + strconv.Itoa(n),
+		Value: config,
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clonebench
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/huandu/go-assert"
+	"github.com/huandu/go-clone"
+)
+
+func TestWorkloads(t *testing.T) {
+	a := assert.New(t)
+
+	tree := DeepTree(3, 2).Value.(*Tree)
+	a.Equal(len(tree.Children), 2)
+	a.Equal(len(tree.Children[1].Children), 2)
+	a.Equal(len(tree.Children[1].Children[0].Children), 0)
+	a.Equal(tree.Children[1].Children[1].ID, 7)
+
+	m := WideMap(10).Value.(map[string]*Item)
+	a.Equal(len(m), 10)
+
+	list := CyclicList(3)
+	a.Assert(list.Slowly)
+	head := list.Value.(*List)
+	a.Assert(head.Next.Next.Next == head)
+	a.Assert(head.Prev.Value == 2)
+
+	config := StringConfig(5).Value.(*Config)
+	a.Equal(len(config.Labels), 5)
+	a.Equal(len(config.Sections), 5)
+
+	for _, w := range Workloads() {
+		cloner := clone.MakeCloner(clone.FromHeap())
+
+		if w.Slowly {
+			a.Equal(cloner.CloneSlowly(w.Value), w.Value)
+		} else {
+			a.Equal(cloner.Clone(w.Value), w.Value)
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip measuring in short mode")
+	}
+
+	a := assert.New(t)
+	result := Measure(nil, DeepTree(3, 2))
+
+	a.Assert(result.N > 0)
+	a.Equal(result.Workload, "DeepTree/depth=3/fanout=2")
+	a.Assert(result.AllocsPerOp() > 0)
+	a.Assert(strings.HasPrefix(result.String(), result.Workload))
+}
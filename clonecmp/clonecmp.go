@@ -0,0 +1,83 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Package clonecmp exports go-cmp options consistent with clone semantics,
+// so that tests comparing original values and clones by cmp.Equal or cmp.Diff
+// see values in the same way as clone methods do.
+//
+//	if diff := cmp.Diff(orig, cloned, clonecmp.Options(nil)); diff != "" {
+//		t.Fatalf("unexpected clone: %v", diff)
+//	}
+package clonecmp
+
+import (
+	"reflect"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/huandu/go-clone"
+)
+
+// Options returns go-cmp options derived from registrations in allocator.
+// If allocator is nil, the heap allocator is used.
+//
+// Options follow these rules.
+//   - Unexported fields are compared, as clone methods clone them.
+//   - Opaque pointers are compared by pointer, as they are never cloned in depth.
+//   - Structs marked as scalar are compared by value, as they are shadow copied.
+//   - Struct fields tagged with `clone:"skip"`, `clone:"-"`, `clone:"zero"`, `clone:"rebind"`,
+//     `clone:"init=methodName"` or `clone:"func=name"` are ignored, as they are zeroed or repopulated in clones.
+//
+// Registrations are checked when comparing values,
+// so that options reflect the latest registrations in allocator.
+func Options(allocator *clone.Allocator) cmp.Options {
+	if allocator == nil {
+		allocator = clone.FromHeap()
+	}
+
+	return cmp.Options{
+		cmp.Exporter(func(reflect.Type) bool {
+			return true
+		}),
+		cmp.FilterPath(isSkippedField, cmp.Ignore()),
+		cmp.FilterPath(func(p cmp.Path) bool {
+			t := p.Last().Type()
+			return t != nil && t.Kind() == reflect.Ptr && allocator.IsOpaquePointer(t)
+		}, cmp.Comparer(samePointer)),
+		cmp.FilterPath(func(p cmp.Path) bool {
+			t := p.Last().Type()
+			return t != nil && t.Kind() == reflect.Struct && allocator.IsMarkedAsScalar(t)
+		}, cmp.Comparer(equalByValue)),
+	}
+}
+
+func isSkippedField(p cmp.Path) bool {
+	sf, ok := p.Last().(cmp.StructField)
+
+	if !ok {
+		return false
+	}
+
+	field := p.Index(-2).Type().Field(sf.Index())
+	policy, _ := clone.ParseFieldTag(field.Tag)
+
+	switch policy.Action {
+	case clone.FieldSkip, clone.FieldZero, clone.FieldRebind, clone.FieldInit, clone.FieldFunc:
+		return true
+	}
+
+	return false
+}
+
+func samePointer(x, y interface{}) bool {
+	return reflect.ValueOf(x).Pointer() == reflect.ValueOf(y).Pointer()
+}
+
+// equalByValue compares scalar structs by value.
+// If a struct is not comparable, it's compared by reflect.DeepEqual.
+func equalByValue(x, y interface{}) bool {
+	if reflect.TypeOf(x).Comparable() {
+		return x == y
+	}
+
+	return reflect.DeepEqual(x, y)
+}
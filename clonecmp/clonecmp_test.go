@@ -0,0 +1,75 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clonecmp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/huandu/go-assert"
+	"github.com/huandu/go-clone"
+)
+
+type handle struct {
+	ID int
+}
+
+type scalar struct {
+	P *int
+}
+
+type data struct {
+	Name    string
+	Handle  *handle
+	Scalar  scalar
+	Skipped []int  `clone:"skip"`
+	Secret  string `clone:"func=redact"`
+	private []int
+}
+
+func TestOptions(t *testing.T) {
+	a := assert.New(t)
+	allocator := clone.FromHeap()
+	allocator.MarkAsOpaquePointer(reflect.TypeOf(&handle{}))
+	allocator.MarkAsScalar(reflect.TypeOf(scalar{}))
+	allocator.RegisterNamedFunc("redact", func(allocator *clone.Allocator, old, new reflect.Value) {
+		new.SetString("<redacted>")
+	})
+	opts := Options(allocator)
+
+	n := 1
+	orig := &data{
+		Name:    "foo",
+		Handle:  &handle{ID: 1},
+		Scalar:  scalar{P: &n},
+		Skipped: []int{1},
+		Secret:  "secret",
+		private: []int{2},
+	}
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*data)
+	a.Equal(cmp.Diff(orig, cloned, opts), "")
+	a.Equal(cloned.Secret, "<redacted>")
+
+	// Opaque pointers are compared by pointer.
+	another := *cloned
+	another.Handle = &handle{ID: 1}
+	a.Assert(!cmp.Equal(orig, &another, opts))
+
+	// Scalar structs are compared by value.
+	m := 1
+	another = *cloned
+	another.Scalar = scalar{P: &m}
+	a.Assert(!cmp.Equal(orig, &another, opts))
+
+	// Unexported fields are compared.
+	another = *cloned
+	another.private = []int{3}
+	a.Assert(!cmp.Equal(orig, &another, opts))
+
+	// Registrations are not applied to other allocators.
+	another = *cloned
+	another.Handle = &handle{ID: 1}
+	a.Assert(cmp.Equal(orig, &another, Options(nil)))
+}
@@ -0,0 +1,11 @@
+module github.com/huandu/go-clone/clonecmp
+
+go 1.13
+
+require (
+	github.com/google/go-cmp v0.6.0
+	github.com/huandu/go-assert v1.1.5
+	github.com/huandu/go-clone v1.8.0
+)
+
+replace github.com/huandu/go-clone => ../
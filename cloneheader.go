@@ -0,0 +1,71 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// CloneHeader clones the header of v in heap and shares everything referenced by the header.
+//
+// See Allocator.CloneHeader for more details.
+func CloneHeader(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	return defaultAllocator.CloneHeader(reflect.ValueOf(v)).Interface()
+}
+
+// CloneHeader clones the header of val with memory allocated from a
+// and shares everything referenced by the header.
+// It's a building block for copy-on-write containers.
+//
+// Following rules apply to different kinds of val.
+//
+//   - slice: A new slice header pointing to the same backing array.
+//   - map: A new map with the same keys and values, as map header cannot be copied.
+//   - pointer: A pointer to a shadow copy of the pointed value.
+//   - Other kinds: A shadow copy of val, including unexported fields of structs.
+//
+// Custom funcs and scalar types are not considered, because nothing is cloned in depth.
+func (a *Allocator) CloneHeader(val reflect.Value) reflect.Value {
+	if !val.IsValid() {
+		return val
+	}
+
+	if !val.CanInterface() {
+		val = forceClearROFlag(val)
+	}
+
+	t := val.Type()
+
+	switch val.Kind() {
+	case reflect.Map:
+		if val.IsNil() {
+			return reflect.Zero(t)
+		}
+
+		nv := a.MakeMap(t, val.Len())
+
+		for iter := mapIter(val); iter.Next(); {
+			nv.SetMapIndex(iter.Key(), iter.Value())
+		}
+
+		return nv
+	case reflect.Ptr:
+		if val.IsNil() {
+			return reflect.Zero(t)
+		}
+
+		nv := a.New(t.Elem())
+		shadowCopy(val.Elem(), unsafe.Pointer(nv.Pointer()))
+		return nv
+	default:
+		nv := a.New(t)
+		shadowCopy(val, unsafe.Pointer(nv.Pointer()))
+		return nv.Elem()
+	}
+}
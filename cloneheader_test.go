@@ -0,0 +1,53 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestCloneHeader(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(CloneHeader(nil), nil)
+
+	// Slices share backing array.
+	s := make([]int, 2, 4)
+	clonedSlice := CloneHeader(s).([]int)
+	clonedSlice[0] = 1
+	clonedSlice = append(clonedSlice, 2)
+	a.Equal(s, []int{1, 0})
+	a.Equal(s[:3], []int{1, 0, 2})
+	a.Equal(CloneHeader([]int(nil)), []int(nil))
+
+	// Maps share keys and values.
+	v := &[]int{1}
+	m := map[string]*[]int{"foo": v}
+	clonedMap := CloneHeader(m).(map[string]*[]int)
+	clonedMap["bar"] = nil
+	a.Equal(len(m), 1)
+	a.Assert(clonedMap["foo"] == v)
+	a.Equal(CloneHeader(map[string]int(nil)), map[string]int(nil))
+
+	// Pointers point to shadow copies.
+	type data struct {
+		unexported []int
+		Ptr        *int
+	}
+	n := 1
+	d := &data{
+		unexported: []int{1, 2},
+		Ptr:        &n,
+	}
+	clonedData := CloneHeader(d).(*data)
+	a.Assert(clonedData != d)
+	a.Assert(clonedData.Ptr == d.Ptr)
+	a.Assert(&clonedData.unexported[0] == &d.unexported[0])
+
+	// Structs are shadow copied.
+	a.Equal(CloneHeader(*d), *d)
+	a.Equal(CloneHeader("foo"), "foo")
+}
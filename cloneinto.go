@@ -0,0 +1,81 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// CloneInto recursively deep clones src into the value pointed by dst with memory allocated from heap.
+// The dst must be a non-nil pointer.
+// If src has the same type as dst, *src is cloned into *dst;
+// otherwise, src must be a value of dst's element type and it's cloned into *dst.
+// If src is nil, *dst is set to zero value.
+//
+// Structs and arrays are cloned into *dst in place,
+// so that the memory of *dst can be reused, e.g. a struct got from a sync.Pool.
+// All content in *dst is overwritten.
+//
+//	obj := pool.Get().(*T)
+//	clone.CloneInto(obj, orig) // orig is a *T.
+func CloneInto(dst, src interface{}) {
+	cloner.CloneInto(dst, src)
+}
+
+func cloneInto(allocator *Allocator, dst, src interface{}) {
+	to := reflect.ValueOf(dst)
+
+	if to.Kind() != reflect.Ptr || to.IsNil() {
+		panic(fmt.Errorf("go-clone: dst must be a non-nil pointer instead of `%T`", dst))
+	}
+
+	to = to.Elem()
+
+	if src == nil {
+		to.Set(reflect.Zero(to.Type()))
+		return
+	}
+
+	from := reflect.ValueOf(src)
+
+	if from.Type() == to.Addr().Type() {
+		if from.IsNil() {
+			to.Set(reflect.Zero(to.Type()))
+			return
+		}
+
+		from = from.Elem()
+	}
+
+	if from.Type() != to.Type() {
+		panic(fmt.Errorf("go-clone: cannot clone `%v` into `%v`", from.Type(), to.Type()))
+	}
+
+	allocator.cloneInto(from, to, false, false)
+}
+
+// CloneInto recursively deep clones src into dst with memory allocated from a.
+// The dst must be settable, e.g. `reflect.ValueOf(ptr).Elem()`, and its type must be src's type.
+// If src is invalid, dst is set to zero value.
+//
+// Structs and arrays are cloned into dst in place without allocating memory for the root value.
+// Like Clone, custom func of src's type is not called for src,
+// so that custom funcs can call `allocator.CloneInto(new, old)` to clone old in place.
+func (a *Allocator) CloneInto(dst, src reflect.Value) {
+	if !dst.CanSet() {
+		panic("go-clone: dst must be settable")
+	}
+
+	if !src.IsValid() {
+		dst.Set(reflect.Zero(dst.Type()))
+		return
+	}
+
+	if src.Type() != dst.Type() {
+		panic(fmt.Errorf("go-clone: cannot clone `%v` into `%v`", src.Type(), dst.Type()))
+	}
+
+	a.cloneInto(src, dst, false, true)
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type cloneIntoData struct {
+	Name   string
+	Values []int
+	Self   *cloneIntoData
+}
+
+func TestCloneInto(t *testing.T) {
+	a := assert.New(t)
+	orig := &cloneIntoData{
+		Name:   "orig",
+		Values: []int{1, 2, 3},
+	}
+	orig.Self = &cloneIntoData{Name: "child"}
+
+	// Clone *orig into dst.
+	dst := &cloneIntoData{Name: "dst", Values: []int{4}}
+	CloneInto(dst, orig)
+	a.Equal(dst.Name, "orig")
+	a.Equal(dst.Values, orig.Values)
+	a.Assert(&dst.Values[0] != &orig.Values[0])
+	a.Assert(dst.Self != orig.Self)
+	a.Equal(dst.Self, orig.Self)
+
+	// Clone value into dst.
+	dst = &cloneIntoData{}
+	CloneInto(dst, *orig)
+	a.Equal(dst.Values, orig.Values)
+	a.Assert(&dst.Values[0] != &orig.Values[0])
+
+	// Clone pointer into *dst.
+	var ptr *cloneIntoData
+	CloneInto(&ptr, orig)
+	a.Assert(ptr != orig)
+	a.Equal(ptr.Values, orig.Values)
+
+	// Nil src clears dst.
+	CloneInto(dst, nil)
+	a.Equal(*dst, cloneIntoData{})
+	dst.Name = "dst"
+	CloneInto(dst, (*cloneIntoData)(nil))
+	a.Equal(*dst, cloneIntoData{})
+
+	// Clone array into dst.
+	arr := [2][]int{{1}, {2}}
+	var clonedArr [2][]int
+	CloneInto(&clonedArr, &arr)
+	a.Equal(clonedArr, arr)
+	a.Assert(&clonedArr[0][0] != &arr[0][0])
+
+	a.Assert(cloneIntoPanics(func() { CloneInto(nil, orig) }))
+	a.Assert(cloneIntoPanics(func() { CloneInto(dst, 1) }))
+}
+
+func TestAllocatorCloneInto(t *testing.T) {
+	a := assert.New(t)
+	typeOfData := reflect.TypeOf(cloneIntoData{})
+	allocator := NewAllocator(nil, nil)
+	allocator.SetCustomFunc(typeOfData, func(allocator *Allocator, old, new reflect.Value) {
+		// Custom func can clone old in place.
+		allocator.CloneInto(new, old)
+		new.Field(0).SetString("custom")
+	})
+
+	orig := []cloneIntoData{{Name: "orig", Values: []int{1}}}
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().([]cloneIntoData)
+	a.Equal(cloned[0].Name, "custom")
+	a.Equal(cloned[0].Values, orig[0].Values)
+	a.Assert(&cloned[0].Values[0] != &orig[0].Values[0])
+
+	dst := &cloneIntoData{}
+	allocator.CloneInto(reflect.ValueOf(dst).Elem(), reflect.ValueOf(orig[0]))
+	a.Equal(dst.Name, "orig")
+
+	allocator.CloneInto(reflect.ValueOf(dst).Elem(), reflect.Value{})
+	a.Equal(*dst, cloneIntoData{})
+
+	a.Assert(cloneIntoPanics(func() { allocator.CloneInto(reflect.ValueOf(*dst), reflect.ValueOf(orig[0])) }))
+	a.Assert(cloneIntoPanics(func() { allocator.CloneInto(reflect.ValueOf(dst).Elem(), reflect.ValueOf(1)) }))
+}
+
+func TestCloneIntoAllocs(t *testing.T) {
+	a := assert.New(t)
+	orig := &cloneIntoData{Name: "orig"}
+	dst := &cloneIntoData{Values: []int{1}}
+	allocs := testing.AllocsPerRun(10, func() {
+		CloneInto(dst, orig)
+	})
+	a.Equal(allocs, float64(0))
+}
+
+func cloneIntoPanics(fn func()) (ok bool) {
+	defer func() {
+		ok = recover() != nil
+	}()
+	fn()
+	return
+}
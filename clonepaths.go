@@ -0,0 +1,221 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// ClonePaths shadow copies v and deep clones values of fields in paths in heap.
+//
+// See Allocator.ClonePaths for more details.
+func ClonePaths(v interface{}, paths ...string) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	return defaultAllocator.ClonePaths(reflect.ValueOf(v), paths...).Interface()
+}
+
+// ClonePaths makes a partially isolated copy of val with memory allocated from a.
+// Values of struct fields in paths are deep cloned,
+// and everything else in the copy is shadow copied from val.
+// Pointers, slices and maps leading to fields in paths are copied,
+// so that the copy can be modified along paths without changing val.
+//
+// A path is written in Go selector syntax relative to the root value like `.Spec.Containers`,
+// with all slice indexes, array indexes and map keys omitted like paths in Profile.
+// The leading dot can be omitted, e.g. `Metadata.Labels` is the same as `.Metadata.Labels`.
+// Paths not found in val are ignored. If any path is empty, val is deep cloned.
+//
+// It's designed to make cheap copies of large values, e.g. API objects,
+// when only a few fields in copies will be modified.
+// Fields in paths are cloned in the same way as Clone.
+// A pointer, slice or map reachable by several paths is copied only once.
+func (a *Allocator) ClonePaths(val reflect.Value, paths ...string) reflect.Value {
+	if !val.IsValid() {
+		return val
+	}
+
+	c := &pathCloner{
+		targets:  make(map[string]struct{}, len(paths)),
+		prefixes: map[string]struct{}{},
+		visited:  map[visit]reflect.Value{},
+	}
+
+	for _, path := range paths {
+		if path != "" && path[0] != '.' {
+			path = "." + path
+		}
+
+		if path == "" || path == "." {
+			return a.clone(val, false)
+		}
+
+		c.targets[path] = struct{}{}
+
+		for i := strings.LastIndexByte(path, '.'); i > 0; i = strings.LastIndexByte(path, '.') {
+			path = path[:i]
+			c.prefixes[path] = struct{}{}
+		}
+	}
+
+	if !val.CanInterface() {
+		val = forceClearROFlag(val)
+	}
+
+	root := reflect.New(val.Type()).Elem()
+	root.Set(val)
+
+	c.state = &cloneState{}
+	a.initCloneState(c.state, false)
+
+	if tx := c.state.tx; tx != nil {
+		c.state.tx = nil
+		defer tx.end()
+		c.copy(root, "")
+		c.state.drain()
+		c.state.rebind(root)
+		tx.commit()
+		return root
+	}
+
+	c.copy(root, "")
+	c.state.drain()
+	c.state.rebind(root)
+	return root
+}
+
+type pathCloner struct {
+	state    *cloneState
+	targets  map[string]struct{}
+	prefixes map[string]struct{}
+	visited  map[visit]reflect.Value
+}
+
+// copy copies values leading to fields in paths in v in place
+// and deep clones all fields in paths.
+// The v must be settable.
+func (c *pathCloner) copy(v reflect.Value, path string) {
+	switch v.Kind() {
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			c.copy(v.Index(i), path)
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		c.copy(elem, path)
+		v.Set(elem)
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+
+		vst := visit{
+			p: v.Pointer(),
+			t: v.Type(),
+		}
+
+		if nv, ok := c.visited[vst]; ok {
+			v.Set(nv)
+			return
+		}
+
+		t := v.Type()
+		nv := c.state.allocator.MakeMap(t, v.Len())
+		c.visited[vst] = nv
+
+		for iter := mapIter(v); iter.Next(); {
+			elem := reflect.New(t.Elem()).Elem()
+			elem.Set(iter.Value())
+			c.copy(elem, path)
+			nv.SetMapIndex(iter.Key(), elem)
+		}
+
+		v.Set(nv)
+	case reflect.Ptr:
+		if v.IsNil() || c.state.config.isOpaquePointer(v.Type()) {
+			return
+		}
+
+		vst := visit{
+			p: v.Pointer(),
+			t: v.Type(),
+		}
+
+		if nv, ok := c.visited[vst]; ok {
+			v.Set(nv)
+			return
+		}
+
+		nv := c.state.allocator.New(v.Type().Elem())
+		nv.Elem().Set(v.Elem())
+		c.visited[vst] = nv
+		c.copy(nv.Elem(), path)
+		v.Set(nv)
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+
+		vst := visit{
+			p:     v.Pointer(),
+			extra: v.Len(),
+			t:     v.Type(),
+		}
+
+		if nv, ok := c.visited[vst]; ok {
+			v.Set(nv)
+			return
+		}
+
+		nv := c.state.allocator.MakeSlice(v.Type(), v.Len(), v.Cap())
+		reflect.Copy(nv, v)
+		c.visited[vst] = nv
+
+		for i := 0; i < nv.Len(); i++ {
+			c.copy(nv.Index(i), path)
+		}
+
+		v.Set(nv)
+	case reflect.Struct:
+		t := v.Type()
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			fieldPath := path + "." + field.Name
+			_, isTarget := c.targets[fieldPath]
+			_, isPrefix := c.prefixes[fieldPath]
+
+			if !isTarget && !isPrefix {
+				continue
+			}
+
+			fv := v.Field(i)
+
+			if !fv.CanSet() {
+				fv = reflect.NewAt(field.Type, unsafe.Pointer(fv.UnsafeAddr())).Elem()
+			}
+
+			if isTarget {
+				// Targets are traced with paths given by caller.
+				if c.state.trace != nil {
+					c.state.path = fieldPath
+				}
+
+				shadowCopy(c.state.clone(fv), unsafe.Pointer(fv.UnsafeAddr()))
+				continue
+			}
+
+			c.copy(fv, fieldPath)
+		}
+	}
+}
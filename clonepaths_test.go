@@ -0,0 +1,82 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type clonePathsPod struct {
+	Metadata clonePathsMetadata
+	Spec     *clonePathsSpec
+	Status   map[string]string
+}
+
+type clonePathsMetadata struct {
+	Name   string
+	Labels map[string]string
+}
+
+type clonePathsSpec struct {
+	Containers []clonePathsContainer
+	Volumes    []string
+	nodes      []string
+}
+
+type clonePathsContainer struct {
+	Image string
+	Args  []string
+}
+
+func TestClonePaths(t *testing.T) {
+	a := assert.New(t)
+	orig := &clonePathsPod{
+		Metadata: clonePathsMetadata{
+			Name:   "pod",
+			Labels: map[string]string{"app": "web"},
+		},
+		Spec: &clonePathsSpec{
+			Containers: []clonePathsContainer{
+				{Image: "nginx", Args: []string{"-g"}},
+			},
+			Volumes: []string{"data"},
+			nodes:   []string{"node1"},
+		},
+		Status: map[string]string{"phase": "running"},
+	}
+	cloned := ClonePaths(orig, "Spec.Containers", ".Metadata.Labels", ".Spec.nodes", ".Not.Found").(*clonePathsPod)
+	a.Equal(cloned, orig)
+
+	// Values leading to paths are copied.
+	a.Assert(cloned != orig)
+	a.Assert(cloned.Spec != orig.Spec)
+
+	// Values in paths are deep cloned.
+	a.Assert(&cloned.Spec.Containers[0] != &orig.Spec.Containers[0])
+	a.Assert(&cloned.Spec.Containers[0].Args[0] != &orig.Spec.Containers[0].Args[0])
+	a.Assert(&cloned.Spec.nodes[0] != &orig.Spec.nodes[0])
+	cloned.Metadata.Labels["app"] = "api"
+	a.Equal(orig.Metadata.Labels["app"], "web")
+
+	// Other values are shadow copied.
+	a.Assert(&cloned.Spec.Volumes[0] == &orig.Spec.Volumes[0])
+	a.Assert(reflect.ValueOf(cloned.Status).Pointer() == reflect.ValueOf(orig.Status).Pointer())
+
+	// Fields in slices are in paths without indexes.
+	pods := []*clonePathsPod{orig}
+	clonedPods := ClonePaths(pods, "Spec.Volumes").([]*clonePathsPod)
+	a.Assert(clonedPods[0] != orig)
+	a.Assert(&clonedPods[0].Spec.Volumes[0] != &orig.Spec.Volumes[0])
+	a.Assert(&clonedPods[0].Spec.Containers[0] == &orig.Spec.Containers[0])
+
+	// An empty path means the root.
+	cloned = ClonePaths(orig, "").(*clonePathsPod)
+	a.Equal(cloned, orig)
+	a.Assert(reflect.ValueOf(cloned.Status).Pointer() != reflect.ValueOf(orig.Status).Pointer())
+
+	a.Equal(ClonePaths(nil, "Spec"), nil)
+}
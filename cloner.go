@@ -25,3 +25,9 @@ func (c Cloner) Clone(v interface{}) interface{} {
 func (c Cloner) CloneSlowly(v interface{}) interface{} {
 	return cloneSlowly(c.allocator, v)
 }
+
+// CloneInto clones src into the value pointed by dst with given allocator.
+// See CloneInto for more details.
+func (c Cloner) CloneInto(dst, src interface{}) {
+	cloneInto(c.allocator, dst, src)
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import "reflect"
+
+// Names of methods used to clone values when cloner interface is used.
+// The first method found in a type is used.
+var clonerMethodNames = []string{"Clone", "DeepCopy"}
+
+// UseClonerInterface enables or disables cloner interface in heap allocator.
+//
+// See Allocator.UseClonerInterface for more details.
+func UseClonerInterface(enabled bool) {
+	defaultAllocator.UseClonerInterface(enabled)
+}
+
+// UseClonerInterface enables or disables cloner interface in a.
+// If it's not set, a inherits it from parent allocator.
+// Cloner interface is disabled in the default allocator.
+//
+// When cloner interface is enabled, a value of type T is cloned by calling its method
+// `Clone() T` or `DeepCopy() T` if any, instead of cloning it by reflection.
+// A struct T can also be cloned by the method `Clone() *T` or `DeepCopy() *T` of *T.
+// It's designed for third-party types which know how to copy themselves,
+// e.g. `http.Header` or types generated by deepcopy-gen.
+//
+// Custom funcs and scalar marks win cloner methods.
+// Nil pointers are never passed to cloner methods.
+// Like custom funcs, the cloner method of the value passed to Allocator.Clone is not called,
+// so that cloner methods can call Allocator.Clone to clone themselves.
+func (a *Allocator) UseClonerInterface(enabled bool) {
+	option := optionDisabled
+
+	if enabled {
+		option = optionEnabled
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.useCloner = option
+		return copied
+	})
+}
+
+func (cfg *config) isUsingCloner() bool {
+	for current := cfg; current != nil; current = current.parent {
+		switch current.useCloner {
+		case optionEnabled:
+			return true
+		case optionDisabled:
+			return false
+		}
+	}
+
+	return false
+}
+
+// lookupClonerMethod returns the index of the cloner method of t
+// which has no argument and returns a value of t.
+// The index is cached in the type cache of cfg.
+func (cfg *config) lookupClonerMethod(t reflect.Type) (index int, ok bool) {
+	cache := &cfg.cache().clonerMethods
+
+	if v, found := cache.load(t); found {
+		index = v.(int)
+		ok = index >= 0
+		return
+	}
+
+	index = -1
+
+	for _, name := range clonerMethodNames {
+		m, found := t.MethodByName(name)
+
+		if !found || m.Type.NumIn() != 1 || m.Type.NumOut() != 1 || m.Type.Out(0) != t {
+			continue
+		}
+
+		index = m.Index
+		break
+	}
+
+	cache.store(t, index)
+	ok = index >= 0
+	return
+}
+
+// cloneByMethod clones non-struct value v by its cloner method.
+// In read-only memory mode, the method is called on a copy of v.
+func (state *cloneState) cloneByMethod(v reflect.Value) (cloned reflect.Value, ok bool) {
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return
+	}
+
+	index, ok := state.config.lookupClonerMethod(v.Type())
+
+	if !ok {
+		return
+	}
+
+	if state.readOnlyMem && v.CanAddr() {
+		v = detachValue(v)
+	} else if !v.CanInterface() {
+		v = forceClearROFlag(v)
+	}
+
+	cloned = v.Method(index).Call(nil)[0]
+	return
+}
+
+// clonerMethodFunc returns a custom func calling the cloner method of struct type t or *t.
+// If there is no cloner method, it returns nil.
+func (cfg *config) clonerMethodFunc(t reflect.Type) Func {
+	if index, ok := cfg.lookupClonerMethod(t); ok {
+		return func(allocator *Allocator, old, new reflect.Value) {
+			new.Set(old.Method(index).Call(nil)[0])
+		}
+	}
+
+	if index, ok := cfg.lookupClonerMethod(reflect.PtrTo(t)); ok {
+		return func(allocator *Allocator, old, new reflect.Value) {
+			if cloned := old.Addr().Method(index).Call(nil)[0]; !cloned.IsNil() {
+				new.Set(cloned.Elem())
+			}
+		}
+	}
+
+	return nil
+}
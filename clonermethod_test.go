@@ -0,0 +1,96 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type clonerValue struct {
+	Values []int
+	calls  int
+}
+
+func (v clonerValue) Clone() clonerValue {
+	return clonerValue{
+		Values: append([]int{}, v.Values...),
+		calls:  v.calls + 1,
+	}
+}
+
+type clonerPtr struct {
+	Name   string
+	copied bool
+}
+
+func (p *clonerPtr) DeepCopy() *clonerPtr {
+	return &clonerPtr{
+		Name:   p.Name,
+		copied: true,
+	}
+}
+
+type clonerHolder struct {
+	Value  clonerValue
+	Ptr    *clonerPtr
+	Inline clonerPtr
+	Header http.Header
+	Nil    *clonerPtr
+}
+
+func TestUseClonerInterface(t *testing.T) {
+	a := assert.New(t)
+	orig := &clonerHolder{
+		Value:  clonerValue{Values: []int{1}},
+		Ptr:    &clonerPtr{Name: "ptr"},
+		Inline: clonerPtr{Name: "inline"},
+		Header: http.Header{"Foo": {"bar"}},
+	}
+
+	// Disabled by default.
+	allocator := NewAllocator(nil, nil)
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*clonerHolder)
+	a.Equal(cloned.Value.calls, 0)
+	a.Assert(!cloned.Ptr.copied)
+	a.Assert(!cloned.Inline.copied)
+
+	allocator.UseClonerInterface(true)
+	cloned = allocator.Clone(reflect.ValueOf(orig)).Interface().(*clonerHolder)
+	a.Equal(cloned.Value.calls, 1)
+	a.Equal(cloned.Value.Values, orig.Value.Values)
+	a.Assert(&cloned.Value.Values[0] != &orig.Value.Values[0])
+	a.Assert(cloned.Ptr.copied)
+	a.Equal(cloned.Ptr.Name, "ptr")
+	a.Assert(cloned.Inline.copied)
+	a.Equal(cloned.Header, orig.Header)
+	a.Assert(cloned.Nil == nil)
+
+	// Cloner method of root value is not called.
+	clonedValue := allocator.Clone(reflect.ValueOf(orig.Value)).Interface().(clonerValue)
+	a.Equal(clonedValue.calls, 0)
+
+	// Custom funcs win cloner methods.
+	allocator.SetCustomFunc(reflect.TypeOf(clonerValue{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.Field(0).Set(reflect.ValueOf([]int{100}))
+	})
+	cloned = allocator.Clone(reflect.ValueOf(orig)).Interface().(*clonerHolder)
+	a.Equal(cloned.Value.calls, 0)
+	a.Equal(cloned.Value.Values, []int{100})
+
+	// Child allocators inherit the option.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().(*clonerHolder)
+	a.Assert(cloned.Ptr.copied)
+
+	child.UseClonerInterface(false)
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().(*clonerHolder)
+	a.Assert(!cloned.Ptr.copied)
+	a.Assert(!cloned.Inline.copied)
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Package clonetest provides helpers to test clone results, e.g. to validate custom clone funcs.
+package clonetest
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// Codec encodes and decodes values.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Built-in codecs.
+var (
+	JSON Codec = jsonCodec{}
+	Gob  Codec = gobCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	if err := gob.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// AssertSemanticEqual asserts that a and b are equal after a round trip in codec.
+// Both a and b are encoded by codec and decoded to new values of their type,
+// and then the decoded values are compared by reflect.DeepEqual.
+//
+// It's a weaker equality than comparing a and b directly.
+// Everything not encoded by codec, e.g. unexported fields, is ignored,
+// so that it's useful to compare a clone made by a custom func, which may not copy
+// internal states, with its original value.
+//
+// If codec is nil, JSON is used.
+// It reports an error to t and returns false if a and b are not equal or codec fails.
+func AssertSemanticEqual(t testing.TB, a, b interface{}, codec Codec) bool {
+	t.Helper()
+
+	if codec == nil {
+		codec = JSON
+	}
+
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+
+	if ta != tb {
+		t.Errorf("clonetest: types are different: `%v` and `%v`", ta, tb)
+		return false
+	}
+
+	if a == nil {
+		return true
+	}
+
+	da, dataA, err := roundTrip(codec, a)
+
+	if err != nil {
+		t.Errorf("clonetest: fail to round trip a: %v", err)
+		return false
+	}
+
+	db, dataB, err := roundTrip(codec, b)
+
+	if err != nil {
+		t.Errorf("clonetest: fail to round trip b: %v", err)
+		return false
+	}
+
+	if !reflect.DeepEqual(da, db) {
+		t.Errorf("clonetest: values are not semantically equal.\na: %s\nb: %s", dataA, dataB)
+		return false
+	}
+
+	return true
+}
+
+func roundTrip(codec Codec, v interface{}) (decoded interface{}, data []byte, err error) {
+	data, err = codec.Marshal(v)
+
+	if err != nil {
+		return
+	}
+
+	ptr := reflect.New(reflect.TypeOf(v))
+
+	if err = codec.Unmarshal(data, ptr.Interface()); err != nil {
+		return
+	}
+
+	decoded = ptr.Elem().Interface()
+	return
+}
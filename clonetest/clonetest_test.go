@@ -0,0 +1,61 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clonetest
+
+import (
+	"testing"
+
+	"github.com/huandu/go-assert"
+	"github.com/huandu/go-clone"
+)
+
+type data struct {
+	Name   string
+	Values map[string][]int
+	cache  []int
+}
+
+type recorder struct {
+	testing.TB
+	errors int
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors++
+}
+
+func TestAssertSemanticEqual(t *testing.T) {
+	a := assert.New(t)
+	orig := &data{
+		Name: "foo",
+		Values: map[string][]int{
+			"a": {1, 2},
+			"b": {3},
+			"c": {4, 5, 6},
+		},
+		cache: []int{1},
+	}
+	cloned := clone.Clone(orig).(*data)
+	cloned.cache = nil
+
+	for _, codec := range []Codec{nil, JSON, Gob} {
+		r := &recorder{TB: t}
+		a.Assert(AssertSemanticEqual(r, orig, cloned, codec))
+		a.Equal(r.errors, 0)
+	}
+
+	cloned.Values["b"][0] = 30
+
+	for _, codec := range []Codec{JSON, Gob} {
+		r := &recorder{TB: t}
+		a.Assert(!AssertSemanticEqual(r, orig, cloned, codec))
+		a.Equal(r.errors, 1)
+	}
+
+	r := &recorder{TB: t}
+	a.Assert(!AssertSemanticEqual(r, orig, *orig, nil))
+	a.Assert(!AssertSemanticEqual(r, make(chan int), make(chan int), nil))
+	a.Equal(r.errors, 2)
+	a.Assert(AssertSemanticEqual(r, nil, nil, nil))
+}
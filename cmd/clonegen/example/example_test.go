@@ -0,0 +1,104 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package example
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+	"github.com/huandu/go-clone"
+)
+
+func newConfig() *Config {
+	timeout := time.Second
+	label := "label"
+	token := "token"
+	server := &Server{
+		Addr:    "127.0.0.1:80",
+		Weights: []float64{1, 2},
+		Next:    &Server{Addr: "127.0.0.1:81"},
+	}
+	c := &Config{
+		Name:    "config",
+		Created: time.Now(),
+		Timeout: &timeout,
+		Servers: []*Server{server, nil},
+		Backup:  server.Next,
+		Primary: *server,
+		Ports:   [2]int{80, 81},
+		Routes: map[string][]Server{
+			"/": {*server},
+		},
+		Labels: Labels{"foo": &label},
+		Events: make(chan string, 4),
+		Any:    &Server{Addr: "any"},
+		Err:    errors.New("error"),
+		Token:  Token{Value: &token},
+		Secret: &Secret{Key: []byte("key")},
+		cache:  map[string]string{"foo": "bar"},
+	}
+	c.Inline.Hosts = []string{"localhost"}
+	c.Owner = c
+	c.BuildIndex()
+	return c
+}
+
+func TestCloneDeep(t *testing.T) {
+	a := assert.New(t)
+	c := newConfig()
+	cloned := c.CloneDeep()
+	expected := clone.Clone(c).(*Config)
+
+	// Chans are always different.
+	a.Equal(cap(cloned.Events), cap(c.Events))
+	a.Assert(cloned.Events != c.Events)
+	cloned.Events = nil
+	expected.Events = nil
+	a.Equal(cloned, expected)
+
+	a.Assert(cloned.Timeout != c.Timeout)
+	a.Assert(cloned.Servers[0] != c.Servers[0])
+	a.Assert(&cloned.Servers[0].Weights[0] != &c.Servers[0].Weights[0])
+	a.Assert(cloned.Servers[0].Next != c.Servers[0].Next)
+	a.Assert(cloned.Backup != c.Backup)
+	a.Assert(&cloned.Primary.Weights[0] != &c.Primary.Weights[0])
+	a.Assert(&cloned.Routes["/"][0] != &c.Routes["/"][0])
+	a.Assert(cloned.Labels["foo"] != c.Labels["foo"])
+	a.Assert(cloned.Any.(*Server) != c.Any.(*Server))
+	a.Assert(cloned.Secret != c.Secret)
+	a.Assert(&cloned.Secret.Key[0] != &c.Secret.Key[0])
+	a.Assert(&cloned.Inline.Hosts[0] != &c.Inline.Hosts[0])
+
+	// Scalars and fields tagged with `clone:"shadowcopy"` are shared.
+	a.Assert(cloned.Token.Value == c.Token.Value)
+	a.Assert(cloned.Owner == c)
+
+	// Fields tagged with `clone:"skip"` are zero and init methods are called.
+	a.Equal(cloned.cache, nil)
+	a.Equal(cloned.index, c.index)
+
+	var nilConfig *Config
+	a.Equal(nilConfig.CloneDeep(), nil)
+	a.Equal((&Config{}).CloneDeep(), &Config{index: map[string]int{}})
+}
+
+func BenchmarkCloneDeep(b *testing.B) {
+	c := newConfig()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.CloneDeep()
+	}
+}
+
+func BenchmarkClone(b *testing.B) {
+	c := newConfig()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		clone.Clone(c)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Package example shows code generated by clonegen.
+package example
+
+import (
+	"time"
+)
+
+//go:generate go run github.com/huandu/go-clone/cmd/clonegen
+
+// Config is a config with all kinds of fields.
+//
+//clonegen:generate
+type Config struct {
+	Name     string
+	Created  time.Time
+	Timeout  *time.Duration
+	Servers  []*Server
+	Backup   *Server
+	Primary  Server
+	Ports    [2]int
+	Routes   map[string][]Server
+	Labels   Labels
+	Events   chan string
+	Any      interface{}
+	Err      error
+	Token    Token
+	Secret   *Secret
+	Inline   struct{ Hosts []string }
+	OnChange func()
+
+	cache map[string]string `clone:"skip"`
+	Owner *Config           `clone:"shadowcopy"`
+	index map[string]int    `clone:"init=BuildIndex"`
+}
+
+// Server is a server.
+//
+//clonegen:generate
+type Server struct {
+	Addr    string
+	Weights []float64
+	Next    *Server
+}
+
+// Labels are labels of a config.
+type Labels map[string]*string
+
+// Token is copied by value.
+//
+//clonegen:scalar
+type Token struct {
+	Value *string
+}
+
+// Secret is cloned by clone.Clone.
+//
+//clonegen:custom
+type Secret struct {
+	Key []byte
+}
+
+// BuildIndex builds the index of servers.
+func (c *Config) BuildIndex() {
+	c.index = make(map[string]int, len(c.Servers))
+
+	for i, s := range c.Servers {
+		if s != nil {
+			c.index[s.Addr] = i
+		}
+	}
+}
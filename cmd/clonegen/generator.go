@@ -0,0 +1,645 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/huandu/go-clone"
+)
+
+const clonePackagePath = "github.com/huandu/go-clone"
+
+// Directives in doc comments of type declarations.
+const (
+	directiveGenerate = "//clonegen:generate"
+	directiveScalar   = "//clonegen:scalar"
+	directiveCustom   = "//clonegen:custom"
+)
+
+// Well-known types defined in other packages which are copied by value,
+// including types marked as scalar by default in package clone.
+var externalScalars = map[string]bool{
+	"time.Time":     true,
+	"time.Duration": true,
+	"time.Month":    true,
+	"time.Weekday":  true,
+	"reflect.Value": true,
+	"reflect.Kind":  true,
+}
+
+var basicTypes = map[string]bool{
+	"bool": true, "string": true, "byte": true, "rune": true, "uintptr": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true, "complex64": true, "complex128": true,
+}
+
+type typeDecl struct {
+	name     string
+	expr     ast.Expr
+	generate bool
+	scalar   bool
+	custom   bool
+}
+
+type generator struct {
+	fset    *token.FileSet
+	pkg     string
+	decls   map[string]*typeDecl
+	order   []*typeDecl
+	imports map[string]string // Import paths of package names used in source files.
+	used    map[string]bool   // Package names used by generated code.
+
+	helpers map[string]bool // Types which have cloneDeepInto methods generated or pending.
+	pending []*typeDecl
+	works   map[string]bool // Cache of needsWork for declared types.
+
+	buf   bytes.Buffer
+	depth int
+	zeros int
+	err   error
+}
+
+// generate generates deep clone methods for types annotated in the package in dir.
+// The output file is excluded from source files.
+func generate(dir, output string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		name := fi.Name()
+		return name != output && !strings.HasSuffix(name, "_test.go")
+	}, parser.ParseComments)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("clonegen: expect one package in `%v` but found %v", dir, len(pkgs))
+	}
+
+	g := &generator{
+		fset:    fset,
+		decls:   map[string]*typeDecl{},
+		imports: map[string]string{},
+		used:    map[string]bool{},
+		helpers: map[string]bool{},
+		works:   map[string]bool{},
+	}
+
+	for name, pkg := range pkgs {
+		g.pkg = name
+
+		if err := g.parse(pkg); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.generate()
+}
+
+func (g *generator) parse(pkg *ast.Package) error {
+	names := make([]string, 0, len(pkg.Files))
+
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		file := pkg.Files[name]
+
+		for _, spec := range file.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			pkgName := path[strings.LastIndexByte(path, '/')+1:]
+
+			if spec.Name != nil {
+				pkgName = spec.Name.Name
+			}
+
+			if old, ok := g.imports[pkgName]; ok && old != path {
+				return fmt.Errorf("clonegen: package name `%v` refers to both `%v` and `%v`", pkgName, old, path)
+			}
+
+			g.imports[pkgName] = path
+		}
+
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				doc := ts.Doc
+
+				if doc == nil && len(gd.Specs) == 1 {
+					doc = gd.Doc
+				}
+
+				td := &typeDecl{
+					name: ts.Name.Name,
+					expr: ts.Type,
+				}
+
+				if doc != nil {
+					for _, c := range doc.List {
+						switch strings.TrimSpace(c.Text) {
+						case directiveGenerate:
+							td.generate = true
+						case directiveScalar:
+							td.scalar = true
+						case directiveCustom:
+							td.custom = true
+						}
+					}
+				}
+
+				g.decls[td.name] = td
+				g.order = append(g.order, td)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (g *generator) generate() ([]byte, error) {
+	body := &g.buf
+
+	for _, td := range g.order {
+		if !td.generate {
+			continue
+		}
+
+		if _, ok := td.expr.(*ast.StructType); !ok {
+			return nil, fmt.Errorf("clonegen: type `%v` is not a struct type", td.name)
+		}
+
+		fmt.Fprintf(body, "// CloneDeep returns a deep clone of t.\n")
+		fmt.Fprintf(body, "func (t *%v) CloneDeep() *%v {\n", td.name, td.name)
+		fmt.Fprintf(body, "if t == nil {\nreturn nil\n}\n\n")
+		fmt.Fprintf(body, "c := new(%v)\n*c = *t\n", td.name)
+
+		if g.needsWork(td.expr) && !td.scalar {
+			if td.custom {
+				g.cloneByRuntime("*c", "*t", td.name)
+			} else {
+				g.requireHelper(td)
+				fmt.Fprintf(body, "t.cloneDeepInto(c)\n")
+			}
+		}
+
+		fmt.Fprintf(body, "return c\n}\n\n")
+	}
+
+	for len(g.pending) != 0 {
+		td := g.pending[0]
+		g.pending = g.pending[1:]
+		g.helper(td)
+	}
+
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by clonegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(out, "package %v\n\n", g.pkg)
+
+	if len(g.used) != 0 {
+		var std, others []string
+
+		for name := range g.used {
+			path, ok := g.imports[name]
+
+			if name == "clone" {
+				if ok && path != clonePackagePath {
+					return nil, fmt.Errorf("clonegen: package name `clone` refers to `%v`", path)
+				}
+
+				path = clonePackagePath
+			}
+
+			spec := strconv.Quote(path)
+
+			if path != clonePackagePath && path[strings.LastIndexByte(path, '/')+1:] != name {
+				spec = name + " " + spec
+			}
+
+			// Standard packages have no dot in the first path element.
+			if first := strings.SplitN(path, "/", 2)[0]; strings.Contains(first, ".") {
+				others = append(others, spec)
+			} else {
+				std = append(std, spec)
+			}
+		}
+
+		sort.Strings(std)
+		sort.Strings(others)
+		groups := std
+
+		if len(std) != 0 && len(others) != 0 {
+			groups = append(groups, "")
+		}
+
+		groups = append(groups, others...)
+		fmt.Fprintf(out, "import (\n%v\n)\n\n", strings.Join(groups, "\n"))
+	}
+
+	out.Write(body.Bytes())
+	return format.Source(out.Bytes())
+}
+
+// requireHelper makes sure that the cloneDeepInto method of td is generated.
+func (g *generator) requireHelper(td *typeDecl) {
+	if g.helpers[td.name] {
+		return
+	}
+
+	g.helpers[td.name] = true
+	g.pending = append(g.pending, td)
+}
+
+// helper generates the cloneDeepInto method of td.
+// The method clones all values referenced by t into c, which is a shadow copy of t.
+func (g *generator) helper(td *typeDecl) {
+	body := &g.buf
+	fmt.Fprintf(body, "func (t *%v) cloneDeepInto(c *%v) {\n", td.name, td.name)
+
+	if st, ok := td.expr.(*ast.StructType); ok {
+		// Fields can be selected by pointers directly.
+		g.cloneStructFields(td.name, st, "c", "t")
+	} else {
+		g.cloneValue("*c", "*t", td.name, td.expr)
+	}
+
+	fmt.Fprintf(body, "}\n\n")
+}
+
+// cloneStructFields clones fields of struct st from src to dst.
+// A struct tagged with `clone:"init=methodName"` must be a declared type named owner.
+func (g *generator) cloneStructFields(owner string, st *ast.StructType, dst, src string) {
+	body := &g.buf
+	var zeroFields []string
+	var initMethods []string
+
+	for _, field := range st.Fields.List {
+		names := fieldNames(field)
+		var policy clone.FieldPolicy
+
+		if field.Tag != nil {
+			tag, _ := strconv.Unquote(field.Tag.Value)
+			p, err := clone.ParseFieldTag(reflect.StructTag(tag))
+
+			if err != nil {
+				g.fail(fmt.Errorf("clonegen: invalid tag of field `%v.%v`: %v", owner, names[0], err))
+				return
+			}
+
+			policy = p
+		}
+
+		switch policy.Action {
+		case clone.FieldDeepClone:
+			for _, name := range names {
+				g.cloneValue(selector(dst, name), selector(src, name), g.typeString(field.Type), field.Type)
+			}
+		case clone.FieldSkip, clone.FieldZero, clone.FieldRebind:
+			zeroFields = append(zeroFields, names...)
+		case clone.FieldInit:
+			zeroFields = append(zeroFields, names...)
+			initMethods = append(initMethods, policy.Arg)
+		case clone.FieldShadowCopy:
+			// Shadow copied already.
+		default:
+			g.fail(fmt.Errorf("clonegen: tag verb `%v` of field `%v.%v` is not supported", policy.Verb, owner, names[0]))
+			return
+		}
+	}
+
+	if len(zeroFields) != 0 {
+		// Inline structs may be in the same scope of their parents.
+		g.zeros++
+		zero := "zero" + strconv.Itoa(g.zeros)
+		fmt.Fprintf(body, "var %v %v\n", zero, owner)
+
+		for _, name := range zeroFields {
+			fmt.Fprintf(body, "%v = %v.%v\n", selector(dst, name), zero, name)
+		}
+	}
+
+	for _, method := range initMethods {
+		fmt.Fprintf(body, "%v()\n", selector(dst, method))
+	}
+}
+
+// cloneValue clones src to dst, which is a shadow copy of src.
+// The typeName is the name of t written in generated code.
+func (g *generator) cloneValue(dst, src, typeName string, t ast.Expr) {
+	if !g.needsWork(t) {
+		return
+	}
+
+	body := &g.buf
+	g.depth++
+	defer func() {
+		g.depth--
+	}()
+
+	switch t := t.(type) {
+	case *ast.ParenExpr:
+		g.cloneValue(dst, src, typeName, t.X)
+	case *ast.Ident:
+		td, ok := g.decls[t.Name]
+
+		if !ok {
+			// Predeclared interfaces, e.g. error.
+			g.cloneByRuntime(dst, src, typeName)
+			return
+		}
+
+		switch td.expr.(type) {
+		case *ast.Ident, *ast.SelectorExpr, *ast.InterfaceType:
+			// Methods of the underlying type are not available.
+			g.cloneByRuntime(dst, src, typeName)
+			return
+		case *ast.StarExpr:
+			if td.custom || !g.isLocal(td.expr) {
+				g.cloneByRuntime(dst, src, typeName)
+				return
+			}
+
+			g.cloneValue(dst, src, typeName, td.expr)
+			return
+		}
+
+		if td.custom {
+			g.cloneByRuntime(dst, src, typeName)
+			return
+		}
+
+		// Methods can be called by pointers directly.
+		recv := src
+
+		if strings.HasPrefix(recv, "*") {
+			recv = recv[1:]
+		}
+
+		g.requireHelper(td)
+		fmt.Fprintf(body, "%v.cloneDeepInto(%v)\n", recv, address(dst))
+	case *ast.StarExpr:
+		if sel, ok := t.X.(*ast.SelectorExpr); ok && !g.isExternalScalar(sel) {
+			// Pointers to types defined in other packages can be opaque pointers.
+			// Leave them to runtime.
+			g.cloneByRuntime(dst, src, typeName)
+			return
+		}
+
+		n := g.name("n")
+		fmt.Fprintf(body, "if %v != nil {\n", src)
+		fmt.Fprintf(body, "%v := new(%v)\n", n, g.typeString(t.X))
+		fmt.Fprintf(body, "*%v = *%v\n", n, src)
+		g.cloneValue("*"+n, "*"+src, g.typeString(t.X), t.X)
+		fmt.Fprintf(body, "%v = %v\n}\n", dst, n)
+	case *ast.ArrayType:
+		i := g.name("i")
+
+		if t.Len != nil {
+			fmt.Fprintf(body, "for %v := range %v {\n", i, src)
+			g.cloneValue(index(dst, i), index(src, i), g.typeString(t.Elt), t.Elt)
+			fmt.Fprintf(body, "}\n")
+			return
+		}
+
+		s := g.name("s")
+		fmt.Fprintf(body, "if %v != nil {\n", src)
+		fmt.Fprintf(body, "%v := make(%v, len(%v), cap(%v))\n", s, typeName, src, src)
+		fmt.Fprintf(body, "copy(%v, %v)\n", s, src)
+
+		if g.needsWork(t.Elt) {
+			fmt.Fprintf(body, "for %v := range %v {\n", i, s)
+			g.cloneValue(index(s, i), index(s, i), g.typeString(t.Elt), t.Elt)
+			fmt.Fprintf(body, "}\n")
+		}
+
+		fmt.Fprintf(body, "%v = %v\n}\n", dst, s)
+	case *ast.MapType:
+		m := g.name("m")
+		k := g.name("k")
+		v := g.name("v")
+		fmt.Fprintf(body, "if %v != nil {\n", src)
+		fmt.Fprintf(body, "%v := make(%v, len(%v))\n", m, typeName, src)
+		fmt.Fprintf(body, "for %v, %v := range %v {\n", k, v, src)
+		g.cloneValue(k, k, g.typeString(t.Key), t.Key)
+		g.cloneValue(v, v, g.typeString(t.Value), t.Value)
+		fmt.Fprintf(body, "%v[%v] = %v\n}\n", m, k, v)
+		fmt.Fprintf(body, "%v = %v\n}\n", dst, m)
+	case *ast.ChanType:
+		fmt.Fprintf(body, "if %v != nil {\n", src)
+		fmt.Fprintf(body, "%v = make(%v, cap(%v))\n}\n", dst, typeName, src)
+	case *ast.StructType:
+		g.cloneStructFields(typeName, t, dst, src)
+	default:
+		g.cloneByRuntime(dst, src, typeName)
+	}
+}
+
+// cloneByRuntime clones src to dst by clone.Clone.
+func (g *generator) cloneByRuntime(dst, src, typeName string) {
+	body := &g.buf
+	v := g.name("v")
+	g.used["clone"] = true
+	fmt.Fprintf(body, "if %v := clone.Clone(%v); %v != nil {\n", v, src, v)
+
+	if typeName == "interface{}" || typeName == "any" {
+		fmt.Fprintf(body, "%v = %v\n}\n", dst, v)
+		return
+	}
+
+	fmt.Fprintf(body, "%v = %v.(%v)\n}\n", dst, v, typeName)
+}
+
+// needsWork returns true if a clone of any value of t is not a shadow copy of it.
+func (g *generator) needsWork(t ast.Expr) bool {
+	switch t := t.(type) {
+	case *ast.ParenExpr:
+		return g.needsWork(t.X)
+	case *ast.Ident:
+		if basicTypes[t.Name] {
+			return false
+		}
+
+		td, ok := g.decls[t.Name]
+
+		if !ok {
+			// Predeclared interfaces, e.g. error.
+			return true
+		}
+
+		if td.scalar {
+			return false
+		}
+
+		if td.custom {
+			return true
+		}
+
+		if works, ok := g.works[td.name]; ok {
+			return works
+		}
+
+		// A type cannot contain itself except through pointers, slices or maps.
+		g.works[td.name] = false
+		works := g.needsWork(td.expr)
+		g.works[td.name] = works
+		return works
+	case *ast.SelectorExpr:
+		return !g.isExternalScalar(t)
+	case *ast.ArrayType:
+		return t.Len == nil || g.needsWork(t.Elt)
+	case *ast.StructType:
+		for _, field := range t.Fields.List {
+			if field.Tag != nil {
+				return true
+			}
+
+			if g.needsWork(field.Type) {
+				return true
+			}
+		}
+
+		return false
+	case *ast.FuncType:
+		return false
+	}
+
+	// Pointers, maps, chans, interfaces and unsupported types.
+	return true
+}
+
+// isExternalScalar returns true if sel is a well-known scalar type defined in other packages.
+func (g *generator) isExternalScalar(sel *ast.SelectorExpr) bool {
+	pkg, ok := sel.X.(*ast.Ident)
+
+	if !ok {
+		return false
+	}
+
+	path := g.imports[pkg.Name]
+
+	if path == "unsafe" && sel.Sel.Name == "Pointer" {
+		return true
+	}
+
+	return externalScalars[path+"."+sel.Sel.Name]
+}
+
+// isLocal returns true if t refers to types defined in this package only.
+func (g *generator) isLocal(t ast.Expr) bool {
+	switch t := t.(type) {
+	case *ast.SelectorExpr:
+		return g.isExternalScalar(t)
+	case *ast.StarExpr:
+		return g.isLocal(t.X)
+	}
+
+	return true
+}
+
+func (g *generator) typeString(t ast.Expr) string {
+	ast.Inspect(t, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if pkg, ok := sel.X.(*ast.Ident); ok {
+				g.used[pkg.Name] = true
+			}
+
+			return false
+		}
+
+		return true
+	})
+
+	buf := &bytes.Buffer{}
+	printer.Fprint(buf, g.fset, t)
+	return buf.String()
+}
+
+// name returns a unique variable name in current depth.
+func (g *generator) name(prefix string) string {
+	return prefix + strconv.Itoa(g.depth)
+}
+
+func (g *generator) fail(err error) {
+	if g.err == nil {
+		g.err = err
+	}
+}
+
+// fieldNames returns names of field.
+// The name of an embedded field is its type name.
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) != 0 {
+		names := make([]string, 0, len(field.Names))
+
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+
+		return names
+	}
+
+	t := field.Type
+
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+
+	if sel, ok := t.(*ast.SelectorExpr); ok {
+		return []string{sel.Sel.Name}
+	}
+
+	return []string{t.(*ast.Ident).Name}
+}
+
+// selector returns the expression to select field name in x.
+func selector(x, name string) string {
+	if strings.HasPrefix(x, "*") {
+		x = "(" + x + ")"
+	}
+
+	return x + "." + name
+}
+
+// index returns the expression to index x by i.
+func index(x, i string) string {
+	if strings.HasPrefix(x, "*") {
+		x = "(" + x + ")"
+	}
+
+	return x + "[" + i + "]"
+}
+
+// address returns the expression to take the address of x.
+func address(x string) string {
+	if strings.HasPrefix(x, "*") {
+		return x[1:]
+	}
+
+	return "&" + x
+}
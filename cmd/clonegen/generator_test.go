@@ -0,0 +1,69 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestGenerateExample(t *testing.T) {
+	a := assert.New(t)
+	src, err := generate("example", defaultOutput)
+	a.NilError(err)
+
+	expected, err := ioutil.ReadFile(filepath.Join("example", defaultOutput))
+	a.NilError(err)
+
+	// Run `go generate ./...` in cmd/clonegen/example if it fails.
+	a.Equal(string(src), string(expected))
+}
+
+func TestGenerateErrors(t *testing.T) {
+	cases := map[string]string{
+		"not struct": `
+//clonegen:generate
+type T []int
+`,
+		"unsupported tag": `
+//clonegen:generate
+type T struct {
+	P *T ` + "`clone:\"parent\"`" + `
+}
+`,
+		"deep tag": `
+//clonegen:generate
+type T struct {
+	S string ` + "`clone:\"deep\"`" + `
+}
+`,
+		"invalid tag": `
+//clonegen:generate
+type T struct {
+	P *T ` + "`clone:\"init\"`" + `
+}
+`,
+	}
+
+	for name, code := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := assert.New(t)
+			dir, err := ioutil.TempDir("", "clonegen")
+			a.NilError(err)
+			defer os.RemoveAll(dir)
+
+			err = ioutil.WriteFile(filepath.Join(dir, "types.go"), []byte("package p\n"+code), 0644)
+			a.NilError(err)
+
+			_, err = generate(dir, defaultOutput)
+			a.Assert(err != nil)
+			a.Assert(strings.HasPrefix(err.Error(), "clonegen: "))
+		})
+	}
+}
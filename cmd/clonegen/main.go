@@ -0,0 +1,83 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Command clonegen generates reflection-free deep clone methods for struct types.
+//
+// The generated code clones values by plain Go assignments in the same way as clone.Clone does,
+// so that hot paths can pay zero reflection cost without losing the semantics of go-clone.
+// Add directives to doc comments of type declarations and run clonegen in the package directory.
+//
+//	//go:generate go run github.com/huandu/go-clone/cmd/clonegen
+//
+//	// Config is the config of a service.
+//	//clonegen:generate
+//	type Config struct {
+//		Name    string
+//		Servers []*Server
+//		cache   map[string]string `clone:"skip"`
+//	}
+//
+// Following directives are recognized.
+//
+//   - `//clonegen:generate`: Generate `func (t *T) CloneDeep() *T` for struct type T.
+//   - `//clonegen:scalar`: Values of T are copied by value like types marked by clone.MarkAsScalar.
+//   - `//clonegen:custom`: Values of T are cloned by clone.Clone,
+//     so that the custom func set by clone.SetCustomFunc for T applies.
+//
+// Struct tags are interpreted by clone.ParseFieldTag.
+// Fields tagged with `clone:"skip"`, `clone:"zero"` or `clone:"rebind"` are zero in clones.
+// Fields tagged with `clone:"shadowcopy"` or `clone:"opaque"` are shadow copied.
+// Fields tagged with `clone:"init=methodName"` are zero and the method is called with the clone after cloning.
+// Tags which need registrations at runtime, e.g. `clone:"func=name"`, are not supported.
+// Neither is `clone:"deep"`, as generated code shares bytes of strings instead of copying them.
+//
+// Values which types are unknown at compile time, e.g. interfaces and types defined in other packages,
+// are cloned by clone.Clone, except well-known scalar types like time.Time.
+// Nil values are cloned to nil values and chans are cloned to new chans with the same buffer size.
+// Like clone.Clone, generated methods assume that there is no pointer cycle in values.
+//
+// Usage:
+//
+//	clonegen [-output file] [dir]
+//
+// The default output file is clonedeep_gen.go in the package directory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const defaultOutput = "clonedeep_gen.go"
+
+func main() {
+	output := flag.String("output", "", "the output file name; default is "+defaultOutput+" in the package directory")
+	flag.Parse()
+
+	dir := "."
+
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	out := *output
+
+	if out == "" {
+		out = filepath.Join(dir, defaultOutput)
+	}
+
+	src, err := generate(dir, filepath.Base(out))
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := ioutil.WriteFile(out, src, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,720 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"sync"
+)
+
+// config is an immutable snapshot of all registrations in an allocator.
+//
+// Registration methods never modify a config in place.
+// They copy current config, modify the copy and publish it atomically,
+// so that a clone operation can capture a config at start and
+// see consistent registrations during the whole traversal.
+type config struct {
+	parent   *config
+	isScalar func(k reflect.Kind) bool
+
+	types         map[reflect.Type]*typeConfig
+	profiles      map[string]*profile
+	shapes        map[string]*shapeFunc
+	families      map[string]*familyFunc
+	tagFuncs      map[string]*tagFunc
+	tagHandlers   map[string]*tagHandler
+	strictMode    int32
+	debugMode     int32
+	readOnly      int32
+	transactional int32
+	canonical     int32
+	minCopy       int32
+	shareRefs     int32
+	readOnlyMem   int32
+	useCloner     int32
+	yield         *yieldOption
+	maxDepth      *maxDepthOption
+	nodeLimit     *nodeLimitOption
+	funcStub      *funcStubOption
+	labels        *labelsOption
+	traceHooks    *traceHooksOption
+	cachePolicy   *cachePolicyOption
+	cacheSize     *cacheSizeOption
+	fallback      *fallbackOption
+	warning       *warningOption
+	generation    *generationOption
+	precedence    Precedence
+	chanPolicy    ChanPolicy
+	nanKeyPolicy  NaNKeyPolicy
+
+	// Package patterns registered by ShareTypesFromPackages in this config.
+	sharedPackages *sharedPackagesOption
+
+	// namedFuncs is true if any custom func is set for a non-struct type in this config.
+	// It's never reset to avoid scanning all types.
+	namedFuncs bool
+
+	// appendOnly is true if any slice type is marked as append-only in this config.
+	// It's never reset for the same reason as namedFuncs.
+	appendOnly bool
+
+	// Struct types analyzed with this config.
+	// It's a cache owned by this config and dropped with the config.
+	structTypes *structTypeCache
+
+	// Conflicting registrations found in this config.
+	// It's computed on demand and dropped with the config.
+	cachedConflicts configConflicts
+
+	// forceDeep is true if this config is derived to clone fields tagged with `clone:"deep"`.
+	forceDeep bool
+
+	// The derived config to clone fields tagged with `clone:"deep"`.
+	// It's created on demand and dropped with the config.
+	cachedDeep deepConfig
+
+	// Options of clones resolved in this config and parents.
+	// It's resolved on demand and dropped with the config.
+	cachedOptions cloneOptions
+}
+
+// cloneOptions is all options of clones resolved in a config and its parents.
+type cloneOptions struct {
+	once          sync.Once
+	state         cloneState // The template of clone states with all options set.
+	transactional bool
+}
+
+// options returns options of clones resolved in cfg and parents.
+// The result is resolved once and cached in cfg,
+// so that a clone doesn't look up every option in the parent chain.
+func (cfg *config) options() *cloneOptions {
+	opts := &cfg.cachedOptions
+	opts.once.Do(func() {
+		opts.state = cloneState{
+			config:       cfg,
+			strict:       cfg.isStrictMode(),
+			debug:        cfg.isDebugMode(),
+			readOnly:     cfg.isReadOnlySource(),
+			canonical:    cfg.isCanonical(),
+			minCopy:      cfg.isMinCopy(),
+			shareRefs:    cfg.isSharingShallowReferences(),
+			useCloner:    cfg.isUsingCloner(),
+			yield:        cfg.lookupYield(),
+			namedFuncs:   cfg.hasNamedFuncs(),
+			appendOnly:   cfg.hasAppendOnly(),
+			generation:   cfg.lookupGeneration(),
+			maxDepth:     cfg.lookupMaxDepth(),
+			nodeLimit:    cfg.lookupNodeLimit(),
+			funcStub:     cfg.lookupFuncStub(),
+			labels:       cfg.lookupLabels(),
+			trace:        cfg.lookupTraceHooks(),
+			chanPolicy:   cfg.lookupChanPolicy(),
+			nanKeyPolicy: cfg.lookupNaNKeyPolicy(),
+			readOnlyMem:  cfg.isReadOnlyMemory(),
+		}
+		opts.transactional = cfg.isTransactional()
+	})
+
+	return opts
+}
+
+// typeConfig is all registrations of a type in one allocator.
+type typeConfig struct {
+	scalar    bool
+	opaque    bool
+	fn        Func
+	rebind    RebindFunc
+	validator ValidateFunc
+
+	interfacePolicy InterfacePolicy
+	mapKeyPolicy    MapKeyPolicy
+	appendOnly      bool
+	guardedBy       string
+	unsafePointers  map[string]reflect.Type // Types referenced by unsafe.Pointer fields.
+}
+
+func newConfig(parent *config, isScalar func(k reflect.Kind) bool) *config {
+	return &config{
+		parent:      parent,
+		isScalar:    isScalar,
+		structTypes: &structTypeCache{},
+	}
+}
+
+// update returns a copy of cfg with t's type config updated by fn.
+func (cfg *config) update(t reflect.Type, fn func(tc *typeConfig)) *config {
+	copied := cfg.copy()
+	types := make(map[reflect.Type]*typeConfig, len(cfg.types)+1)
+
+	for k, v := range cfg.types {
+		types[k] = v
+	}
+
+	tc := &typeConfig{}
+
+	if old, ok := types[t]; ok {
+		*tc = *old
+	}
+
+	fn(tc)
+	types[t] = tc
+	copied.types = types
+	return copied
+}
+
+// copy returns a shadow copy of cfg with an empty struct type cache.
+func (cfg *config) copy() *config {
+	copied := newConfig(cfg.parent, cfg.isScalar)
+	copied.types = cfg.types
+	copied.profiles = cfg.profiles
+	copied.shapes = cfg.shapes
+	copied.families = cfg.families
+	copied.tagFuncs = cfg.tagFuncs
+	copied.tagHandlers = cfg.tagHandlers
+	copied.strictMode = cfg.strictMode
+	copied.debugMode = cfg.debugMode
+	copied.readOnly = cfg.readOnly
+	copied.transactional = cfg.transactional
+	copied.canonical = cfg.canonical
+	copied.minCopy = cfg.minCopy
+	copied.shareRefs = cfg.shareRefs
+	copied.readOnlyMem = cfg.readOnlyMem
+	copied.useCloner = cfg.useCloner
+	copied.yield = cfg.yield
+	copied.maxDepth = cfg.maxDepth
+	copied.nodeLimit = cfg.nodeLimit
+	copied.funcStub = cfg.funcStub
+	copied.labels = cfg.labels
+	copied.traceHooks = cfg.traceHooks
+	copied.cachePolicy = cfg.cachePolicy
+	copied.cacheSize = cfg.cacheSize
+	copied.fallback = cfg.fallback
+	copied.warning = cfg.warning
+	copied.generation = cfg.generation
+	copied.precedence = cfg.precedence
+	copied.chanPolicy = cfg.chanPolicy
+	copied.nanKeyPolicy = cfg.nanKeyPolicy
+	copied.sharedPackages = cfg.sharedPackages
+	copied.namedFuncs = cfg.namedFuncs
+	copied.appendOnly = cfg.appendOnly
+	return copied
+}
+
+// flatten returns a copy of cfg without parent.
+// All type configs in parents are merged into the copy.
+func (cfg *config) flatten() *config {
+	flattened := newConfig(nil, cfg.isScalar)
+	types := map[reflect.Type]*typeConfig{}
+	profiles := map[string]*profile{}
+	shapes := map[string]*shapeFunc{}
+	families := map[string]*familyFunc{}
+	tagFuncs := map[string]*tagFunc{}
+	tagHandlers := map[string]*tagHandler{}
+
+	for current := cfg; current != nil; current = current.parent {
+		for t, tc := range current.types {
+			if merged, ok := types[t]; ok {
+				merged.inherit(tc)
+				continue
+			}
+
+			copied := *tc
+			types[t] = &copied
+		}
+
+		for name, p := range current.profiles {
+			if _, ok := profiles[name]; !ok {
+				profiles[name] = p
+			}
+		}
+
+		for shape, sf := range current.shapes {
+			if _, ok := shapes[shape]; !ok {
+				shapes[shape] = sf
+			}
+		}
+
+		for family, ff := range current.families {
+			if _, ok := families[family]; !ok {
+				families[family] = ff
+			}
+		}
+
+		for name, tf := range current.tagFuncs {
+			if _, ok := tagFuncs[name]; !ok {
+				tagFuncs[name] = tf
+			}
+		}
+
+		for name, th := range current.tagHandlers {
+			if _, ok := tagHandlers[name]; !ok {
+				tagHandlers[name] = th
+			}
+		}
+
+		if flattened.strictMode == optionUnset {
+			flattened.strictMode = current.strictMode
+		}
+
+		if flattened.debugMode == optionUnset {
+			flattened.debugMode = current.debugMode
+		}
+
+		if flattened.readOnly == optionUnset {
+			flattened.readOnly = current.readOnly
+		}
+
+		if flattened.transactional == optionUnset {
+			flattened.transactional = current.transactional
+		}
+
+		if flattened.canonical == optionUnset {
+			flattened.canonical = current.canonical
+		}
+
+		if flattened.minCopy == optionUnset {
+			flattened.minCopy = current.minCopy
+		}
+
+		if flattened.shareRefs == optionUnset {
+			flattened.shareRefs = current.shareRefs
+		}
+
+		if flattened.readOnlyMem == optionUnset {
+			flattened.readOnlyMem = current.readOnlyMem
+		}
+
+		if flattened.useCloner == optionUnset {
+			flattened.useCloner = current.useCloner
+		}
+
+		if flattened.yield == nil {
+			flattened.yield = current.yield
+		}
+
+		if flattened.maxDepth == nil {
+			flattened.maxDepth = current.maxDepth
+		}
+
+		if flattened.nodeLimit == nil {
+			flattened.nodeLimit = current.nodeLimit
+		}
+
+		if flattened.funcStub == nil {
+			flattened.funcStub = current.funcStub
+		}
+
+		if flattened.labels == nil {
+			flattened.labels = current.labels
+		}
+
+		if flattened.traceHooks == nil {
+			flattened.traceHooks = current.traceHooks
+		}
+
+		if flattened.cachePolicy == nil {
+			flattened.cachePolicy = current.cachePolicy
+		}
+
+		if flattened.cacheSize == nil {
+			flattened.cacheSize = current.cacheSize
+		}
+
+		if flattened.fallback == nil {
+			flattened.fallback = current.fallback
+		}
+
+		if flattened.warning == nil {
+			flattened.warning = current.warning
+		}
+
+		if flattened.generation == nil {
+			flattened.generation = current.generation
+		}
+
+		if flattened.precedence == 0 {
+			flattened.precedence = current.precedence
+		}
+
+		if flattened.chanPolicy == 0 {
+			flattened.chanPolicy = current.chanPolicy
+		}
+
+		if flattened.nanKeyPolicy == 0 {
+			flattened.nanKeyPolicy = current.nanKeyPolicy
+		}
+
+		if current.sharedPackages != nil {
+			flattened.sharedPackages = flattened.sharedPackages.merge(current.sharedPackages.patterns)
+		}
+
+		flattened.namedFuncs = flattened.namedFuncs || current.namedFuncs
+		flattened.appendOnly = flattened.appendOnly || current.appendOnly
+	}
+
+	flattened.types = types
+	flattened.profiles = profiles
+	flattened.shapes = shapes
+	flattened.families = families
+	flattened.tagFuncs = tagFuncs
+	flattened.tagHandlers = tagHandlers
+	return flattened
+}
+
+// inherit fills tc with registrations in parent which are not set in tc.
+// It must follow the same precedence as lookups in config.
+func (tc *typeConfig) inherit(parent *typeConfig) {
+	if !tc.scalar && tc.fn == nil {
+		tc.scalar = parent.scalar
+		tc.fn = parent.fn
+	}
+
+	tc.opaque = tc.opaque || parent.opaque
+	tc.appendOnly = tc.appendOnly || parent.appendOnly
+
+	if tc.rebind == nil {
+		tc.rebind = parent.rebind
+	}
+
+	if tc.validator == nil {
+		tc.validator = parent.validator
+	}
+
+	if tc.interfacePolicy == 0 {
+		tc.interfacePolicy = parent.interfacePolicy
+	}
+
+	if tc.mapKeyPolicy == 0 {
+		tc.mapKeyPolicy = parent.mapKeyPolicy
+	}
+
+	if tc.guardedBy == "" {
+		tc.guardedBy = parent.guardedBy
+	}
+
+	if len(parent.unsafePointers) != 0 {
+		unsafePointers := make(map[string]reflect.Type, len(parent.unsafePointers)+len(tc.unsafePointers))
+
+		for name, t := range parent.unsafePointers {
+			unsafePointers[name] = t
+		}
+
+		for name, t := range tc.unsafePointers {
+			unsafePointers[name] = t
+		}
+
+		tc.unsafePointers = unsafePointers
+	}
+}
+
+// lookup returns the nearest type config of t matching fn.
+func (cfg *config) lookup(t reflect.Type, fn func(tc *typeConfig) bool) *typeConfig {
+	for current := cfg; current != nil; current = current.parent {
+		if tc, ok := current.types[t]; ok && fn(tc) {
+			return tc
+		}
+	}
+
+	return nil
+}
+
+func (cfg *config) isOpaquePointer(t reflect.Type) bool {
+	cache := &cfg.cache().opaquePointers
+
+	if v, ok := cache.load(t); ok {
+		return v.(bool)
+	}
+
+	opaque := cfg.lookup(t, func(tc *typeConfig) bool {
+		return tc.opaque
+	}) != nil || t.Kind() == reflect.Ptr && cfg.isSharedPackagePointer(t)
+	cache.store(t, opaque)
+	return opaque
+}
+
+func (cfg *config) lookupValidator(t reflect.Type) ValidateFunc {
+	tc := cfg.lookup(t, func(tc *typeConfig) bool {
+		return tc.validator != nil
+	})
+
+	if tc == nil {
+		return nil
+	}
+
+	return tc.validator
+}
+
+// hasNamedFuncs returns true if any custom func is set for a non-struct type in cfg or parents.
+func (cfg *config) hasNamedFuncs() bool {
+	for current := cfg; current != nil; current = current.parent {
+		if current.namedFuncs {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lookupNamedFunc returns the custom func for non-struct type t.
+func (cfg *config) lookupNamedFunc(t reflect.Type) Func {
+	if !cfg.hasNamedFuncs() {
+		return nil
+	}
+
+	tc := cfg.lookup(t, func(tc *typeConfig) bool {
+		return tc.fn != nil
+	})
+
+	if tc == nil {
+		return nil
+	}
+
+	return tc.fn
+}
+
+// isScalarType returns true if values of t can be copied by value.
+// A scalar kind type with custom func is not scalar.
+func (cfg *config) isScalarType(t reflect.Type) bool {
+	if t.Kind() == reflect.Func && cfg.lookupFuncStub() != nil {
+		return false
+	}
+
+	return cfg.isScalar(t.Kind()) && cfg.lookupNamedFunc(t) == nil
+}
+
+func (cfg *config) isStrictMode() bool {
+	for current := cfg; current != nil; current = current.parent {
+		switch current.strictMode {
+		case optionEnabled:
+			return true
+		case optionDisabled:
+			return false
+		}
+	}
+
+	return false
+}
+
+func (cfg *config) loadStructType(t reflect.Type) (st *structType) {
+	cache := cfg.cache()
+
+	if st, ok := cache.load(t); ok {
+		return st
+	}
+
+	// The nearest registration of scalar or custom func wins.
+	// In the same allocator, the winner is decided by precedence.
+	tc, scalar := cfg.lookupScalar(t)
+
+	if scalar && !cfg.forceDeep {
+		cache.store(t, &zeroStructType)
+		return &zeroStructType
+	}
+
+	num := t.NumField()
+	zeroFeilds := make([]structFieldSize, 0, num)
+	pointerFields := make([]structFieldType, 0, num)
+	var parentFields []structFieldType
+	var generationFields []structFieldType
+	var initMethods []int
+	var funcFields []structFieldFunc
+
+	// Find pointer fields in depth-first order.
+	for i := 0; i < num; i++ {
+		field := t.Field(i)
+		ft := field.Type
+		k := ft.Kind()
+		tag := field.Tag.Get(fieldTagName)
+
+		if tag == fieldTagValueSkip || tag == fieldTagValueSkipAlias || tag == fieldTagValueZero || tag == fieldTagValueRebind {
+			zeroFeilds = append(zeroFeilds, structFieldSize{
+				Offset: field.Offset,
+				Size:   uintptr(ft.Size()),
+			})
+			continue
+		}
+
+		if name, ok := parseInitTag(tag); ok {
+			zeroFeilds = append(zeroFeilds, structFieldSize{
+				Offset: field.Offset,
+				Size:   uintptr(ft.Size()),
+			})
+			initMethods = appendInitMethod(initMethods, t, name)
+			continue
+		}
+
+		if name, ok := parseFuncTag(tag); ok {
+			funcFields = append(funcFields, structFieldFunc{
+				Offset: field.Offset,
+				Index:  i,
+				Func:   cfg.lookupTagFunc(t, name),
+			})
+			continue
+		}
+
+		if tag != "" {
+			if fn := cfg.handleTag(field, tag); fn != nil {
+				funcFields = append(funcFields, structFieldFunc{
+					Offset: field.Offset,
+					Index:  i,
+					Func:   fn,
+				})
+				continue
+			}
+		}
+
+		if tag == fieldTagValueParent && k == reflect.Ptr {
+			parentFields = append(parentFields, structFieldType{
+				Offset: field.Offset,
+				Index:  i,
+			})
+			continue
+		}
+
+		if tag == fieldTagValueGeneration && isIntegerKind(k) {
+			generationFields = append(generationFields, structFieldType{
+				Offset: field.Offset,
+				Index:  i,
+			})
+			continue
+		}
+
+		if tag == fieldTagValueDeep {
+			pointerFields = append(pointerFields, structFieldType{
+				Offset: field.Offset,
+				Index:  i,
+				Deep:   true,
+			})
+			continue
+		}
+
+		if tag == fieldTagValueShadowCopy || tag == fieldTagValueOpaque {
+			continue
+		}
+
+		if k == reflect.UnsafePointer {
+			if pointee := cfg.lookupUnsafePointerType(t, field.Name); pointee != nil {
+				pointerFields = append(pointerFields, structFieldType{
+					Offset:  field.Offset,
+					Index:   i,
+					Pointee: pointee,
+				})
+				continue
+			}
+		}
+
+		if cfg.isScalarType(ft) {
+			continue
+		}
+
+		switch k {
+		case reflect.Array:
+			if ft.Len() == 0 {
+				continue
+			}
+
+			elem := ft.Elem()
+
+			if cfg.isScalarType(elem) {
+				continue
+			}
+
+			if elem.Kind() == reflect.Struct {
+				if fst := cfg.loadStructType(elem); fst.CanShadowCopy() {
+					continue
+				}
+			}
+		case reflect.Struct:
+			if fst := cfg.loadStructType(ft); fst.CanShadowCopy() {
+				continue
+			}
+		}
+
+		pointerFields = append(pointerFields, structFieldType{
+			Offset: field.Offset,
+			Index:  i,
+		})
+	}
+
+	st = &structType{}
+
+	if len(zeroFeilds) != 0 {
+		st.ZeroFields = append(st.ZeroFields, zeroFeilds...)
+	}
+
+	if len(pointerFields) != 0 {
+		st.PointerFields = append(st.PointerFields, pointerFields...)
+	}
+
+	st.ParentFields = parentFields
+	st.GenerationFields = generationFields
+	st.InitMethods = initMethods
+	st.FuncFields = funcFields
+	st.TrackAncestors = reachParentFields(t, map[reflect.Type]struct{}{})
+	st.Guard = cfg.lookupGuard(t)
+	st.validated = cfg.lookupValidator(t) != nil
+
+	if tc != nil {
+		st.fn = tc.fn
+	}
+
+	if st.fn == nil {
+		st.fn = cfg.lookupFamilyFunc(t)
+	}
+
+	if st.fn == nil {
+		st.fn = cfg.lookupShapeFunc(t)
+	}
+
+	if st.fn == nil && cfg.isUsingCloner() {
+		st.fn = cfg.clonerMethodFunc(t)
+	}
+
+	if tc := cfg.lookup(t, func(tc *typeConfig) bool {
+		return tc.rebind != nil
+	}); tc != nil {
+		st.rebind = tc.rebind
+	}
+
+	st.plan = cfg.compilePlan(t, st)
+	cache.store(t, st)
+	return
+}
+
+// reachParentFields returns true if any struct type reachable from t
+// has fields tagged with `clone:"parent"`.
+// Types inside interfaces are unknown and ignored.
+func reachParentFields(t reflect.Type, visited map[reflect.Type]struct{}) bool {
+	if _, ok := visited[t]; ok {
+		return false
+	}
+
+	visited[t] = struct{}{}
+
+	switch t.Kind() {
+	case reflect.Array, reflect.Ptr, reflect.Slice:
+		return reachParentFields(t.Elem(), visited)
+	case reflect.Map:
+		return reachParentFields(t.Key(), visited) || reachParentFields(t.Elem(), visited)
+	case reflect.Struct:
+		num := t.NumField()
+
+		for i := 0; i < num; i++ {
+			field := t.Field(i)
+
+			if field.Tag.Get(fieldTagName) == fieldTagValueParent {
+				return true
+			}
+		}
+
+		for i := 0; i < num; i++ {
+			if reachParentFields(t.Field(i).Type, visited) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,237 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Precedence decides which registration wins
+// if a type is marked as scalar and has a custom func in the same allocator.
+//
+// Registrations in different allocators never conflict:
+// the registration in the nearest allocator always wins,
+// e.g. a custom func in an allocator wins a scalar mark in its parent.
+type Precedence int
+
+// All precedences.
+const (
+	PrecedenceScalar     Precedence = iota + 1 // The scalar mark wins. It's the default precedence.
+	PrecedenceCustomFunc                       // The custom func wins.
+)
+
+// Registration is a kind of registration for a type.
+type Registration int
+
+// All kinds of registrations which may conflict.
+const (
+	RegistrationScalar     Registration = iota + 1 // Set by MarkAsScalar.
+	RegistrationCustomFunc                         // Set by SetCustomFunc.
+	RegistrationRebindFunc                         // Set by SetRebindFunc.
+	RegistrationValidator                          // Set by RegisterValidator.
+)
+
+func (r Registration) String() string {
+	switch r {
+	case RegistrationScalar:
+		return "scalar"
+	case RegistrationCustomFunc:
+		return "custom func"
+	case RegistrationRebindFunc:
+		return "rebind func"
+	case RegistrationValidator:
+		return "validator"
+	default:
+		return fmt.Sprintf("Registration(%d)", int(r))
+	}
+}
+
+// Conflict is a pair of overlapping registrations for a type.
+// Only the winner takes effect.
+type Conflict struct {
+	Type   reflect.Type
+	Winner Registration
+	Loser  Registration
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%v: %v overrides %v", c.Type, c.Winner, c.Loser)
+}
+
+// ConflictError is the error of conflicting registrations found in strict mode.
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	conflicts := make([]string, 0, len(e.Conflicts))
+
+	for _, c := range e.Conflicts {
+		conflicts = append(conflicts, c.String())
+	}
+
+	return "go-clone: conflicting registrations: " + strings.Join(conflicts, "; ")
+}
+
+// SetPrecedence sets the precedence in heap allocator.
+//
+// See Allocator.SetPrecedence for more details.
+func SetPrecedence(p Precedence) {
+	defaultAllocator.SetPrecedence(p)
+}
+
+// SetPrecedence sets the precedence to resolve conflicts between a scalar mark and a custom func
+// set for the same type in the same allocator.
+// If p is not a valid precedence, a inherits the precedence from parent allocator.
+func (a *Allocator) SetPrecedence(p Precedence) {
+	if p != PrecedenceScalar && p != PrecedenceCustomFunc {
+		p = 0
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.precedence = p
+		return copied
+	})
+}
+
+// Conflicts returns all conflicting registrations visible in a, sorted by type.
+//
+// A scalar mark conflicts with a custom func set for the same type in the same allocator,
+// and the winner is decided by precedence.
+// A scalar mark also conflicts with any rebind func or validator for the same type,
+// as scalar values are copied by value and never passed to them.
+//
+// In strict mode, clone methods panic with a *ConflictError if there is any conflict.
+func (a *Allocator) Conflicts() []Conflict {
+	conflicts := a.loadConfig().conflicts()
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	return append([]Conflict(nil), conflicts...)
+}
+
+func (cfg *config) lookupPrecedence() Precedence {
+	for current := cfg; current != nil; current = current.parent {
+		if current.precedence != 0 {
+			return current.precedence
+		}
+	}
+
+	return PrecedenceScalar
+}
+
+// lookupScalar returns the nearest type config of t with scalar mark or custom func
+// and reports whether t is scalar according to the precedence.
+func (cfg *config) lookupScalar(t reflect.Type) (tc *typeConfig, scalar bool) {
+	tc = cfg.lookup(t, func(tc *typeConfig) bool {
+		return tc.scalar || tc.fn != nil
+	})
+
+	if tc == nil {
+		// Struct types defined in shared packages are scalar unless a custom func is set.
+		scalar = t.Kind() == reflect.Struct && cfg.isSharedPackageType(t)
+		return
+	}
+
+	if !tc.scalar {
+		return
+	}
+
+	scalar = tc.fn == nil || cfg.lookupPrecedence() == PrecedenceScalar
+	return
+}
+
+type configConflicts struct {
+	once      sync.Once
+	conflicts []Conflict
+}
+
+// conflicts returns all conflicts in cfg.
+// The result is computed once and cached in cfg.
+func (cfg *config) conflicts() []Conflict {
+	cfg.cachedConflicts.once.Do(func() {
+		cfg.cachedConflicts.conflicts = cfg.findConflicts()
+	})
+
+	return cfg.cachedConflicts.conflicts
+}
+
+func (cfg *config) findConflicts() (conflicts []Conflict) {
+	types := map[reflect.Type]struct{}{}
+
+	for current := cfg; current != nil; current = current.parent {
+		for t := range current.types {
+			types[t] = struct{}{}
+		}
+	}
+
+	for t := range types {
+		tc, scalar := cfg.lookupScalar(t)
+
+		if tc == nil {
+			continue
+		}
+
+		if tc.scalar && tc.fn != nil {
+			c := Conflict{
+				Type:   t,
+				Winner: RegistrationScalar,
+				Loser:  RegistrationCustomFunc,
+			}
+
+			if !scalar {
+				c.Winner, c.Loser = c.Loser, c.Winner
+			}
+
+			conflicts = append(conflicts, c)
+		}
+
+		if !scalar {
+			continue
+		}
+
+		if cfg.lookup(t, func(tc *typeConfig) bool {
+			return tc.rebind != nil
+		}) != nil {
+			conflicts = append(conflicts, Conflict{
+				Type:   t,
+				Winner: RegistrationScalar,
+				Loser:  RegistrationRebindFunc,
+			})
+		}
+
+		if cfg.lookupValidator(t) != nil {
+			conflicts = append(conflicts, Conflict{
+				Type:   t,
+				Winner: RegistrationScalar,
+				Loser:  RegistrationValidator,
+			})
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		if ti, tj := conflicts[i].Type.String(), conflicts[j].Type.String(); ti != tj {
+			return ti < tj
+		}
+
+		return conflicts[i].Loser < conflicts[j].Loser
+	})
+	return
+}
+
+// checkConflicts panics if there is any conflict in cfg.
+func (cfg *config) checkConflicts() {
+	if conflicts := cfg.conflicts(); len(conflicts) != 0 {
+		panic(&ConflictError{
+			Conflicts: append([]Conflict(nil), conflicts...),
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type conflictData struct {
+	Values []int
+}
+
+type conflictOther struct {
+	Ptr *int
+}
+
+func TestPrecedence(t *testing.T) {
+	a := assert.New(t)
+	typeOfData := reflect.TypeOf(conflictData{})
+	allocator := NewAllocator(nil, nil)
+	allocator.MarkAsScalar(typeOfData)
+	allocator.SetCustomFunc(typeOfData, func(allocator *Allocator, old, new reflect.Value) {
+		new.Field(0).Set(reflect.ValueOf([]int{42}))
+	})
+
+	orig := []conflictData{{Values: []int{1}}}
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().([]conflictData)
+	a.Assert(&cloned[0].Values[0] == &orig[0].Values[0])
+	a.Assert(allocator.IsMarkedAsScalar(typeOfData))
+
+	allocator.SetPrecedence(PrecedenceCustomFunc)
+	cloned = allocator.Clone(reflect.ValueOf(orig)).Interface().([]conflictData)
+	a.Equal(cloned[0].Values, []int{42})
+	a.Assert(!allocator.IsMarkedAsScalar(typeOfData))
+
+	// Child allocators inherit precedence.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().([]conflictData)
+	a.Equal(cloned[0].Values, []int{42})
+
+	child.SetPrecedence(PrecedenceScalar)
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().([]conflictData)
+	a.Assert(&cloned[0].Values[0] == &orig[0].Values[0])
+
+	// Invalid precedence means inherit.
+	child.SetPrecedence(Precedence(100))
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().([]conflictData)
+	a.Equal(cloned[0].Values, []int{42})
+}
+
+func TestConflicts(t *testing.T) {
+	a := assert.New(t)
+	typeOfData := reflect.TypeOf(conflictData{})
+	typeOfOther := reflect.TypeOf(conflictOther{})
+	allocator := NewAllocator(nil, nil)
+	a.Equal(allocator.Conflicts(), []Conflict(nil))
+
+	allocator.MarkAsScalar(typeOfData)
+	allocator.SetCustomFunc(typeOfData, func(allocator *Allocator, old, new reflect.Value) {})
+	allocator.RegisterValidator(typeOfData, func(v reflect.Value) error { return nil })
+	allocator.SetRebindFunc(typeOfOther, func(root, owner reflect.Value) {})
+	a.Equal(allocator.Conflicts(), []Conflict{
+		{Type: typeOfData, Winner: RegistrationScalar, Loser: RegistrationCustomFunc},
+		{Type: typeOfData, Winner: RegistrationScalar, Loser: RegistrationValidator},
+	})
+
+	allocator.SetPrecedence(PrecedenceCustomFunc)
+	a.Equal(allocator.Conflicts(), []Conflict{
+		{Type: typeOfData, Winner: RegistrationCustomFunc, Loser: RegistrationScalar},
+	})
+
+	// Registrations in different allocators don't conflict.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	child.MarkAsScalar(typeOfOther)
+	child.SetPrecedence(PrecedenceScalar)
+	a.Equal(child.Conflicts(), []Conflict{
+		{Type: typeOfData, Winner: RegistrationScalar, Loser: RegistrationCustomFunc},
+		{Type: typeOfData, Winner: RegistrationScalar, Loser: RegistrationValidator},
+		{Type: typeOfOther, Winner: RegistrationScalar, Loser: RegistrationRebindFunc},
+	})
+
+	// Strict mode rejects conflicts.
+	orig := []conflictData{{Values: []int{1}}}
+	child.SetStrictMode(true)
+	err := func() (err interface{}) {
+		defer func() {
+			err = recover()
+		}()
+		child.Clone(reflect.ValueOf(orig))
+		return
+	}()
+	a.Assert(err != nil)
+	ce, ok := err.(*ConflictError)
+	a.Assert(ok)
+	a.Equal(ce.Conflicts, child.Conflicts())
+	a.Equal(ce.Error(), "go-clone: conflicting registrations: "+
+		"clone.conflictData: scalar overrides custom func; "+
+		"clone.conflictData: scalar overrides validator; "+
+		"clone.conflictOther: scalar overrides rebind func")
+
+	// No conflict, no panic.
+	noConflict := NewAllocator(nil, nil)
+	noConflict.MarkAsScalar(typeOfData)
+	noConflict.SetStrictMode(true)
+	cloned := noConflict.Clone(reflect.ValueOf(orig)).Interface().([]conflictData)
+	a.Equal(cloned, orig)
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+)
+
+// WarningFunc is a func called when a suspicious clone is found in debug mode.
+// The t is the type of the suspicious value.
+type WarningFunc func(t reflect.Type, warning string)
+
+// warningOption wraps a WarningFunc so that configs can tell whether it's changed.
+type warningOption struct {
+	fn WarningFunc
+}
+
+// SetDebugMode enables or disables debug mode in heap allocator.
+//
+// See Allocator.SetDebugMode for more details.
+func SetDebugMode(debug bool) {
+	defaultAllocator.SetDebugMode(debug)
+}
+
+// SetDebugMode enables or disables debug mode in a.
+// If debug mode is not set, a inherits it from parent allocator.
+// Debug mode is disabled in the default allocator.
+//
+// In debug mode, every value cloned by a custom func is checked after the func returns.
+// If a pointer, map, slice, chan, func or interface field is nil in the new value
+// while it's not nil in the old value, a warning is reported by the warning func set by SetWarningFunc.
+// Nested struct fields are checked in the same way.
+// Fields tagged with `clone:"skip"` or other tags which leave fields zero in clones are not checked.
+// Types defined in sync and sync/atomic are not checked, as their clones are reset on purpose.
+//
+// It's designed to catch custom funcs which initialize new values partially.
+// Checks are expensive. Don't enable debug mode in production unless necessary.
+func (a *Allocator) SetDebugMode(debug bool) {
+	option := optionDisabled
+
+	if debug {
+		option = optionEnabled
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.debugMode = option
+		return copied
+	})
+}
+
+// SetWarningFunc sets the warning func in heap allocator.
+//
+// See Allocator.SetWarningFunc for more details.
+func SetWarningFunc(fn WarningFunc) {
+	defaultAllocator.SetWarningFunc(fn)
+}
+
+// SetWarningFunc sets a func to be called with warnings found in debug mode.
+// If fn is nil, remove the warning func in a.
+// If warning func is not set, a inherits it from parent allocator.
+// If no warning func is set in a and its parents, warnings are printed by the standard logger.
+func (a *Allocator) SetWarningFunc(fn WarningFunc) {
+	var opt *warningOption
+
+	if fn != nil {
+		opt = &warningOption{
+			fn: fn,
+		}
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.warning = opt
+		return copied
+	})
+}
+
+func (cfg *config) isDebugMode() bool {
+	for current := cfg; current != nil; current = current.parent {
+		switch current.debugMode {
+		case optionEnabled:
+			return true
+		case optionDisabled:
+			return false
+		}
+	}
+
+	return false
+}
+
+func (cfg *config) lookupWarning() WarningFunc {
+	for current := cfg; current != nil; current = current.parent {
+		if current.warning != nil {
+			return current.warning.fn
+		}
+	}
+
+	return nil
+}
+
+// warn reports a warning of type t.
+func (state *cloneState) warn(t reflect.Type, warning string) {
+	if fn := state.config.lookupWarning(); fn != nil {
+		fn(t, warning)
+		return
+	}
+
+	log.Printf("go-clone: warning: %v: %v", t, warning)
+}
+
+// checkCustomFunc reports nil fields in new which are not nil in old after a custom func returns.
+func (state *cloneState) checkCustomFunc(old, new reflect.Value) {
+	t := old.Type()
+
+	if isSyncType(t) {
+		return
+	}
+
+	if isNilable(t.Kind()) {
+		if !old.IsNil() && new.IsNil() {
+			state.warn(t, "custom func leaves new value nil while old value is not nil")
+		}
+
+		return
+	}
+
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for _, field := range nilFields(old, new, "", nil) {
+		state.warn(t, fmt.Sprintf("custom func leaves field `%v` nil while it's not nil in old value", field))
+	}
+}
+
+// nilFields appends names of fields which are nil in new but not in old to fields.
+func nilFields(old, new reflect.Value, prefix string, fields []string) []string {
+	t := old.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if isZeroedInClone(field.Tag.Get(fieldTagName)) {
+			continue
+		}
+
+		name := prefix + field.Name
+		k := field.Type.Kind()
+
+		switch {
+		case isNilable(k):
+			if !old.Field(i).IsNil() && new.Field(i).IsNil() {
+				fields = append(fields, name)
+			}
+		case k == reflect.Struct:
+			if isSyncType(field.Type) {
+				continue
+			}
+
+			fields = nilFields(old.Field(i), new.Field(i), name+".", fields)
+		}
+	}
+
+	return fields
+}
+
+func isNilable(k reflect.Kind) bool {
+	switch k {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+		return true
+	}
+
+	return false
+}
+
+// isSyncType returns true if t is defined in sync or sync/atomic.
+func isSyncType(t reflect.Type) bool {
+	pkg := t.PkgPath()
+	return pkg == "sync" || pkg == "sync/atomic"
+}
+
+// isZeroedInClone returns true if a field tagged with tag may be zero in clones on purpose.
+func isZeroedInClone(tag string) bool {
+	switch tag {
+	case fieldTagValueSkip, fieldTagValueSkipAlias, fieldTagValueZero, fieldTagValueRebind:
+		return true
+	}
+
+	if _, ok := parseFuncTag(tag); ok {
+		return true
+	}
+
+	_, ok := parseInitTag(tag)
+	return ok
+}
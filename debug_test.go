@@ -0,0 +1,82 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type debugInner struct {
+	Values []int
+}
+
+type debugData struct {
+	Name    *string
+	Inner   debugInner
+	Skipped *int `clone:"skip"`
+	mu      sync.Mutex
+	pool    sync.Pool
+}
+
+type debugMap map[string]int
+
+func TestDebugMode(t *testing.T) {
+	a := assert.New(t)
+	typeOfData := reflect.TypeOf(debugData{})
+	typeOfMap := reflect.TypeOf(debugMap{})
+	var warnings []string
+	allocator := NewAllocator(nil, nil)
+	allocator.SetWarningFunc(func(t reflect.Type, warning string) {
+		warnings = append(warnings, t.String()+": "+warning)
+	})
+	allocator.SetCustomFunc(typeOfData, func(allocator *Allocator, old, new reflect.Value) {
+		// Forget to clone Inner.
+		new.Field(0).Set(allocator.Clone(old.Field(0)))
+	})
+	allocator.SetCustomFunc(typeOfMap, func(allocator *Allocator, old, new reflect.Value) {})
+
+	name := "foo"
+	n := 1
+	orig := &debugData{
+		Name:    &name,
+		Inner:   debugInner{Values: []int{1}},
+		Skipped: &n,
+	}
+	orig.pool.Put(&n)
+	m := debugMap{"a": 1}
+
+	// No check without debug mode.
+	allocator.Clone(reflect.ValueOf(orig))
+	allocator.Clone(reflect.ValueOf([]debugMap{m}))
+	a.Equal(len(warnings), 0)
+
+	allocator.SetDebugMode(true)
+	allocator.Clone(reflect.ValueOf(orig))
+	a.Equal(warnings, []string{
+		"clone.debugData: custom func leaves field `Inner.Values` nil while it's not nil in old value",
+	})
+
+	warnings = nil
+	allocator.Clone(reflect.ValueOf([]debugMap{m, nil}))
+	a.Equal(warnings, []string{
+		"clone.debugMap: custom func leaves new value nil while old value is not nil",
+	})
+
+	// Child allocators inherit debug mode and warning func.
+	warnings = nil
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	child.Clone(reflect.ValueOf(orig))
+	a.Equal(len(warnings), 1)
+
+	warnings = nil
+	child.SetDebugMode(false)
+	child.Clone(reflect.ValueOf(orig))
+	a.Equal(len(warnings), 0)
+}
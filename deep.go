@@ -0,0 +1,56 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+type deepConfig struct {
+	once   sync.Once
+	config *config
+}
+
+// deepConfig returns a config derived from cfg to clone fields tagged with `clone:"deep"`.
+// In the derived config, strings are not scalar and scalar marks of struct types are ignored.
+func (cfg *config) deepConfig() *config {
+	if cfg.forceDeep {
+		return cfg
+	}
+
+	cfg.cachedDeep.once.Do(func() {
+		isScalar := cfg.isScalar
+		deep := cfg.copy()
+		deep.isScalar = func(k reflect.Kind) bool {
+			return k != reflect.String && isScalar(k)
+		}
+		deep.forceDeep = true
+		cfg.cachedDeep.config = deep
+	})
+
+	return cfg.cachedDeep.config
+}
+
+// copyDeepField clones a field tagged with `clone:"deep"` to p.
+// All strings inside the field are copied with new underlying data,
+// and values of types marked as scalar are cloned in depth.
+func (state *cloneState) copyDeepField(field reflect.Value, p unsafe.Pointer) {
+	cfg := state.config
+	state.config = cfg.deepConfig()
+
+	switch field.Kind() {
+	case reflect.Struct:
+		zeroMemory(p, field.Type().Size())
+		state.copyStruct(field, reflect.NewAt(field.Type(), p))
+	case reflect.Array:
+		zeroMemory(p, field.Type().Size())
+		state.copyArray(field, reflect.NewAt(field.Type(), p))
+	default:
+		shadowCopy(state.clone(field), p)
+	}
+
+	state.config = cfg
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+type deepScalar struct {
+	P *int
+}
+
+type deepData struct {
+	Shared string
+	Data   string            `clone:"deep"`
+	List   []string          `clone:"deep"`
+	Attrs  map[string]string `clone:"deep"`
+	Array  [2]string         `clone:"deep"`
+	Scalar deepScalar        `clone:"deep"`
+	Ptr    *deepScalar       `clone:"deep"`
+	Plain  deepScalar
+	inner  string `clone:"deep"`
+}
+
+func stringData(s string) uintptr {
+	return (*stringHeader)(unsafe.Pointer(&s)).Data
+}
+
+func TestCloneDeepTag(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.MarkAsScalar(reflect.TypeOf(deepScalar{}))
+
+	n := 1
+	buf := "shared data from a large buffer"
+	orig := &deepData{
+		Shared: buf[:6],
+		Data:   buf[7:11],
+		List:   []string{buf[12:16], buf[17:18]},
+		Attrs:  map[string]string{buf[0:6]: buf[7:11]},
+		Array:  [2]string{buf[12:16], buf[17:18]},
+		Scalar: deepScalar{P: &n},
+		Ptr:    &deepScalar{P: &n},
+		Plain:  deepScalar{P: &n},
+		inner:  buf[19:24],
+	}
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*deepData)
+	a.Equal(cloned, orig)
+
+	// Strings in fields without the deep tag are shared.
+	a.Equal(stringData(cloned.Shared), stringData(orig.Shared))
+	a.Assert(cloned.Plain.P == orig.Plain.P)
+
+	a.Assert(stringData(cloned.Data) != stringData(orig.Data))
+	a.Assert(stringData(cloned.List[0]) != stringData(orig.List[0]))
+	a.Assert(stringData(cloned.Array[1]) != stringData(orig.Array[1]))
+	a.Assert(stringData(cloned.inner) != stringData(orig.inner))
+
+	for k, v := range cloned.Attrs {
+		a.Assert(stringData(k) != stringData(buf))
+		a.Assert(stringData(v) != stringData(orig.Data))
+	}
+
+	// Types marked as scalar are cloned in depth.
+	a.Assert(cloned.Scalar.P != orig.Scalar.P)
+	a.Equal(*cloned.Scalar.P, n)
+	a.Assert(cloned.Ptr != orig.Ptr)
+	a.Assert(cloned.Ptr.P != orig.Ptr.P)
+}
+
+func TestCloneDeepTagInSlowly(t *testing.T) {
+	a := assert.New(t)
+	buf := "shared data from a large buffer"
+	orig := &deepData{
+		Data: buf[7:11],
+	}
+	orig.Ptr = &deepScalar{}
+	cloned := Slowly(orig).(*deepData)
+	a.Equal(cloned, orig)
+	a.Assert(stringData(cloned.Data) != stringData(orig.Data))
+}
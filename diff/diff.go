@@ -0,0 +1,333 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Package diff finds differences between two values, e.g. a value and its old clone.
+// It compares unexported fields and values with pointer cycles in the same way as package walk walks them.
+//
+//	for _, c := range diff.Diff(old, new) {
+//		fmt.Printf("%v: %v => %v\n", c.Path, c.Old, c.New)
+//	}
+package diff
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"unsafe"
+
+	"github.com/huandu/go-clone/walk"
+)
+
+// Change is a difference between two values.
+type Change struct {
+	// Path is the path to the changed value written in Go selector syntax like `.Foo[2]["key"]`.
+	// The empty path means root itself.
+	Path string
+
+	// Old is the value in old value or nil if it doesn't exist in old value,
+	// e.g. a new map entry or a new slice element.
+	Old interface{}
+
+	// New is the value in new value or nil if it doesn't exist in new value.
+	New interface{}
+}
+
+// Diff returns all changes from old to new in depth-first order.
+// It returns nil if there is no change.
+//
+// Following rules apply to different kinds of values.
+//
+//   - Array and slice: Elements are compared in order of index.
+//     Missing elements are reported as changes, and so is a nil slice compared with an empty one.
+//   - Interface: Values with different dynamic types are changed.
+//   - Map: Entries are compared by key in order of formatted keys.
+//     Missing entries are reported as changes.
+//   - Pointer: Pointed values are compared unless pointers are the same.
+//   - Struct: Fields are compared in order of declaration including unexported fields.
+//   - Chan, func and unsafe.Pointer: Values are changed if their pointers are different.
+//   - Other values are compared by ==.
+//
+// Values of different types are always changed.
+// Pointers, maps and slices reached again through pointer cycles are considered unchanged.
+func Diff(old, new interface{}) []Change {
+	return DiffValue(reflect.ValueOf(old), reflect.ValueOf(new))
+}
+
+// DiffValue returns all changes from old to new in the same way as Diff.
+// If old or new is an unexported field, it must be addressable.
+func DiffValue(old, new reflect.Value) []Change {
+	d := &differ{
+		visited: map[visit]struct{}{},
+	}
+	d.diff(readable(old), readable(new), "")
+	return d.changes
+}
+
+// Equal reports whether a and b are deeply equal, i.e. Diff(a, b) returns no change.
+// It stops at the first change, so it's much cheaper than Diff for values which are not equal.
+//
+// Unlike reflect.DeepEqual, Equal compares funcs by their pointers,
+// and compares maps containing themselves without overflowing stack in every Go release.
+// See https://github.com/golang/go/issues/33907 for details.
+func Equal(a, b interface{}) bool {
+	return EqualValue(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// EqualValue reports whether a and b are deeply equal in the same way as Equal.
+// If a or b is an unexported field, it must be addressable.
+func EqualValue(a, b reflect.Value) bool {
+	d := &differ{
+		visited:   map[visit]struct{}{},
+		equalOnly: true,
+	}
+	d.diff(readable(a), readable(b), "")
+	return !d.changed
+}
+
+type visit struct {
+	old, new uintptr
+	extra    int
+	t        reflect.Type
+}
+
+type differ struct {
+	changes []Change
+	visited map[visit]struct{}
+
+	// equalOnly is true if only the first change matters.
+	// Changes are not recorded and diff stops once changed is true.
+	equalOnly bool
+	changed   bool
+}
+
+func (d *differ) diff(old, new reflect.Value, path string) {
+	if d.changed {
+		return
+	}
+
+	if !old.IsValid() || !new.IsValid() {
+		if old.IsValid() || new.IsValid() {
+			d.report(old, new, path)
+		}
+
+		return
+	}
+
+	if old.Type() != new.Type() {
+		d.report(old, new, path)
+		return
+	}
+
+	switch old.Kind() {
+	case reflect.Array:
+		for i := 0; i < old.Len(); i++ {
+			d.diff(old.Index(i), new.Index(i), d.indexPath(path, i))
+		}
+	case reflect.Slice:
+		if old.IsNil() != new.IsNil() {
+			d.report(old, new, path)
+			return
+		}
+
+		if old.Pointer() == new.Pointer() && old.Len() == new.Len() {
+			return
+		}
+
+		if !d.visit(old, new, old.Len()) {
+			return
+		}
+
+		d.diffElements(old, new, path)
+	case reflect.Interface:
+		if old.IsNil() || new.IsNil() {
+			if old.IsNil() != new.IsNil() {
+				d.report(old, new, path)
+			}
+
+			return
+		}
+
+		d.diff(readable(old.Elem()), readable(new.Elem()), path)
+	case reflect.Map:
+		if old.IsNil() != new.IsNil() {
+			d.report(old, new, path)
+			return
+		}
+
+		if old.Pointer() == new.Pointer() || !d.visit(old, new, 0) {
+			return
+		}
+
+		d.diffEntries(old, new, path)
+	case reflect.Ptr:
+		if old.IsNil() != new.IsNil() {
+			d.report(old, new, path)
+			return
+		}
+
+		if old.Pointer() == new.Pointer() || !d.visit(old, new, 0) {
+			return
+		}
+
+		d.diff(old.Elem(), new.Elem(), path)
+	case reflect.Struct:
+		t := old.Type()
+		old = addressable(old)
+		new = addressable(new)
+
+		for i := 0; i < t.NumField(); i++ {
+			d.diff(readable(old.Field(i)), readable(new.Field(i)), d.fieldPath(path, t, i))
+		}
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if old.Pointer() != new.Pointer() {
+			d.report(old, new, path)
+		}
+	default:
+		if old.Interface() != new.Interface() {
+			d.report(old, new, path)
+		}
+	}
+}
+
+func (d *differ) diffElements(old, new reflect.Value, path string) {
+	i := 0
+
+	for ; i < old.Len() && i < new.Len(); i++ {
+		d.diff(old.Index(i), new.Index(i), d.indexPath(path, i))
+	}
+
+	for ; i < old.Len(); i++ {
+		d.report(old.Index(i), reflect.Value{}, d.indexPath(path, i))
+	}
+
+	for ; i < new.Len(); i++ {
+		d.report(reflect.Value{}, new.Index(i), d.indexPath(path, i))
+	}
+}
+
+func (d *differ) diffEntries(old, new reflect.Value, path string) {
+	// Paths are not reported in equalOnly mode. It's not necessary to sort keys.
+	if d.equalOnly {
+		if old.Len() != new.Len() {
+			d.report(old, new, path)
+			return
+		}
+
+		for iter := old.MapRange(); iter.Next() && !d.changed; {
+			d.diff(iter.Value(), new.MapIndex(iter.Key()), path)
+		}
+
+		return
+	}
+
+	type entry struct {
+		formatted string
+		key       reflect.Value
+	}
+
+	entries := make([]entry, 0, old.Len()+new.Len())
+
+	for _, key := range old.MapKeys() {
+		entries = append(entries, entry{
+			formatted: walk.FormatMapKey(key),
+			key:       key,
+		})
+	}
+
+	for _, key := range new.MapKeys() {
+		if !old.MapIndex(key).IsValid() {
+			entries = append(entries, entry{
+				formatted: walk.FormatMapKey(key),
+				key:       key,
+			})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].formatted < entries[j].formatted
+	})
+
+	for _, e := range entries {
+		d.diff(old.MapIndex(e.key), new.MapIndex(e.key), path+"["+e.formatted+"]")
+	}
+}
+
+// indexPath returns the path to the i-th element of the value at path.
+// Paths are not built in equalOnly mode.
+func (d *differ) indexPath(path string, i int) string {
+	if d.equalOnly {
+		return path
+	}
+
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+// fieldPath returns the path to the i-th field of the struct of type t at path.
+// Paths are not built in equalOnly mode.
+func (d *differ) fieldPath(path string, t reflect.Type, i int) string {
+	if d.equalOnly {
+		return path
+	}
+
+	return path + "." + t.Field(i).Name
+}
+
+// visit records the pair of old and new as visited and reports whether it's visited at the first time.
+func (d *differ) visit(old, new reflect.Value, extra int) bool {
+	vst := visit{
+		old:   old.Pointer(),
+		new:   new.Pointer(),
+		extra: extra,
+		t:     old.Type(),
+	}
+
+	if _, ok := d.visited[vst]; ok {
+		return false
+	}
+
+	d.visited[vst] = struct{}{}
+	return true
+}
+
+func (d *differ) report(old, new reflect.Value, path string) {
+	if d.equalOnly {
+		d.changed = true
+		return
+	}
+
+	c := Change{
+		Path: path,
+	}
+
+	if old.IsValid() {
+		c.Old = old.Interface()
+	}
+
+	if new.IsValid() {
+		c.New = new.Interface()
+	}
+
+	d.changes = append(d.changes, c)
+}
+
+// readable returns v which can be used with v.Interface().
+// If v is an unexported field, v must be addressable.
+func readable(v reflect.Value) reflect.Value {
+	if !v.IsValid() || v.CanInterface() {
+		return v
+	}
+
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// addressable returns an addressable copy of readable v if v is not addressable,
+// so that unexported fields in v can be made readable.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+
+	nv := reflect.New(v.Type()).Elem()
+	nv.Set(v)
+	return nv
+}
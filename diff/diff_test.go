@@ -0,0 +1,119 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type config struct {
+	Name    string
+	Ports   []int
+	Labels  map[string]string
+	Backend interface{}
+	next    *config
+	weight  float64
+}
+
+func TestDiff(t *testing.T) {
+	a := assert.New(t)
+	old := &config{
+		Name:    "api",
+		Ports:   []int{80, 443},
+		Labels:  map[string]string{"env": "prod", "team": "infra"},
+		Backend: 1,
+		weight:  0.5,
+	}
+	new := &config{
+		Name:    "api",
+		Ports:   []int{80, 8443, 9090},
+		Labels:  map[string]string{"env": "staging", "zone": "us"},
+		Backend: "1",
+		weight:  1,
+	}
+
+	a.Equal(Diff(old, new), []Change{
+		{Path: ".Ports[1]", Old: 443, New: 8443},
+		{Path: ".Ports[2]", New: 9090},
+		{Path: `.Labels["env"]`, Old: "prod", New: "staging"},
+		{Path: `.Labels["team"]`, Old: "infra"},
+		{Path: `.Labels["zone"]`, New: "us"},
+		{Path: ".Backend", Old: 1, New: "1"},
+		{Path: ".weight", Old: 0.5, New: 1.0},
+	})
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	a := assert.New(t)
+	old := &config{
+		Name:  "api",
+		Ports: []int{80},
+	}
+	old.next = old
+	new := &config{
+		Name:  "api",
+		Ports: []int{80},
+	}
+	new.next = new
+
+	a.Equal(Diff(old, new), []Change(nil))
+	a.Equal(Diff(nil, nil), []Change(nil))
+	a.Equal(Diff(*old, *new), []Change(nil))
+}
+
+func TestDiffNil(t *testing.T) {
+	a := assert.New(t)
+	var nilSlice []int
+	var nilMap map[string]int
+
+	a.Equal(Diff(nil, 1), []Change{{New: 1}})
+	a.Equal(Diff(1, int64(1)), []Change{{Old: 1, New: int64(1)}})
+	a.Equal(Diff(nilSlice, []int{}), []Change{{Old: nilSlice, New: []int{}}})
+	a.Equal(Diff(map[string]int{}, nilMap), []Change{{Old: map[string]int{}, New: nilMap}})
+	a.Equal(Diff(&config{}, (*config)(nil)), []Change{{Old: &config{}, New: (*config)(nil)}})
+}
+
+func TestEqual(t *testing.T) {
+	a := assert.New(t)
+	old := &config{
+		Name:   "api",
+		Ports:  []int{80},
+		Labels: map[string]string{"env": "prod"},
+		weight: 0.5,
+	}
+	old.next = old
+	new := &config{
+		Name:   "api",
+		Ports:  []int{80},
+		Labels: map[string]string{"env": "prod"},
+		weight: 0.5,
+	}
+	new.next = new
+	a.Assert(Equal(old, new))
+	a.Assert(Equal(nil, nil))
+
+	new.weight = 1
+	a.Assert(!Equal(old, new))
+	new.weight = 0.5
+	new.Labels["env"] = "staging"
+	a.Assert(!Equal(old, new))
+	new.Labels = map[string]string{"env": "prod", "team": "infra"}
+	a.Assert(!Equal(old, new))
+	new.Labels = map[string]string{"team": "prod"}
+	a.Assert(!Equal(old, new))
+	a.Assert(!Equal(1, int64(1)))
+
+	// Maps containing themselves are compared without overflowing stack.
+	// See https://github.com/golang/go/issues/33907.
+	m1 := map[string]interface{}{"n": 1}
+	m1["loop"] = m1
+	m2 := map[string]interface{}{"n": 1}
+	m2["loop"] = m2
+	a.Assert(Equal(m1, m2))
+
+	m2["n"] = 2
+	a.Assert(!Equal(m1, m2))
+}
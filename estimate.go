@@ -0,0 +1,162 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+)
+
+// EstimateSize estimates how many bytes are allocated in heap when cloning v by Slowly.
+//
+// See Allocator.EstimateSize for more details.
+func EstimateSize(v interface{}) int {
+	if v == nil {
+		return 0
+	}
+
+	return defaultAllocator.EstimateSize(reflect.ValueOf(v))
+}
+
+// EstimateSize estimates how many bytes are allocated by a when cloning val by CloneSlowly.
+// It's designed to pre-allocate memory pools, e.g. to size the first chunk of a pool
+// to fit a whole clone.
+//
+// The estimate sums the size of all memory blocks allocated by a
+// according to all customizations in a, e.g. scalar types and opaque pointers.
+// It's not accurate in following cases.
+//   - Overhead of map buckets and chan buffers is not counted.
+//   - Memory allocated by custom funcs is estimated as if there is no custom func.
+//   - Values shared by several pointers are counted once, but Clone clones them several times.
+func (a *Allocator) EstimateSize(val reflect.Value) int {
+	if !val.IsValid() {
+		return 0
+	}
+
+	e := &estimator{
+		allocator: a,
+		config:    a.loadConfig(),
+		visited:   map[visit]struct{}{},
+	}
+	return boxedSize(val) + e.estimate(val)
+}
+
+// boxedSize returns the size of memory allocated to store v in an interface.
+func boxedSize(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Map, reflect.Ptr, reflect.UnsafePointer:
+		return 0
+	default:
+		return int(v.Type().Size())
+	}
+}
+
+type estimator struct {
+	allocator *Allocator
+	config    *config
+	visited   map[visit]struct{}
+}
+
+// estimate returns the size of memory allocated to clone v except v itself.
+func (e *estimator) estimate(v reflect.Value) (size int) {
+	kind := v.Kind()
+
+	if e.allocator.isScalar(kind) {
+		return
+	}
+
+	switch kind {
+	case reflect.Array:
+		if e.allocator.isScalar(v.Type().Elem().Kind()) {
+			return
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			size += e.estimate(v.Index(i))
+		}
+	case reflect.Chan:
+		if policy := e.config.lookupChanPolicy(); policy == ChanShare || policy == ChanNil {
+			return
+		}
+
+		size = v.Cap() * int(v.Type().Elem().Size())
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+
+		elem := v.Elem()
+		size = boxedSize(elem) + e.estimate(elem)
+	case reflect.Map:
+		if v.IsNil() || !e.visit(v, 0) {
+			return
+		}
+
+		t := v.Type()
+		size = v.Len() * int(t.Key().Size()+t.Elem().Size())
+
+		for iter := mapIter(v); iter.Next(); {
+			size += e.estimate(iter.Key())
+			size += e.estimate(iter.Value())
+		}
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+
+		if e.config.isOpaquePointer(v.Type()) {
+			if !v.CanInterface() {
+				size = int(v.Type().Size())
+			}
+
+			return
+		}
+
+		if !e.visit(v, 0) {
+			return
+		}
+
+		elem := v.Elem()
+		size = int(elem.Type().Size()) + e.estimate(elem)
+	case reflect.Slice:
+		if v.IsNil() || !e.visit(v, v.Len()) {
+			return
+		}
+
+		t := v.Type()
+		size = v.Cap() * int(t.Elem().Size())
+
+		if e.allocator.isScalar(t.Elem().Kind()) {
+			return
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			size += e.estimate(v.Index(i))
+		}
+	case reflect.Struct:
+		st := e.config.loadStructType(v.Type())
+
+		for _, pf := range st.PointerFields {
+			size += e.estimate(pf.field(v))
+		}
+	case reflect.String:
+		size = v.Len() + int(v.Type().Size())
+	}
+
+	return
+}
+
+func (e *estimator) visit(v reflect.Value, extra int) bool {
+	vst := visit{
+		p:     v.Pointer(),
+		extra: extra,
+		t:     v.Type(),
+	}
+
+	if _, ok := e.visited[vst]; ok {
+		return false
+	}
+
+	e.visited[vst] = struct{}{}
+	return true
+}
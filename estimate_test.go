@@ -0,0 +1,65 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestEstimateSize(t *testing.T) {
+	if arenaIsEnabled {
+		t.Skip("strings are not scalar if arena is enabled")
+	}
+
+	a := assert.New(t)
+
+	type node struct {
+		ID    int
+		Name  string
+		Tags  []string
+		Attrs map[string]int
+		Next  *node
+	}
+
+	sizeOfNode := int(unsafe.Sizeof(node{}))
+	sizeOfString := int(unsafe.Sizeof(""))
+
+	a.Equal(EstimateSize(nil), 0)
+	a.Equal(EstimateSize(123), int(unsafe.Sizeof(0)))
+	a.Equal(EstimateSize(&node{}), sizeOfNode)
+	a.Equal(EstimateSize(node{}), sizeOfNode)
+	a.Equal(EstimateSize(&node{
+		Tags: make([]string, 1, 4),
+	}), sizeOfNode+4*sizeOfString)
+	a.Equal(EstimateSize(&node{
+		Attrs: map[string]int{"foo": 1, "bar": 2},
+	}), sizeOfNode+2*(sizeOfString+int(unsafe.Sizeof(0))))
+
+	// Cycles are counted once.
+	n := &node{}
+	n.Next = n
+	a.Equal(EstimateSize(n), sizeOfNode)
+
+	// Scalar types and opaque pointers are not counted.
+	type withScalar struct {
+		Node *node
+	}
+	allocator := FromHeap()
+	allocator.MarkAsScalar(reflect.TypeOf(withScalar{}))
+	a.Equal(allocator.EstimateSize(reflect.ValueOf(&withScalar{Node: &node{}})), int(unsafe.Sizeof(withScalar{})))
+	allocator.MarkAsOpaquePointer(reflect.TypeOf(&node{}))
+	a.Equal(allocator.EstimateSize(reflect.ValueOf([]*node{{}})), int(unsafe.Sizeof([]*node{})+unsafe.Sizeof(uintptr(0))))
+
+	// Strings are counted if they are not scalar.
+	allocator = NewAllocator(nil, &AllocatorMethods{
+		IsScalar: func(k reflect.Kind) bool {
+			return k != reflect.String && IsScalar(k)
+		},
+	})
+	a.Equal(allocator.EstimateSize(reflect.ValueOf([]string{"abcd"})), int(unsafe.Sizeof([]string{}))+2*sizeOfString+4)
+}
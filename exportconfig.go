@@ -0,0 +1,216 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+)
+
+// Config is a snapshot of all customizations in an allocator exported by ExportConfig.
+// It's immutable and safe to be applied to any number of allocators concurrently.
+//
+// Config holds funcs registered in allocators, so it cannot be serialized.
+// It's designed to be shared by allocators in the same process, e.g. in worker goroutines.
+type Config struct {
+	config *config
+}
+
+// ExportConfig returns all customizations in a and a's parents.
+func (a *Allocator) ExportConfig() Config {
+	return Config{
+		config: a.loadConfig().flatten(),
+	}
+}
+
+// ApplyConfig applies all customizations in c to a,
+// as if all registration calls made on the exported allocator were made on a.
+// Registrations in c win registrations in a for the same type or option.
+// All other registrations in a are kept.
+//
+// The scalar kinds checker, which is set by AllocatorMethods.IsScalar, is not a part of c.
+func (a *Allocator) ApplyConfig(c Config) {
+	if c.config == nil {
+		return
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		return cfg.apply(c.config)
+	})
+}
+
+// apply returns a copy of cfg with all registrations in flattened config applied.
+func (cfg *config) apply(flattened *config) *config {
+	copied := cfg.copy()
+	types := make(map[reflect.Type]*typeConfig, len(cfg.types)+len(flattened.types))
+	profiles := make(map[string]*profile, len(cfg.profiles)+len(flattened.profiles))
+
+	for t, tc := range cfg.types {
+		types[t] = tc
+	}
+
+	for t, tc := range flattened.types {
+		merged := *tc
+
+		if own, ok := types[t]; ok {
+			merged.inherit(own)
+		}
+
+		types[t] = &merged
+	}
+
+	for name, p := range cfg.profiles {
+		profiles[name] = p
+	}
+
+	for name, p := range flattened.profiles {
+		profiles[name] = p
+	}
+
+	shapes := make(map[string]*shapeFunc, len(cfg.shapes)+len(flattened.shapes))
+
+	for shape, sf := range cfg.shapes {
+		shapes[shape] = sf
+	}
+
+	for shape, sf := range flattened.shapes {
+		shapes[shape] = sf
+	}
+
+	families := make(map[string]*familyFunc, len(cfg.families)+len(flattened.families))
+
+	for family, ff := range cfg.families {
+		families[family] = ff
+	}
+
+	for family, ff := range flattened.families {
+		families[family] = ff
+	}
+
+	tagFuncs := make(map[string]*tagFunc, len(cfg.tagFuncs)+len(flattened.tagFuncs))
+
+	for name, tf := range cfg.tagFuncs {
+		tagFuncs[name] = tf
+	}
+
+	for name, tf := range flattened.tagFuncs {
+		tagFuncs[name] = tf
+	}
+
+	tagHandlers := make(map[string]*tagHandler, len(cfg.tagHandlers)+len(flattened.tagHandlers))
+
+	for name, th := range cfg.tagHandlers {
+		tagHandlers[name] = th
+	}
+
+	for name, th := range flattened.tagHandlers {
+		tagHandlers[name] = th
+	}
+
+	copied.types = types
+	copied.profiles = profiles
+	copied.shapes = shapes
+	copied.families = families
+	copied.tagFuncs = tagFuncs
+	copied.tagHandlers = tagHandlers
+	copied.namedFuncs = cfg.namedFuncs || flattened.namedFuncs
+	copied.appendOnly = cfg.appendOnly || flattened.appendOnly
+
+	if flattened.strictMode != optionUnset {
+		copied.strictMode = flattened.strictMode
+	}
+
+	if flattened.debugMode != optionUnset {
+		copied.debugMode = flattened.debugMode
+	}
+
+	if flattened.readOnly != optionUnset {
+		copied.readOnly = flattened.readOnly
+	}
+
+	if flattened.transactional != optionUnset {
+		copied.transactional = flattened.transactional
+	}
+
+	if flattened.canonical != optionUnset {
+		copied.canonical = flattened.canonical
+	}
+
+	if flattened.minCopy != optionUnset {
+		copied.minCopy = flattened.minCopy
+	}
+
+	if flattened.shareRefs != optionUnset {
+		copied.shareRefs = flattened.shareRefs
+	}
+
+	if flattened.readOnlyMem != optionUnset {
+		copied.readOnlyMem = flattened.readOnlyMem
+	}
+
+	if flattened.useCloner != optionUnset {
+		copied.useCloner = flattened.useCloner
+	}
+
+	if flattened.yield != nil {
+		copied.yield = flattened.yield
+	}
+
+	if flattened.maxDepth != nil {
+		copied.maxDepth = flattened.maxDepth
+	}
+
+	if flattened.nodeLimit != nil {
+		copied.nodeLimit = flattened.nodeLimit
+	}
+
+	if flattened.funcStub != nil {
+		copied.funcStub = flattened.funcStub
+	}
+
+	if flattened.labels != nil {
+		copied.labels = flattened.labels
+	}
+
+	if flattened.traceHooks != nil {
+		copied.traceHooks = flattened.traceHooks
+	}
+
+	if flattened.cachePolicy != nil {
+		copied.cachePolicy = flattened.cachePolicy
+	}
+
+	if flattened.cacheSize != nil {
+		copied.cacheSize = flattened.cacheSize
+	}
+
+	if flattened.fallback != nil {
+		copied.fallback = flattened.fallback
+	}
+
+	if flattened.warning != nil {
+		copied.warning = flattened.warning
+	}
+
+	if flattened.generation != nil {
+		copied.generation = flattened.generation
+	}
+
+	if flattened.precedence != 0 {
+		copied.precedence = flattened.precedence
+	}
+
+	if flattened.chanPolicy != 0 {
+		copied.chanPolicy = flattened.chanPolicy
+	}
+
+	if flattened.nanKeyPolicy != 0 {
+		copied.nanKeyPolicy = flattened.nanKeyPolicy
+	}
+
+	if flattened.sharedPackages != nil {
+		copied.sharedPackages = cfg.sharedPackages.merge(flattened.sharedPackages.patterns)
+	}
+
+	return copied
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type exportConfigData struct {
+	Values []int
+}
+
+func TestExportConfig(t *testing.T) {
+	a := assert.New(t)
+	typeOfData := reflect.TypeOf(exportConfigData{})
+	typeOfPtr := reflect.TypeOf(&exportConfigData{})
+	typeOfOther := reflect.TypeOf(conflictData{})
+
+	parent := FromHeap()
+	parent.MarkAsOpaquePointer(typeOfPtr)
+	source := NewAllocator(nil, &AllocatorMethods{
+		Parent: parent,
+	})
+	source.SetCustomFunc(typeOfData, func(allocator *Allocator, old, new reflect.Value) {
+		new.Field(0).Set(reflect.ValueOf([]int{42}))
+	})
+	source.RegisterProfile("empty", Profile{})
+	source.SetPrecedence(PrecedenceCustomFunc)
+	c := source.ExportConfig()
+
+	// Registrations after export are not in c.
+	source.MarkAsScalar(typeOfOther)
+
+	target := NewAllocator(nil, nil)
+	target.MarkAsScalar(typeOfData)
+	target.SetStrictMode(true)
+	target.ApplyConfig(c)
+	target.ApplyConfig(Config{})
+
+	cfg := target.loadConfig()
+	a.Assert(target.IsOpaquePointer(typeOfPtr))
+	a.Assert(!target.IsMarkedAsScalar(typeOfOther))
+	a.Assert(cfg.lookupProfile("empty") != nil)
+	a.Equal(cfg.lookupPrecedence(), PrecedenceCustomFunc)
+	a.Assert(cfg.isStrictMode())
+
+	// The custom func in c wins the scalar mark in target.
+	a.Assert(!target.IsMarkedAsScalar(typeOfData))
+	a.Equal(target.Conflicts(), []Conflict(nil))
+
+	orig := []exportConfigData{{Values: []int{1}}}
+	cloned := target.Clone(reflect.ValueOf(orig)).Interface().([]exportConfigData)
+	a.Equal(cloned[0].Values, []int{42})
+}
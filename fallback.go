@@ -0,0 +1,69 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+)
+
+// FallbackFunc is a func called when an allocator cannot allocate memory of type t from its pool
+// and allocates it from heap instead.
+// The reason describes why the allocator falls back to heap.
+type FallbackFunc func(t reflect.Type, reason string)
+
+// fallbackOption wraps a FallbackFunc so that configs can tell whether it's changed.
+type fallbackOption struct {
+	fn FallbackFunc
+}
+
+// SetFallbackFunc sets a fallback func in heap allocator.
+// All allocators inherit it unless they set their own fallback funcs.
+//
+// See Allocator.SetFallbackFunc for more details.
+func SetFallbackFunc(fn FallbackFunc) {
+	defaultAllocator.SetFallbackFunc(fn)
+}
+
+// SetFallbackFunc sets a func to be called every time a falls back to heap.
+// If fn is nil, remove the fallback func in a.
+// If fallback func is not set, a inherits it from parent allocator.
+//
+// It's designed to quantify how much memory escapes to GC heap
+// in a clone which is expected to allocate all memory from a pool or an arena.
+// Allocator created by FromArena falls back to heap when allocating maps and chans.
+// Custom allocator methods should call ReportFallback when falling back to heap.
+func (a *Allocator) SetFallbackFunc(fn FallbackFunc) {
+	var opt *fallbackOption
+
+	if fn != nil {
+		opt = &fallbackOption{
+			fn: fn,
+		}
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.fallback = opt
+		return copied
+	})
+}
+
+// ReportFallback reports that a allocates memory of type t from heap
+// instead of its pool for the reason.
+// It calls the fallback func set by SetFallbackFunc if any.
+func (a *Allocator) ReportFallback(t reflect.Type, reason string) {
+	if fn := a.loadConfig().lookupFallback(); fn != nil {
+		fn(t, reason)
+	}
+}
+
+func (cfg *config) lookupFallback() FallbackFunc {
+	for current := cfg; current != nil; current = current.parent {
+		if current.fallback != nil {
+			return current.fallback.fn
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestSetFallbackFunc(t *testing.T) {
+	a := assert.New(t)
+
+	// A pool allocator which cannot allocate maps.
+	var allocator *Allocator
+	allocator = NewAllocator(nil, &AllocatorMethods{
+		MakeMap: func(pool unsafe.Pointer, t reflect.Type, n int) reflect.Value {
+			allocator.ReportFallback(t, "no map")
+			return reflect.MakeMapWithSize(t, n)
+		},
+	})
+
+	// Nothing happens without fallback func.
+	v := map[string][]map[int]bool{
+		"foo": {{1: true}, {2: false}},
+	}
+	a.Equal(allocator.Clone(reflect.ValueOf(v)).Interface(), v)
+
+	var fallbacks []reflect.Type
+	allocator.SetFallbackFunc(func(t reflect.Type, reason string) {
+		a.Equal(reason, "no map")
+		fallbacks = append(fallbacks, t)
+	})
+	a.Equal(allocator.Clone(reflect.ValueOf(v)).Interface(), v)
+	a.Equal(fallbacks, []reflect.Type{
+		reflect.TypeOf(map[string][]map[int]bool{}),
+		reflect.TypeOf(map[int]bool{}),
+		reflect.TypeOf(map[int]bool{}),
+	})
+
+	// Child inherits fallback func.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	fallbacks = nil
+	child.ReportFallback(reflect.TypeOf(0), "no map")
+	a.Equal(fallbacks, []reflect.Type{reflect.TypeOf(0)})
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"strings"
+)
+
+// familyFunc wraps a custom func set for a generic type family so that configs can tell whether it's changed.
+type familyFunc struct {
+	fn Func
+}
+
+// SetCustomFuncFamily sets a custom clone function for all instantiations of a generic struct type in heap allocator.
+//
+// See Allocator.SetCustomFuncFamily for more details.
+func SetCustomFuncFamily(family string, fn Func) {
+	defaultAllocator.SetCustomFuncFamily(family, fn)
+}
+
+// SetCustomFuncFamily sets a custom clone function for all instantiations of a generic struct type,
+// e.g. "sync/atomic.Pointer" for all `atomic.Pointer[T]` types.
+// The family is the package path and the name of the generic type without type arguments.
+// See TypeFamily for details.
+// If fn is nil, remove the custom clone function for the family.
+// If family is empty, SetCustomFuncFamily ignores it.
+//
+// The fn is called with old and new values of the instantiated type,
+// so it can get the instantiated type by old.Type().
+//
+// Custom functions set by SetCustomFunc, and marks set by MarkAsScalar, for a struct type win the family func.
+// The family func wins the shape func set by SetShapeFunc.
+func (a *Allocator) SetCustomFuncFamily(family string, fn Func) {
+	if family == "" {
+		return
+	}
+
+	var ff *familyFunc
+
+	if fn != nil {
+		ff = &familyFunc{
+			fn: fn,
+		}
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		families := make(map[string]*familyFunc, len(cfg.families)+1)
+
+		for k, v := range cfg.families {
+			families[k] = v
+		}
+
+		families[family] = ff
+		copied.families = families
+		return copied
+	})
+}
+
+// TypeFamily returns the family of an instantiated generic type t,
+// i.e. the package path and the name of the generic type without type arguments,
+// e.g. "sync/atomic.Pointer" for `atomic.Pointer[T]`.
+// If t is not an instantiated generic type, TypeFamily returns an empty string.
+func TypeFamily(t reflect.Type) string {
+	name := t.Name()
+	i := strings.IndexByte(name, '[')
+
+	if i <= 0 {
+		return ""
+	}
+
+	if pkg := t.PkgPath(); pkg != "" {
+		return pkg + "." + name[:i]
+	}
+
+	return name[:i]
+}
+
+// lookupFamilyFunc returns the nearest custom func set for the family of struct type t.
+func (cfg *config) lookupFamilyFunc(t reflect.Type) Func {
+	var family string
+
+	for current := cfg; current != nil; current = current.parent {
+		if len(current.families) == 0 {
+			continue
+		}
+
+		if family == "" {
+			family = TypeFamily(t)
+
+			if family == "" {
+				return nil
+			}
+		}
+
+		if ff, ok := current.families[family]; ok {
+			if ff == nil {
+				return nil
+			}
+
+			return ff.fn
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build go1.19
+// +build go1.19
+
+package clone
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+type familyBox[T any] struct {
+	Value T
+	Count int
+}
+
+type familyPayload struct {
+	Data []int
+}
+
+func TestTypeFamily(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(TypeFamily(reflect.TypeOf(atomic.Pointer[familyPayload]{})), "sync/atomic.Pointer")
+	a.Equal(TypeFamily(reflect.TypeOf(familyBox[map[string][]int]{})), "github.com/huandu/go-clone.familyBox")
+	a.Equal(TypeFamily(reflect.TypeOf(familyPayload{})), "")
+	a.Equal(TypeFamily(reflect.TypeOf(struct{}{})), "")
+	a.Equal(TypeFamily(reflect.TypeOf(0)), "")
+}
+
+func TestSetCustomFuncFamily(t *testing.T) {
+	a := assert.New(t)
+	var types []reflect.Type
+	allocator := FromHeap()
+	allocator.SetCustomFuncFamily("github.com/huandu/go-clone.familyBox", func(allocator *Allocator, old, new reflect.Value) {
+		types = append(types, old.Type())
+		new.Field(0).Set(allocator.Clone(old.Field(0)))
+	})
+
+	type boxes struct {
+		Int    familyBox[int]
+		Ints   *familyBox[[]int]
+		String familyBox[string]
+	}
+	orig := &boxes{
+		Int:    familyBox[int]{Value: 1, Count: 1},
+		Ints:   &familyBox[[]int]{Value: []int{1, 2}, Count: 2},
+		String: familyBox[string]{Value: "s", Count: 3},
+	}
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*boxes)
+	a.Equal(cloned.Int, familyBox[int]{Value: 1})
+	a.Equal(cloned.Ints, &familyBox[[]int]{Value: []int{1, 2}})
+	a.Equal(cloned.String, familyBox[string]{Value: "s"})
+	a.Assert(&cloned.Ints.Value[0] != &orig.Ints.Value[0])
+	a.Equal(types, []reflect.Type{
+		reflect.TypeOf(familyBox[int]{}),
+		reflect.TypeOf(familyBox[[]int]{}),
+		reflect.TypeOf(familyBox[string]{}),
+	})
+	a.Equal(allocator.BehaviorOf(reflect.TypeOf(familyBox[int]{})).Reason, "family func")
+
+	// Custom func for a type wins the family func.
+	allocator.SetCustomFunc(reflect.TypeOf(familyBox[int]{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.Field(1).SetInt(42)
+	})
+	cloned = allocator.Clone(reflect.ValueOf(orig)).Interface().(*boxes)
+	a.Equal(cloned.Int, familyBox[int]{Count: 42})
+
+	// Remove family func.
+	allocator.SetCustomFuncFamily("github.com/huandu/go-clone.familyBox", nil)
+	cloned = allocator.Clone(reflect.ValueOf(orig)).Interface().(*boxes)
+	a.Equal(cloned.Ints, orig.Ints)
+	a.Equal(cloned.String, orig.String)
+}
+
+func TestSetCustomFuncFamilyAtomicPointer(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.SetCustomFuncFamily("sync/atomic.Pointer", func(allocator *Allocator, old, new reflect.Value) {
+		src := (*unsafe.Pointer)(unsafe.Pointer(old.UnsafeAddr()))
+		dst := (*unsafe.Pointer)(unsafe.Pointer(new.UnsafeAddr()))
+		atomic.StorePointer(dst, atomic.LoadPointer(src))
+	})
+
+	type pointers struct {
+		P1 atomic.Pointer[familyPayload]
+		P2 atomic.Pointer[int]
+	}
+	p1 := &familyPayload{Data: []int{1}}
+	p2 := new(int)
+	orig := &pointers{}
+	orig.P1.Store(p1)
+	orig.P2.Store(p2)
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*pointers)
+	a.Assert(cloned.P1.Load() == p1)
+	a.Assert(cloned.P2.Load() == p2)
+
+	// Registrations can be undone.
+	scope := allocator.Register(func(allocator *Allocator) {
+		allocator.SetCustomFuncFamily("sync/atomic.Pointer", nil)
+	})
+	a.Assert(allocator.loadStructType(reflect.TypeOf(atomic.Pointer[int]{})).fn == nil)
+	scope.Close()
+	a.Assert(allocator.loadStructType(reflect.TypeOf(atomic.Pointer[int]{})).fn != nil)
+
+	// Families are exported with config.
+	other := FromHeap()
+	other.ApplyConfig(allocator.ExportConfig())
+	a.Assert(other.loadStructType(reflect.TypeOf(atomic.Pointer[int]{})).fn != nil)
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"unsafe"
+
+	"github.com/huandu/go-clone/walk"
+)
+
+// FindPaths returns paths to all values inside root which point to target.
+// It's a debugging aid to locate where a pointer lives in a value,
+// e.g. to find out why a cloned value shares memory with the original one.
+//
+// A value points to target if it's a pointer, unsafe.Pointer, map, chan or slice
+// and its underlying pointer equals to target.
+// Paths are written in Go selector syntax relative to root like `.Foo[2]["key"]`.
+// The empty path means root itself.
+//
+// FindPaths can walk values with pointer cycles. Every pointer, map and slice is walked once,
+// so that a value reachable from several paths is reported once for each path
+// but its descendants are reported only under the first path walked.
+func FindPaths(root interface{}, target unsafe.Pointer) []string {
+	if root == nil || target == nil {
+		return nil
+	}
+
+	var paths []string
+	walk.Walk(root, walk.VisitorFunc(func(path string, v reflect.Value) bool {
+		switch v.Kind() {
+		case reflect.Chan, reflect.Map, reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+			if !v.IsNil() && v.Pointer() == uintptr(target) {
+				paths = append(paths, path)
+			}
+		}
+
+		// Opaque pointers are never cloned in depth. Don't walk into them.
+		return v.Kind() != reflect.Ptr || !defaultAllocator.isOpaquePointer(v.Type())
+	}))
+	return paths
+}
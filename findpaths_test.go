@@ -0,0 +1,58 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestFindPaths(t *testing.T) {
+	a := assert.New(t)
+
+	type node struct {
+		Value int
+		next  *node
+		Items []*node
+		Index map[string]interface{}
+	}
+
+	target := &node{Value: 1}
+	root := &node{
+		Value: 0,
+		next:  target,
+		Items: []*node{{Value: 2}, target},
+		Index: map[string]interface{}{
+			"target": target,
+			"other":  &node{Value: 3},
+		},
+	}
+	target.next = root // Make a cycle.
+
+	paths := FindPaths(root, unsafe.Pointer(target))
+	a.Equal(paths, []string{".next", ".Items[1]", `.Index["target"]`})
+
+	paths = FindPaths(root, unsafe.Pointer(root))
+	a.Equal(paths, []string{"", ".next.next"})
+
+	a.Equal(FindPaths(root, unsafe.Pointer(&root.Items)), []string(nil))
+	a.Equal(FindPaths(nil, unsafe.Pointer(root)), []string(nil))
+	a.Equal(FindPaths(root, nil), []string(nil))
+}
+
+func TestFindPathsInArrayAndSlice(t *testing.T) {
+	a := assert.New(t)
+
+	data := []int{1, 2, 3}
+	arr := [2][]int{nil, data}
+	paths := FindPaths(arr, unsafe.Pointer(&data[0]))
+	a.Equal(paths, []string{"[1]"})
+
+	ch := make(chan int)
+	m := map[int]chan int{1: ch}
+	paths = FindPaths(m, *(*unsafe.Pointer)(unsafe.Pointer(&ch)))
+	a.Equal(paths, []string{"[1]"})
+}
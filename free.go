@@ -0,0 +1,153 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+)
+
+// Free walks the value graph of cloned, a value cloned by a,
+// and returns every pointer, slice, map and chan in the graph to the pool by AllocatorMethods.Free.
+// Values in the graph are freed before values referencing them,
+// and every value is freed once even if it's referenced many times, e.g. in a value cloned by Slowly.
+// If AllocatorMethods.Free is not set, Free does nothing.
+//
+// Values shared with the source are not freed, including values pointed by opaque pointers,
+// append-only slices, shared chans and map keys, fields tagged with `clone:"shadowcopy"` or `clone:"parent"`,
+// and values of types cloned by custom funcs or Clone methods,
+// as they may not be allocated by a.
+// Strings and the memory holding cloned itself are not freed either,
+// so pass a pointer to free all memory of a cloned struct.
+//
+// If a falls back to another allocator, values allocated by the fallback allocator are passed to
+// AllocatorMethods.Free as well, so Free method must ignore values not allocated from the pool.
+// The cloned and all values in its graph must not be used after Free.
+func (a *Allocator) Free(cloned reflect.Value) {
+	if a.free == nil || !cloned.IsValid() {
+		return
+	}
+
+	fs := &freeState{
+		allocator: a,
+		config:    a.loadConfig(),
+		visited:   map[visit]struct{}{},
+	}
+	fs.free(cloned)
+}
+
+type freeState struct {
+	allocator *Allocator
+	config    *config
+	visited   map[visit]struct{}
+}
+
+// free frees v and all values referenced by v.
+func (fs *freeState) free(v reflect.Value) {
+	t := v.Type()
+
+	if fs.config.lookupNamedFunc(t) != nil || (fs.config.isUsingCloner() && fs.config.hasClonerMethod(t)) {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || fs.config.isOpaquePointer(t) || !fs.mark(v, 0) {
+			return
+		}
+
+		fs.walk(v.Elem())
+	case reflect.Slice:
+		if v.IsNil() || fs.config.isAppendOnly(t) || !fs.mark(v, v.Len()) {
+			return
+		}
+
+		if !fs.config.isScalarType(t.Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				fs.free(v.Index(i))
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() || !fs.mark(v, 0) {
+			return
+		}
+
+		freeKeys := !fs.config.isScalar(t.Key().Kind()) && fs.config.lookupMapKeyPolicy(t) != MapKeyPolicyShare
+		freeValues := !fs.config.isScalarType(t.Elem())
+
+		for iter := mapIter(v); iter.Next(); {
+			if freeKeys {
+				fs.free(iter.Key())
+			}
+
+			if freeValues {
+				fs.free(iter.Value())
+			}
+		}
+	case reflect.Chan:
+		if v.IsNil() || fs.config.lookupChanPolicy() == ChanShare || !fs.mark(v, 0) {
+			return
+		}
+	default:
+		fs.walk(v)
+		return
+	}
+
+	if !v.CanInterface() {
+		v = forceClearROFlag(v)
+	}
+
+	fs.allocator.free(fs.allocator.pool, v)
+}
+
+// walk frees all values referenced by v without freeing v itself.
+func (fs *freeState) walk(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Array:
+		if fs.config.isScalarType(v.Type().Elem()) {
+			return
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			fs.free(v.Index(i))
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+
+		elem := v.Elem()
+
+		if !IsExportedType(elem.Type()) && fs.config.lookupInterfacePolicy(v.Type()) != InterfacePolicyClone {
+			return
+		}
+
+		fs.free(elem)
+	case reflect.Struct:
+		st := fs.config.loadStructType(v.Type())
+
+		if st.fn != nil {
+			return
+		}
+
+		for _, pf := range st.PointerFields {
+			fs.free(pf.field(v))
+		}
+	}
+}
+
+// mark marks v as freed and returns false if v is freed already.
+func (fs *freeState) mark(v reflect.Value, extra int) bool {
+	vst := visit{
+		p:     v.Pointer(),
+		extra: extra,
+		t:     v.Type(),
+	}
+
+	if _, ok := fs.visited[vst]; ok {
+		return false
+	}
+
+	fs.visited[vst] = struct{}{}
+	return true
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+type freeNode struct {
+	Name     string
+	Children []*freeNode
+	Attrs    map[string]*int
+	Parent   *freeNode
+	Any      interface{}
+	Shared   *int `clone:"shadowcopy"`
+	Events   chan int
+	values   [2]*int
+}
+
+type freeInts []int
+
+type freeRecorder struct {
+	freed map[reflect.Type]int
+	ptrs  map[uintptr]int
+}
+
+func newFreeAllocator() (*Allocator, *freeRecorder) {
+	rec := &freeRecorder{
+		freed: map[reflect.Type]int{},
+		ptrs:  map[uintptr]int{},
+	}
+	allocator := NewAllocator(unsafe.Pointer(rec), &AllocatorMethods{
+		Free: func(pool unsafe.Pointer, v reflect.Value) {
+			r := (*freeRecorder)(pool)
+			r.freed[v.Type()]++
+			r.ptrs[v.Pointer()]++
+		},
+	})
+	return allocator, rec
+}
+
+func TestAllocatorFree(t *testing.T) {
+	a := assert.New(t)
+	allocator, rec := newFreeAllocator()
+
+	n1, n2, shared := 1, 2, 3
+	root := &freeNode{
+		Name: "root",
+		Attrs: map[string]*int{
+			"n1": &n1,
+		},
+		Shared: &shared,
+		Events: make(chan int, 1),
+		values: [2]*int{&n2, nil},
+	}
+	child := &freeNode{
+		Name:   "child",
+		Parent: root,
+		Any:    &n2,
+	}
+	root.Children = []*freeNode{child}
+	root.Any = child
+
+	cloned := allocator.CloneSlowly(reflect.ValueOf(root))
+	allocator.Free(cloned)
+
+	a.Equal(rec.freed, map[reflect.Type]int{
+		reflect.TypeOf(&freeNode{}):       2,
+		reflect.TypeOf([]*freeNode{}):     1,
+		reflect.TypeOf(map[string]*int{}): 1,
+		reflect.TypeOf(&n1):               2,
+		reflect.TypeOf(make(chan int)):    1,
+	})
+
+	for _, count := range rec.ptrs {
+		a.Equal(count, 1)
+	}
+
+	// Shared values are not freed.
+	c := cloned.Interface().(*freeNode)
+	a.Equal(c.Shared, &shared)
+	a.Equal(rec.ptrs[reflect.ValueOf(&shared).Pointer()], 0)
+}
+
+func TestAllocatorFreeShared(t *testing.T) {
+	a := assert.New(t)
+	allocator, rec := newFreeAllocator()
+	allocator.MarkAsOpaquePointer(reflect.TypeOf(&freeNode{}))
+	allocator.SetChanPolicy(ChanShare)
+	allocator.SetCustomFunc(reflect.TypeOf(freeInts{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.Set(old)
+	})
+
+	type T struct {
+		Node   *freeNode
+		Events chan int
+		Ints   freeInts
+		Ptr    *int
+	}
+	v := &T{
+		Node:   &freeNode{},
+		Events: make(chan int),
+		Ints:   freeInts{1},
+		Ptr:    new(int),
+	}
+	cloned := allocator.Clone(reflect.ValueOf(v))
+	allocator.Free(cloned)
+	a.Equal(rec.freed, map[reflect.Type]int{
+		reflect.TypeOf(&T{}):     1,
+		reflect.TypeOf(new(int)): 1,
+	})
+
+	// Free does nothing without AllocatorMethods.Free.
+	FromHeap().Free(cloned)
+	FromHeap().Free(reflect.Value{})
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+)
+
+// FuncStubFactory is a func to make a stub func of type t to replace a func value in clones.
+// The returned value must be assignable to t.
+// If the returned value is invalid, the func value is set to nil in clones.
+type FuncStubFactory func(t reflect.Type) reflect.Value
+
+// funcStubOption wraps a FuncStubFactory so that configs can tell whether it's changed.
+type funcStubOption struct {
+	factory FuncStubFactory
+}
+
+// SetFuncStubFactory sets a factory of stub funcs in heap allocator.
+//
+// See Allocator.SetFuncStubFactory for more details.
+func SetFuncStubFactory(factory FuncStubFactory) {
+	defaultAllocator.SetFuncStubFactory(factory)
+}
+
+// SetFuncStubFactory sets a factory to make stub funcs replacing all non-nil func values in clones made by a.
+// If factory is nil, remove the factory in a.
+// If factory is not set, a inherits it from parent allocator.
+//
+// Func values are usually closures capturing live states, e.g. loop variables, locks or connections.
+// It's designed for snapshots which are serialized or transferred to another process,
+// so that clones never call into live closures by mistake.
+//
+// The factory is called with the type of every func value cloned by a.
+// Custom funcs set for func types win the factory.
+// Func fields tagged with `clone:"shadowcopy"` are copied as they are.
+func (a *Allocator) SetFuncStubFactory(factory FuncStubFactory) {
+	var opt *funcStubOption
+
+	if factory != nil {
+		opt = &funcStubOption{
+			factory: factory,
+		}
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.funcStub = opt
+		return copied
+	})
+}
+
+func (cfg *config) lookupFuncStub() FuncStubFactory {
+	for current := cfg; current != nil; current = current.parent {
+		if current.funcStub != nil {
+			return current.funcStub.factory
+		}
+	}
+
+	return nil
+}
+
+// stubFunc returns a stub func made by factory to replace func value v.
+func stubFunc(factory FuncStubFactory, v reflect.Value) reflect.Value {
+	t := v.Type()
+
+	if v.IsNil() {
+		return reflect.Zero(t)
+	}
+
+	stub := factory(t)
+
+	if !stub.IsValid() {
+		return reflect.Zero(t)
+	}
+
+	nv := reflect.New(t).Elem()
+	nv.Set(stub)
+	return nv
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type funcStubHandler func(string) string
+
+type funcStubHandlers struct {
+	OnEvent  funcStubHandler
+	Handlers map[string]funcStubHandler
+	Values   []interface{}
+	onClose  func()
+	Nil      func()
+	Shared   func() `clone:"shadowcopy"`
+}
+
+func TestSetFuncStubFactory(t *testing.T) {
+	a := assert.New(t)
+	counter := 0
+	live := funcStubHandler(func(s string) string {
+		counter++
+		return "live " + s
+	})
+	orig := &funcStubHandlers{
+		OnEvent:  live,
+		Handlers: map[string]funcStubHandler{"event": live},
+		Values:   []interface{}{live, 1},
+		onClose:  func() { counter++ },
+		Shared:   func() { counter++ },
+	}
+
+	allocator := FromHeap()
+	allocator.SetFuncStubFactory(func(t reflect.Type) reflect.Value {
+		if t == reflect.TypeOf(live) {
+			return reflect.ValueOf(funcStubHandler(func(s string) string {
+				return "stub " + s
+			}))
+		}
+
+		return reflect.Value{}
+	})
+
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*funcStubHandlers)
+	a.Equal(cloned.OnEvent("a"), "stub a")
+	a.Equal(cloned.Handlers["event"]("b"), "stub b")
+	a.Equal(cloned.Values[0].(funcStubHandler)("c"), "stub c")
+	a.Equal(cloned.Values[1], 1)
+	a.Assert(cloned.onClose == nil)
+	a.Assert(cloned.Nil == nil)
+	cloned.Shared()
+	a.Equal(counter, 1)
+
+	// Custom funcs set for func types win the factory.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	child.SetCustomFunc(reflect.TypeOf(live), func(allocator *Allocator, old, new reflect.Value) {
+		new.Set(old)
+	})
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().(*funcStubHandlers)
+	a.Equal(cloned.OnEvent("d"), "live d")
+	a.Assert(cloned.onClose == nil)
+
+	// Remove the factory.
+	allocator.SetFuncStubFactory(nil)
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().(*funcStubHandlers)
+	a.Assert(cloned.onClose != nil)
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type generationData struct {
+	Gen  uint64 `clone:"generation"`
+	Name string
+}
+
+type generationNode struct {
+	Gen      int32  `clone:"generation"`
+	Label    string `clone:"generation"` // Ignored.
+	Data     generationData
+	Children []*generationNode
+}
+
+func TestGeneration(t *testing.T) {
+	a := assert.New(t)
+	orig := &generationNode{
+		Gen:   -1,
+		Label: "root",
+		Data:  generationData{Name: "data"},
+		Children: []*generationNode{
+			{Label: "child"},
+		},
+	}
+
+	cloned := Clone(orig).(*generationNode)
+	gen := cloned.Data.Gen
+	a.Assert(gen != 0)
+	a.Equal(cloned.Gen, int32(gen))
+	a.Equal(cloned.Children[0].Gen, int32(gen))
+	a.Equal(cloned.Children[0].Data.Gen, gen)
+	a.Equal(cloned.Label, "root")
+	a.Equal(cloned.Data.Name, "data")
+	a.Equal(orig.Gen, int32(-1))
+
+	// Every clone has a new generation.
+	cloned = Slowly(cloned).(*generationNode)
+	a.Assert(cloned.Data.Gen > gen)
+	a.Equal(cloned.Gen, int32(cloned.Data.Gen))
+
+	// Structs with generation fields cannot be shadow copied.
+	values := []generationData{{Name: "a"}, {Name: "b"}}
+	clonedValues := Clone(values).([]generationData)
+	a.Assert(clonedValues[0].Gen != 0)
+	a.Equal(clonedValues[0].Gen, clonedValues[1].Gen)
+	a.Equal(clonedValues[1].Name, "b")
+}
+
+func TestSetGenerationFunc(t *testing.T) {
+	a := assert.New(t)
+	calls := 0
+	allocator := NewAllocator(nil, nil)
+	allocator.SetGenerationFunc(func() uint64 {
+		calls++
+		return 42
+	})
+
+	orig := []*generationNode{{}, {Children: []*generationNode{{}}}}
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().([]*generationNode)
+	a.Equal(cloned[0].Gen, int32(42))
+	a.Equal(cloned[1].Children[0].Data.Gen, uint64(42))
+	a.Equal(calls, 1)
+
+	// No generation field, no call.
+	allocator.Clone(reflect.ValueOf([]int{1}))
+	a.Equal(calls, 1)
+
+	// Child allocators inherit generation func.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	clonedData := child.Clone(reflect.ValueOf(generationData{})).Interface().(generationData)
+	a.Equal(clonedData.Gen, uint64(42))
+	a.Equal(calls, 2)
+
+	// Custom funcs cannot override generation.
+	child.SetCustomFunc(reflect.TypeOf(generationData{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.Field(0).SetUint(1)
+		new.Field(1).SetString("custom")
+	})
+	clonedValues := child.Clone(reflect.ValueOf([]generationData{{}})).Interface().([]generationData)
+	a.Equal(clonedValues[0], generationData{Gen: 42, Name: "custom"})
+
+	// Nil func means the default counter.
+	child.SetGenerationFunc(nil)
+	clonedData = child.Clone(reflect.ValueOf(generationData{})).Interface().(generationData)
+	a.Assert(clonedData.Gen != 42 && clonedData.Gen != 0)
+
+	scope := child.Register(func(a *Allocator) {
+		a.SetGenerationFunc(func() uint64 { return 7 })
+	})
+	clonedData = child.Clone(reflect.ValueOf(generationData{})).Interface().(generationData)
+	a.Equal(clonedData.Gen, uint64(7))
+
+	scope.Close()
+	clonedData = child.Clone(reflect.ValueOf(generationData{})).Interface().(generationData)
+	a.Assert(clonedData.Gen != 7 && clonedData.Gen != 42)
+}
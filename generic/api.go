@@ -41,6 +41,18 @@ func Undo[T any](t T) {
 	clone.Undo(t)
 }
 
+func WrapT[T any](v *T) *T {
+	return clone.WrapT(v)
+}
+
+func UnwrapT[T any](wrapped *T) *T {
+	return clone.UnwrapT(wrapped)
+}
+
+func UndoT[T any](wrapped *T) {
+	clone.UndoT(wrapped)
+}
+
 func MarkAsOpaquePointer(t reflect.Type) {
 	clone.MarkAsOpaquePointer(t)
 }
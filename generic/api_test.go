@@ -37,6 +37,13 @@ func TestGenericAPI(t *testing.T) {
 
 	Undo(v)
 	a.Equal(v, original)
+
+	v = WrapT(original)
+	a.Assert(UnwrapT(v) == original)
+
+	v.Foo = 777
+	UndoT(v)
+	a.Equal(v, original)
 }
 
 type MyPointer struct {
@@ -9,23 +9,14 @@ package clone
 import (
 	"arena"
 	"reflect"
-	"runtime"
-	"unsafe"
 
 	"github.com/huandu/go-clone"
 )
 
-// The arenaAllocator allocates memory from arena.
-var arenaAllocatorMethods = &clone.AllocatorMethods{
-	New:       arenaNew,
-	MakeSlice: arenaMakeSlice,
-	MakeMap:   arenaMakeMap,
-	MakeChan:  arenaMakeChan,
-}
-
 // FromArena creates an allocator using arena a to allocate memory.
+// It's the same as FromArena in the main package.
 func FromArena(a *arena.Arena) *clone.Allocator {
-	return clone.NewAllocator(unsafe.Pointer(a), arenaAllocatorMethods)
+	return clone.FromArena(a)
 }
 
 // ArenaClone recursively deep clones v to a new value in arena a.
@@ -58,46 +49,25 @@ func ArenaCloneSlowly[T any](a *arena.Arena, v T) (nv T) {
 	return
 }
 
-func arenaNew(pool unsafe.Pointer, t reflect.Type) reflect.Value {
-	return reflect.ArenaNew((*arena.Arena)(pool), reflect.PtrTo(t))
-}
-
-// Define the slice header again to mute golint's warning.
-type sliceHeader reflect.SliceHeader
-
-func arenaMakeSlice(pool unsafe.Pointer, t reflect.Type, len, cap int) reflect.Value {
-	a := (*arena.Arena)(pool)
-
-	// As of go1.20, there is no reflect method to allocate slice in arena.
-	// Following code is a hack to allocate a large enough byte buffer
-	// and then cast it to T[].
-	et := t.Elem()
-	l := int(et.Size())
-	total := l * cap
-
-	data := arena.MakeSlice[byte](a, total, total)
-	ptr := unsafe.Pointer(&data[0])
-	elem := reflect.NewAt(et, ptr)
-	slicePtr := reflect.ArenaNew(a, reflect.PtrTo(t))
-	*(*sliceHeader)(slicePtr.UnsafePointer()) = sliceHeader{
-		Data: elem.Pointer(),
-		Len:  l,
-		Cap:  cap,
+// ArenaMakeCloneSlice makes a new slice in arena a and deeply clones all elements in src to it.
+// The new slice has the same len and cap as src.
+// If src is nil, ArenaMakeCloneSlice returns nil.
+//
+// If elements of T can be shadow copied, e.g. T is a number type or a struct of numbers,
+// ArenaMakeCloneSlice copies src to the new slice directly without reflection.
+// Otherwise, it works in the same way as ArenaClone(a, src).
+func ArenaMakeCloneSlice[T any](a *arena.Arena, src []T) []T {
+	if src == nil {
+		return nil
 	}
-	runtime.KeepAlive(elem)
 
-	slice := slicePtr.Elem()
-	return slice.Slice3(0, len, cap)
-}
+	allocator := FromArena(a)
 
-func arenaMakeMap(pool unsafe.Pointer, t reflect.Type, n int) reflect.Value {
-	// As of go1.20, there is no way to allocate map in arena.
-	// Fallback to heap allocation.
-	return reflect.MakeMapWithSize(t, n)
-}
+	if !allocator.CanShadowCopy(reflect.TypeOf((*T)(nil)).Elem()) {
+		return allocator.Clone(reflect.ValueOf(src)).Interface().([]T)
+	}
 
-func arenaMakeChan(pool unsafe.Pointer, t reflect.Type, buffer int) reflect.Value {
-	// As of go1.20, there is no way to allocate chan in arena.
-	// Fallback to heap allocation.
-	return reflect.MakeChan(t, buffer)
+	dst := arena.MakeSlice[T](a, len(src), cap(src))
+	copy(dst, src)
+	return dst
 }
@@ -66,3 +66,34 @@ func TestArenaClone(t *testing.T) {
 	// Make sure ar is alive.
 	runtime.KeepAlive(ar)
 }
+
+func TestArenaMakeCloneSlice(t *testing.T) {
+	a := assert.New(t)
+	ar := arena.NewArena()
+
+	type point struct {
+		X, Y int
+	}
+
+	a.Equal(ArenaMakeCloneSlice[int](ar, nil), []int(nil))
+
+	points := make([]point, 2, 4)
+	points[1] = point{X: 1, Y: 2}
+	clonedPoints := ArenaMakeCloneSlice(ar, points)
+	a.Equal(clonedPoints, points)
+	a.Equal(cap(clonedPoints), 4)
+	a.Assert(&clonedPoints[0] != &points[0])
+
+	// The slice is allocated in arena.
+	s := arena.Clone(clonedPoints)
+	a.Assert(&s[0] != &clonedPoints[0])
+
+	ptrs := []*point{{X: 3}, nil}
+	clonedPtrs := ArenaMakeCloneSlice(ar, ptrs)
+	a.Equal(clonedPtrs, ptrs)
+	a.Assert(clonedPtrs[0] != ptrs[0])
+	a.Assert(arena.Clone(clonedPtrs[0]) != clonedPtrs[0])
+
+	// Make sure ar is alive.
+	runtime.KeepAlive(ar)
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+
+	"github.com/huandu/go-clone"
+)
+
+// TypedCloner clones values of T with a bound allocator.
+// It's a typed version of Cloner, which neither boxes values in interface{}
+// nor asserts types of cloned values.
+//
+// The name Cloner is taken by the alias of clone.Cloner in this package.
+type TypedCloner[T any] struct {
+	allocator *Allocator
+}
+
+// MakeTypedCloner creates a cloner of T with allocator.
+// If allocator is nil, values are cloned in heap.
+func MakeTypedCloner[T any](allocator *Allocator) TypedCloner[T] {
+	if allocator == nil {
+		allocator = clone.FromHeap()
+	}
+
+	return TypedCloner[T]{
+		allocator: allocator,
+	}
+}
+
+// Allocator returns the allocator bound to c.
+func (c TypedCloner[T]) Allocator() *Allocator {
+	return c.allocator
+}
+
+// Clone clones v with the bound allocator in the same way as Cloner.Clone,
+// so that custom func of T applies to v.
+// If T is an interface type, the dynamic value of v is cloned.
+//
+// If T contains scalar values only, Clone returns v without any memory allocation.
+func (c TypedCloner[T]) Clone(v T) T {
+	if c.canShadowCopy() {
+		return v
+	}
+
+	return c.clone(v)
+}
+
+// CloneSlowly clones v with the bound allocator in the same way as Cloner.CloneSlowly.
+// It can clone v with cycle pointer.
+func (c TypedCloner[T]) CloneSlowly(v T) T {
+	if c.canShadowCopy() {
+		return v
+	}
+
+	return c.cloneSlowly(v)
+}
+
+// clone and cloneSlowly take the address of v.
+// They are separated from Clone and CloneSlowly, so that v doesn't escape to heap if T can be shadow copied.
+func (c TypedCloner[T]) clone(v T) (nv T) {
+	clone.MakeCloner(c.allocator).CloneInto(&nv, &v)
+	return
+}
+
+func (c TypedCloner[T]) cloneSlowly(v T) T {
+	// The pointer to v is cloned, so that v itself is not boxed in interface{}.
+	return *clone.MakeCloner(c.allocator).CloneSlowly(&v).(*T)
+}
+
+func (c TypedCloner[T]) canShadowCopy() bool {
+	return c.allocator.CanShadowCopy(reflect.TypeOf((*T)(nil)).Elem())
+}
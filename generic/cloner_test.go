@@ -0,0 +1,99 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type typedClonerPoint struct {
+	X, Y int
+}
+
+type typedClonerNode struct {
+	Name string
+	Tags []string
+	Next *typedClonerNode
+}
+
+func TestTypedCloner(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	c := MakeTypedCloner[*typedClonerNode](allocator)
+	a.Assert(c.Allocator() == allocator)
+
+	v := &typedClonerNode{
+		Name: "foo",
+		Tags: []string{"bar"},
+	}
+	cloned := c.Clone(v)
+	a.Equal(cloned, v)
+	a.Assert(cloned != v)
+	a.Assert(&cloned.Tags[0] != &v.Tags[0])
+
+	// CloneSlowly can clone cycle pointers.
+	v.Next = v
+	cloned = c.CloneSlowly(v)
+	a.Assert(cloned != v)
+	a.Assert(cloned.Next == cloned)
+
+	var nilNode *typedClonerNode
+	a.Equal(c.Clone(nilNode), nil)
+	a.Equal(c.CloneSlowly(nilNode), nil)
+
+	// Values of interface types are cloned by dynamic types.
+	ic := MakeTypedCloner[interface{}](nil)
+	a.Assert(ic.Allocator() != nil)
+	iv := ic.Clone(&typedClonerNode{Name: "foo"})
+	a.Equal(iv, &typedClonerNode{Name: "foo"})
+	a.Equal(ic.Clone(nil), nil)
+	a.Equal(ic.CloneSlowly([]int{1}), []int{1})
+
+	// Scalar values are returned as they are.
+	sc := MakeTypedCloner[typedClonerPoint](nil)
+	a.Equal(sc.Clone(typedClonerPoint{1, 2}), typedClonerPoint{1, 2})
+	a.Equal(sc.CloneSlowly(typedClonerPoint{1, 2}), typedClonerPoint{1, 2})
+}
+
+func TestTypedClonerAllocs(t *testing.T) {
+	a := assert.New(t)
+	c := MakeTypedCloner[typedClonerPoint](nil)
+	v := typedClonerPoint{1, 2}
+	allocs := testing.AllocsPerRun(100, func() {
+		v = c.Clone(v)
+	})
+	a.Equal(allocs, 0.0)
+}
+
+type typedClonerSecret struct {
+	Key  string
+	Tags []string
+}
+
+func TestTypedClonerCustomFunc(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.SetCustomFunc(reflect.TypeOf(typedClonerSecret{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.FieldByName("Key").SetString("redacted")
+	})
+
+	v := typedClonerSecret{
+		Key:  "secret",
+		Tags: []string{"a"},
+	}
+	expected := MakeCloner(allocator).Clone(v).(typedClonerSecret)
+	a.Equal(expected, typedClonerSecret{Key: "redacted"})
+
+	// Custom func of T applies to the root value as it does in Cloner.
+	c := MakeTypedCloner[typedClonerSecret](allocator)
+	a.Equal(c.Clone(v), expected)
+	a.Equal(c.CloneSlowly(v), expected)
+
+	pc := MakeTypedCloner[*typedClonerSecret](allocator)
+	a.Equal(pc.Clone(&v), &expected)
+	a.Equal(pc.CloneSlowly(&v), &expected)
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build go1.23
+
+package clone
+
+import (
+	"iter"
+
+	"github.com/huandu/go-clone"
+)
+
+// CloneSeq returns an iterator yielding deep clones of all values yielded by seq.
+// Values are cloned lazily when they are yielded.
+//
+// Iterators are funcs, which are copied by value when cloning,
+// so that iterators stored in structs are always shared by the clones.
+func CloneSeq[T any](seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if !yield(cloneValue(v)) {
+				return
+			}
+		}
+	}
+}
+
+// CloneSeq2 returns an iterator yielding deep clones of all pairs yielded by seq.
+// Values are cloned lazily when they are yielded.
+func CloneSeq2[K, V any](seq iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range seq {
+			if !yield(cloneValue(k), cloneValue(v)) {
+				return
+			}
+		}
+	}
+}
+
+func cloneValue[T any](v T) (nv T) {
+	if cloned := clone.Clone(v); cloned != nil {
+		nv = cloned.(T)
+	}
+
+	return
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build go1.23
+
+package clone
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestCloneSeq(t *testing.T) {
+	a := assert.New(t)
+	data := [][]int{{1}, {2, 3}, nil}
+	var cloned [][]int
+
+	for v := range CloneSeq(slices.Values(data)) {
+		cloned = append(cloned, v)
+	}
+
+	a.Equal(cloned, data)
+	a.Assert(&cloned[0][0] != &data[0][0])
+
+	// Stop early.
+	cnt := 0
+
+	for range CloneSeq(slices.Values(data)) {
+		cnt++
+		break
+	}
+
+	a.Equal(cnt, 1)
+}
+
+func TestCloneSeq2(t *testing.T) {
+	a := assert.New(t)
+	data := map[string]*MyType{
+		"foo": {Foo: 1},
+		"bar": {Foo: 2},
+	}
+	cloned := maps.Collect(CloneSeq2(maps.All(data)))
+
+	a.Equal(cloned, data)
+	a.Assert(cloned["foo"] != data["foo"])
+}
+
+func TestCloneIteratorInStruct(t *testing.T) {
+	a := assert.New(t)
+
+	type Holder struct {
+		Seq iter.Seq[int]
+	}
+
+	h := &Holder{
+		Seq: slices.Values([]int{1, 2, 3}),
+	}
+	cloned := Clone(h)
+
+	a.Assert(cloned != h)
+	a.Equal(slices.Collect(cloned.Seq), []int{1, 2, 3})
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"sync"
+	"unsafe"
+
+	"github.com/huandu/go-clone"
+)
+
+// Lazy is a lazily cloned value of T.
+//
+// If T is a struct, fields of T are cloned on first access through LazyField,
+// so that it doesn't pay for cloning fields which are never used.
+// Fields are cloned one by one with clone.Clone, and struct tags `clone:"skip"`,
+// `clone:"zero"` and `clone:"shadowcopy"` on fields of T are respected.
+// Custom funcs or scalar marks of T itself are not used.
+//
+// Go cannot intercept reads on a plain *T, so values inside Lazy are only
+// accessible after they are cloned by LazyField or Materialize.
+type Lazy[T any] struct {
+	mu     sync.Mutex
+	orig   *T
+	shadow *T     // A shadow copy of orig, whose fields are replaced by clones on access.
+	fields []bool // Whether a struct field in shadow is cloned.
+	all    bool
+}
+
+// LazyClone returns a lazily cloned value of v.
+// The v must not be modified until all needed values are cloned.
+func LazyClone[T any](v *T) *Lazy[T] {
+	l := &Lazy[T]{
+		orig: v,
+	}
+
+	if v == nil {
+		l.all = true
+		return l
+	}
+
+	l.shadow = new(T)
+
+	if t := reflect.TypeOf(v).Elem(); t.Kind() == reflect.Struct {
+		*l.shadow = *v
+		l.fields = make([]bool, t.NumField())
+	}
+
+	return l
+}
+
+// LazyField clones a field of l on first access and returns a pointer to the cloned field.
+// The field func must return a pointer to a field of the struct passed to it, e.g.
+//
+//	orders := LazyField(l, func(u *User) *[]*Order { return &u.Orders })
+//
+// LazyField panics if T is not a struct or field doesn't return a pointer to a field.
+// It returns nil if l is created with a nil pointer.
+func LazyField[T, F any](l *Lazy[T], field func(v *T) *F) *F {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.shadow == nil {
+		return nil
+	}
+
+	f := field(l.shadow)
+
+	if l.all {
+		return f
+	}
+
+	l.materializeField(l.fieldIndex(unsafe.Pointer(f), reflect.TypeOf(f).Elem()))
+	return f
+}
+
+// Materialize clones all values which are not cloned yet and returns the cloned value.
+// Values cloned by LazyField before are kept as is.
+func (l *Lazy[T]) Materialize() *T {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.all {
+		return l.shadow
+	}
+
+	if l.fields == nil {
+		cloned := clone.FromHeap().Clone(reflect.ValueOf(l.orig).Elem())
+		reflect.ValueOf(l.shadow).Elem().Set(cloned)
+	} else {
+		for i := range l.fields {
+			l.materializeField(i)
+		}
+	}
+
+	l.all = true
+	return l.shadow
+}
+
+func (l *Lazy[T]) materialized() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.all {
+		if l.shadow == nil {
+			return nil
+		}
+
+		return []string{""}
+	}
+
+	var paths []string
+	t := reflect.TypeOf(l.shadow).Elem()
+
+	for i, ok := range l.fields {
+		if ok {
+			paths = append(paths, "."+t.Field(i).Name)
+		}
+	}
+
+	return paths
+}
+
+func (l *Lazy[T]) materializeAll() {
+	l.Materialize()
+}
+
+func (l *Lazy[T]) fieldIndex(p unsafe.Pointer, ft reflect.Type) int {
+	t := reflect.TypeOf(l.shadow).Elem()
+
+	if t.Kind() != reflect.Struct {
+		panic("go-clone: LazyField requires a pointer to struct")
+	}
+
+	offset := uintptr(p) - uintptr(unsafe.Pointer(l.shadow))
+
+	for i := 0; i < t.NumField(); i++ {
+		if sf := t.Field(i); sf.Offset == offset && sf.Type == ft {
+			return i
+		}
+	}
+
+	panic("go-clone: LazyField requires a func returning pointer to a field")
+}
+
+func (l *Lazy[T]) materializeField(i int) {
+	if l.fields[i] {
+		return
+	}
+
+	l.fields[i] = true
+	sf := reflect.TypeOf(l.shadow).Elem().Field(i)
+	dst := reflect.NewAt(sf.Type, unsafe.Add(unsafe.Pointer(l.shadow), sf.Offset)).Elem()
+
+	policy, _ := clone.ParseFieldTag(sf.Tag)
+
+	switch policy.Action {
+	case clone.FieldSkip, clone.FieldZero:
+		dst.Set(reflect.Zero(sf.Type))
+	case clone.FieldShadowCopy:
+		// Keep the shadow copy.
+	default:
+		src := reflect.NewAt(sf.Type, unsafe.Add(unsafe.Pointer(l.orig), sf.Offset)).Elem()
+
+		if cloned := clone.FromHeap().Clone(src); cloned.IsValid() {
+			dst.Set(cloned)
+		}
+	}
+}
+
+type materializer interface {
+	materialized() []string
+	materializeAll()
+}
+
+// Materialized returns paths of values in v which are cloned and not shared with the original value.
+// Paths are in the same syntax as clone.FindPaths. The path "" means the whole value.
+//
+// Only a *Lazy[T] tracks which values are cloned, so Materialized returns nil for any other value.
+// A value returned by Wrap is not a proxy. It's always cloned as a whole, and needs no materialization.
+// Values are never probed to tell whether they are wrapped, as it reads memory out of v.
+func Materialized(v any) []string {
+	if m, ok := v.(materializer); ok {
+		return m.materialized()
+	}
+
+	return nil
+}
+
+// MaterializeAll clones all values in v which are still shared with the original value,
+// so that v is fully isolated from the original value.
+//
+// The v should be a *Lazy[T]. It does nothing for any other value,
+// including a value returned by Wrap, as it's fully isolated already.
+func MaterializeAll(v any) {
+	if m, ok := v.(materializer); ok {
+		m.materializeAll()
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type lazyOrder struct {
+	ID    int
+	Items []string
+}
+
+type lazyUser struct {
+	Name    string
+	Orders  []*lazyOrder
+	Labels  map[string]string
+	Secret  *string `clone:"skip"`
+	private []int
+}
+
+func TestLazyClone(t *testing.T) {
+	a := assert.New(t)
+	secret := "secret"
+	orig := &lazyUser{
+		Name: "user",
+		Orders: []*lazyOrder{
+			{ID: 1, Items: []string{"a", "b"}},
+		},
+		Labels: map[string]string{
+			"foo": "bar",
+		},
+		Secret:  &secret,
+		private: []int{1, 2, 3},
+	}
+	l := LazyClone(orig)
+
+	orders := LazyField(l, func(u *lazyUser) *[]*lazyOrder { return &u.Orders })
+	a.Equal(*orders, orig.Orders)
+	a.Assert((*orders)[0] != orig.Orders[0])
+	a.Assert(l.fields[1])
+	a.Assert(!l.fields[2])
+
+	// Accessing again returns the same cloned field.
+	(*orders)[0].ID = 2
+	orders = LazyField(l, func(u *lazyUser) *[]*lazyOrder { return &u.Orders })
+	a.Equal((*orders)[0].ID, 2)
+	a.Equal(orig.Orders[0].ID, 1)
+
+	// Fields cloned by LazyField are kept in materialized value.
+	cloned := l.Materialize()
+	a.Equal(cloned.Name, orig.Name)
+	a.Equal(cloned.Orders[0].ID, 2)
+	a.Equal(cloned.Labels, orig.Labels)
+	a.Assert(cloned.Secret == nil)
+	a.Equal(cloned.private, orig.private)
+	a.Assert(&cloned.private[0] != &orig.private[0])
+
+	labels := LazyField(l, func(u *lazyUser) *map[string]string { return &u.Labels })
+	a.Assert(labels == &cloned.Labels)
+
+	// Bad field funcs panic.
+	a.Assert(func() (ok bool) {
+		defer func() {
+			ok = recover() != nil
+		}()
+		LazyField(LazyClone(orig), func(u *lazyUser) *string { return &u.Orders[0].Items[0] })
+		return
+	}())
+}
+
+func TestLazyCloneNonStruct(t *testing.T) {
+	a := assert.New(t)
+	orig := map[string][]int{
+		"foo": {1, 2},
+	}
+	l := LazyClone(&orig)
+	cloned := l.Materialize()
+	a.Equal(*cloned, orig)
+	a.Assert(&(*cloned)["foo"][0] != &orig["foo"][0])
+
+	var nilMap *map[string]int
+	a.Assert(LazyClone(nilMap).Materialize() == nil)
+}
+
+func TestMaterialized(t *testing.T) {
+	a := assert.New(t)
+	orig := &lazyUser{
+		Name: "user",
+		Labels: map[string]string{
+			"foo": "bar",
+		},
+	}
+	l := LazyClone(orig)
+	a.Equal(Materialized(l), []string(nil))
+
+	LazyField(l, func(u *lazyUser) *map[string]string { return &u.Labels })
+	LazyField(l, func(u *lazyUser) *string { return &u.Name })
+	a.Equal(Materialized(l), []string{".Name", ".Labels"})
+
+	MaterializeAll(l)
+	a.Equal(Materialized(l), []string{""})
+
+	// Wrapped values are not probed.
+	wrapped := Wrap(orig)
+	a.Equal(Materialized(wrapped), []string(nil))
+	MaterializeAll(wrapped)
+	a.Equal(wrapped, orig)
+	a.Equal(Materialized(orig), []string(nil))
+	a.Equal(Materialized([]int{1}), []string(nil))
+	a.Equal(Materialized(nil), []string(nil))
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import "github.com/huandu/go-clone"
+
+// ClonePipe returns a chan emitting deep clones of all values received from in.
+// Values are cloned with memory allocated from allocator.
+// If allocator is nil, values are cloned in heap.
+//
+// The returned chan is unbuffered so that the backpressure of consumers is preserved.
+// It's closed after in is closed and all values are emitted.
+//
+// A value is cloned after it's received from in and before it's emitted.
+// Producers must not reuse a sent value until it's emitted from the returned chan.
+func ClonePipe[T any](in <-chan T, allocator *Allocator) <-chan T {
+	if allocator == nil {
+		allocator = clone.FromHeap()
+	}
+
+	out := make(chan T)
+	cloner := clone.MakeCloner(allocator)
+
+	go func() {
+		defer close(out)
+
+		for v := range in {
+			out <- cloneWith(cloner, v)
+		}
+	}()
+
+	return out
+}
+
+// cloneWith clones v in the same way as Clone, so that custom func of T applies to v.
+func cloneWith[T any](cloner Cloner, v T) (nv T) {
+	cloner.CloneInto(&nv, &v)
+	return
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestClonePipe(t *testing.T) {
+	a := assert.New(t)
+	in := make(chan []int, 1)
+	out := ClonePipe(in, nil)
+	buf := []int{1, 2, 3}
+	var received [][]int
+
+	for i := 0; i < 3; i++ {
+		// Reuse buf on purpose. Consumers should not see any change.
+		buf[0] = i
+		in <- buf
+		received = append(received, <-out)
+	}
+
+	close(in)
+	_, ok := <-out
+	a.Assert(!ok)
+	a.Equal(received, [][]int{
+		{0, 2, 3},
+		{1, 2, 3},
+		{2, 2, 3},
+	})
+
+	// Nil interface value can be sent through the pipe.
+	errs := make(chan error, 1)
+	errs <- nil
+	close(errs)
+	a.Equal(<-ClonePipe(errs, FromHeap()), nil)
+}
+
+type pipeToken struct {
+	Value string
+}
+
+func TestClonePipeCustomFunc(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.SetCustomFunc(reflect.TypeOf(pipeToken{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.FieldByName("Value").SetString("redacted")
+	})
+
+	in := make(chan pipeToken, 1)
+	in <- pipeToken{Value: "secret"}
+	close(in)
+
+	// Values emitted from the pipe are the same as the ones cloned by Clone.
+	a.Equal(<-ClonePipe(in, allocator), pipeToken{Value: "redacted"})
+	a.Equal(MakeCloner(allocator).Clone(pipeToken{Value: "secret"}), pipeToken{Value: "redacted"})
+}
@@ -9,6 +9,8 @@ package clone
 import (
 	"reflect"
 	"sync/atomic"
+
+	"github.com/huandu/go-clone"
 )
 
 // Record the count of cloning atomic.Pointer[T] for test purpose only.
@@ -30,3 +32,9 @@ func RegisterAtomicPointer[T any]() {
 		atomic.AddInt32(&registerAtomicPointerCalled, 1)
 	})
 }
+
+// Replace replaces the value stored in target with a mutated deep clone of it.
+// It's the same as Replace in the main package.
+func Replace[T any](target *atomic.Pointer[T], mutate func(*T)) *T {
+	return clone.Replace(target, mutate)
+}
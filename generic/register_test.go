@@ -7,6 +7,7 @@
 package clone
 
 import (
+	"reflect"
 	"sync/atomic"
 	"testing"
 
@@ -29,13 +30,38 @@ type Pointers struct {
 func TestRegisterAtomicPointer(t *testing.T) {
 	a := assert.New(t)
 	s := &Pointers{}
-	stackPointerCannotBeCloned := atomic.Pointer[RegisteredPayload]{}
+	payload := &RegisteredPayload{T: "payload"}
+
+	// Use reflect to avoid copying atomic.Pointer[T] by value.
+	typ := reflect.TypeOf(&s.P1).Elem()
+	v := reflect.New(typ)
+	v.Interface().(*atomic.Pointer[RegisteredPayload]).Store(payload)
+	unaddressable := v.Elem().Interface()
 
 	// Register atomic.Pointer[RegisteredPayload] only.
 	RegisterAtomicPointer[RegisteredPayload]()
 
 	prev := registerAtomicPointerCalled
 	Clone(s)
-	Clone(stackPointerCannotBeCloned)
 	a.Equal(registerAtomicPointerCalled, prev+1)
+
+	// Values inside an interface are not addressable but can be cloned.
+	cloned := reflect.New(typ)
+	cloned.Elem().Set(reflect.ValueOf(Clone(unaddressable)))
+	a.Equal(registerAtomicPointerCalled, prev+2)
+	a.Assert(cloned.Interface().(*atomic.Pointer[RegisteredPayload]).Load() == payload)
+}
+
+func TestReplace(t *testing.T) {
+	a := assert.New(t)
+	var target atomic.Pointer[MyType]
+	target.Store(&MyType{Foo: 1})
+	old := target.Load()
+
+	nv := Replace(&target, func(v *MyType) {
+		v.Foo++
+	})
+	a.Assert(target.Load() == nv)
+	a.Equal(nv.Foo, 2)
+	a.Equal(old.Foo, 1)
 }
@@ -0,0 +1,61 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"sync"
+	"time"
+
+	"github.com/huandu/go-clone/diff"
+)
+
+type Change = diff.Change
+
+// Watch takes a snapshot of the value returned by get every interval and reports drifts to onChange.
+// A snapshot is a deep clone of the value, so that it's safe to compare it with a later one
+// even if the value is mutated in place.
+//
+// The first snapshot is taken before Watch returns.
+// When a new snapshot is different from the last one, onChange is called with both snapshots
+// and all changes found by diff.Diff. The new snapshot is compared with later snapshots then.
+// It's safe to keep or modify old and new in onChange.
+//
+// The get and onChange are called in a goroutine started by Watch.
+// The returned stop func stops the goroutine and waits for it to exit.
+// The stop func must not be called in onChange, otherwise it will be blocked forever.
+func Watch[T any](get func() T, interval time.Duration, onChange func(old, new T, changes []Change)) (stop func()) {
+	old := Slowly(get())
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			new := Slowly(get())
+
+			if changes := diff.Diff(old, new); len(changes) != 0 {
+				onChange(old, Slowly(new), changes)
+			}
+
+			old = new
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+		})
+		<-exited
+	}
+}
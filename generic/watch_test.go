@@ -0,0 +1,51 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestWatch(t *testing.T) {
+	a := assert.New(t)
+	type state struct {
+		Counters map[string]int
+	}
+
+	var mu sync.Mutex
+	s := &state{
+		Counters: map[string]int{"a": 1},
+	}
+	changed := make(chan []Change, 1)
+	stop := Watch(func() *state {
+		mu.Lock()
+		defer mu.Unlock()
+		return s
+	}, time.Millisecond, func(old, new *state, changes []Change) {
+		a.Equal(old.Counters, map[string]int{"a": 1})
+		a.Equal(new.Counters, map[string]int{"a": 2, "b": 3})
+		changed <- changes
+	})
+	defer stop()
+
+	// Mutate s in place. Watch should detect it.
+	mu.Lock()
+	s.Counters["a"] = 2
+	s.Counters["b"] = 3
+	mu.Unlock()
+
+	a.Equal(<-changed, []Change{
+		{Path: `.Counters["a"]`, Old: 1, New: 2},
+		{Path: `.Counters["b"]`, New: 3},
+	})
+
+	stop()
+
+	// stop can be called more than once.
+	stop()
+}
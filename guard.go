@@ -0,0 +1,136 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+var (
+	typeOfMutex   = reflect.TypeOf(sync.Mutex{})
+	typeOfRWMutex = reflect.TypeOf(sync.RWMutex{})
+	typeOfLocker  = reflect.TypeOf((*sync.Locker)(nil)).Elem()
+)
+
+// structGuard is the lock field guarding a struct.
+type structGuard struct {
+	Offset uintptr      // The offset of the lock field.
+	Type   reflect.Type // The type of the lock field.
+}
+
+// MarkAsGuardedBy marks struct type t as guarded by the lock field named lockField in heap allocator.
+//
+// See Allocator.MarkAsGuardedBy for more details.
+func MarkAsGuardedBy(t reflect.Type, lockField string) {
+	defaultAllocator.MarkAsGuardedBy(t, lockField)
+}
+
+// MarkAsGuardedBy marks struct type t as guarded by the lock field named lockField,
+// so that clone methods hold the lock while cloning a value of t and all values inside it.
+// If t is a pointer to struct, its elem type is used.
+// If t is not a struct type or lockField is not a lock field in t, MarkAsGuardedBy ignores t.
+// If lockField is empty, remove the mark.
+//
+// A lock field can be a sync.Mutex, a sync.RWMutex, or any type implementing sync.Locker by value or by pointer.
+// For sync.RWMutex, the read lock is held.
+// Nil lock fields are ignored.
+//
+// The lock is held only if the value of t is addressable, e.g. a value pointed by a pointer.
+// Values which are not addressable are copies and don't need any lock.
+// Callers must not hold the lock when cloning the value, or clone methods will deadlock.
+//
+// Guarded types marked as scalar are copied by value without lock.
+func (a *Allocator) MarkAsGuardedBy(t reflect.Type, lockField string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	if lockField != "" {
+		field, ok := t.FieldByName(lockField)
+
+		if !ok || len(field.Index) != 1 || !isLockType(field.Type) {
+			return
+		}
+	}
+
+	a.updateTypeConfig(t, func(tc *typeConfig) {
+		tc.guardedBy = lockField
+	})
+}
+
+func isLockType(t reflect.Type) bool {
+	return t == typeOfMutex || t == typeOfRWMutex ||
+		t.Implements(typeOfLocker) || reflect.PtrTo(t).Implements(typeOfLocker)
+}
+
+func (cfg *config) lookupGuard(t reflect.Type) *structGuard {
+	tc := cfg.lookup(t, func(tc *typeConfig) bool {
+		return tc.guardedBy != ""
+	})
+
+	if tc == nil {
+		return nil
+	}
+
+	field, _ := t.FieldByName(tc.guardedBy)
+	return &structGuard{
+		Offset: field.Offset,
+		Type:   field.Type,
+	}
+}
+
+// Lock locks the lock field in src and returns a func to unlock it.
+// If src is not addressable or the lock field is nil, Lock returns nil.
+func (guard *structGuard) Lock(src reflect.Value) (unlock func()) {
+	if !src.CanAddr() {
+		return nil
+	}
+
+	ptr := unsafe.Pointer(src.UnsafeAddr())
+	p := unsafe.Pointer(uintptr(ptr) + guard.Offset)
+
+	switch guard.Type {
+	case typeOfMutex:
+		mu := (*sync.Mutex)(p)
+		mu.Lock()
+		return mu.Unlock
+	case typeOfRWMutex:
+		mu := (*sync.RWMutex)(p)
+		mu.RLock()
+		return mu.RUnlock
+	}
+
+	field := reflect.NewAt(guard.Type, p)
+	var locker sync.Locker
+
+	if guard.Type.Implements(typeOfLocker) {
+		elem := field.Elem()
+
+		switch elem.Kind() {
+		case reflect.Interface, reflect.Ptr:
+			if elem.IsNil() {
+				return nil
+			}
+		}
+
+		locker = elem.Interface().(sync.Locker)
+	} else {
+		locker = field.Interface().(sync.Locker)
+	}
+
+	locker.Lock()
+	return locker.Unlock
+}
+
+// isGuardHeld returns true if the guard lock of src is held by callers.
+func (state *cloneState) isGuardHeld(src reflect.Value) bool {
+	held := state.heldGuard
+	return held.IsValid() && src.CanAddr() && src.Type() == held.Type() && src.UnsafeAddr() == held.UnsafeAddr()
+}
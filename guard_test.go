@@ -0,0 +1,153 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type recordLocker struct {
+	locked bool
+	locks  int
+}
+
+func (l *recordLocker) Lock() {
+	l.locked = true
+	l.locks++
+}
+
+func (l *recordLocker) Unlock() {
+	l.locked = false
+}
+
+type guardedItem struct {
+	Value int
+}
+
+type guardedGroup struct {
+	lock  *recordLocker
+	Items []*guardedItem
+}
+
+type rwGuarded struct {
+	mu     sync.RWMutex
+	Values map[string]int
+}
+
+func TestMarkAsGuardedBy(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.MarkAsGuardedBy(reflect.TypeOf(&guardedGroup{}), "lock")
+
+	// Check the lock is held when cloning items.
+	locker := &recordLocker{}
+	group := &guardedGroup{
+		lock:  locker,
+		Items: []*guardedItem{{Value: 1}, {Value: 2}},
+	}
+	held := 0
+	allocator.SetCustomFunc(reflect.TypeOf(guardedItem{}), func(allocator *Allocator, old, new reflect.Value) {
+		if locker.locked {
+			held++
+		}
+
+		new.Set(old)
+	})
+
+	cloned := allocator.Clone(reflect.ValueOf(group)).Interface().(*guardedGroup)
+	a.Equal(cloned.Items, group.Items)
+	a.Equal(held, 2)
+	a.Equal(locker.locks, 1)
+	a.Assert(!locker.locked)
+
+	// Nil lock is ignored.
+	group.lock = nil
+	allocator.Clone(reflect.ValueOf(group))
+
+	// Invalid lock fields are ignored.
+	allocator.MarkAsGuardedBy(reflect.TypeOf(guardedItem{}), "Value")
+	allocator.MarkAsGuardedBy(reflect.TypeOf(guardedItem{}), "NotExist")
+	a.Assert(allocator.loadStructType(reflect.TypeOf(guardedItem{})).Guard == nil)
+
+	// Remove the mark.
+	allocator.MarkAsGuardedBy(reflect.TypeOf(guardedGroup{}), "")
+	a.Assert(allocator.loadStructType(reflect.TypeOf(guardedGroup{})).Guard == nil)
+}
+
+func TestMarkAsGuardedByRWMutex(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.MarkAsGuardedBy(reflect.TypeOf(rwGuarded{}), "mu")
+
+	v := &rwGuarded{
+		Values: map[string]int{"foo": 1},
+	}
+
+	// A read lock held by others doesn't block cloning.
+	v.mu.RLock()
+	cloned := allocator.Clone(reflect.ValueOf(v)).Interface().(*rwGuarded)
+	v.mu.RUnlock()
+	a.Equal(cloned.Values, v.Values)
+
+	// Clone waits for the write lock.
+	v.mu.Lock()
+	done := make(chan *rwGuarded)
+	go func() {
+		done <- allocator.Clone(reflect.ValueOf(v)).Interface().(*rwGuarded)
+	}()
+	v.Values["bar"] = 2
+	v.mu.Unlock()
+	cloned = <-done
+	a.Equal(cloned.Values, map[string]int{"foo": 1, "bar": 2})
+}
+
+type guardedNode struct {
+	lock *recordLocker
+	Next *guardedNode
+	Data *guardedNodeData
+}
+
+type guardedNodeData struct {
+	owner *recordLocker
+}
+
+func TestMarkAsGuardedByDeepValues(t *testing.T) {
+	a := assert.New(t)
+	const n = maxPtrDepth * 2
+	var head *guardedNode
+
+	for i := 0; i < n; i++ {
+		lock := &recordLocker{}
+		head = &guardedNode{
+			lock: lock,
+			Next: head,
+			Data: &guardedNodeData{
+				owner: lock,
+			},
+		}
+	}
+
+	unlocked := 0
+	allocator := FromHeap()
+	allocator.MarkAsGuardedBy(reflect.TypeOf(guardedNode{}), "lock")
+	allocator.SetCustomFunc(reflect.TypeOf(guardedNodeData{}), func(allocator *Allocator, old, new reflect.Value) {
+		if !old.Addr().Interface().(*guardedNodeData).owner.locked {
+			unlocked++
+		}
+	})
+	cloned := allocator.Clone(reflect.ValueOf(head)).Interface().(*guardedNode)
+
+	// Values deeper than the work stack threshold are still cloned while their owners are locked.
+	a.Equal(unlocked, 0)
+	a.Assert(cloned != head)
+
+	for node := head; node != nil; node = node.Next {
+		a.Equal(node.lock.locks, 1)
+		a.Assert(!node.lock.locked)
+	}
+}
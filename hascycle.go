@@ -0,0 +1,156 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// HasCycle reports whether v contains any cycle which Clone cannot handle in heap allocator.
+//
+// See Allocator.HasCycle for more details.
+func HasCycle(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+
+	return defaultAllocator.HasCycle(reflect.ValueOf(v))
+}
+
+// HasCycle reports whether val contains any cycle of pointers, maps or slices
+// which clone methods in a walk through, without cloning val.
+// If it returns false, val can be cloned by Clone. Otherwise, Slowly is required.
+//
+// Values which are not cloned in depth by a are not walked,
+// e.g. values of scalar types, opaque pointers and struct fields tagged with `clone:"skip"`.
+// A value reachable from several paths, e.g. a shared node in a DAG, is not a cycle.
+//
+// HasCycle walks every value reachable from val once.
+// It's much cheaper than cloning val, as nothing is allocated except the map of walked values.
+func (a *Allocator) HasCycle(val reflect.Value) bool {
+	if !val.IsValid() {
+		return false
+	}
+
+	d := &cycleDetector{
+		config: a.loadConfig(),
+		states: map[visit]bool{},
+	}
+	return d.detect(val)
+}
+
+type cycleDetector struct {
+	config *config
+
+	// states is true for values being walked and false for values walked without any cycle.
+	states map[visit]bool
+}
+
+func (d *cycleDetector) detect(v reflect.Value) bool {
+	cfg := d.config
+
+	if cfg.isScalar(v.Kind()) {
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.Array:
+		if cfg.isScalarType(v.Type().Elem()) {
+			return false
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			if d.detect(v.Index(i)) {
+				return true
+			}
+		}
+	case reflect.Interface:
+		return !v.IsNil() && d.detect(v.Elem())
+	case reflect.Map:
+		t := v.Type()
+
+		if v.IsNil() || cfg.isScalarType(t.Key()) && cfg.isScalarType(t.Elem()) {
+			return false
+		}
+
+		return d.walk(v, 0, func() bool {
+			for iter := v.MapRange(); iter.Next(); {
+				if d.detect(iter.Key()) || d.detect(iter.Value()) {
+					return true
+				}
+			}
+
+			return false
+		})
+	case reflect.Ptr:
+		t := v.Type()
+
+		if v.IsNil() || cfg.isOpaquePointer(t) || cfg.isScalarType(t.Elem()) {
+			return false
+		}
+
+		return d.walk(v, 0, func() bool {
+			return d.detect(v.Elem())
+		})
+	case reflect.Slice:
+		if v.IsNil() || cfg.isScalarType(v.Type().Elem()) {
+			return false
+		}
+
+		return d.walk(v, v.Len(), func() bool {
+			for i := 0; i < v.Len(); i++ {
+				if d.detect(v.Index(i)) {
+					return true
+				}
+			}
+
+			return false
+		})
+	case reflect.Struct:
+		st := cfg.loadStructType(v.Type())
+
+		for _, pf := range st.PointerFields {
+			field := v.Field(pf.Index)
+
+			// An unsafe.Pointer field with a pointee type is cloned as a pointer to the pointee.
+			if pf.Pointee != nil {
+				if field.IsNil() {
+					continue
+				}
+
+				field = reflect.NewAt(pf.Pointee, unsafe.Pointer(field.Pointer()))
+			}
+
+			if d.detect(field) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// walk calls walkInside to walk values inside v if v is not walked yet.
+// It returns true if v is being walked, which means there is a cycle.
+func (d *cycleDetector) walk(v reflect.Value, extra int, walkInside func() bool) bool {
+	key := visit{
+		p:     v.Pointer(),
+		extra: extra,
+		t:     v.Type(),
+	}
+
+	if walking, ok := d.states[key]; ok {
+		return walking
+	}
+
+	d.states[key] = true
+
+	if walkInside() {
+		return true
+	}
+
+	d.states[key] = false
+	return false
+}
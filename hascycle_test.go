@@ -0,0 +1,73 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type cycleNode struct {
+	Data     int
+	Next     *cycleNode
+	Children []*cycleNode
+	Attrs    map[string]interface{}
+}
+
+type cycleSkipped struct {
+	Self  *cycleSkipped `clone:"skip"`
+	Share *cycleSkipped `clone:"shadowcopy"`
+}
+
+type cycleUnexported struct {
+	next *cycleUnexported
+}
+
+func TestHasCycle(t *testing.T) {
+	a := assert.New(t)
+	a.Assert(!HasCycle(nil))
+	a.Assert(!HasCycle(123))
+	a.Assert(!HasCycle([]int{1, 2}))
+
+	// Shared values are not cycles.
+	shared := &cycleNode{Data: 1}
+	dag := &cycleNode{Children: []*cycleNode{shared, shared}, Next: shared}
+	a.Assert(!HasCycle(dag))
+
+	list := &cycleNode{Next: &cycleNode{Next: &cycleNode{}}}
+	a.Assert(!HasCycle(list))
+	list.Next.Next.Next = list
+	a.Assert(HasCycle(list))
+
+	child := &cycleNode{}
+	tree := &cycleNode{Children: []*cycleNode{child}}
+	child.Attrs = map[string]interface{}{"parent": tree}
+	a.Assert(HasCycle(tree))
+
+	m := map[string]interface{}{}
+	m["self"] = m
+	a.Assert(HasCycle(m))
+
+	s := make([]interface{}, 1)
+	s[0] = s
+	a.Assert(HasCycle(s))
+
+	u := &cycleUnexported{}
+	u.next = u
+	a.Assert(HasCycle(u))
+
+	// Fields which are not cloned in depth are not walked.
+	skipped := &cycleSkipped{}
+	skipped.Self = skipped
+	skipped.Share = skipped
+	a.Assert(!HasCycle(skipped))
+
+	// Registrations in allocator apply.
+	allocator := FromHeap()
+	allocator.MarkAsOpaquePointer(reflect.TypeOf(&cycleNode{}))
+	a.Assert(!allocator.HasCycle(reflect.ValueOf(list)))
+	a.Assert(!allocator.HasCycle(reflect.Value{}))
+}
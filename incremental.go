@@ -0,0 +1,288 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+var dirtyValues = struct {
+	sync.Mutex
+	m map[visit]struct{}
+}{
+	m: map[visit]struct{}{},
+}
+
+// MarkDirty marks the value pointed by ptr as dirty, e.g. `MarkDirty(&obj.Field)`.
+// If ptr is not a pointer or is nil, MarkDirty ignores it.
+//
+// A dirty value is cloned again by the next CloneIncremental on any value containing it.
+// The mark is removed after the value is cloned again.
+func MarkDirty(ptr interface{}) {
+	if ptr == nil {
+		return
+	}
+
+	v := reflect.ValueOf(ptr)
+
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return
+	}
+
+	dirtyValues.Lock()
+	defer dirtyValues.Unlock()
+
+	dirtyValues.m[visit{
+		p: v.Pointer(),
+		t: v.Type().Elem(),
+	}] = struct{}{}
+}
+
+// ClearDirty removes all dirty marks.
+func ClearDirty() {
+	dirtyValues.Lock()
+	defer dirtyValues.Unlock()
+
+	dirtyValues.m = map[visit]struct{}{}
+}
+
+// CloneIncremental refreshes prev, which is a clone of src made earlier, to be a clone of src again.
+// It uses the default allocator.
+//
+// See Allocator.CloneIncremental for more details.
+func CloneIncremental(prev, src interface{}) interface{} {
+	return defaultAllocator.CloneIncremental(prev, src)
+}
+
+// CloneIncremental refreshes prev, which is a clone of src made earlier by a, to be a clone of src again.
+// Only values marked by MarkDirty and values whose shape changes,
+// e.g. a pointer changing from nil to non-nil or a slice changing its length, are cloned again.
+// All other values in prev are reused as is, even if they are changed in src.
+//
+// Values pointed by pointers, slices and maps in prev are updated in place.
+// The refreshed value is returned.
+// If prev and src are not in the same type, CloneIncremental returns a clone of src.
+// Dirty values are cloned again by a, so that custom funcs and policies in a apply to them.
+//
+// CloneIncremental walks the whole src to find dirty values,
+// but it doesn't copy any value which is not dirty.
+// It's callers' responsibility to mark all changed values dirty.
+func (a *Allocator) CloneIncremental(prev, src interface{}) interface{} {
+	if src == nil {
+		return nil
+	}
+
+	if prev == nil || reflect.TypeOf(prev) != reflect.TypeOf(src) {
+		return a.clone(reflect.ValueOf(src), false).Interface()
+	}
+
+	dst := reflect.New(reflect.TypeOf(prev)).Elem()
+	dst.Set(reflect.ValueOf(prev))
+
+	dirtyValues.Lock()
+	defer dirtyValues.Unlock()
+
+	inc := &incrementalState{
+		allocator: a,
+		config:    a.loadConfig(),
+		visited:   map[incrementalVisit]struct{}{},
+	}
+	inc.refresh(reflect.ValueOf(src), dst)
+
+	for _, vst := range inc.dirty {
+		delete(dirtyValues.m, vst)
+	}
+
+	return dst.Interface()
+}
+
+type incrementalState struct {
+	allocator *Allocator
+	config    *config
+	visited   map[incrementalVisit]struct{}
+	dirty     []visit
+}
+
+type incrementalVisit struct {
+	src, dst uintptr
+	extra    int
+	t        reflect.Type
+}
+
+// refresh updates dst to be a clone of src.
+// The dst must be settable.
+func (inc *incrementalState) refresh(src, dst reflect.Value) {
+	if src.CanAddr() {
+		vst := visit{
+			p: src.UnsafeAddr(),
+			t: src.Type(),
+		}
+
+		if _, ok := dirtyValues.m[vst]; ok {
+			inc.dirty = append(inc.dirty, vst)
+			inc.reclone(src, dst)
+			return
+		}
+	}
+
+	switch src.Kind() {
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			inc.refresh(src.Index(i), dst.Index(i))
+		}
+	case reflect.Interface:
+		if src.IsNil() || dst.IsNil() || src.Elem().Type() != dst.Elem().Type() {
+			inc.reclone(src, dst)
+			return
+		}
+
+		elem := reflect.New(dst.Elem().Type()).Elem()
+		elem.Set(dst.Elem())
+		inc.refresh(src.Elem(), elem)
+		dst.Set(elem)
+	case reflect.Map:
+		if src.IsNil() || dst.IsNil() || src.Len() != dst.Len() {
+			inc.reclone(src, dst)
+			return
+		}
+
+		if !inc.visit(src, dst, 0) {
+			return
+		}
+
+		elem := reflect.New(dst.Type().Elem()).Elem()
+
+		for iter := mapIter(src); iter.Next(); {
+			key := iter.Key()
+
+			// Keys and values in unexported maps are read-only.
+			if !key.CanInterface() {
+				key = forceClearROFlag(key)
+			}
+
+			val := dst.MapIndex(key)
+
+			// A new key is added or map keys are cloned in depth.
+			if !val.IsValid() {
+				inc.reclone(src, dst)
+				return
+			}
+
+			if !val.CanInterface() {
+				val = forceClearROFlag(val)
+			}
+
+			elem.Set(val)
+			inc.refresh(iter.Value(), elem)
+			dst.SetMapIndex(key, elem)
+		}
+	case reflect.Ptr:
+		if src.IsNil() || dst.IsNil() {
+			inc.reclone(src, dst)
+			return
+		}
+
+		if inc.config.isOpaquePointer(src.Type()) {
+			return
+		}
+
+		if !inc.visit(src, dst, 0) {
+			return
+		}
+
+		inc.refresh(src.Elem(), inc.settable(dst.Elem()))
+	case reflect.Slice:
+		if src.IsNil() || dst.IsNil() || src.Len() != dst.Len() {
+			inc.reclone(src, dst)
+			return
+		}
+
+		if !inc.visit(src, dst, src.Len()) {
+			return
+		}
+
+		if inc.allocator.isScalar(src.Type().Elem().Kind()) {
+			return
+		}
+
+		for i := 0; i < src.Len(); i++ {
+			inc.refresh(src.Index(i), inc.settable(dst.Index(i)))
+		}
+	case reflect.Struct:
+		st := inc.config.loadStructType(src.Type())
+
+		// Struct fields may not be cloned field by field with custom func.
+		if st.fn != nil || len(st.FuncFields) != 0 {
+			inc.reclone(src, dst)
+			return
+		}
+
+		// Fields of a shadow copied struct can still be marked dirty, e.g. `MarkDirty(&obj.Name)`.
+		if st.CanShadowCopy() && len(dirtyValues.m) == 0 {
+			return
+		}
+
+		t := src.Type()
+
+		for i := 0; i < src.NumField(); i++ {
+			tag := t.Field(i).Tag.Get(fieldTagName)
+
+			if _, ok := parseInitTag(tag); ok {
+				// Fields populated by init methods are kept as is.
+				continue
+			}
+
+			switch tag {
+			case fieldTagValueSkip, fieldTagValueSkipAlias, fieldTagValueZero, fieldTagValueRebind, fieldTagValueParent, fieldTagValueShadowCopy, fieldTagValueOpaque:
+				// These fields are not cloned in depth.
+			default:
+				inc.refresh(src.Field(i), inc.settable(dst.Field(i)))
+			}
+		}
+	}
+}
+
+func (inc *incrementalState) reclone(src, dst reflect.Value) {
+	dst.Set(inc.clone(src))
+}
+
+func (inc *incrementalState) clone(src reflect.Value) reflect.Value {
+	cloned := inc.allocator.clone(src, false)
+
+	if !cloned.CanInterface() {
+		cloned = forceClearROFlag(cloned)
+	}
+
+	return cloned
+}
+
+// settable returns a settable value of addressable v, even if v is an unexported field.
+func (inc *incrementalState) settable(v reflect.Value) reflect.Value {
+	if v.CanSet() {
+		return v
+	}
+
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// visit records a pair of src and dst as visited and reports whether it's visited at the first time.
+// As Clone clones a value shared by several pointers to distinct values,
+// a src value must be visited again with a different dst.
+func (inc *incrementalState) visit(src, dst reflect.Value, extra int) bool {
+	vst := incrementalVisit{
+		src:   src.Pointer(),
+		dst:   dst.Pointer(),
+		extra: extra,
+		t:     src.Type(),
+	}
+
+	if _, ok := inc.visited[vst]; ok {
+		return false
+	}
+
+	inc.visited[vst] = struct{}{}
+	return true
+}
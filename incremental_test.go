@@ -0,0 +1,149 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestCloneIncremental(t *testing.T) {
+	a := assert.New(t)
+
+	type item struct {
+		Name   string
+		Values []int
+	}
+	type state struct {
+		Items   []*item
+		Index   map[string]*item
+		Current *item
+		Counter int
+		Cache   []int `clone:"skip"`
+		private *item
+	}
+
+	src := &state{
+		Items: []*item{
+			{Name: "a", Values: []int{1}},
+			{Name: "b", Values: []int{2}},
+		},
+		Index:   map[string]*item{},
+		Counter: 1,
+		Cache:   []int{1, 2, 3},
+		private: &item{Name: "p"},
+	}
+	src.Index["a"] = src.Items[0]
+	src.Current = src.Items[1]
+	prev := Clone(src).(*state)
+	prevItems := prev.Items
+	prevIndexA := prev.Index["a"]
+	prevCurrent := prev.Current
+
+	// Change a value and mark it dirty.
+	src.Items[0].Values = append(src.Items[0].Values, 10)
+	MarkDirty(&src.Items[0].Values)
+
+	// Values not marked dirty are reused.
+	src.Items[1].Values[0] = 20
+	src.Counter = 2
+	src.private.Name = "q"
+
+	refreshed := CloneIncremental(prev, src).(*state)
+	a.Assert(refreshed == prev)
+	a.Equal(refreshed.Items[0].Values, []int{1, 10})
+	a.Assert(&refreshed.Items[0].Values[0] != &src.Items[0].Values[0])
+	a.Equal(refreshed.Index["a"].Values, []int{1, 10})
+	a.Assert(&refreshed.Items[1].Values[0] == &prevItems[1].Values[0])
+	a.Equal(refreshed.Items[1].Values, []int{2})
+	a.Equal(refreshed.Counter, 1)
+	a.Equal(refreshed.private.Name, "p")
+	a.Assert(refreshed.Cache == nil)
+	a.Assert(refreshed.Index["a"] == prevIndexA)
+	a.Assert(refreshed.Current == prevCurrent)
+
+	// Dirty marks are consumed.
+	a.Equal(len(dirtyValues.m), 0)
+
+	// Shape changes are detected without marks.
+	src.Items = append(src.Items, &item{Name: "c"})
+	src.Index["c"] = src.Items[2]
+	src.Current = nil
+	refreshed = CloneIncremental(prev, src).(*state)
+	a.Equal(len(refreshed.Items), 3)
+	a.Equal(refreshed.Items[2].Name, "c")
+	a.Assert(refreshed.Items[2] != src.Items[2])
+	a.Equal(refreshed.Index["c"].Name, "c")
+	a.Assert(refreshed.Current == nil)
+
+	// A dirty root is cloned again as a whole.
+	MarkDirty(src)
+	refreshed = CloneIncremental(prev, src).(*state)
+	a.Assert(refreshed == prev)
+	a.Equal(refreshed.Items[1].Values, []int{20})
+	a.Equal(refreshed.Items, src.Items)
+	a.Equal(refreshed.Counter, 2)
+
+	// Values in different types are cloned.
+	a.Equal(CloneIncremental(1, "foo"), "foo")
+	a.Equal(CloneIncremental(nil, []int{1}), []int{1})
+	a.Equal(CloneIncremental(1, nil), nil)
+
+	MarkDirty(&src.Counter)
+	ClearDirty()
+	a.Equal(len(dirtyValues.m), 0)
+}
+
+type incrementalItem struct {
+	Name string
+}
+
+type incrementalDoc struct {
+	items map[string]*incrementalItem
+}
+
+func TestCloneIncrementalUnexportedMap(t *testing.T) {
+	a := assert.New(t)
+	src := &incrementalDoc{
+		items: map[string]*incrementalItem{
+			"a": {Name: "a"},
+			"b": {Name: "b"},
+		},
+	}
+	prev := Clone(src).(*incrementalDoc)
+	prevA := prev.items["a"]
+
+	src.items["a"].Name = "new a"
+	MarkDirty(&src.items["a"].Name)
+	src.items["b"].Name = "new b"
+	refreshed := CloneIncremental(prev, src).(*incrementalDoc)
+	a.Assert(refreshed == prev)
+	a.Assert(refreshed.items["a"] == prevA)
+	a.Equal(refreshed.items["a"].Name, "new a")
+	a.Equal(refreshed.items["b"].Name, "b")
+}
+
+func TestAllocatorCloneIncremental(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.SetCustomFunc(reflect.TypeOf(incrementalItem{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.FieldByName("Name").SetString("custom " + old.FieldByName("Name").String())
+	})
+	src := &incrementalDoc{
+		items: map[string]*incrementalItem{
+			"a": {Name: "a"},
+		},
+	}
+	prev := allocator.CloneIncremental(nil, src).(*incrementalDoc)
+	a.Equal(prev.items["a"].Name, "custom a")
+
+	// Dirty values are cloned again by allocator.
+	src.items["a"].Name = "b"
+	MarkDirty(src.items["a"])
+	refreshed := allocator.CloneIncremental(prev, src).(*incrementalDoc)
+	a.Assert(refreshed == prev)
+	a.Equal(refreshed.items["a"].Name, "custom b")
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// parseInitTag returns the method name in tag `clone:"init=methodName"`.
+func parseInitTag(tag string) (name string, ok bool) {
+	if !strings.HasPrefix(tag, fieldTagValueInitPrefix) {
+		return
+	}
+
+	name = tag[len(fieldTagValueInitPrefix):]
+	ok = true
+	return
+}
+
+// appendInitMethod appends the index of method name of *t to methods.
+// Methods named by more than one field are called only once.
+//
+// The method must be an exported method of t or *t without any argument and return value.
+// Otherwise, appendInitMethod panics, as the struct type cannot be cloned as expected.
+func appendInitMethod(methods []int, t reflect.Type, name string) []int {
+	pt := reflect.PtrTo(t)
+	m, ok := pt.MethodByName(name)
+
+	if !ok {
+		panic(fmt.Errorf("go-clone: init method `%v` is not found in `%v`", name, pt))
+	}
+
+	if m.Type.NumIn() != 1 || m.Type.NumOut() != 0 {
+		panic(fmt.Errorf("go-clone: init method `%v` of `%v` must be a func without any argument and return value", name, pt))
+	}
+
+	for _, i := range methods {
+		if i == m.Index {
+			return methods
+		}
+	}
+
+	return append(methods, m.Index)
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type initMethodIndex struct {
+	Items []string
+	index map[string]int `clone:"init=BuildIndex"`
+	count int            `clone:"init=BuildIndex"`
+	calls int
+}
+
+func (idx *initMethodIndex) BuildIndex() {
+	idx.index = make(map[string]int, len(idx.Items))
+
+	for i, item := range idx.Items {
+		idx.index[item] = i
+	}
+
+	idx.count = len(idx.Items)
+	idx.calls++
+}
+
+type initMethodOwner struct {
+	Index   initMethodIndex
+	Indexes map[string]initMethodIndex
+	size    int `clone:"init=Size"`
+}
+
+func (owner *initMethodOwner) Size() {
+	// Init methods of fields are called before owner's.
+	owner.size = owner.Index.count
+}
+
+type initMethodNotFound struct {
+	p *int `clone:"init=NotFound"`
+}
+
+type initMethodBadSignature struct {
+	p *int `clone:"init=Bad"`
+}
+
+func (initMethodBadSignature) Bad() error { return nil }
+
+func TestInitMethod(t *testing.T) {
+	a := assert.New(t)
+	orig := &initMethodOwner{
+		Index: initMethodIndex{
+			Items: []string{"a", "b"},
+			index: map[string]int{"stale": 1},
+			count: 100,
+		},
+		Indexes: map[string]initMethodIndex{
+			"c": {Items: []string{"c"}},
+		},
+	}
+	cloned := Clone(orig).(*initMethodOwner)
+	a.Equal(cloned.Index.index, map[string]int{"a": 0, "b": 1})
+	a.Equal(cloned.Index.count, 2)
+	a.Equal(cloned.Index.calls, 1)
+	a.Equal(cloned.Indexes["c"].index, map[string]int{"c": 0})
+	a.Equal(cloned.size, 2)
+	a.Equal(orig.Index.index, map[string]int{"stale": 1})
+
+	cloned = Slowly(orig).(*initMethodOwner)
+	a.Equal(cloned.Index.index, map[string]int{"a": 0, "b": 1})
+	a.Equal(cloned.size, 2)
+
+	// Init methods are not called if struct is cloned by custom func.
+	allocator := NewAllocator(nil, nil)
+	allocator.SetCustomFunc(reflect.TypeOf(initMethodIndex{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.Field(0).Set(old.Field(0))
+	})
+	clonedIndexes := allocator.Clone(reflect.ValueOf([]initMethodIndex{orig.Index})).Interface().([]initMethodIndex)
+	a.Equal(clonedIndexes[0].Items, orig.Index.Items)
+	a.Equal(clonedIndexes[0].index, map[string]int(nil))
+	a.Equal(clonedIndexes[0].calls, 0)
+}
+
+func TestInitMethodPanics(t *testing.T) {
+	a := assert.New(t)
+	defer func() {
+		a.Assert(recover() != nil)
+	}()
+	Clone(&initMethodNotFound{})
+}
+
+func TestInitMethodBadSignature(t *testing.T) {
+	a := assert.New(t)
+	defer func() {
+		a.Assert(recover() != nil)
+	}()
+	Clone(&initMethodBadSignature{})
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+)
+
+// InterfacePolicy is the policy to clone an interface value
+// whose dynamic type is not exported.
+type InterfacePolicy int
+
+// All interface policies.
+const (
+	InterfacePolicyClone InterfacePolicy = iota + 1 // Clone the dynamic value in depth. It's the default policy.
+	InterfacePolicyShare                            // Copy the interface value as it is and share the dynamic value.
+	InterfacePolicyZero                             // Set the cloned interface value to nil.
+	InterfacePolicyError                            // Panic with an *InterfaceError.
+)
+
+// InterfaceError is the error of an interface value rejected by InterfacePolicyError.
+type InterfaceError struct {
+	Type        reflect.Type // The interface type.
+	DynamicType reflect.Type // The dynamic type of the interface value.
+}
+
+func (e *InterfaceError) Error() string {
+	return fmt.Sprintf("go-clone: cannot clone interface `%v` with unexported dynamic type `%v`", e.Type, e.DynamicType)
+}
+
+// SetInterfacePolicy sets the policy for interface type t in heap allocator.
+// If t is not an interface type, SetInterfacePolicy ignores t.
+//
+// See Allocator.SetInterfacePolicy for more details.
+func SetInterfacePolicy(t reflect.Type, policy InterfacePolicy) {
+	defaultAllocator.SetInterfacePolicy(t, policy)
+}
+
+// SetInterfacePolicy sets the policy for interface type t.
+// If t is not an interface type, SetInterfacePolicy ignores t.
+//
+// The policy applies to values of t whose dynamic type is not exported,
+// which is checked by IsExportedType.
+// Cloning such a value in depth relies on internal details of another package,
+// and the cloned value may panic on method calls if the package keeps invariants
+// which cannot be kept by copying memory, e.g. pointers registered in a global table.
+// Values with exported dynamic types are always cloned in depth.
+//
+// If policy is not a valid policy, a inherits the policy from parent allocator.
+func (a *Allocator) SetInterfacePolicy(t reflect.Type, policy InterfacePolicy) {
+	if t.Kind() != reflect.Interface {
+		return
+	}
+
+	if policy < InterfacePolicyClone || policy > InterfacePolicyError {
+		policy = 0
+	}
+
+	a.updateTypeConfig(t, func(tc *typeConfig) {
+		tc.interfacePolicy = policy
+	})
+}
+
+// IsExportedType reports whether t can be referenced outside its package.
+// A named type is exported if its name is exported.
+// An unnamed type is exported if all its element types and struct fields are exported.
+func IsExportedType(t reflect.Type) bool {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return true
+		}
+
+		r, _ := utf8.DecodeRuneInString(t.Name())
+		return unicode.IsUpper(r)
+	}
+
+	switch t.Kind() {
+	case reflect.Array, reflect.Chan, reflect.Ptr, reflect.Slice:
+		return IsExportedType(t.Elem())
+	case reflect.Map:
+		return IsExportedType(t.Key()) && IsExportedType(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+
+			if field.PkgPath != "" || !IsExportedType(field.Type) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (cfg *config) lookupInterfacePolicy(t reflect.Type) InterfacePolicy {
+	tc := cfg.lookup(t, func(tc *typeConfig) bool {
+		return tc.interfacePolicy != 0
+	})
+
+	if tc == nil {
+		return InterfacePolicyClone
+	}
+
+	return tc.interfacePolicy
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type unexportedStringer struct {
+	s *string
+}
+
+func (u *unexportedStringer) String() string {
+	return *u.s
+}
+
+type ExportedStringer struct {
+	S *string
+}
+
+func (e *ExportedStringer) String() string {
+	return *e.S
+}
+
+type interfaceHolder struct {
+	Stringer fmt.Stringer
+}
+
+func TestSetInterfacePolicy(t *testing.T) {
+	a := assert.New(t)
+	s := "foo"
+	unexported := &interfaceHolder{
+		Stringer: &unexportedStringer{s: &s},
+	}
+	exported := &interfaceHolder{
+		Stringer: &ExportedStringer{S: &s},
+	}
+	typeOfStringer := reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	clone := func(allocator *Allocator, v *interfaceHolder) *interfaceHolder {
+		return allocator.Clone(reflect.ValueOf(v)).Interface().(*interfaceHolder)
+	}
+
+	// Clone in depth by default.
+	allocator := FromHeap()
+	cloned := clone(allocator, unexported)
+	a.Equal(cloned.Stringer.String(), s)
+	a.Assert(cloned.Stringer != unexported.Stringer)
+
+	allocator.SetInterfacePolicy(typeOfStringer, InterfacePolicyShare)
+	cloned = clone(allocator, unexported)
+	a.Assert(cloned.Stringer == unexported.Stringer)
+	cloned = clone(allocator, exported)
+	a.Assert(cloned.Stringer != exported.Stringer)
+
+	// Child allocator inherits the policy.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	cloned = clone(child, unexported)
+	a.Assert(cloned.Stringer == unexported.Stringer)
+
+	child.SetInterfacePolicy(typeOfStringer, InterfacePolicyZero)
+	cloned = clone(child, unexported)
+	a.Assert(cloned.Stringer == nil)
+
+	child.SetInterfacePolicy(typeOfStringer, InterfacePolicyError)
+	a.Assert(func() (ok bool) {
+		defer func() {
+			err, _ := recover().(error)
+			var interfaceErr *InterfaceError
+			ok = errors.As(err, &interfaceErr) && interfaceErr.Type == typeOfStringer
+		}()
+		clone(child, unexported)
+		return
+	}())
+
+	// Invalid policy resets to parent's policy.
+	child.SetInterfacePolicy(typeOfStringer, 0)
+	cloned = clone(child, unexported)
+	a.Assert(cloned.Stringer == unexported.Stringer)
+}
+
+func TestIsExportedType(t *testing.T) {
+	a := assert.New(t)
+
+	a.Assert(IsExportedType(reflect.TypeOf(0)))
+	a.Assert(IsExportedType(reflect.TypeOf(&ExportedStringer{})))
+	a.Assert(IsExportedType(reflect.TypeOf(map[string][]*ExportedStringer{})))
+	a.Assert(IsExportedType(reflect.TypeOf(struct{ A int }{})))
+	a.Assert(!IsExportedType(reflect.TypeOf(&unexportedStringer{})))
+	a.Assert(!IsExportedType(reflect.TypeOf(map[string]unexportedStringer{})))
+	a.Assert(!IsExportedType(reflect.TypeOf(struct{ a int }{})))
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/huandu/go-clone/walk"
+)
+
+var typeOfReflectValue = reflect.TypeOf(reflect.Value{})
+
+// InvalidValueError is the error of invalid reflect.Values found in strict mode.
+//
+// Clone methods copy a reflect.Value by value, so that an invalid reflect.Value is cloned as it is.
+// It's usually a bug to keep such a value in a graph, e.g. a lookup result left unchecked.
+type InvalidValueError struct {
+	Type  reflect.Type // The type of the source value.
+	Paths []string     // Paths to all invalid reflect.Values in depth-first order.
+}
+
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf("go-clone: value of type `%v` contains invalid reflect.Value at %v", e.Type, strings.Join(e.Paths, ", "))
+}
+
+// checkInvalidValues panics with an *InvalidValueError if any reflect.Value inside v is invalid.
+// Paths are written in Go selector syntax relative to v like `.Foo[2]["key"]`.
+// The empty path means v itself.
+func (cfg *config) checkInvalidValues(v reflect.Value) {
+	if !mayContainReflectValue(v.Type(), map[reflect.Type]struct{}{}) {
+		return
+	}
+
+	var paths []string
+	walk.WalkValue(v, walk.VisitorFunc(func(path string, v reflect.Value) bool {
+		if v.Type() == typeOfReflectValue {
+			if !v.Interface().(reflect.Value).IsValid() {
+				paths = append(paths, fmt.Sprintf("`%v`", path))
+			}
+
+			return false
+		}
+
+		// Opaque pointers are never cloned in depth. Don't walk into them.
+		return v.Kind() != reflect.Ptr || !cfg.isOpaquePointer(v.Type())
+	}))
+
+	if len(paths) != 0 {
+		panic(&InvalidValueError{
+			Type:  v.Type(),
+			Paths: paths,
+		})
+	}
+}
+
+// mayContainReflectValue returns true if values of t may contain any reflect.Value.
+func mayContainReflectValue(t reflect.Type, visiting map[reflect.Type]struct{}) bool {
+	if t == typeOfReflectValue {
+		return true
+	}
+
+	if _, ok := visiting[t]; ok {
+		return false
+	}
+
+	visiting[t] = struct{}{}
+
+	switch t.Kind() {
+	case reflect.Array, reflect.Ptr, reflect.Slice:
+		return mayContainReflectValue(t.Elem(), visiting)
+	case reflect.Interface:
+		// The dynamic type can be anything.
+		return true
+	case reflect.Map:
+		return mayContainReflectValue(t.Key(), visiting) || mayContainReflectValue(t.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if mayContainReflectValue(t.Field(i).Type, visiting) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
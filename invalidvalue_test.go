@@ -0,0 +1,120 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+type zeroNodes struct {
+	Ptr       *int
+	Map       map[string]int
+	Slice     []int
+	Chan      chan int
+	Func      func()
+	Interface interface{}
+	Unsafe    unsafe.Pointer
+	Value     reflect.Value
+
+	Maps   map[string]interface{}
+	Keys   map[interface{}]interface{}
+	Values map[string]reflect.Value
+	Items  []interface{}
+	Array  [2]interface{}
+	Nested interface{}
+
+	private *zeroNodes
+}
+
+func TestCloneZeroNodes(t *testing.T) {
+	a := assert.New(t)
+	var nilIface interface{}
+	v := &zeroNodes{
+		Maps:   map[string]interface{}{"nil": nil, "ptr": (*int)(nil)},
+		Keys:   map[interface{}]interface{}{nil: nil},
+		Values: map[string]reflect.Value{"invalid": {}},
+		Items:  []interface{}{nil, &nilIface},
+		Nested: map[string]interface{}{"nil": nil},
+		private: &zeroNodes{
+			Maps: map[string]interface{}{"nil": nil},
+		},
+	}
+
+	for _, cloned := range []*zeroNodes{
+		Clone(v).(*zeroNodes),
+		Slowly(v).(*zeroNodes),
+		ReadOnlySource(nil).Clone(reflect.ValueOf(v)).Interface().(*zeroNodes),
+	} {
+		a.Assert(cloned.Ptr == nil)
+		a.Assert(cloned.Map == nil)
+		a.Assert(cloned.Slice == nil)
+		a.Assert(cloned.Chan == nil)
+		a.Assert(cloned.Func == nil)
+		a.Assert(cloned.Interface == nil)
+		a.Assert(cloned.Unsafe == nil)
+		a.Assert(!cloned.Value.IsValid())
+		a.Equal(cloned.Maps, v.Maps)
+		a.Equal(cloned.Keys, v.Keys)
+		a.Assert(!cloned.Values["invalid"].IsValid())
+		a.Equal(len(cloned.Items), 2)
+		a.Assert(cloned.Items[0] == nil)
+		a.Assert(*cloned.Items[1].(*interface{}) == nil)
+		a.Equal(cloned.Nested, v.Nested)
+		a.Equal(cloned.private.Maps, v.private.Maps)
+	}
+
+	// Invalid values are returned as they are.
+	a.Assert(!FromHeap().Clone(reflect.Value{}).IsValid())
+	a.Assert(!FromHeap().CloneSlowly(reflect.Value{}).IsValid())
+	a.Equal(FindPaths(v, unsafe.Pointer(v.private)), []string{".private"})
+}
+
+func TestInvalidValueStrictMode(t *testing.T) {
+	a := assert.New(t)
+	v := &zeroNodes{
+		Value:  reflect.ValueOf(1),
+		Values: map[string]reflect.Value{"invalid": {}, "valid": reflect.ValueOf("foo")},
+		Items:  []interface{}{reflect.Value{}},
+		private: &zeroNodes{
+			Value: reflect.Value{},
+		},
+	}
+	allocator := FromHeap()
+	allocator.SetStrictMode(true)
+
+	_, err := allocator.TryClone(reflect.ValueOf(v))
+	a.Assert(err != nil)
+	e, ok := err.(*InvalidValueError)
+	a.Assert(ok)
+	a.Equal(e.Type, reflect.TypeOf(v))
+	a.Equal(e.Paths, []string{"`.Values[\"invalid\"]`", "`.Items[0]`", "`.private.Value`"})
+	a.Equal(e.Error(), "go-clone: value of type `*clone.zeroNodes` contains invalid reflect.Value at `.Values[\"invalid\"]`, `.Items[0]`, `.private.Value`")
+
+	var dst zeroNodes
+	func() {
+		defer func() {
+			a.Equal(recover(), &InvalidValueError{
+				Type:  reflect.TypeOf(dst),
+				Paths: []string{"`.Value`"},
+			})
+		}()
+		allocator.CloneInto(reflect.ValueOf(&dst).Elem(), reflect.ValueOf(v.private).Elem())
+	}()
+
+	// Valid values and values without any reflect.Value are not affected.
+	v.Values = nil
+	v.Items = nil
+	v.private = nil
+	cloned := allocator.Clone(reflect.ValueOf(v)).Interface().(*zeroNodes)
+	a.Equal(cloned.Value.Interface(), 1)
+	a.Equal(allocator.Clone(reflect.ValueOf([]int{1})).Interface(), []int{1})
+
+	// Invalid values are cloned as they are if strict mode is disabled.
+	allocator.SetStrictMode(false)
+	a.Assert(!allocator.Clone(reflect.ValueOf(reflect.Value{})).Interface().(reflect.Value).IsValid())
+}
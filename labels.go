@@ -0,0 +1,81 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"context"
+	"reflect"
+	"runtime/pprof"
+)
+
+// Keys of pprof labels of clones.
+const (
+	LabelType = "go-clone.type" // The label of the type of the root value.
+	LabelTag  = "go-clone.tag"  // The label of the tag set by SetProfilerLabels.
+)
+
+type labelsOption struct {
+	enabled bool
+	tag     string
+}
+
+// SetProfilerLabels enables or disables pprof labels of clones made by heap allocator.
+//
+// See Allocator.SetProfilerLabels for more details.
+func SetProfilerLabels(enabled bool, tag string) {
+	defaultAllocator.SetProfilerLabels(enabled, tag)
+}
+
+// SetProfilerLabels enables or disables pprof labels of clones made by a.
+// If it's not set, a inherits it from parent allocator.
+//
+// When labels are enabled, every clone is run with goroutine labels
+// LabelType set to the type of the root value and LabelTag set to tag if tag is not empty,
+// so that CPU profiles attribute clone costs to root types and tags,
+// e.g. `go tool pprof -tagfocus=go-clone.type=*main.Config`.
+//
+// Labels are set by pprof.Do, which resets goroutine labels to none after a clone returns.
+// Labels set by callers are not restored,
+// and values cloned in custom funcs are labeled by their own types.
+func (a *Allocator) SetProfilerLabels(enabled bool, tag string) {
+	opt := &labelsOption{
+		enabled: enabled,
+		tag:     tag,
+	}
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.labels = opt
+		return copied
+	})
+}
+
+// lookupLabels returns the nearest labels option or nil if labels are disabled.
+func (cfg *config) lookupLabels() *labelsOption {
+	for current := cfg; current != nil; current = current.parent {
+		if current.labels == nil {
+			continue
+		}
+
+		if !current.labels.enabled {
+			return nil
+		}
+
+		return current.labels
+	}
+
+	return nil
+}
+
+// do calls fn with pprof labels of root type t.
+func (opt *labelsOption) do(t reflect.Type, fn func()) {
+	labels := pprof.Labels(LabelType, t.String())
+
+	if opt.tag != "" {
+		labels = pprof.Labels(LabelType, t.String(), LabelTag, opt.tag)
+	}
+
+	pprof.Do(context.Background(), labels, func(context.Context) {
+		fn()
+	})
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"bytes"
+	"reflect"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type labeledData struct {
+	Value *labeledValue
+}
+
+type labeledValue struct {
+	N int
+}
+
+// goroutineLabels returns the labels of all goroutines in goroutine profile.
+func goroutineLabels() string {
+	buf := &bytes.Buffer{}
+	pprof.Lookup("goroutine").WriteTo(buf, 1)
+	return buf.String()
+}
+
+func TestSetProfilerLabels(t *testing.T) {
+	a := assert.New(t)
+	var profile string
+	allocator := FromHeap()
+	allocator.SetCustomFunc(reflect.TypeOf(labeledValue{}), func(allocator *Allocator, old, new reflect.Value) {
+		profile = goroutineLabels()
+		new.Set(old)
+	})
+	allocator.SetProfilerLabels(true, "test-tag")
+
+	orig := &labeledData{
+		Value: &labeledValue{N: 1},
+	}
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*labeledData)
+	a.Equal(cloned, orig)
+	a.Assert(bytes.Contains([]byte(profile), []byte(`"go-clone.type":"*clone.labeledData"`)))
+	a.Assert(bytes.Contains([]byte(profile), []byte(`"go-clone.tag":"test-tag"`)))
+
+	var data labeledData
+	allocator.CloneInto(reflect.ValueOf(&data).Elem(), reflect.ValueOf(orig).Elem())
+	a.Equal(data, *orig)
+	a.Assert(bytes.Contains([]byte(profile), []byte(`"go-clone.type":"clone.labeledData"`)))
+
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	child.SetProfilerLabels(false, "")
+	profile = ""
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().(*labeledData)
+	a.Equal(cloned, orig)
+	a.Assert(!bytes.Contains([]byte(profile), []byte(`"go-clone.type"`)))
+}
+
+func TestLookupLabels(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	a.Equal(allocator.loadConfig().lookupLabels(), (*labelsOption)(nil))
+
+	allocator.SetProfilerLabels(true, "")
+	opt := allocator.loadConfig().lookupLabels()
+	a.Assert(opt != nil)
+	a.Equal(opt.tag, "")
+
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	a.Equal(child.loadConfig().lookupLabels(), opt)
+}
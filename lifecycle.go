@@ -0,0 +1,53 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import "unsafe"
+
+// Reset returns all memory allocated by a to its pool at once,
+// so that the pool can be reused by the next batch of clones.
+// It calls AllocatorMethods.Reset and does nothing if Reset is not set, e.g. in heap allocator.
+//
+// All values allocated by a before Reset must not be used after Reset.
+// Memory allocated by fallback allocator is not returned. Reset fallback allocator separately if necessary.
+func (a *Allocator) Reset() {
+	if a.reset != nil {
+		a.reset(a.pool)
+	}
+}
+
+// Release returns all memory of a's pool, including the memory of a itself if it's allocated in the pool.
+// It calls AllocatorMethods.Release and does nothing if Release is not set, e.g. in heap allocator.
+//
+// Neither a nor any value allocated by a can be used after Release.
+// Memory allocated by fallback allocator is not released. Release fallback allocator separately if necessary.
+func (a *Allocator) Release() {
+	if a.release != nil {
+		a.release(a.pool)
+	}
+}
+
+func (am *AllocatorMethods) reset(parent *Allocator, pool unsafe.Pointer) func(pool unsafe.Pointer) {
+	if am != nil && am.Reset != nil {
+		return am.Reset
+	}
+
+	if parent != nil && parent.pool == pool {
+		return parent.reset
+	}
+
+	return nil
+}
+
+func (am *AllocatorMethods) release(parent *Allocator, pool unsafe.Pointer) func(pool unsafe.Pointer) {
+	if am != nil && am.Release != nil {
+		return am.Release
+	}
+
+	if parent != nil && parent.pool == pool {
+		return parent.release
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+type batchPool struct {
+	values   []reflect.Value
+	resets   int
+	released bool
+}
+
+func newBatchAllocator(bp *batchPool) *Allocator {
+	return NewAllocator(unsafe.Pointer(bp), &AllocatorMethods{
+		New: func(pool unsafe.Pointer, t reflect.Type) reflect.Value {
+			bp := (*batchPool)(pool)
+			v := reflect.New(t)
+			bp.values = append(bp.values, v)
+			return v
+		},
+		Reset: func(pool unsafe.Pointer) {
+			bp := (*batchPool)(pool)
+
+			// Keep the allocator itself.
+			bp.values = bp.values[:1]
+			bp.resets++
+		},
+		Release: func(pool unsafe.Pointer) {
+			bp := (*batchPool)(pool)
+			bp.values = nil
+			bp.released = true
+		},
+	})
+}
+
+func TestAllocatorLifecycle(t *testing.T) {
+	a := assert.New(t)
+	type T struct {
+		Foo *int
+		Bar *string
+	}
+	n := 1
+	s := "bar"
+	orig := &T{
+		Foo: &n,
+		Bar: &s,
+	}
+
+	bp := &batchPool{}
+	allocator := newBatchAllocator(bp)
+	a.Equal(len(bp.values), 1)
+
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*T)
+	a.Equal(cloned, orig)
+	a.Equal(len(bp.values), 4)
+
+	allocator.Reset()
+	a.Equal(len(bp.values), 1)
+	a.Equal(bp.resets, 1)
+
+	// Allocator can be used after Reset.
+	cloned = allocator.Clone(reflect.ValueOf(orig)).Interface().(*T)
+	a.Equal(cloned, orig)
+	a.Equal(len(bp.values), 4)
+
+	// Child allocators sharing the pool inherit hooks.
+	child := NewAllocator(unsafe.Pointer(bp), &AllocatorMethods{
+		Parent: allocator,
+	})
+	child.Reset()
+	a.Equal(bp.resets, 2)
+
+	allocator.Release()
+	a.Assert(bp.released)
+	a.Equal(len(bp.values), 0)
+
+	// Hooks are no-op in heap allocator.
+	heap := FromHeap()
+	heap.Reset()
+	heap.Release()
+}
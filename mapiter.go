@@ -39,3 +39,9 @@ func (it *iter) Key() reflect.Value {
 func (it *iter) Value() reflect.Value {
 	return it.m.MapIndex(it.k)
 }
+
+const iterKeyIsReused = false
+
+func setIterKey(key reflect.Value, it *iter) {
+	key.Set(it.k)
+}
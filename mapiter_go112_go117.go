@@ -0,0 +1,20 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build go1.12 && !go1.18
+// +build go1.12,!go1.18
+
+package clone
+
+import (
+	"reflect"
+)
+
+// iterKeyIsReused is true if map keys can be set without allocating memory.
+const iterKeyIsReused = false
+
+// setIterKey sets key to the key of current map entry.
+// Memory is allocated for each key before go1.18.
+func setIterKey(key reflect.Value, iter *reflect.MapIter) {
+	key.Set(iter.Key())
+}
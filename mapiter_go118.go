@@ -0,0 +1,19 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package clone
+
+import (
+	"reflect"
+)
+
+// iterKeyIsReused is true if map keys can be set without allocating memory.
+const iterKeyIsReused = true
+
+// setIterKey sets key to the key of current map entry without allocating memory.
+func setIterKey(key reflect.Value, iter *reflect.MapIter) {
+	key.SetIterKey(iter)
+}
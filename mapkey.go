@@ -0,0 +1,164 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MapKeyPolicy is the policy to clone keys of a map.
+type MapKeyPolicy int
+
+// All map key policies.
+const (
+	MapKeyPolicyClone MapKeyPolicy = iota + 1 // Clone keys in depth. It's the default policy.
+	MapKeyPolicyShare                         // Share keys with the original map and clone values only.
+)
+
+// SetMapKeyPolicy sets the key policy for map type t in heap allocator.
+// If t is not a map type, SetMapKeyPolicy ignores t.
+//
+// See Allocator.SetMapKeyPolicy for more details.
+func SetMapKeyPolicy(t reflect.Type, policy MapKeyPolicy) {
+	defaultAllocator.SetMapKeyPolicy(t, policy)
+}
+
+// SetMapKeyPolicy sets the key policy for map type t.
+// If t is not a map type, SetMapKeyPolicy ignores t.
+//
+// By default, keys are cloned in depth like values.
+// If a key is a pointer, the cloned key is a new pointer,
+// so that external holders of the original key cannot look up the cloned map with it.
+// Set MapKeyPolicyShare to keep such key identity in cloned maps.
+//
+// If policy is not a valid policy, a inherits the policy from parent allocator.
+func (a *Allocator) SetMapKeyPolicy(t reflect.Type, policy MapKeyPolicy) {
+	if t.Kind() != reflect.Map {
+		return
+	}
+
+	if policy < MapKeyPolicyClone || policy > MapKeyPolicyShare {
+		policy = 0
+	}
+
+	a.updateTypeConfig(t, func(tc *typeConfig) {
+		tc.mapKeyPolicy = policy
+	})
+}
+
+func (cfg *config) lookupMapKeyPolicy(t reflect.Type) MapKeyPolicy {
+	tc := cfg.lookup(t, func(tc *typeConfig) bool {
+		return tc.mapKeyPolicy != 0
+	})
+
+	if tc == nil {
+		return MapKeyPolicyClone
+	}
+
+	return tc.mapKeyPolicy
+}
+
+// CloneMapValues clones map m in heap with keys shared and values cloned in depth.
+//
+// See Allocator.CloneMapValues for more details.
+func CloneMapValues(m interface{}) interface{} {
+	if m == nil {
+		return nil
+	}
+
+	return defaultAllocator.CloneMapValues(reflect.ValueOf(m)).Interface()
+}
+
+// CloneMapKeys clones map m in heap with keys cloned in depth and values shared.
+//
+// See Allocator.CloneMapKeys for more details.
+func CloneMapKeys(m interface{}) interface{} {
+	if m == nil {
+		return nil
+	}
+
+	return defaultAllocator.CloneMapKeys(reflect.ValueOf(m)).Interface()
+}
+
+// CloneMapValues returns a new map with memory allocated from a.
+// All keys in m are shared with the new map and all values are cloned in depth,
+// no matter what key policy is set for the map type.
+// It panics if m is not a map.
+//
+// It's a building block for caches and indexes,
+// which must keep key identity but isolate values from the original map.
+func (a *Allocator) CloneMapValues(m reflect.Value) reflect.Value {
+	return a.cloneMapPartially(m, false, true)
+}
+
+// CloneMapKeys returns a new map with memory allocated from a.
+// All keys in m are cloned in depth and all values are shared with the new map.
+// It panics if m is not a map.
+func (a *Allocator) CloneMapKeys(m reflect.Value) reflect.Value {
+	return a.cloneMapPartially(m, true, false)
+}
+
+func (a *Allocator) cloneMapPartially(m reflect.Value, cloneKeys, cloneValues bool) reflect.Value {
+	if !m.IsValid() {
+		return m
+	}
+
+	t := m.Type()
+
+	if t.Kind() != reflect.Map {
+		panic(fmt.Errorf("go-clone: `%v` is not a map", t))
+	}
+
+	if m.IsNil() {
+		return reflect.Zero(t)
+	}
+
+	state := &cloneState{}
+	a.initCloneState(state, false)
+
+	tx := state.tx
+
+	if tx != nil {
+		state.tx = nil
+		defer tx.end()
+	}
+
+	nv := state.allocator.MakeMap(t, m.Len())
+	checksNaNKeys := state.checksNaNKeys(t)
+
+	for iter := mapIter(m); iter.Next(); {
+		key := iter.Key()
+		value := iter.Value()
+
+		if checksNaNKeys && state.skipNaNKey(t, key) {
+			continue
+		}
+
+		state.traceKey("", key)
+
+		if cloneKeys {
+			key = state.clone(key)
+		} else if !key.CanInterface() {
+			key = forceClearROFlag(key)
+		}
+
+		if cloneValues {
+			value = state.clone(value)
+		} else if !value.CanInterface() {
+			value = forceClearROFlag(value)
+		}
+
+		nv.SetMapIndex(key, value)
+	}
+
+	state.drain()
+	state.rebind(nv)
+
+	if tx != nil {
+		tx.commit()
+	}
+
+	return nv
+}
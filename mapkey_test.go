@@ -0,0 +1,141 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+type mapKeyEntry struct {
+	Name string
+}
+
+func TestMapKeyPolicy(t *testing.T) {
+	a := assert.New(t)
+	typeOfMap := reflect.TypeOf(map[*mapKeyEntry][]int{})
+
+	key := &mapKeyEntry{Name: "foo"}
+	orig := map[*mapKeyEntry][]int{
+		key: {1, 2},
+	}
+
+	// Keys are cloned by default.
+	allocator := NewAllocator(nil, nil)
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(map[*mapKeyEntry][]int)
+	a.Equal(len(cloned), 1)
+	a.Assert(cloned[key] == nil)
+
+	allocator.SetMapKeyPolicy(typeOfMap, MapKeyPolicyShare)
+	allocator.SetMapKeyPolicy(reflect.TypeOf(0), MapKeyPolicyShare)
+	cloned = allocator.Clone(reflect.ValueOf(orig)).Interface().(map[*mapKeyEntry][]int)
+	a.Equal(cloned[key], []int{1, 2})
+	a.Assert(&cloned[key][0] != &orig[key][0])
+
+	// Child allocators inherit the policy.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	type data struct {
+		m map[*mapKeyEntry][]int
+	}
+	clonedData := child.CloneSlowly(reflect.ValueOf(data{m: orig})).Interface().(data)
+	a.Equal(clonedData.m[key], []int{1, 2})
+
+	child.SetMapKeyPolicy(typeOfMap, MapKeyPolicyClone)
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().(map[*mapKeyEntry][]int)
+	a.Assert(cloned[key] == nil)
+
+	// Invalid policy means inherit.
+	child.SetMapKeyPolicy(typeOfMap, MapKeyPolicy(100))
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().(map[*mapKeyEntry][]int)
+	a.Assert(cloned[key] != nil)
+}
+
+func TestCloneMapValuesAndKeys(t *testing.T) {
+	a := assert.New(t)
+
+	key := &mapKeyEntry{Name: "foo"}
+	value := []int{1, 2}
+	orig := map[*mapKeyEntry][]int{
+		key: value,
+	}
+
+	clonedValues := CloneMapValues(orig).(map[*mapKeyEntry][]int)
+	a.Equal(clonedValues[key], value)
+	a.Assert(&clonedValues[key][0] != &value[0])
+
+	clonedKeys := CloneMapKeys(orig).(map[*mapKeyEntry][]int)
+	a.Equal(len(clonedKeys), 1)
+	a.Assert(clonedKeys[key] == nil)
+
+	for k, v := range clonedKeys {
+		a.Assert(k != key)
+		a.Equal(k, key)
+		a.Assert(&v[0] == &value[0])
+	}
+
+	a.Equal(CloneMapValues(nil), nil)
+	a.Equal(CloneMapKeys(map[string]int(nil)), map[string]int(nil))
+	a.Assert(func() (ok bool) {
+		defer func() {
+			ok = recover() != nil
+		}()
+		CloneMapValues([]int{1})
+		return
+	}())
+}
+
+type stringKeyMaps struct {
+	Names   map[string]struct{}
+	aliases map[mapKeyName]int
+}
+
+type mapKeyName string
+
+func TestCloneStringKeyMap(t *testing.T) {
+	a := assert.New(t)
+	orig := &stringKeyMaps{
+		Names:   map[string]struct{}{"foo": {}, "bar": {}},
+		aliases: map[mapKeyName]int{"foo": 1, "bar": 2},
+	}
+	cloned := Clone(orig).(*stringKeyMaps)
+	a.Equal(cloned, orig)
+	a.Assert(reflect.ValueOf(cloned.aliases).Pointer() != reflect.ValueOf(orig.aliases).Pointer())
+
+	for k := range cloned.Names {
+		// Keys are shared with the original map.
+		a.Equal(len(k), 3)
+
+		for ok := range orig.Names {
+			if k == ok {
+				a.Equal((*stringHeader)(unsafe.Pointer(&k)).Data, (*stringHeader)(unsafe.Pointer(&ok)).Data)
+			}
+		}
+	}
+}
+
+func TestCloneStringKeyMapAllocs(t *testing.T) {
+	a := assert.New(t)
+	small := map[string]struct{}{"0": {}}
+	large := make(map[string]struct{}, 1000)
+
+	for i := 0; i < 1000; i++ {
+		large[fmt.Sprint(i)] = struct{}{}
+	}
+
+	smallAllocs := testing.AllocsPerRun(10, func() {
+		Clone(small)
+	})
+	largeAllocs := testing.AllocsPerRun(10, func() {
+		Clone(large)
+	})
+
+	// No allocation for each key. Only the map itself allocates memory.
+	a.Assert(largeAllocs-smallAllocs < 10)
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type maxDepthOption struct {
+	n int
+}
+
+// DepthError is the error of a value beyond max depth found in strict mode.
+type DepthError struct {
+	MaxDepth int          // The max depth.
+	Type     reflect.Type // The type of the value beyond max depth.
+}
+
+func (e *DepthError) Error() string {
+	return fmt.Sprintf("go-clone: value of type `%v` is beyond max depth %v", e.Type, e.MaxDepth)
+}
+
+// SetMaxDepth sets the max depth of values cloned by heap allocator.
+//
+// See Allocator.SetMaxDepth for more details.
+func SetMaxDepth(n int) {
+	defaultAllocator.SetMaxDepth(n)
+}
+
+// SetMaxDepth sets the max depth of values cloned by clone methods in a.
+// If n is not positive, the depth is unlimited.
+// If max depth is not set, a inherits it from parent allocator.
+//
+// The root value is in depth 1, and values referenced by a value in depth n are in depth n+1.
+// Struct fields and array elements cloned in place are in the same depth as their owners.
+// Values beyond max depth are shadow copied.
+// In strict mode, clone methods panic with a *DepthError instead,
+// unless the value is a scalar or a nil value which is the same in both ways.
+//
+// Values cloned by custom funcs with allocator methods, e.g. Allocator.Clone, are in new clones.
+// Their depth starts from 1.
+func (a *Allocator) SetMaxDepth(n int) {
+	if n < 0 {
+		n = 0
+	}
+
+	opt := &maxDepthOption{
+		n: n,
+	}
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.maxDepth = opt
+		return copied
+	})
+}
+
+// CloneWithMaxDepth clones v in heap like Clone with max depth n.
+// It overrides max depth set by SetMaxDepth.
+//
+// See Allocator.SetMaxDepth for more details.
+func CloneWithMaxDepth(v interface{}, n int) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	return defaultAllocator.cloneWithMaxDepth(reflect.ValueOf(v), n, false).Interface()
+}
+
+// CloneWithMaxDepth works in the same way as Clone with max depth n.
+// It overrides max depth set by SetMaxDepth.
+func (a *Allocator) CloneWithMaxDepth(val reflect.Value, n int) reflect.Value {
+	return a.cloneWithMaxDepth(val, n, true)
+}
+
+func (a *Allocator) cloneWithMaxDepth(val reflect.Value, n int, inCustomFunc bool) reflect.Value {
+	if !val.IsValid() {
+		return val
+	}
+
+	if n < 0 {
+		n = 0
+	}
+
+	state := &cloneState{}
+	a.initCloneState(state, false)
+	state.maxDepth = n
+
+	if inCustomFunc {
+		state.skipCustomFuncValue = val
+	}
+
+	return state.cloneRoot(val)
+}
+
+// CloneDepth clones v in heap like Clone down to depth n and shadow copies values beyond depth n.
+//
+// See Allocator.CloneDepth for more details.
+func CloneDepth(v interface{}, n int) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	return defaultAllocator.cloneDepth(reflect.ValueOf(v), n, false).Interface()
+}
+
+// CloneDepth works in the same way as Clone down to depth n and shadow copies values beyond depth n.
+// Depth is counted in the same way as SetMaxDepth.
+// If n is not positive, val is returned as it is.
+//
+// Unlike CloneWithMaxDepth, values beyond depth n are shared with val even in strict mode,
+// as sharing is intended, e.g. in copy-on-write trees in which only top levels are mutated.
+func (a *Allocator) CloneDepth(val reflect.Value, n int) reflect.Value {
+	return a.cloneDepth(val, n, true)
+}
+
+func (a *Allocator) cloneDepth(val reflect.Value, n int, inCustomFunc bool) reflect.Value {
+	if !val.IsValid() || n <= 0 {
+		return val
+	}
+
+	state := &cloneState{}
+	a.initCloneState(state, false)
+	state.maxDepth = n
+	state.shareDeep = true
+
+	if inCustomFunc {
+		state.skipCustomFuncValue = val
+	}
+
+	return state.cloneRoot(val)
+}
+
+// lookupMaxDepth returns the nearest max depth or 0 if the depth is unlimited.
+func (cfg *config) lookupMaxDepth() int {
+	for current := cfg; current != nil; current = current.parent {
+		if current.maxDepth != nil {
+			return current.maxDepth.n
+		}
+	}
+
+	return 0
+}
+
+// cloneBeyondMaxDepth shadow copies v which is beyond max depth.
+func (state *cloneState) cloneBeyondMaxDepth(v reflect.Value) reflect.Value {
+	if state.allocator.isScalar(v.Kind()) {
+		return copyScalarValue(v)
+	}
+
+	if isNilable(v.Kind()) && v.IsNil() {
+		return reflect.Zero(v.Type())
+	}
+
+	if state.strict && !state.shareDeep {
+		panic(&DepthError{
+			MaxDepth: state.maxDepth,
+			Type:     v.Type(),
+		})
+	}
+
+	if !v.CanInterface() {
+		v = forceClearROFlag(v)
+	}
+
+	return v
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type depthNode struct {
+	Name     string
+	Children []*depthNode
+	next     *depthNode
+}
+
+func TestSetMaxDepth(t *testing.T) {
+	a := assert.New(t)
+	leaf := &depthNode{Name: "leaf"}
+	mid := &depthNode{Name: "mid", Children: []*depthNode{leaf}, next: leaf}
+	root := &depthNode{Name: "root", Children: []*depthNode{mid}}
+
+	// The root pointer is in depth 1, the slice in depth 2 and mid in depth 3.
+	allocator := NewAllocator(nil, nil)
+	allocator.SetMaxDepth(3)
+	cloned := allocator.Clone(reflect.ValueOf(root)).Interface().(*depthNode)
+	a.Assert(cloned != root)
+	a.Assert(cloned.Children[0] != mid)
+	a.Assert(&cloned.Children[0].Children[0] == &mid.Children[0])
+	a.Assert(cloned.Children[0].next == leaf)
+	a.Equal(cloned, root)
+
+	// Child allocators inherit max depth.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	cloned = child.Clone(reflect.ValueOf(root)).Interface().(*depthNode)
+	a.Assert(cloned.Children[0].next == leaf)
+
+	child.SetMaxDepth(0)
+	cloned = child.Clone(reflect.ValueOf(root)).Interface().(*depthNode)
+	a.Assert(cloned.Children[0].next != leaf)
+	a.Equal(cloned, root)
+
+	// Max depth per call overrides the allocator's.
+	cloned = allocator.CloneWithMaxDepth(reflect.ValueOf(root), 1).Interface().(*depthNode)
+	a.Assert(cloned != root)
+	a.Assert(&cloned.Children[0] == &root.Children[0])
+
+	cloned = CloneWithMaxDepth(root, 2).(*depthNode)
+	a.Assert(&cloned.Children[0] != &root.Children[0])
+	a.Assert(cloned.Children[0] == mid)
+
+	// Strict mode rejects values beyond max depth except scalars and nil values.
+	allocator.SetStrictMode(true)
+	_, err := allocator.TryClone(reflect.ValueOf(root))
+	de, ok := err.(*DepthError)
+	a.Assert(ok)
+	a.Equal(de.MaxDepth, 3)
+	a.Equal(de.Type, reflect.TypeOf([]*depthNode{}))
+	a.Equal(de.Error(), "go-clone: value of type `[]*clone.depthNode` is beyond max depth 3")
+
+	_, err = allocator.TryClone(reflect.ValueOf(&depthNode{Name: "single"}))
+	a.NilError(err)
+}
+
+func TestCloneDepth(t *testing.T) {
+	a := assert.New(t)
+	leaf := &depthNode{Name: "leaf"}
+	mid := &depthNode{Name: "mid", Children: []*depthNode{leaf}}
+	root := &depthNode{Name: "root", Children: []*depthNode{mid}}
+
+	// Top of the tree is cloned and mid is shared.
+	cloned := CloneDepth(root, 2).(*depthNode)
+	a.Assert(cloned != root)
+	a.Assert(&cloned.Children[0] != &root.Children[0])
+	a.Assert(cloned.Children[0] == mid)
+	a.Equal(cloned, root)
+
+	cloned = CloneDepth(root, 3).(*depthNode)
+	a.Assert(cloned.Children[0] != mid)
+	a.Assert(&cloned.Children[0].Children[0] == &mid.Children[0])
+
+	a.Assert(CloneDepth(root, 0).(*depthNode) == root)
+	a.Equal(CloneDepth(nil, 1), nil)
+
+	// Values beyond depth n are shared in strict mode.
+	allocator := NewAllocator(nil, nil)
+	allocator.SetStrictMode(true)
+	allocator.SetMaxDepth(10)
+	cloned = allocator.CloneDepth(reflect.ValueOf(root), 1).Interface().(*depthNode)
+	a.Assert(cloned != root)
+	a.Assert(&cloned.Children[0] == &root.Children[0])
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+)
+
+// SetMinCopy enables or disables min-copy mode in heap allocator.
+//
+// See Allocator.SetMinCopy for more details.
+func SetMinCopy(minCopy bool) {
+	defaultAllocator.SetMinCopy(minCopy)
+}
+
+// SetMinCopy enables or disables min-copy mode in a.
+// If min-copy mode is not set, a inherits it from parent allocator.
+// Min-copy mode is disabled in the default allocator.
+//
+// In min-copy mode, values which cannot be modified by any means are shared with the clone instead of copied.
+// Besides strings and funcs, which are always shared, an interface holding a value by value is shared,
+// if the value can be shadow copied, i.e. it's a scalar, string, func
+// or a struct or array of them without any custom func or tag.
+// Such a value references nothing else, and a value held by an interface is not addressable.
+//
+// Pointers, slices and maps are still copied, as values referenced by them can be modified through them.
+// Call SetShareShallowReferences to share them as well.
+//
+// Shared values are not cloned, so that validators are not called with them.
+func (a *Allocator) SetMinCopy(minCopy bool) {
+	option := optionDisabled
+
+	if minCopy {
+		option = optionEnabled
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.minCopy = option
+		return copied
+	})
+}
+
+// SetShareShallowReferences sets whether to share shallow references in heap allocator.
+//
+// See Allocator.SetShareShallowReferences for more details.
+func SetShareShallowReferences(share bool) {
+	defaultAllocator.SetShareShallowReferences(share)
+}
+
+// SetShareShallowReferences sets whether a shares pointers, slices and maps with the clone instead of copying them,
+// if all values they reference can be shadow copied, e.g. `*Limits`, `[]int` or `map[string]string`
+// where `Limits` is a struct of numbers and strings.
+// If it's not set, a inherits it from parent allocator.
+// Shallow references are copied in the default allocator.
+//
+// Shared values are not immutable. Writing through a shared reference changes both the source and the clone.
+// It's designed for read-mostly snapshots, e.g. big configs,
+// which are never modified in place by either the source or the clone.
+//
+// Shared values are not cloned, so that validators are not called with them.
+func (a *Allocator) SetShareShallowReferences(share bool) {
+	option := optionDisabled
+
+	if share {
+		option = optionEnabled
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.shareRefs = option
+		return copied
+	})
+}
+
+func (cfg *config) isSharingShallowReferences() bool {
+	for current := cfg; current != nil; current = current.parent {
+		switch current.shareRefs {
+		case optionEnabled:
+			return true
+		case optionDisabled:
+			return false
+		}
+	}
+
+	return false
+}
+
+func (cfg *config) isMinCopy() bool {
+	for current := cfg; current != nil; current = current.parent {
+		switch current.minCopy {
+		case optionEnabled:
+			return true
+		case optionDisabled:
+			return false
+		}
+	}
+
+	return false
+}
+
+// isShallowType returns true if a clone of any value of t is a shadow copy of the value.
+func (cfg *config) isShallowType(t reflect.Type) bool {
+	if cfg.isScalarType(t) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Array:
+		return t.Len() == 0 || cfg.isShallowType(t.Elem())
+	case reflect.Struct:
+		st := cfg.loadStructType(t)
+		return st.CanShadowCopy()
+	}
+
+	return false
+}
+
+// shareMinCopy returns v itself if v can be shared with the clone
+// in min-copy mode or if shallow references are shared.
+func (state *cloneState) shareMinCopy(v reflect.Value) (shared reflect.Value, ok bool) {
+	t := v.Type()
+
+	switch t.Kind() {
+	case reflect.Interface:
+		if !state.minCopy {
+			return
+		}
+
+		// Interface policies apply to unexported dynamic types.
+		et := v.Elem().Type()
+		ok = IsExportedType(et) && state.config.isShallowType(et)
+	case reflect.Map:
+		ok = state.shareRefs && state.config.isShallowType(t.Key()) && state.config.isShallowType(t.Elem())
+	case reflect.Ptr, reflect.Slice:
+		ok = state.shareRefs && state.config.isShallowType(t.Elem())
+	}
+
+	if !ok {
+		return
+	}
+
+	shared = v
+
+	if !shared.CanInterface() {
+		shared = forceClearROFlag(shared)
+	}
+
+	return
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+type minCopyLimits struct {
+	Max     int
+	Timeout time.Duration
+	Names   [2]string
+}
+
+type minCopyConfig struct {
+	Limits   *minCopyLimits
+	Ports    []int
+	Backends []minCopyLimits
+	Labels   map[string]string
+	Routes   map[string]*minCopyLimits
+	Children []*minCopyConfig
+	Any      interface{}
+	Ptr      interface{}
+	private  *int
+	Skipped  *minCopySkipped
+}
+
+type minCopySkipped struct {
+	Token string `clone:"skip"`
+}
+
+func newMinCopyConfig() *minCopyConfig {
+	n := 1
+	limits := &minCopyLimits{Max: 10}
+	return &minCopyConfig{
+		Limits:   limits,
+		Ports:    []int{80, 443},
+		Backends: []minCopyLimits{{Max: 1}},
+		Labels:   map[string]string{"env": "prod"},
+		Routes:   map[string]*minCopyLimits{"/": limits},
+		Children: []*minCopyConfig{{Ports: []int{8080}}},
+		Any:      minCopyLimits{Max: 2},
+		Ptr:      limits,
+		private:  &n,
+		Skipped:  &minCopySkipped{Token: "secret"},
+	}
+}
+
+func TestMinCopy(t *testing.T) {
+	a := assert.New(t)
+	v := newMinCopyConfig()
+
+	parent := FromHeap()
+	parent.SetMinCopy(true)
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: parent,
+	})
+
+	for _, allocator := range []*Allocator{parent, child} {
+		cloned := allocator.Clone(reflect.ValueOf(v)).Interface().(*minCopyConfig)
+		a.Assert(cloned != v)
+
+		// Values held by interfaces can be shared.
+		a.Equal(cloned.Any, v.Any)
+
+		// Values which can be modified through references are copied.
+		a.Assert(cloned.Limits != v.Limits)
+		a.Assert(&cloned.Ports[0] != &v.Ports[0])
+		a.Assert(&cloned.Backends[0] != &v.Backends[0])
+		a.Assert(reflect.ValueOf(cloned.Labels).Pointer() != reflect.ValueOf(v.Labels).Pointer())
+		a.Assert(cloned.Ptr.(*minCopyLimits) != v.Limits)
+		a.Assert(cloned.private != v.private)
+	}
+}
+
+func TestMinCopyMutableValues(t *testing.T) {
+	a := assert.New(t)
+	n := 1
+	v := &struct {
+		Int   *int
+		Bytes []byte
+		Map   map[string]int
+	}{
+		Int:   &n,
+		Bytes: []byte("go-clone"),
+		Map:   map[string]int{"a": 1},
+	}
+
+	allocator := FromHeap()
+	allocator.SetMinCopy(true)
+	cloned := allocator.Clone(reflect.ValueOf(v)).Elem()
+	*cloned.Field(0).Interface().(*int) = 2
+	cloned.Field(1).Interface().([]byte)[0] = 'G'
+	cloned.Field(2).Interface().(map[string]int)["a"] = 2
+
+	a.Equal(n, 1)
+	a.Equal(string(v.Bytes), "go-clone")
+	a.Equal(v.Map["a"], 1)
+}
+
+func TestShareShallowReferences(t *testing.T) {
+	a := assert.New(t)
+	v := newMinCopyConfig()
+	limits := v.Limits
+
+	parent := FromHeap()
+	parent.SetShareShallowReferences(true)
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: parent,
+	})
+
+	for _, allocator := range []*Allocator{parent, child} {
+		cloned := allocator.Clone(reflect.ValueOf(v)).Interface().(*minCopyConfig)
+		a.Assert(cloned != v)
+
+		// Values which can be shadow copied are shared.
+		a.Assert(cloned.Limits == v.Limits)
+		a.Assert(&cloned.Ports[0] == &v.Ports[0])
+		a.Assert(&cloned.Backends[0] == &v.Backends[0])
+		a.Assert(reflect.ValueOf(cloned.Labels).Pointer() == reflect.ValueOf(v.Labels).Pointer())
+		a.Assert(cloned.Ptr.(*minCopyLimits) == limits)
+		a.Assert(cloned.private == v.private)
+		a.Assert(&cloned.Children[0].Ports[0] == &v.Children[0].Ports[0])
+		a.Equal(cloned.Any, v.Any)
+
+		// Values referencing other values are still copied.
+		a.Assert(reflect.ValueOf(cloned.Routes).Pointer() != reflect.ValueOf(v.Routes).Pointer())
+		a.Assert(cloned.Routes["/"] == limits)
+		a.Assert(&cloned.Children[0] != &v.Children[0])
+		a.Assert(cloned.Children[0] != v.Children[0])
+		a.Assert(cloned.Skipped != v.Skipped)
+		a.Equal(cloned.Skipped.Token, "")
+
+		// Values are shared by slow clones as well.
+		cloned = allocator.CloneSlowly(reflect.ValueOf(v)).Interface().(*minCopyConfig)
+		a.Assert(cloned.Limits == v.Limits)
+		a.Assert(&cloned.Ports[0] == &v.Ports[0])
+	}
+
+	// Shallow references are copied by default.
+	cloned := Clone(v).(*minCopyConfig)
+	a.Assert(cloned.Limits != v.Limits)
+	a.Assert(&cloned.Ports[0] != &v.Ports[0])
+
+	// Custom funcs disable sharing.
+	allocator := FromHeap()
+	allocator.SetShareShallowReferences(true)
+	allocator.SetCustomFunc(reflect.TypeOf(minCopyLimits{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.Set(old)
+	})
+	cloned = allocator.Clone(reflect.ValueOf(v)).Interface().(*minCopyConfig)
+	a.Assert(cloned.Limits != v.Limits)
+	a.Equal(cloned.Limits, v.Limits)
+	a.Assert(&cloned.Ports[0] == &v.Ports[0])
+}
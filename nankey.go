@@ -0,0 +1,139 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// NaNKeyPolicy is the policy to clone map entries whose keys contain NaN.
+//
+// As NaN is not equal to itself, such an entry cannot be looked up by its key.
+// Every insertion with a NaN key adds a new entry, so that a map may contain
+// many entries with NaN keys.
+type NaNKeyPolicy int
+
+// All NaN key policies.
+const (
+	NaNKeyPolicyPreserve NaNKeyPolicy = iota + 1 // Copy entries with NaN keys as they are. It's the default policy.
+	NaNKeyPolicyDrop                             // Drop entries with NaN keys and report a warning by the warning func.
+	NaNKeyPolicyError                            // Panic with a *NaNKeyError.
+)
+
+// NaNKeyError is the error of a map containing NaN keys rejected by NaNKeyPolicyError.
+type NaNKeyError struct {
+	Type reflect.Type // The type of the map.
+}
+
+func (e *NaNKeyError) Error() string {
+	return fmt.Sprintf("go-clone: map of type `%v` contains NaN keys", e.Type)
+}
+
+// SetNaNKeyPolicy sets the NaN key policy in heap allocator.
+//
+// See Allocator.SetNaNKeyPolicy for more details.
+func SetNaNKeyPolicy(policy NaNKeyPolicy) {
+	defaultAllocator.SetNaNKeyPolicy(policy)
+}
+
+// SetNaNKeyPolicy sets the policy to clone map entries whose keys contain NaN,
+// including NaN floats, complex numbers with NaN parts,
+// and arrays, structs or interfaces containing them.
+// If policy is not a valid policy, a inherits the policy from parent allocator.
+//
+// By default, entries with NaN keys are copied to cloned maps one by one,
+// so that no entry is lost or duplicated.
+// Numeric workloads, which treat NaN keys as corrupted data,
+// can drop such entries with warnings reported by the func set by SetWarningFunc,
+// or reject such maps with a *NaNKeyError.
+func (a *Allocator) SetNaNKeyPolicy(policy NaNKeyPolicy) {
+	if policy < NaNKeyPolicyPreserve || policy > NaNKeyPolicyError {
+		policy = 0
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.nanKeyPolicy = policy
+		return copied
+	})
+}
+
+func (cfg *config) lookupNaNKeyPolicy() NaNKeyPolicy {
+	for current := cfg; current != nil; current = current.parent {
+		if current.nanKeyPolicy != 0 {
+			return current.nanKeyPolicy
+		}
+	}
+
+	return NaNKeyPolicyPreserve
+}
+
+// checksNaNKeys returns true if keys of map type t must be checked by skipNaNKey.
+func (state *cloneState) checksNaNKeys(t reflect.Type) bool {
+	return state.nanKeyPolicy != NaNKeyPolicyPreserve && mayContainNaN(t.Key())
+}
+
+// skipNaNKey returns true if the entry of key in map type t must be dropped according to NaN key policy.
+// It panics with a *NaNKeyError if the policy is NaNKeyError.
+func (state *cloneState) skipNaNKey(t reflect.Type, key reflect.Value) bool {
+	if !containsNaN(key) {
+		return false
+	}
+
+	if state.nanKeyPolicy == NaNKeyPolicyError {
+		panic(&NaNKeyError{
+			Type: t,
+		})
+	}
+
+	state.warn(t, "entry with NaN key is dropped")
+	return true
+}
+
+// mayContainNaN returns true if values of t may contain NaN.
+func mayContainNaN(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.Interface:
+		return true
+	case reflect.Array:
+		return t.Len() != 0 && mayContainNaN(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if mayContainNaN(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// containsNaN returns true if v contains NaN.
+func containsNaN(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return math.IsNaN(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return math.IsNaN(real(c)) || math.IsNaN(imag(c))
+	case reflect.Interface:
+		return !v.IsNil() && containsNaN(v.Elem())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if containsNaN(v.Index(i)) {
+				return true
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if containsNaN(v.Field(i)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestNaNKeyPolicy(t *testing.T) {
+	a := assert.New(t)
+	type Point struct {
+		X, Y float64
+	}
+	nan := math.NaN()
+	floats := map[float64]int{nan: 1, 1: 2}
+	floats[nan] = 3
+	points := map[Point]string{{X: nan}: "nan", {X: 1}: "one"}
+	ifaces := map[interface{}]int{nan: 1, "foo": 2}
+	complexes := map[complex128]int{complex(0, nan): 1, 1: 2}
+
+	// Entries with NaN keys are preserved by default.
+	allocator := FromHeap()
+	cloned := allocator.Clone(reflect.ValueOf(floats)).Interface().(map[float64]int)
+	a.Equal(len(cloned), 3)
+	a.Equal(cloned[1], 2)
+	sum := 0
+
+	for k, v := range cloned {
+		if k != k {
+			sum += v
+		}
+	}
+
+	a.Equal(sum, 4)
+	a.Equal(len(allocator.Clone(reflect.ValueOf(points)).Interface().(map[Point]string)), 2)
+
+	// Entries with NaN keys are dropped with warnings.
+	var warnings []string
+	allocator.SetWarningFunc(func(t reflect.Type, warning string) {
+		warnings = append(warnings, t.String()+": "+warning)
+	})
+	allocator.SetNaNKeyPolicy(NaNKeyPolicyDrop)
+	a.Equal(allocator.Clone(reflect.ValueOf(floats)).Interface(), map[float64]int{1: 2})
+	a.Equal(allocator.Clone(reflect.ValueOf(points)).Interface(), map[Point]string{{X: 1}: "one"})
+	a.Equal(allocator.Clone(reflect.ValueOf(ifaces)).Interface(), map[interface{}]int{"foo": 2})
+	a.Equal(allocator.Clone(reflect.ValueOf(complexes)).Interface(), map[complex128]int{1: 2})
+	a.Equal(len(warnings), 5)
+	a.Equal(warnings[0], "map[float64]int: entry with NaN key is dropped")
+
+	// Maps without NaN keys are not affected.
+	ints := map[int]int{1: 2}
+	a.Equal(allocator.Clone(reflect.ValueOf(ints)).Interface(), ints)
+	a.Equal(allocator.CloneMapKeys(reflect.ValueOf(floats)).Interface(), map[float64]int{1: 2})
+
+	// Maps with NaN keys are rejected.
+	allocator.SetNaNKeyPolicy(NaNKeyPolicyError)
+	_, err := allocator.TryClone(reflect.ValueOf(points))
+	a.Equal(err, &NaNKeyError{Type: reflect.TypeOf(points)})
+	a.Equal(err.Error(), "go-clone: map of type `map[clone.Point]string` contains NaN keys")
+
+	// Invalid policy resets the policy.
+	allocator.SetNaNKeyPolicy(0)
+	a.Equal(len(allocator.Clone(reflect.ValueOf(floats)).Interface().(map[float64]int)), 3)
+}
@@ -0,0 +1,272 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// clonePlan is a flat list of copy operations to clone values of a struct type.
+//
+// A plan is compiled once per struct type and config.
+// It's executed against the memory of a new value which is a shadow copy of the old value,
+// so that old pointers, slices and other references are read from the slots they are replaced in.
+// Fields of inline structs are flattened into the plan of the outer struct.
+type clonePlan struct {
+	ops []planOp
+}
+
+type planOpKind int
+
+const (
+	planOpZero        planOpKind = iota // Set the field to zero.
+	planOpPtrScalar                     // Copy the value pointed by a pointer to a new value.
+	planOpPtrStruct                     // Copy the struct pointed by a pointer to a new struct and execute its plan.
+	planOpSliceScalar                   // Copy elements of a slice to a new slice.
+	planOpSliceStruct                   // Copy struct elements of a slice to a new slice and execute their plan.
+	planOpValue                         // Clone the field by reflection.
+)
+
+// planSlice is the memory layout of a slice.
+// Unlike sliceHeader, the data is a pointer, so that it's safe to read and write it in place.
+type planSlice struct {
+	data unsafe.Pointer
+	len  int
+	cap  int
+}
+
+type planOp struct {
+	kind   planOpKind
+	offset uintptr      // The offset from the beginning of the struct.
+	size   uintptr      // The size of the field for planOpZero or the size of elem.
+	t      reflect.Type // The type of the field.
+	elem   reflect.Type // The elem type of a pointer or slice.
+}
+
+// compilePlan compiles a plan for struct type t analyzed as st.
+// It returns nil if t has any feature which must be handled by copyStruct,
+// e.g. custom funcs, guards or fields tagged with `clone:"deep"` or `clone:"parent"`.
+func (cfg *config) compilePlan(t reflect.Type, st *structType) *clonePlan {
+	if !st.plannable() {
+		return nil
+	}
+
+	plan := &clonePlan{}
+
+	if !cfg.appendPlanOps(plan, t, st, 0) {
+		return nil
+	}
+
+	return plan
+}
+
+// plannable returns true if st can be cloned by a plan.
+func (st *structType) plannable() bool {
+	return st.fn == nil && st.rebind == nil && st.Guard == nil && !st.TrackAncestors &&
+		len(st.ParentFields) == 0 && len(st.FuncFields) == 0 && len(st.GenerationFields) == 0 && len(st.InitMethods) == 0 &&
+		(len(st.ZeroFields) != 0 || len(st.PointerFields) != 0)
+}
+
+func (cfg *config) appendPlanOps(plan *clonePlan, t reflect.Type, st *structType, base uintptr) bool {
+	for _, zf := range st.ZeroFields {
+		plan.ops = append(plan.ops, planOp{
+			kind:   planOpZero,
+			offset: base + zf.Offset,
+			size:   zf.Size,
+		})
+	}
+
+	for _, pf := range st.PointerFields {
+		if pf.Deep || pf.Pointee != nil {
+			return false
+		}
+
+		ft := t.Field(pf.Index).Type
+		op := planOp{
+			kind:   planOpValue,
+			offset: base + pf.Offset,
+			t:      ft,
+		}
+
+		// Fields of types with custom funcs are cloned by reflection, which calls the funcs.
+		if cfg.lookupNamedFunc(ft) != nil {
+			plan.ops = append(plan.ops, op)
+			continue
+		}
+
+		switch ft.Kind() {
+		case reflect.Struct:
+			// Inline structs are cloned in place. Flatten their plans.
+			fst := cfg.loadStructType(ft)
+
+			if !fst.plannable() || !cfg.appendPlanOps(plan, ft, fst, op.offset) {
+				return false
+			}
+
+			continue
+		case reflect.Array:
+			// Arrays are cloned in place by copyArray, which cannot work on a shadow copy.
+			return false
+		case reflect.Ptr:
+			if cfg.isOpaquePointer(ft) {
+				continue
+			}
+
+			if elem := ft.Elem(); cfg.isScalarType(elem) {
+				op.kind = planOpPtrScalar
+				op.elem = elem
+				op.size = elem.Size()
+			} else if elem.Kind() == reflect.Struct {
+				op.kind = planOpPtrStruct
+				op.elem = elem
+				op.size = elem.Size()
+			}
+		case reflect.Slice:
+			if cfg.isAppendOnly(ft) {
+				break
+			}
+
+			if elem := ft.Elem(); cfg.isScalarType(elem) {
+				op.kind = planOpSliceScalar
+				op.elem = elem
+				op.size = elem.Size()
+			} else if elem.Kind() == reflect.Struct {
+				op.kind = planOpSliceStruct
+				op.elem = elem
+				op.size = elem.Size()
+			}
+		}
+
+		plan.ops = append(plan.ops, op)
+	}
+
+	return true
+}
+
+// usePlans returns true if plans can be used by state.
+// Plans don't count, mark or check cloned values.
+func (state *cloneState) usePlans() bool {
+	return state.visited == nil && state.stats == nil && state.yield == nil && state.trace == nil &&
+		state.maxDepth == 0 && state.nodeLimit == 0 &&
+		!state.strict && !state.debug && !state.canonical && !state.useCloner
+}
+
+// exec clones all values referenced by the struct at p in place.
+// The struct at p must be a shadow copy of the old value.
+func (plan *clonePlan) exec(state *cloneState, p unsafe.Pointer) {
+	for i := range plan.ops {
+		op := &plan.ops[i]
+		fp := unsafe.Pointer(uintptr(p) + op.offset)
+
+		switch op.kind {
+		case planOpZero:
+			zeroMemory(fp, op.size)
+		case planOpPtrScalar:
+			old := *(*unsafe.Pointer)(fp)
+
+			// The shadow copy of the pointer is shared if shallow references are shared.
+			if old == nil || state.shareRefs {
+				continue
+			}
+
+			nv := state.allocator.New(op.elem)
+			np := unsafe.Pointer(nv.Pointer())
+			copyMemory(np, old, op.size)
+			*(*unsafe.Pointer)(fp) = np
+		case planOpPtrStruct:
+			old := *(*unsafe.Pointer)(fp)
+
+			if old == nil {
+				continue
+			}
+
+			// The value is too deep to clone recursively. Clone it in the work stack.
+			if state.ptrDepth >= maxPtrDepth {
+				state.execValueOp(op, fp)
+				continue
+			}
+
+			st := state.config.loadStructType(op.elem)
+
+			if st.CanShadowCopy() && state.shareRefs {
+				continue
+			}
+
+			if !st.CanShadowCopy() && st.plan == nil {
+				state.execValueOp(op, fp)
+				continue
+			}
+
+			nv := state.allocator.New(op.elem)
+			np := unsafe.Pointer(nv.Pointer())
+			copyMemory(np, old, op.size)
+
+			if st.plan != nil {
+				state.ptrDepth++
+				st.plan.exec(state, np)
+				state.ptrDepth--
+			}
+
+			*(*unsafe.Pointer)(fp) = np
+		case planOpSliceScalar:
+			old := (*planSlice)(fp)
+
+			if old.data == nil || state.shareRefs {
+				continue
+			}
+
+			nv := state.allocator.MakeSlice(op.t, old.len, old.cap)
+			np := unsafe.Pointer(nv.Pointer())
+			copyMemory(np, old.data, uintptr(old.len)*op.size)
+			old.data = np
+		case planOpSliceStruct:
+			old := (*planSlice)(fp)
+
+			if old.data == nil {
+				continue
+			}
+
+			st := state.config.loadStructType(op.elem)
+
+			if st.CanShadowCopy() && state.shareRefs {
+				continue
+			}
+
+			if !st.CanShadowCopy() && st.plan == nil {
+				state.execValueOp(op, fp)
+				continue
+			}
+
+			nv := state.allocator.MakeSlice(op.t, old.len, old.cap)
+			np := unsafe.Pointer(nv.Pointer())
+			copyMemory(np, old.data, uintptr(old.len)*op.size)
+
+			if st.plan != nil {
+				for j := 0; j < old.len; j++ {
+					st.plan.exec(state, unsafe.Pointer(uintptr(np)+uintptr(j)*op.size))
+				}
+			}
+
+			old.data = np
+		default:
+			state.execValueOp(op, fp)
+		}
+	}
+}
+
+// execValueOp clones the field at fp by reflection.
+func (state *cloneState) execValueOp(op *planOp, fp unsafe.Pointer) {
+	field := reflect.NewAt(op.t, fp).Elem()
+	shadowCopy(state.clone(field), fp)
+}
+
+// copyMemory copies sz bytes from src to dst.
+func copyMemory(dst, src unsafe.Pointer, sz uintptr) {
+	if sz == 0 {
+		return
+	}
+
+	copy((*[maxByteSize]byte)(dst)[:sz:sz], (*[maxByteSize]byte)(src)[:sz:sz])
+}
@@ -0,0 +1,194 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+type planLeaf struct {
+	ID    int
+	Name  string
+	Score *float64
+	Tags  []int
+	Attrs map[string]string
+	cache []byte `clone:"skip"`
+}
+
+type planNode struct {
+	Leaf    planLeaf
+	Next    *planLeaf
+	Leaves  []planLeaf
+	Count   *int
+	Any     interface{}
+	Child   *planNode
+	Created time.Time
+	Updated *time.Time
+	Shared  *int `clone:"shadowcopy"`
+}
+
+type planGuarded struct {
+	mu    sync.Mutex
+	Items []int
+}
+
+type planTags []int
+
+type planNamed struct {
+	ID      int
+	Tags    planTags
+	TagsPtr *planTags
+}
+
+type planArray struct {
+	Ptrs [2]*int
+}
+
+func TestClonePlanCompile(t *testing.T) {
+	a := assert.New(t)
+	cfg := FromHeap().loadConfig()
+
+	a.Assert(cfg.loadStructType(reflect.TypeOf(planLeaf{})).plan != nil)
+	a.Assert(cfg.loadStructType(reflect.TypeOf(planNode{})).plan != nil)
+	a.Assert(cfg.loadStructType(reflect.TypeOf(planArray{})).plan == nil)
+
+	// Inline structs are flattened.
+	plan := cfg.loadStructType(reflect.TypeOf(planNode{})).plan
+	leaf := cfg.loadStructType(reflect.TypeOf(planLeaf{})).plan
+	a.Equal(plan.ops[:len(leaf.ops)], leaf.ops)
+
+	// Structs with custom funcs cannot be planned.
+	allocator := FromHeap()
+	allocator.SetCustomFunc(reflect.TypeOf(planLeaf{}), func(allocator *Allocator, old, new reflect.Value) {})
+	cfg = allocator.loadConfig()
+	a.Assert(cfg.loadStructType(reflect.TypeOf(planLeaf{})).plan == nil)
+	a.Assert(cfg.loadStructType(reflect.TypeOf(planNode{})).plan == nil)
+
+	// Fields with custom funcs of non-struct types are cloned by reflection in plans.
+	allocator = FromHeap()
+	allocator.SetCustomFunc(reflect.TypeOf(planTags{}), func(allocator *Allocator, old, new reflect.Value) {})
+	cfg = allocator.loadConfig()
+	plan = cfg.loadStructType(reflect.TypeOf(planNamed{})).plan
+	a.Assert(plan != nil)
+	a.Equal(len(plan.ops), 2)
+	a.Equal(plan.ops[0].kind, planOpValue)
+	a.Equal(plan.ops[1].kind, planOpValue)
+}
+
+func TestClonePlanNamedFunc(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.SetCustomFunc(reflect.TypeOf(planTags{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.Set(reflect.ValueOf(planTags{42}))
+	})
+	tags := planTags{2}
+	v := &planNamed{
+		Tags:    planTags{1},
+		TagsPtr: &tags,
+	}
+	cloned := allocator.Clone(reflect.ValueOf(v)).Interface().(*planNamed)
+	a.Equal(cloned.Tags, planTags{42})
+	a.Equal(*cloned.TagsPtr, planTags{42})
+	a.Assert(cloned.TagsPtr != v.TagsPtr)
+}
+
+func TestClonePlan(t *testing.T) {
+	a := assert.New(t)
+	score := 1.5
+	count := 3
+	shared := 4
+	now := time.Now()
+	v := &planNode{
+		Leaf: planLeaf{
+			ID:    1,
+			Name:  "leaf",
+			Score: &score,
+			Tags:  []int{1, 2, 3},
+			Attrs: map[string]string{"foo": "bar"},
+			cache: []byte("cache"),
+		},
+		Next: &planLeaf{
+			ID:   2,
+			Tags: make([]int, 1, 4),
+		},
+		Leaves: []planLeaf{
+			{ID: 3, Score: &score},
+			{ID: 4, Tags: []int{}},
+		},
+		Count: &count,
+		Any:   &planLeaf{ID: 5},
+		Child: &planNode{
+			Next: &planLeaf{ID: 6},
+		},
+		Created: now,
+		Updated: &now,
+		Shared:  &shared,
+	}
+	expected := *v
+	expected.Leaf.cache = nil
+
+	for _, allocator := range []*Allocator{FromHeap(), ReadOnlySource(nil), func() *Allocator {
+		// Plans are not used in strict mode.
+		allocator := FromHeap()
+		allocator.SetStrictMode(true)
+		return allocator
+	}()} {
+		cloned := allocator.Clone(reflect.ValueOf(v)).Interface().(*planNode)
+		a.Equal(*cloned, expected)
+
+		// Nothing is shared except fields tagged with `clone:"shadowcopy"`.
+		a.Assert(cloned.Leaf.Score != v.Leaf.Score)
+		a.Assert(&cloned.Leaf.Tags[0] != &v.Leaf.Tags[0])
+		a.Assert(reflect.ValueOf(cloned.Leaf.Attrs).Pointer() != reflect.ValueOf(v.Leaf.Attrs).Pointer())
+		a.Assert(cloned.Next != v.Next)
+		a.Equal(cap(cloned.Next.Tags), 4)
+		a.Assert(&cloned.Leaves[0] != &v.Leaves[0])
+		a.Assert(cloned.Leaves[0].Score != v.Leaves[0].Score)
+		a.Assert(cloned.Leaves[1].Tags != nil)
+		a.Assert(cloned.Count != v.Count)
+		a.Assert(cloned.Any.(*planLeaf) != v.Any.(*planLeaf))
+		a.Assert(cloned.Child.Next != v.Child.Next)
+		a.Assert(cloned.Updated != v.Updated)
+		a.Assert(cloned.Shared == v.Shared)
+	}
+
+	// Structs which cannot be planned are cloned by reflection.
+	g := &planGuarded{Items: []int{1}}
+	clonedGuarded := Clone(g).(*planGuarded)
+	a.Equal(clonedGuarded.Items, g.Items)
+	a.Assert(&clonedGuarded.Items[0] != &g.Items[0])
+}
+
+func TestClonePlanDeepList(t *testing.T) {
+	a := assert.New(t)
+	type list struct {
+		Value int
+		Next  *list
+	}
+	const n = 10000
+	var head *list
+
+	for i := 0; i < n; i++ {
+		head = &list{
+			Value: i,
+			Next:  head,
+		}
+	}
+
+	cloned := Clone(head).(*list)
+	count := 0
+
+	for p, q := cloned, head; p != nil; p, q = p.Next, q.Next {
+		a.Assert(p != q)
+		a.Equal(p.Value, q.Value)
+		count++
+	}
+
+	a.Equal(count, n)
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+)
+
+// pooledBuffer is a buffer which content can be read by Bytes and written by Write.
+type pooledBuffer interface {
+	Bytes() []byte
+	io.Writer
+}
+
+var (
+	typeOfBytesBuffer  = reflect.TypeOf(bytes.Buffer{})
+	typeOfPooledBuffer = reflect.TypeOf((*pooledBuffer)(nil)).Elem()
+)
+
+// MarkAsPooledBuffer marks t as a buffer type managed by a pool in heap allocator.
+//
+// See Allocator.MarkAsPooledBuffer for more details.
+func MarkAsPooledBuffer(t reflect.Type) {
+	defaultAllocator.MarkAsPooledBuffer(t)
+}
+
+// MarkAsPooledBuffer marks t as a buffer type managed by a pool, e.g. bytes.Buffer got from a sync.Pool.
+// The t can be bytes.Buffer, a struct type which pointer type has methods `Bytes() []byte`
+// and `Write(p []byte) (int, error)`, or pointer to them.
+// If t is neither of them, MarkAsPooledBuffer ignores t.
+//
+// A clone of a pooled buffer is a new buffer with a copy of the unread content in the buffer.
+// Nothing in the pool-managed buffer, e.g. the spare capacity of the backing array, is referenced by the clone,
+// so that it's safe to return the original buffer to its pool while the clone is in use.
+// The memory of a bytes.Buffer clone is allocated by a and has no spare capacity.
+// Other buffers are filled by their Write methods.
+//
+// MarkAsPooledBuffer sets a custom func for t.
+// Call SetCustomFunc with a nil func to remove the mark.
+func (a *Allocator) MarkAsPooledBuffer(t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == typeOfBytesBuffer {
+		a.SetCustomFunc(t, cloneBytesBuffer)
+		return
+	}
+
+	if t.Kind() == reflect.Struct && reflect.PtrTo(t).Implements(typeOfPooledBuffer) {
+		a.SetCustomFunc(t, clonePooledBuffer)
+	}
+}
+
+func cloneBytesBuffer(allocator *Allocator, old, new reflect.Value) {
+	content := old.Addr().Interface().(*bytes.Buffer).Bytes()
+	buf := allocator.MakeSlice(typeOfByteSlice, len(content), len(content)).Interface().([]byte)
+	copy(buf, content)
+	*new.Addr().Interface().(*bytes.Buffer) = *bytes.NewBuffer(buf)
+}
+
+func clonePooledBuffer(allocator *Allocator, old, new reflect.Value) {
+	content := old.Addr().Interface().(pooledBuffer).Bytes()
+
+	if len(content) == 0 {
+		return
+	}
+
+	new.Addr().Interface().(pooledBuffer).Write(content)
+}
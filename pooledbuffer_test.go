@@ -0,0 +1,70 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type pooledBufferData struct {
+	Buf    *bytes.Buffer
+	Inline bytes.Buffer
+}
+
+type pooledWriter struct {
+	data []byte
+}
+
+func (w *pooledWriter) Bytes() []byte {
+	return w.data
+}
+
+func (w *pooledWriter) Write(p []byte) (int, error) {
+	w.data = append(w.data, p...)
+	return len(p), nil
+}
+
+func TestMarkAsPooledBuffer(t *testing.T) {
+	a := assert.New(t)
+	allocator := NewAllocator(nil, nil)
+	allocator.MarkAsPooledBuffer(reflect.TypeOf(&bytes.Buffer{}))
+	allocator.MarkAsPooledBuffer(reflect.TypeOf(pooledWriter{}))
+	allocator.MarkAsPooledBuffer(reflect.TypeOf(strings.Builder{})) // Ignored.
+	allocator.MarkAsPooledBuffer(reflect.TypeOf(0))                 // Ignored.
+
+	buf := bytes.NewBuffer(make([]byte, 0, 1024))
+	buf.WriteString("hello, world")
+	buf.Next(7)
+	orig := &pooledBufferData{
+		Buf: buf,
+	}
+	orig.Inline.WriteString("inline")
+
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*pooledBufferData)
+	a.Equal(cloned.Buf.String(), "world")
+	a.Equal(cloned.Buf.Cap(), 5)
+	a.Equal(cloned.Inline.String(), "inline")
+
+	// Reusing the original buffer doesn't change the clone.
+	buf.Reset()
+	buf.WriteString("reused")
+	a.Equal(cloned.Buf.String(), "world")
+
+	w := &pooledWriter{data: make([]byte, 3, 100)}
+	copy(w.data, "abc")
+	clonedWriter := allocator.Clone(reflect.ValueOf([]*pooledWriter{w, {}})).Interface().([]*pooledWriter)
+	a.Equal(clonedWriter[0].data, []byte("abc"))
+	a.Assert(&clonedWriter[0].data[0] != &w.data[0])
+	a.Equal(clonedWriter[1].data, []byte(nil))
+
+	// Remove the mark.
+	allocator.SetCustomFunc(reflect.TypeOf(bytes.Buffer{}), nil)
+	cloned = allocator.Clone(reflect.ValueOf(orig)).Interface().(*pooledBufferData)
+	a.Equal(cloned.Buf.Cap(), buf.Cap())
+}
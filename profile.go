@@ -0,0 +1,342 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// The tag name of visibility values used by profiles.
+const visibilityTagName = "visibility"
+
+// Profile defines struct fields visible in a clone made by CloneForProfile.
+// A struct field is visible if its path is in Fields or any of its visibility tag values is in Tags.
+// All other struct fields are zeroed in the clone,
+// unless they lead to visible fields in depth.
+//
+// A path is written in Go selector syntax relative to the root value like `.User.Name`,
+// with all slice indexes, array indexes and map keys omitted,
+// e.g. `.Users.Name` matches the field Name in all elements of the slice Users.
+//
+// Visibility tag values are separated by comma, e.g. `visibility:"public,internal"`.
+type Profile struct {
+	Fields []string // Paths to visible fields.
+	Tags   []string // Visibility tag values of visible fields.
+}
+
+type profile struct {
+	fields   map[string]struct{}
+	prefixes map[string]struct{}
+	tags     map[string]struct{}
+}
+
+func newProfile(p Profile) *profile {
+	compiled := &profile{
+		fields:   make(map[string]struct{}, len(p.Fields)),
+		prefixes: map[string]struct{}{},
+		tags:     make(map[string]struct{}, len(p.Tags)),
+	}
+
+	for _, path := range p.Fields {
+		compiled.fields[path] = struct{}{}
+
+		for i := strings.LastIndexByte(path, '.'); i >= 0; i = strings.LastIndexByte(path, '.') {
+			path = path[:i]
+			compiled.prefixes[path] = struct{}{}
+		}
+	}
+
+	for _, tag := range p.Tags {
+		compiled.tags[tag] = struct{}{}
+	}
+
+	return compiled
+}
+
+// RegisterProfile registers a profile named name in heap allocator.
+//
+// See Allocator.RegisterProfile for more details.
+func RegisterProfile(name string, p Profile) {
+	defaultAllocator.RegisterProfile(name, p)
+}
+
+// CloneForProfile clones v in heap with fields invisible in the profile named name zeroed.
+//
+// See Allocator.CloneForProfile for more details.
+func CloneForProfile(v interface{}, name string) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	return defaultAllocator.CloneForProfile(reflect.ValueOf(v), name).Interface()
+}
+
+// RegisterProfile registers a profile named name in a.
+// If there is a profile with the same name, it's replaced.
+// Profiles registered in parent allocators are visible in a.
+func (a *Allocator) RegisterProfile(name string, p Profile) {
+	compiled := newProfile(p)
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		profiles := make(map[string]*profile, len(cfg.profiles)+1)
+
+		for k, v := range cfg.profiles {
+			profiles[k] = v
+		}
+
+		profiles[name] = compiled
+		copied.profiles = profiles
+		return copied
+	})
+}
+
+// CloneForProfile clones val with memory allocated from a
+// and zeroes all struct fields invisible in the profile named name.
+// It panics if the profile is not registered.
+//
+// CloneForProfile works in the same way as CloneSlowly.
+// It's designed to replace hand-written scrubbers which clear fields in clones,
+// e.g. to build a public view of an internal value.
+//
+// Values shared with val in the clone, e.g. opaque pointers, are never modified.
+// A pointer, slice or map reachable by several paths is filtered by the first path walked.
+func (a *Allocator) CloneForProfile(val reflect.Value, name string) reflect.Value {
+	if !val.IsValid() {
+		return val
+	}
+
+	cfg := a.loadConfig()
+	p := cfg.lookupProfile(name)
+
+	if p == nil {
+		panic(fmt.Errorf("go-clone: profile `%v` is not registered", name))
+	}
+
+	cloned := a.cloneSlowly(val, false)
+	root := reflect.New(cloned.Type()).Elem()
+	root.Set(cloned)
+
+	f := &profileFilter{
+		allocator: a,
+		config:    cfg,
+		profile:   p,
+		visited:   map[visit]reflect.Value{},
+	}
+	f.filter(root, "")
+	return root
+}
+
+func (cfg *config) lookupProfile(name string) *profile {
+	for current := cfg; current != nil; current = current.parent {
+		if p, ok := current.profiles[name]; ok {
+			return p
+		}
+	}
+
+	return nil
+}
+
+type profileFilter struct {
+	allocator *Allocator
+	config    *config
+	profile   *profile
+	visited   map[visit]reflect.Value
+}
+
+// filter zeroes invisible fields in v in place.
+// The v must be settable.
+// Pointers, slices and maps are copied before filtering,
+// as the values they point to may be shared with the original value.
+func (f *profileFilter) filter(v reflect.Value, path string) {
+	switch v.Kind() {
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			f.filter(v.Index(i), path)
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		f.filter(elem, path)
+		v.Set(elem)
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+
+		vst := visit{
+			p: v.Pointer(),
+			t: v.Type(),
+		}
+
+		if nv, ok := f.visited[vst]; ok {
+			v.Set(nv)
+			return
+		}
+
+		t := v.Type()
+		nv := f.allocator.MakeMap(t, v.Len())
+		f.visited[vst] = nv
+
+		for iter := mapIter(v); iter.Next(); {
+			elem := reflect.New(t.Elem()).Elem()
+			elem.Set(iter.Value())
+			f.filter(elem, path)
+			nv.SetMapIndex(iter.Key(), elem)
+		}
+
+		v.Set(nv)
+	case reflect.Ptr:
+		if v.IsNil() || f.config.isOpaquePointer(v.Type()) {
+			return
+		}
+
+		vst := visit{
+			p: v.Pointer(),
+			t: v.Type(),
+		}
+
+		if nv, ok := f.visited[vst]; ok {
+			v.Set(nv)
+			return
+		}
+
+		nv := f.allocator.New(v.Type().Elem())
+		nv.Elem().Set(v.Elem())
+		f.visited[vst] = nv
+		f.filter(nv.Elem(), path)
+		v.Set(nv)
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+
+		vst := visit{
+			p:     v.Pointer(),
+			extra: v.Len(),
+			t:     v.Type(),
+		}
+
+		if nv, ok := f.visited[vst]; ok {
+			v.Set(nv)
+			return
+		}
+
+		nv := f.allocator.MakeSlice(v.Type(), v.Len(), v.Cap())
+		reflect.Copy(nv, v)
+		f.visited[vst] = nv
+
+		for i := 0; i < nv.Len(); i++ {
+			f.filter(nv.Index(i), path)
+		}
+
+		v.Set(nv)
+	case reflect.Struct:
+		t := v.Type()
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			fieldPath := path + "." + field.Name
+
+			if f.isVisible(field, fieldPath) {
+				continue
+			}
+
+			fv := v.Field(i)
+
+			if !fv.CanSet() {
+				fv = reflect.NewAt(field.Type, unsafe.Pointer(fv.UnsafeAddr())).Elem()
+			}
+
+			if f.leadsToVisibleFields(field.Type, fieldPath) {
+				f.filter(fv, fieldPath)
+				continue
+			}
+
+			fv.Set(reflect.Zero(field.Type))
+		}
+	}
+}
+
+func (f *profileFilter) isVisible(field reflect.StructField, path string) bool {
+	if _, ok := f.profile.fields[path]; ok {
+		return true
+	}
+
+	if len(f.profile.tags) == 0 {
+		return false
+	}
+
+	tag, ok := field.Tag.Lookup(visibilityTagName)
+
+	if !ok {
+		return false
+	}
+
+	for _, value := range strings.Split(tag, ",") {
+		if _, ok := f.profile.tags[strings.TrimSpace(value)]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *profileFilter) leadsToVisibleFields(t reflect.Type, path string) bool {
+	if _, ok := f.profile.prefixes[path]; ok {
+		return true
+	}
+
+	return len(f.profile.tags) != 0 && f.config.hasVisibilityTag(t)
+}
+
+// hasVisibilityTag reports whether t contains any struct field with visibility tag in depth.
+// The result is cached in the type cache of cfg.
+func (cfg *config) hasVisibilityTag(t reflect.Type) bool {
+	cache := &cfg.cache().visibilityTags
+
+	if v, ok := cache.load(t); ok {
+		return v.(bool)
+	}
+
+	has := findVisibilityTag(t, map[reflect.Type]struct{}{})
+	cache.store(t, has)
+	return has
+}
+
+func findVisibilityTag(t reflect.Type, visiting map[reflect.Type]struct{}) bool {
+	if _, ok := visiting[t]; ok {
+		return false
+	}
+
+	visiting[t] = struct{}{}
+
+	switch t.Kind() {
+	case reflect.Array, reflect.Ptr, reflect.Slice:
+		return findVisibilityTag(t.Elem(), visiting)
+	case reflect.Map:
+		return findVisibilityTag(t.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+
+			if _, ok := field.Tag.Lookup(visibilityTagName); ok {
+				return true
+			}
+
+			if findVisibilityTag(field.Type, visiting) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
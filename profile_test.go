@@ -0,0 +1,131 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type profileAddress struct {
+	City   string `visibility:"public"`
+	Street string `visibility:"internal"`
+}
+
+type profileUser struct {
+	Name     string `visibility:"public, internal"`
+	Email    string `visibility:"internal"`
+	Password string
+	Address  *profileAddress
+	Friends  []*profileUser
+	Labels   map[string]profileAddress
+	secret   []int
+}
+
+func TestCloneForProfile(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.RegisterProfile("public", Profile{
+		Tags: []string{"public"},
+	})
+	allocator.RegisterProfile("internal", Profile{
+		Tags:   []string{"internal"},
+		Fields: []string{".secret", ".Friends.Password"},
+	})
+
+	orig := &profileUser{
+		Name:     "Alice",
+		Email:    "alice@example.com",
+		Password: "password",
+		Address: &profileAddress{
+			City:   "Paris",
+			Street: "Rue de Rivoli",
+		},
+		Labels: map[string]profileAddress{
+			"home": {City: "Lyon", Street: "Rue de la Republique"},
+		},
+		secret: []int{1, 2, 3},
+	}
+	orig.Friends = []*profileUser{orig}
+	cloneForProfile := func(name string) *profileUser {
+		return allocator.CloneForProfile(reflect.ValueOf(orig), name).Interface().(*profileUser)
+	}
+
+	public := cloneForProfile("public")
+	a.Equal(public.Name, "Alice")
+	a.Equal(public.Email, "")
+	a.Equal(public.Password, "")
+	a.Equal(public.Address, &profileAddress{City: "Paris"})
+	a.Equal(public.Labels, map[string]profileAddress{"home": {City: "Lyon"}})
+	a.Equal(public.secret, []int(nil))
+	a.Assert(public.Friends[0] == public)
+
+	internal := cloneForProfile("internal")
+	a.Equal(internal.Name, "Alice")
+	a.Equal(internal.Email, "alice@example.com")
+	a.Equal(internal.Password, "")
+	a.Equal(internal.Address, &profileAddress{Street: "Rue de Rivoli"})
+	a.Equal(internal.secret, []int{1, 2, 3})
+
+	// The Friends[0] is the root itself, which is filtered by the path `.` first.
+	a.Assert(internal.Friends[0] == internal)
+
+	// The original value is never modified.
+	a.Equal(orig.Password, "password")
+	a.Equal(orig.Address.City, "Paris")
+	a.Equal(orig.Labels["home"].Street, "Rue de la Republique")
+
+	// Child allocators can use profiles in parent.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	cloned := child.CloneForProfile(reflect.ValueOf(orig), "public").Interface().(*profileUser)
+	a.Equal(cloned.Email, "")
+
+	a.Assert(func() (ok bool) {
+		defer func() {
+			ok = recover() != nil
+		}()
+		child.CloneForProfile(reflect.ValueOf(orig), "not-registered")
+		return
+	}())
+}
+
+func TestCloneForProfileWithPaths(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.RegisterProfile("paths", Profile{
+		Fields: []string{".Name", ".Friends.Address.City"},
+	})
+
+	orig := &profileUser{
+		Name:     "Alice",
+		Password: "password",
+		Address: &profileAddress{
+			City: "Paris",
+		},
+		Friends: []*profileUser{
+			{
+				Name: "Bob",
+				Address: &profileAddress{
+					City:   "Berlin",
+					Street: "Unter den Linden",
+				},
+			},
+		},
+	}
+	cloned := allocator.CloneForProfile(reflect.ValueOf(orig), "paths").Interface().(*profileUser)
+	a.Equal(cloned, &profileUser{
+		Name: "Alice",
+		Friends: []*profileUser{
+			{
+				Address: &profileAddress{
+					City: "Berlin",
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"unsafe"
+
+	"github.com/huandu/go-clone/walk"
+)
+
+// ReadOnlySource returns a new allocator which clones values with memory allocated from a
+// and reports any modification of source values while cloning.
+// If a is nil, the new allocator allocates memory from heap.
+//
+// Cloning is supposed to be read-only on source values.
+// The new allocator takes a fingerprint of the whole source value before cloning,
+// and checks it again after cloning.
+// Every modified value is reported by the warning func set by SetWarningFunc,
+// e.g. a custom func writes to the old value by mistake.
+//
+// The fingerprint covers every value reachable from the source value, including map entries.
+// Taking fingerprints is expensive. It's designed for debugging and tests.
+func ReadOnlySource(a *Allocator) *Allocator {
+	allocator := NewAllocator(nil, &AllocatorMethods{
+		Parent: a,
+	})
+	allocator.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.readOnly = optionEnabled
+		return copied
+	})
+	return allocator
+}
+
+func (cfg *config) isReadOnlySource() bool {
+	for current := cfg; current != nil; current = current.parent {
+		switch current.readOnly {
+		case optionEnabled:
+			return true
+		case optionDisabled:
+			return false
+		}
+	}
+
+	return false
+}
+
+// location is the location of a value in memory.
+// A map is located by its pointer and type.
+type location struct {
+	p uintptr
+	t reflect.Type
+}
+
+type fingerprintEntry struct {
+	path string
+	t    reflect.Type
+	hash uint64
+}
+
+// fingerprint hashes the memory of all values reachable from v by their locations.
+// Structs and arrays are hashed field by field or element by element, so that padding bytes are ignored.
+func fingerprint(v reflect.Value) map[location]fingerprintEntry {
+	fp := map[location]fingerprintEntry{}
+
+	if !v.IsValid() {
+		return fp
+	}
+
+	walk.WalkValue(v, walk.VisitorFunc(func(path string, v reflect.Value) bool {
+		switch v.Kind() {
+		case reflect.Struct, reflect.Array:
+			return true
+		case reflect.Map:
+			if !v.IsNil() {
+				fp[location{p: v.Pointer(), t: v.Type()}] = fingerprintEntry{
+					path: path,
+					t:    v.Type(),
+					hash: hashMapEntries(v),
+				}
+			}
+		}
+
+		if v.CanAddr() {
+			fp[location{p: v.UnsafeAddr(), t: v.Type()}] = fingerprintEntry{
+				path: path,
+				t:    v.Type(),
+				hash: hashMemory(unsafe.Pointer(v.UnsafeAddr()), v.Type().Size()),
+			}
+		}
+
+		return true
+	}))
+	return fp
+}
+
+func hashMemory(p unsafe.Pointer, sz uintptr) uint64 {
+	h := fnv.New64a()
+
+	if sz != 0 {
+		h.Write((*[maxByteSize]byte)(p)[:sz:sz])
+	}
+
+	return h.Sum64()
+}
+
+// hashMapEntries hashes all entries in map m regardless of the order of entries.
+func hashMapEntries(m reflect.Value) uint64 {
+	t := m.Type()
+	key := reflect.New(t.Key()).Elem()
+	value := reflect.New(t.Elem()).Elem()
+	hash := uint64(m.Len())
+
+	for iter := mapIter(m); iter.Next(); {
+		key.Set(iter.Key())
+		value.Set(iter.Value())
+		hash ^= hashMemory(unsafe.Pointer(key.UnsafeAddr()), t.Key().Size())*31 +
+			hashMemory(unsafe.Pointer(value.UnsafeAddr()), t.Elem().Size())
+	}
+
+	return hash
+}
+
+// checkSource reports all values in v which are different from the fingerprint before.
+func (state *cloneState) checkSource(v reflect.Value, before map[location]fingerprintEntry) {
+	after := fingerprint(v)
+	var modified []fingerprintEntry
+
+	for loc, entry := range before {
+		if current, ok := after[loc]; ok && current.hash != entry.hash {
+			modified = append(modified, entry)
+		}
+	}
+
+	sort.Slice(modified, func(i, j int) bool {
+		return modified[i].path < modified[j].path
+	})
+
+	for _, entry := range modified {
+		state.warn(entry.t, fmt.Sprintf("source value is modified while cloning at path `%v`", entry.path))
+	}
+}
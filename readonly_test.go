@@ -0,0 +1,61 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type readOnlyData struct {
+	Name   string
+	Values []int
+	Attrs  map[string]int
+	inner  *readOnlyInner
+}
+
+type readOnlyInner struct {
+	count int
+}
+
+func TestReadOnlySource(t *testing.T) {
+	a := assert.New(t)
+	var warnings []string
+	allocator := ReadOnlySource(nil)
+	allocator.SetWarningFunc(func(t reflect.Type, warning string) {
+		warnings = append(warnings, t.String()+": "+warning)
+	})
+
+	orig := []*readOnlyData{{
+		Name:   "foo",
+		Values: []int{1, 2},
+		Attrs:  map[string]int{"a": 1},
+		inner:  &readOnlyInner{count: 1},
+	}}
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().([]*readOnlyData)
+	a.Equal(cloned, orig)
+	a.Equal(len(warnings), 0)
+
+	// A custom func modifying old values by mistake.
+	allocator.SetCustomFunc(reflect.TypeOf(readOnlyData{}), func(allocator *Allocator, old, new reflect.Value) {
+		data := old.Addr().Interface().(*readOnlyData)
+		data.Values[1] = 3
+		data.Attrs["b"] = 2
+		data.inner.count++
+		new.Set(old)
+	})
+	allocator.Clone(reflect.ValueOf(orig))
+	a.Equal(warnings, []string{
+		"map[string]int: source value is modified while cloning at path `[0].Attrs`",
+		"int: source value is modified while cloning at path `[0].Values[1]`",
+		"int: source value is modified while cloning at path `[0].inner.count`",
+	})
+
+	// Parent allocator is not affected.
+	parent := FromHeap()
+	a.Assert(ReadOnlySource(parent).loadConfig().isReadOnlySource())
+	a.Assert(!parent.loadConfig().isReadOnlySource())
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+)
+
+// SetReadOnlyMemory enables or disables read-only memory mode in heap allocator.
+//
+// See Allocator.SetReadOnlyMemory for more details.
+func SetReadOnlyMemory(readOnly bool) {
+	defaultAllocator.SetReadOnlyMemory(readOnly)
+}
+
+// SetReadOnlyMemory enables or disables read-only memory mode in a.
+// If the mode is not set, a inherits it from parent allocator.
+// The mode is disabled in the default allocator.
+//
+// In read-only memory mode, source values may live in memory which faults on any write,
+// e.g. structs and byte slices pointing to a file mapped by mmap with PROT_READ.
+// Clone methods guarantee that no memory in source values is written or passed to other code as an addressable value.
+//
+//   - Locks set by MarkAsGuardedBy are not held, as values in read-only memory cannot be modified.
+//   - Custom funcs are called with an addressable copy of the old value instead of the old value itself,
+//     so that custom funcs, e.g. the built-in funcs for sync.Map and atomic.Value, work on the copy.
+//   - Trace hooks and cloner methods of non-struct types are called with a copy of the value as well.
+//
+// Values referenced by pointers, slices or maps inside the old value are not copied for custom funcs.
+// Custom funcs and cloner methods of pointer types must not write to them.
+func (a *Allocator) SetReadOnlyMemory(readOnly bool) {
+	option := optionDisabled
+
+	if readOnly {
+		option = optionEnabled
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.readOnlyMem = option
+		return copied
+	})
+}
+
+func (cfg *config) isReadOnlyMemory() bool {
+	for current := cfg; current != nil; current = current.parent {
+		switch current.readOnlyMem {
+		case optionEnabled:
+			return true
+		case optionDisabled:
+			return false
+		}
+	}
+
+	return false
+}
+
+// detachValue returns an addressable shadow copy of v in heap,
+// so that v's memory is never written through the copy.
+func detachValue(v reflect.Value) reflect.Value {
+	if !v.CanInterface() {
+		v = forceClearROFlag(v)
+	}
+
+	tmp := reflect.New(v.Type()).Elem()
+	tmp.Set(v)
+	return tmp
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build (linux || darwin) && !race
+// +build linux darwin
+// +build !race
+
+package clone
+
+import (
+	"os"
+	"reflect"
+	"runtime/debug"
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+// TestReadOnlyMemoryFault makes sure that the mapping in tests is read-only.
+// Faults in the race detector runtime cannot be recovered, so it's skipped with -race.
+func TestReadOnlyMemoryFault(t *testing.T) {
+	a := assert.New(t)
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+
+	data, unmap := mapReadOnly(t, make([]byte, os.Getpagesize()))
+	defer unmap()
+	v := (*readOnlyRecord)(unsafe.Pointer(&data[0]))
+	allocator := FromHeap()
+	allocator.MarkAsGuardedBy(reflect.TypeOf(readOnlyRecord{}), "mu")
+
+	// Writes to read-only memory fault.
+	func() {
+		defer func() {
+			a.Assert(recover() != nil)
+		}()
+
+		allocator.Clone(reflect.ValueOf(v))
+	}()
+
+	allocator.SetReadOnlyMemory(true)
+	cloned := allocator.Clone(reflect.ValueOf(v)).Interface().(*readOnlyRecord)
+	a.Assert(cloned != v)
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build linux || darwin
+// +build linux darwin
+
+package clone
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"runtime/debug"
+	"sync"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+type readOnlyRecord struct {
+	mu     sync.Mutex
+	ID     int64
+	Score  float64
+	Counts sync.Map
+}
+
+type readOnlyCustom struct {
+	N int
+}
+
+type readOnlyIDs []int64
+
+func (ids readOnlyIDs) Clone() readOnlyIDs {
+	cloned := make(readOnlyIDs, 0, len(ids))
+
+	for _, id := range ids {
+		cloned = append(cloned, id*10)
+	}
+
+	return cloned
+}
+
+type readOnlyTraced struct {
+	IDs readOnlyIDs
+}
+
+type readOnlyHolder struct {
+	Record  *readOnlyRecord
+	Custom  *readOnlyCustom
+	Payload []byte
+}
+
+// mapReadOnly maps a read-only copy of src.
+// Call unmap to release the mapping.
+func mapReadOnly(t *testing.T, src []byte) (data []byte, unmap func()) {
+	f, err := ioutil.TempFile("", "clone-readonly")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if _, err := f.Write(src); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err = syscall.Mmap(int(f.Fd()), 0, len(src), syscall.PROT_READ, syscall.MAP_SHARED)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data, func() {
+		syscall.Munmap(data)
+	}
+}
+
+func TestReadOnlyMemory(t *testing.T) {
+	a := assert.New(t)
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+
+	const recordOffset = 0
+	const customOffset = 256
+	const payloadOffset = 512
+	buf := make([]byte, os.Getpagesize())
+	*(*readOnlyRecord)(unsafe.Pointer(&buf[recordOffset])) = readOnlyRecord{
+		ID:    1,
+		Score: 2.5,
+	}
+	*(*readOnlyCustom)(unsafe.Pointer(&buf[customOffset])) = readOnlyCustom{
+		N: 3,
+	}
+	copy(buf[payloadOffset:], "payload")
+	data, unmap := mapReadOnly(t, buf)
+	defer unmap()
+
+	v := &readOnlyHolder{
+		Record:  (*readOnlyRecord)(unsafe.Pointer(&data[recordOffset])),
+		Custom:  (*readOnlyCustom)(unsafe.Pointer(&data[customOffset])),
+		Payload: data[payloadOffset : payloadOffset+len("payload")],
+	}
+
+	parent := FromHeap()
+	parent.MarkAsGuardedBy(reflect.TypeOf(readOnlyRecord{}), "mu")
+	parent.SetCustomFunc(reflect.TypeOf(readOnlyCustom{}), func(allocator *Allocator, old, new reflect.Value) {
+		// A careless custom func writing to old value.
+		old.Field(0).SetInt(old.Field(0).Int() + 1)
+		new.Set(old)
+	})
+
+	parent.SetReadOnlyMemory(true)
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: parent,
+	})
+
+	for _, allocator := range []*Allocator{parent, child, ReadOnlySource(parent)} {
+		for _, cloned := range []*readOnlyHolder{
+			allocator.Clone(reflect.ValueOf(v)).Interface().(*readOnlyHolder),
+			allocator.CloneSlowly(reflect.ValueOf(v)).Interface().(*readOnlyHolder),
+		} {
+			a.Assert(cloned.Record != v.Record)
+			a.Equal(cloned.Record.ID, int64(1))
+			a.Equal(cloned.Record.Score, 2.5)
+			a.Equal(cloned.Custom.N, 4)
+			a.Equal(string(cloned.Payload), "payload")
+			a.Assert(&cloned.Payload[0] != &v.Payload[0])
+		}
+	}
+
+	// Clones are writable.
+	cloned := child.Clone(reflect.ValueOf(v)).Interface().(*readOnlyHolder)
+	cloned.Record.mu.Lock()
+	cloned.Record.ID = 10
+	cloned.Record.mu.Unlock()
+	cloned.Payload[0] = 'P'
+	a.Equal(v.Record.ID, int64(1))
+	a.Equal(v.Custom.N, 3)
+	a.Equal(string(v.Payload), "payload")
+}
+
+func TestReadOnlyMemoryHooks(t *testing.T) {
+	a := assert.New(t)
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+
+	const idsOffset = 256
+	buf := make([]byte, os.Getpagesize())
+	*(*[2]int64)(unsafe.Pointer(&buf[idsOffset])) = [2]int64{2, 3}
+	data, unmap := mapReadOnly(t, buf)
+	defer unmap()
+
+	// Headers of the struct and the slice are in read-only memory as well.
+	*(*readOnlyTraced)(unsafe.Pointer(&buf[0])) = readOnlyTraced{
+		IDs: (*[2]int64)(unsafe.Pointer(&data[idsOffset]))[:],
+	}
+	data, unmap = mapReadOnly(t, buf)
+	defer unmap()
+	v := (*readOnlyTraced)(unsafe.Pointer(&data[0]))
+
+	allocator := FromHeap()
+	allocator.UseClonerInterface(true)
+	allocator.SetTraceHooks(&TraceHooks{
+		OnEnter: func(path string, v reflect.Value) {
+			// A careless hook writing to values being cloned.
+			if v.Type() == reflect.TypeOf(readOnlyIDs{}) && v.CanSet() {
+				v.SetLen(1)
+			}
+		},
+	})
+	allocator.SetReadOnlyMemory(true)
+
+	cloned := allocator.Clone(reflect.ValueOf(v)).Interface().(*readOnlyTraced)
+	a.Assert(cloned != v)
+	a.Equal(cloned.IDs, readOnlyIDs{20, 30})
+	a.Equal(len(v.IDs), 2)
+}
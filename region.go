@@ -0,0 +1,254 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// DefaultRegionChunkSize is the default size of chunks in a Region.
+const DefaultRegionChunkSize = 64 << 10
+
+// DefaultRegionHeadroom is the default headroom in percentage of a Region sized by NewRegionFor.
+const DefaultRegionHeadroom = 10
+
+// Region is a pure Go bump allocator which allocates memory from pre-sized chunks.
+// It doesn't require GOEXPERIMENT=arenas, so it works with stock toolchains.
+//
+// As memory in chunks is not scanned by GC, only pointer-free types,
+// e.g. numbers, and arrays and structs of numbers, are allocated in a Region.
+// Values of other types, maps and chans are allocated in heap and reported by ReportFallback.
+// Call FromRegion to create an allocator using a Region.
+//
+// All methods of Region are safe for concurrent use.
+type Region struct {
+	mu        sync.Mutex
+	chunkSize int
+	chunks    [][]uint64 // Chunks of chunkSize bytes. Chunks after current are reused after Reset.
+	large     [][]uint64 // Chunks allocated for values larger than chunkSize.
+	current   int        // Index of current chunk.
+	offset    int        // Bytes used in current chunk.
+	allocated int
+}
+
+// NewRegion creates a Region with chunks of chunkSize bytes.
+// If chunkSize is not positive, DefaultRegionChunkSize is used.
+func NewRegion(chunkSize int) *Region {
+	if chunkSize <= 0 {
+		chunkSize = DefaultRegionChunkSize
+	}
+
+	// Round up to multiple of 8 so that chunks are fully used by []uint64.
+	chunkSize = (chunkSize + 7) &^ 7
+	return &Region{
+		chunkSize: chunkSize,
+	}
+}
+
+// NewRegionFor creates a Region which first chunk is sized to fit a clone of v,
+// so that the clone is allocated in one chunk without chaining chunks.
+//
+// The chunk size is the size estimated by EstimateSize plus headroom in percentage of the estimate,
+// e.g. a headroom of 10 makes a chunk 10% larger than the estimate.
+// The headroom covers memory which is not estimated, e.g. memory allocated by custom funcs.
+// If headroom is negative, DefaultRegionHeadroom is used.
+// If v is nil, the Region is the same as NewRegion(0).
+//
+// The estimate follows registrations in heap allocator, as allocators created by FromRegion do.
+func NewRegionFor(v interface{}, headroom int) *Region {
+	if headroom < 0 {
+		headroom = DefaultRegionHeadroom
+	}
+
+	size := EstimateSize(v)
+	r := NewRegion(size + size*headroom/100)
+
+	if size > 0 {
+		r.chunks = append(r.chunks, make([]uint64, r.chunkSize/8))
+	}
+
+	return r
+}
+
+// FromRegion creates an allocator using Region r to allocate memory.
+//
+// Calling Reset on the allocator zeros all chunks in r for reuse,
+// and calling Release drops all chunks.
+// Values allocated in r must not be used after Reset or Release.
+func FromRegion(r *Region) *Allocator {
+	var allocator *Allocator
+	reportFallback := func(t reflect.Type, reason string) {
+		// The allocator itself is allocated in heap before it's created.
+		if allocator != nil {
+			allocator.ReportFallback(t, reason)
+		}
+	}
+	pointerFree := func(t reflect.Type) bool {
+		if allocator == nil {
+			return isPointerFree(t)
+		}
+
+		return allocator.loadConfig().isPointerFree(t)
+	}
+	methods := &AllocatorMethods{
+		New: func(pool unsafe.Pointer, t reflect.Type) reflect.Value {
+			if !pointerFree(t) {
+				reportFallback(t, "region cannot allocate pointers")
+				return heapNew(pool, t)
+			}
+
+			return reflect.NewAt(t, (*Region)(pool).alloc(t.Size(), t.Align()))
+		},
+		MakeSlice: func(pool unsafe.Pointer, t reflect.Type, len, cap int) reflect.Value {
+			elem := t.Elem()
+
+			if !pointerFree(elem) {
+				reportFallback(t, "region cannot allocate pointers")
+				return heapMakeSlice(pool, t, len, cap)
+			}
+
+			p := (*Region)(pool).alloc(elem.Size()*uintptr(cap), elem.Align())
+			return reflect.NewAt(reflect.ArrayOf(cap, elem), p).Elem().Slice3(0, len, cap).Convert(t)
+		},
+		MakeMap: func(pool unsafe.Pointer, t reflect.Type, n int) reflect.Value {
+			reportFallback(t, "region cannot allocate map")
+			return heapMakeMap(pool, t, n)
+		},
+		MakeChan: func(pool unsafe.Pointer, t reflect.Type, buffer int) reflect.Value {
+			reportFallback(t, "region cannot allocate chan")
+			return heapMakeChan(pool, t, buffer)
+		},
+		Reset: func(pool unsafe.Pointer) {
+			(*Region)(pool).Reset()
+		},
+		Release: func(pool unsafe.Pointer) {
+			(*Region)(pool).Release()
+		},
+	}
+	allocator = NewAllocator(unsafe.Pointer(r), methods)
+	return allocator
+}
+
+// Allocated returns the number of bytes allocated in r since r is created or reset,
+// including padding for alignment.
+func (r *Region) Allocated() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.allocated
+}
+
+// Reset zeros all chunks in r for reuse.
+// Chunks allocated for values larger than chunk size are dropped.
+func (r *Region) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := 0; i <= r.current && i < len(r.chunks); i++ {
+		chunk := r.chunks[i]
+
+		for j := range chunk {
+			chunk[j] = 0
+		}
+	}
+
+	r.large = nil
+	r.current = 0
+	r.offset = 0
+	r.allocated = 0
+}
+
+// Release drops all chunks in r.
+func (r *Region) Release() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.chunks = nil
+	r.large = nil
+	r.current = 0
+	r.offset = 0
+	r.allocated = 0
+}
+
+// alloc allocates size bytes aligned to align in r.
+// All bytes are zero.
+func (r *Region) alloc(size uintptr, align int) unsafe.Pointer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := int(size)
+
+	if n == 0 {
+		n = 1
+	}
+
+	if n > r.chunkSize {
+		chunk := make([]uint64, (n+7)/8)
+		r.large = append(r.large, chunk)
+		r.allocated += n
+		return unsafe.Pointer(&chunk[0])
+	}
+
+	offset := (r.offset + align - 1) &^ (align - 1)
+
+	if len(r.chunks) == 0 || offset+n > r.chunkSize {
+		if len(r.chunks) != 0 {
+			r.allocated += r.chunkSize - r.offset
+			r.current++
+		}
+
+		if r.current == len(r.chunks) {
+			r.chunks = append(r.chunks, make([]uint64, r.chunkSize/8))
+		}
+
+		r.offset = 0
+		offset = 0
+	}
+
+	r.allocated += offset + n - r.offset
+	r.offset = offset + n
+	return unsafe.Pointer(uintptr(unsafe.Pointer(&r.chunks[r.current][0])) + uintptr(offset))
+}
+
+// isPointerFree returns true if values of t doesn't contain any pointer.
+// The result is cached in the type cache of cfg.
+func (cfg *config) isPointerFree(t reflect.Type) bool {
+	cache := &cfg.cache().pointerFree
+
+	if v, ok := cache.load(t); ok {
+		return v.(bool)
+	}
+
+	free := isPointerFree(t)
+	cache.store(t, free)
+	return free
+}
+
+func isPointerFree(t reflect.Type) bool {
+	free := false
+
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Complex64, reflect.Complex128:
+		free = true
+	case reflect.Array:
+		free = t.Len() == 0 || isPointerFree(t.Elem())
+	case reflect.Struct:
+		free = true
+
+		for i := 0; i < t.NumField(); i++ {
+			if !isPointerFree(t.Field(i).Type) {
+				free = false
+				break
+			}
+		}
+	}
+
+	return free
+}
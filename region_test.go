@@ -0,0 +1,152 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestRegion(t *testing.T) {
+	a := assert.New(t)
+	type Point struct {
+		X, Y float64
+		Tag  int8
+	}
+	type T struct {
+		Point  *Point
+		Values []int32
+		Grid   *[4][4]uint16
+		Names  []*string
+		Attrs  map[string]int
+	}
+	name := "foo"
+	orig := &T{
+		Point:  &Point{X: 1, Y: 2, Tag: 3},
+		Values: []int32{1, 2, 3},
+		Grid:   &[4][4]uint16{{1}, {2}},
+		Names:  []*string{&name},
+		Attrs:  map[string]int{"a": 1},
+	}
+
+	r := NewRegion(64)
+	allocator := FromRegion(r)
+	var fallbacks []string
+	allocator.SetFallbackFunc(func(t reflect.Type, reason string) {
+		fallbacks = append(fallbacks, t.String()+": "+reason)
+	})
+
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*T)
+	runtime.GC()
+	a.Equal(cloned, orig)
+	a.Assert(cloned.Point != orig.Point)
+	a.Assert(cloned.Grid != orig.Grid)
+	a.Assert(&cloned.Values[0] != &orig.Values[0])
+	a.Assert(r.Allocated() >= int(unsafe.Sizeof(Point{})+unsafe.Sizeof([4][4]uint16{})+4*3))
+	a.Equal(fallbacks, []string{
+		"clone.T: region cannot allocate pointers",
+		"[]*string: region cannot allocate pointers",
+		"string: region cannot allocate pointers",
+		"map[string]int: region cannot allocate map",
+	})
+
+	// Pointer-free values are allocated in region with proper alignment.
+	for _, v := range []reflect.Value{
+		reflect.ValueOf(cloned.Point),
+		reflect.ValueOf(cloned.Grid),
+		reflect.ValueOf(&cloned.Values[0]),
+	} {
+		a.Equal(v.Pointer()%uintptr(v.Type().Elem().Align()), uintptr(0))
+		a.Assert(r.contains(unsafe.Pointer(v.Pointer())))
+	}
+
+	// Values larger than chunk size are allocated in dedicated chunks.
+	large := make([]int64, 100)
+	large[99] = 1
+	clonedLarge := allocator.Clone(reflect.ValueOf(large)).Interface().([]int64)
+	a.Equal(clonedLarge, large)
+
+	// Reset zeros memory for reuse.
+	allocator.Reset()
+	a.Equal(r.Allocated(), 0)
+	p := allocator.New(reflect.TypeOf(Point{})).Interface().(*Point)
+	a.Equal(*p, Point{})
+
+	allocator.Release()
+	a.Equal(len(r.chunks), 0)
+}
+
+func TestNewRegionFor(t *testing.T) {
+	a := assert.New(t)
+	type T struct {
+		Values []int32
+		Matrix *[32][32]float64
+		Points [][2]int
+	}
+	orig := &T{
+		Values: make([]int32, 1000),
+		Matrix: &[32][32]float64{},
+		Points: make([][2]int, 100, 200),
+	}
+
+	// The whole clone fits in the first chunk.
+	r := NewRegionFor(orig, 0)
+	a.Equal(len(r.chunks), 1)
+	a.Equal(r.chunkSize, (EstimateSize(orig)+7)&^7)
+
+	allocator := FromRegion(r)
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*T)
+	a.Equal(cloned, orig)
+	a.Equal(len(r.chunks), 1)
+	a.Equal(r.current, 0)
+	a.Equal(len(r.large), 0)
+	a.Assert(r.contains(unsafe.Pointer(cloned.Matrix)))
+
+	// Headroom enlarges the chunk.
+	size := EstimateSize(orig)
+	a.Equal(NewRegionFor(orig, 50).chunkSize, (size+size*50/100+7)&^7)
+	a.Equal(NewRegionFor(orig, -1).chunkSize, (size+size*DefaultRegionHeadroom/100+7)&^7)
+
+	// Nothing to estimate.
+	r = NewRegionFor(nil, 0)
+	a.Equal(r.chunkSize, DefaultRegionChunkSize)
+	a.Equal(len(r.chunks), 0)
+}
+
+func (r *Region) contains(p unsafe.Pointer) bool {
+	for _, chunk := range r.chunks {
+		start := uintptr(unsafe.Pointer(&chunk[0]))
+
+		if uintptr(p) >= start && uintptr(p) < start+uintptr(r.chunkSize) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestIsPointerFree(t *testing.T) {
+	a := assert.New(t)
+	type S struct {
+		A int
+		B [2]float32
+	}
+	type P struct {
+		A int
+		B *int
+	}
+
+	a.Assert(isPointerFree(reflect.TypeOf(0)))
+	a.Assert(isPointerFree(reflect.TypeOf(S{})))
+	a.Assert(isPointerFree(reflect.TypeOf([0]*int{})))
+	a.Assert(!isPointerFree(reflect.TypeOf("")))
+	a.Assert(!isPointerFree(reflect.TypeOf(P{})))
+	a.Assert(!isPointerFree(reflect.TypeOf([1]P{})))
+	a.Assert(!isPointerFree(reflect.TypeOf([]int{})))
+	a.Assert(!isPointerFree(reflect.TypeOf(func() {})))
+}
@@ -0,0 +1,351 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"sync"
+)
+
+// RegistrationScope is a group of registrations made by Register.
+// Call Close to undo all of them.
+type RegistrationScope struct {
+	allocator *Allocator
+	before    *config
+	after     *config
+	once      sync.Once
+}
+
+// Register calls fn to make registrations in heap allocator in a scope.
+//
+// See Allocator.Register for more details.
+func Register(fn func(a *Allocator)) *RegistrationScope {
+	return defaultAllocator.Register(fn)
+}
+
+// Register calls fn with a to make registrations, e.g. MarkAsScalar or SetCustomFunc,
+// and returns a scope to undo all registrations made by fn.
+//
+// When the scope is closed, all types, shapes, families and profiles registered by fn are restored
+// to the state right before Register is called,
+// and so are all allocator-wide options set by fn, e.g. strict mode.
+// Registrations made outside fn are kept.
+// It's designed for tests and plugins which customize an allocator temporarily,
+// e.g. `t.Cleanup(clone.Register(fn).Close)`.
+//
+// Scopes must be closed in the reverse order of creation.
+// Register must not be called concurrently with other registrations in a.
+func (a *Allocator) Register(fn func(a *Allocator)) *RegistrationScope {
+	before := a.loadConfig()
+	fn(a)
+	after := a.loadConfig()
+
+	// The a shared its config with a frozen parent and fn derived a new one from it.
+	if after.parent == before {
+		before = newConfig(before, a.isScalar)
+	}
+
+	return &RegistrationScope{
+		allocator: a,
+		before:    before,
+		after:     after,
+	}
+}
+
+// Close undoes all registrations in the scope.
+// It's safe to call Close more than once.
+func (scope *RegistrationScope) Close() {
+	scope.once.Do(func() {
+		scope.allocator.updateConfig(func(cfg *config) *config {
+			return cfg.revert(scope.before, scope.after)
+		})
+	})
+}
+
+// revert returns a copy of cfg with all changes from before to after reverted.
+func (cfg *config) revert(before, after *config) *config {
+	copied := cfg.copy()
+	types := make(map[reflect.Type]*typeConfig, len(cfg.types))
+
+	for t, tc := range cfg.types {
+		types[t] = tc
+	}
+
+	for t, tc := range after.types {
+		if before.types[t] != tc {
+			revertTypeConfig(types, t, before.types[t])
+		}
+	}
+
+	for t := range before.types {
+		if _, ok := after.types[t]; !ok {
+			revertTypeConfig(types, t, before.types[t])
+		}
+	}
+
+	profiles := make(map[string]*profile, len(cfg.profiles))
+
+	for name, p := range cfg.profiles {
+		profiles[name] = p
+	}
+
+	for name, p := range after.profiles {
+		if before.profiles[name] != p {
+			revertProfile(profiles, name, before.profiles[name])
+		}
+	}
+
+	for name := range before.profiles {
+		if _, ok := after.profiles[name]; !ok {
+			revertProfile(profiles, name, before.profiles[name])
+		}
+	}
+
+	shapes := make(map[string]*shapeFunc, len(cfg.shapes))
+
+	for shape, sf := range cfg.shapes {
+		shapes[shape] = sf
+	}
+
+	for shape, sf := range after.shapes {
+		if before.shapes[shape] != sf {
+			revertShapeFunc(shapes, shape, before.shapes)
+		}
+	}
+
+	for shape := range before.shapes {
+		if _, ok := after.shapes[shape]; !ok {
+			revertShapeFunc(shapes, shape, before.shapes)
+		}
+	}
+
+	families := make(map[string]*familyFunc, len(cfg.families))
+
+	for family, ff := range cfg.families {
+		families[family] = ff
+	}
+
+	for family, ff := range after.families {
+		if before.families[family] != ff {
+			revertFamilyFunc(families, family, before.families)
+		}
+	}
+
+	for family := range before.families {
+		if _, ok := after.families[family]; !ok {
+			revertFamilyFunc(families, family, before.families)
+		}
+	}
+
+	tagFuncs := make(map[string]*tagFunc, len(cfg.tagFuncs))
+
+	for name, tf := range cfg.tagFuncs {
+		tagFuncs[name] = tf
+	}
+
+	for name, tf := range after.tagFuncs {
+		if before.tagFuncs[name] != tf {
+			revertTagFunc(tagFuncs, name, before.tagFuncs)
+		}
+	}
+
+	for name := range before.tagFuncs {
+		if _, ok := after.tagFuncs[name]; !ok {
+			revertTagFunc(tagFuncs, name, before.tagFuncs)
+		}
+	}
+
+	tagHandlers := make(map[string]*tagHandler, len(cfg.tagHandlers))
+
+	for name, th := range cfg.tagHandlers {
+		tagHandlers[name] = th
+	}
+
+	for name, th := range after.tagHandlers {
+		if before.tagHandlers[name] != th {
+			revertTagHandler(tagHandlers, name, before.tagHandlers)
+		}
+	}
+
+	for name := range before.tagHandlers {
+		if _, ok := after.tagHandlers[name]; !ok {
+			revertTagHandler(tagHandlers, name, before.tagHandlers)
+		}
+	}
+
+	copied.types = types
+	copied.profiles = profiles
+	copied.shapes = shapes
+	copied.families = families
+	copied.tagFuncs = tagFuncs
+	copied.tagHandlers = tagHandlers
+
+	if before.strictMode != after.strictMode {
+		copied.strictMode = before.strictMode
+	}
+
+	if before.debugMode != after.debugMode {
+		copied.debugMode = before.debugMode
+	}
+
+	if before.readOnly != after.readOnly {
+		copied.readOnly = before.readOnly
+	}
+
+	if before.transactional != after.transactional {
+		copied.transactional = before.transactional
+	}
+
+	if before.canonical != after.canonical {
+		copied.canonical = before.canonical
+	}
+
+	if before.minCopy != after.minCopy {
+		copied.minCopy = before.minCopy
+	}
+
+	if before.shareRefs != after.shareRefs {
+		copied.shareRefs = before.shareRefs
+	}
+
+	if before.readOnlyMem != after.readOnlyMem {
+		copied.readOnlyMem = before.readOnlyMem
+	}
+
+	if before.useCloner != after.useCloner {
+		copied.useCloner = before.useCloner
+	}
+
+	if before.yield != after.yield {
+		copied.yield = before.yield
+	}
+
+	if before.maxDepth != after.maxDepth {
+		copied.maxDepth = before.maxDepth
+	}
+
+	if before.nodeLimit != after.nodeLimit {
+		copied.nodeLimit = before.nodeLimit
+	}
+
+	if before.funcStub != after.funcStub {
+		copied.funcStub = before.funcStub
+	}
+
+	if before.labels != after.labels {
+		copied.labels = before.labels
+	}
+
+	if before.traceHooks != after.traceHooks {
+		copied.traceHooks = before.traceHooks
+	}
+
+	if before.cachePolicy != after.cachePolicy {
+		copied.cachePolicy = before.cachePolicy
+	}
+
+	if before.cacheSize != after.cacheSize {
+		copied.cacheSize = before.cacheSize
+	}
+
+	if before.fallback != after.fallback {
+		copied.fallback = before.fallback
+	}
+
+	if before.warning != after.warning {
+		copied.warning = before.warning
+	}
+
+	if before.generation != after.generation {
+		copied.generation = before.generation
+	}
+
+	if before.precedence != after.precedence {
+		copied.precedence = before.precedence
+	}
+
+	if before.chanPolicy != after.chanPolicy {
+		copied.chanPolicy = before.chanPolicy
+	}
+
+	if before.nanKeyPolicy != after.nanKeyPolicy {
+		copied.nanKeyPolicy = before.nanKeyPolicy
+	}
+
+	if before.sharedPackages != after.sharedPackages {
+		copied.sharedPackages = before.sharedPackages
+	}
+
+	return copied
+}
+
+func revertTypeConfig(types map[reflect.Type]*typeConfig, t reflect.Type, tc *typeConfig) {
+	if tc == nil {
+		delete(types, t)
+		return
+	}
+
+	types[t] = tc
+}
+
+func revertProfile(profiles map[string]*profile, name string, p *profile) {
+	if p == nil {
+		delete(profiles, name)
+		return
+	}
+
+	profiles[name] = p
+}
+
+// revertShapeFunc restores the shape func in before.
+// A nil shape func in before removes the shape func in parents, so it's restored as well.
+func revertShapeFunc(shapes map[string]*shapeFunc, shape string, before map[string]*shapeFunc) {
+	sf, ok := before[shape]
+
+	if !ok {
+		delete(shapes, shape)
+		return
+	}
+
+	shapes[shape] = sf
+}
+
+// revertFamilyFunc restores the family func in before.
+// A nil family func in before removes the family func in parents, so it's restored as well.
+func revertFamilyFunc(families map[string]*familyFunc, family string, before map[string]*familyFunc) {
+	ff, ok := before[family]
+
+	if !ok {
+		delete(families, family)
+		return
+	}
+
+	families[family] = ff
+}
+
+// revertTagFunc restores the func registered with name in before.
+// A nil func in before removes the func in parents, so it's restored as well.
+func revertTagFunc(tagFuncs map[string]*tagFunc, name string, before map[string]*tagFunc) {
+	tf, ok := before[name]
+
+	if !ok {
+		delete(tagFuncs, name)
+		return
+	}
+
+	tagFuncs[name] = tf
+}
+
+// revertTagHandler restores the handler registered with name in before.
+// A nil handler in before removes the handler in parents, so it's restored as well.
+func revertTagHandler(tagHandlers map[string]*tagHandler, name string, before map[string]*tagHandler) {
+	th, ok := before[name]
+
+	if !ok {
+		delete(tagHandlers, name)
+		return
+	}
+
+	tagHandlers[name] = th
+}
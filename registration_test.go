@@ -0,0 +1,121 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type registrationData struct {
+	P *int
+}
+
+func TestUnmark(t *testing.T) {
+	a := assert.New(t)
+	typeOfData := reflect.TypeOf(registrationData{})
+	typeOfPtr := reflect.TypeOf(&registrationData{})
+
+	parent := FromHeap()
+	parent.MarkAsScalar(typeOfData)
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: parent,
+	})
+	child.MarkAsOpaquePointer(typeOfPtr)
+	a.Assert(child.IsMarkedAsScalar(typeOfData))
+	a.Assert(child.IsOpaquePointer(typeOfPtr))
+
+	// Marks in parent are not affected.
+	child.UnmarkAsScalar(typeOfPtr)
+	child.UnmarkAsOpaquePointer(typeOfPtr)
+	a.Assert(child.IsMarkedAsScalar(typeOfData))
+	a.Assert(!child.IsOpaquePointer(typeOfPtr))
+
+	parent.UnmarkAsScalar(typeOfData)
+	a.Assert(!child.IsMarkedAsScalar(typeOfData))
+
+	n := 1
+	orig := []registrationData{{P: &n}}
+	cloned := child.Clone(reflect.ValueOf(orig)).Interface().([]registrationData)
+	a.Assert(cloned[0].P != orig[0].P)
+}
+
+func TestRegister(t *testing.T) {
+	a := assert.New(t)
+	typeOfData := reflect.TypeOf(registrationData{})
+	typeOfPtr := reflect.TypeOf(&registrationData{})
+	typeOfOther := reflect.TypeOf(conflictData{})
+
+	allocator := FromHeap()
+	allocator.MarkAsOpaquePointer(typeOfPtr)
+	allocator.RegisterProfile("kept", Profile{})
+
+	scope := allocator.Register(func(allocator *Allocator) {
+		allocator.MarkAsScalar(typeOfData)
+		allocator.UnmarkAsOpaquePointer(typeOfPtr)
+		allocator.RegisterProfile("scoped", Profile{})
+		allocator.SetStrictMode(true)
+		allocator.SetPrecedence(PrecedenceCustomFunc)
+		allocator.SetFallbackFunc(func(t reflect.Type, reason string) {})
+	})
+
+	// Registrations outside the scope are kept after Close.
+	allocator.MarkAsScalar(typeOfOther)
+
+	cfg := allocator.loadConfig()
+	a.Assert(allocator.IsMarkedAsScalar(typeOfData))
+	a.Assert(!allocator.IsOpaquePointer(typeOfPtr))
+	a.Assert(cfg.lookupProfile("scoped") != nil)
+	a.Assert(cfg.isStrictMode())
+	a.Equal(cfg.lookupPrecedence(), PrecedenceCustomFunc)
+	a.Assert(cfg.lookupFallback() != nil)
+
+	scope.Close()
+	scope.Close()
+	cfg = allocator.loadConfig()
+	a.Assert(!allocator.IsMarkedAsScalar(typeOfData))
+	a.Assert(allocator.IsOpaquePointer(typeOfPtr))
+	a.Assert(allocator.IsMarkedAsScalar(typeOfOther))
+	a.Assert(cfg.lookupProfile("scoped") == nil)
+	a.Assert(cfg.lookupProfile("kept") != nil)
+	a.Assert(!cfg.isStrictMode())
+	a.Equal(cfg.lookupPrecedence(), PrecedenceScalar)
+	a.Assert(cfg.lookupFallback() == nil)
+
+	// Nested scopes.
+	outer := allocator.Register(func(allocator *Allocator) {
+		allocator.MarkAsScalar(typeOfData)
+	})
+	inner := allocator.Register(func(allocator *Allocator) {
+		allocator.UnmarkAsScalar(typeOfData)
+	})
+	a.Assert(!allocator.IsMarkedAsScalar(typeOfData))
+	inner.Close()
+	a.Assert(allocator.IsMarkedAsScalar(typeOfData))
+	outer.Close()
+	a.Assert(!allocator.IsMarkedAsScalar(typeOfData))
+}
+
+func TestRegisterWithFrozenParent(t *testing.T) {
+	a := assert.New(t)
+	typeOfData := reflect.TypeOf(registrationData{})
+	typeOfOther := reflect.TypeOf(conflictData{})
+
+	parent := FromHeap()
+	parent.MarkAsScalar(typeOfData)
+	parent.Freeze()
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: parent,
+	})
+
+	scope := child.Register(func(allocator *Allocator) {
+		allocator.MarkAsScalar(typeOfOther)
+	})
+	a.Assert(child.IsMarkedAsScalar(typeOfOther))
+	scope.Close()
+	a.Assert(!child.IsMarkedAsScalar(typeOfOther))
+	a.Assert(child.IsMarkedAsScalar(typeOfData))
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build go1.19
+// +build go1.19
+
+package clone
+
+import (
+	"sync/atomic"
+)
+
+// Replace replaces the value stored in target with a mutated deep clone of it
+// in a read-copy-update way, and returns the new value stored in target.
+//
+// Replace loads the current value in target, clones it in heap, calls mutate with the clone
+// and stores the clone by CompareAndSwap.
+// If target is changed by others in the meantime, Replace starts over with the latest value,
+// so that mutate can be called more than once and must not have side effects other than modifying the clone.
+// If target stores nil, mutate is called with a new zero value.
+//
+// Readers loading target never see a partially mutated value.
+// Values loaded from target must not be modified in place, as they are shared by readers.
+// If mutate panics, target is not changed.
+func Replace[T any](target *atomic.Pointer[T], mutate func(*T)) *T {
+	for {
+		old := target.Load()
+		var nv *T
+
+		switch {
+		case old == nil:
+			nv = new(T)
+		case canShadowCopy[T]():
+			nv = new(T)
+			*nv = *old
+		default:
+			nv = cloneNew(old)
+		}
+
+		mutate(nv)
+
+		if target.CompareAndSwap(old, nv) {
+			return nv
+		}
+	}
+}
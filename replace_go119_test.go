@@ -0,0 +1,108 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build go1.19
+// +build go1.19
+
+package clone
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type replaceConfig struct {
+	Version int
+	Routes  map[string]string
+	Hosts   []string
+}
+
+func TestReplace(t *testing.T) {
+	a := assert.New(t)
+	var target atomic.Pointer[replaceConfig]
+
+	// A nil value is replaced by a zero value.
+	first := Replace(&target, func(c *replaceConfig) {
+		c.Routes = map[string]string{}
+	})
+	a.Assert(target.Load() == first)
+	a.Equal(first.Version, 0)
+
+	const workers = 8
+	const updates = 100
+	var wg sync.WaitGroup
+	wg.Add(workers * 2)
+
+	for i := 0; i < workers; i++ {
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < updates; j++ {
+				key := strconv.Itoa(i) + "/" + strconv.Itoa(j)
+				Replace(&target, func(c *replaceConfig) {
+					c.Version++
+					c.Routes[key] = key
+					c.Hosts = append(c.Hosts, key)
+				})
+			}
+		}(i)
+
+		// Readers never see partially mutated values.
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < updates; j++ {
+				c := target.Load()
+
+				if len(c.Routes) != c.Version || len(c.Hosts) != c.Version {
+					t.Errorf("inconsistent value: version=%v routes=%v hosts=%v", c.Version, len(c.Routes), len(c.Hosts))
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	last := target.Load()
+	a.Equal(last.Version, workers*updates)
+	a.Equal(len(last.Routes), workers*updates)
+	a.Equal(len(last.Hosts), workers*updates)
+
+	// Old values are not modified.
+	a.Equal(first.Version, 0)
+	a.Equal(len(first.Routes), 0)
+
+	// Target is not changed if mutate panics.
+	func() {
+		defer func() {
+			a.Assert(recover() != nil)
+		}()
+
+		Replace(&target, func(c *replaceConfig) {
+			c.Version = -1
+			panic("abort")
+		})
+	}()
+	a.Assert(target.Load() == last)
+	a.Equal(last.Version, workers*updates)
+}
+
+func TestReplaceScalar(t *testing.T) {
+	a := assert.New(t)
+	type point struct {
+		X, Y int
+	}
+	var target atomic.Pointer[point]
+	old := &point{X: 1}
+	target.Store(old)
+
+	nv := Replace(&target, func(p *point) {
+		p.Y = 2
+	})
+	a.Equal(*nv, point{X: 1, Y: 2})
+	a.Equal(*old, point{X: 1})
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Report is the footprint of a clone broken down by types.
+type Report struct {
+	Stats
+
+	// Types is the footprint of every type of objects allocated by allocator methods,
+	// sorted by bytes and then objects in descending order.
+	// The type of a value created by New is the type of the value rather than the pointer type.
+	Types []TypeStats
+}
+
+// TypeStats is the footprint of objects of a type in a clone.
+type TypeStats struct {
+	Type    reflect.Type // The type of objects, e.g. a struct type, a slice type or a map type.
+	Objects int          // The number of objects allocated.
+	Bytes   int          // The number of bytes allocated. It's estimated in the same way as Stats.Bytes.
+}
+
+// CloneWithReport clones v in heap like Clone and returns the report of the clone.
+//
+// It's designed to find out why a clone is expensive, e.g. to compare snapshots of a value.
+// It's more expensive than CloneWithStats, as every allocation is counted by its type.
+func CloneWithReport(v interface{}) (interface{}, Report) {
+	return cloneWithReport(defaultAllocator, v, false)
+}
+
+// SlowlyWithReport clones v in heap like Slowly and returns the report of the clone.
+func SlowlyWithReport(v interface{}) (interface{}, Report) {
+	return cloneWithReport(defaultAllocator, v, true)
+}
+
+func cloneWithReport(allocator *Allocator, v interface{}, slowly bool) (interface{}, Report) {
+	if v == nil {
+		return nil, Report{}
+	}
+
+	cloned, report := allocator.cloneWithReport(reflect.ValueOf(v), slowly, false)
+	return cloned.Interface(), report
+}
+
+// CloneWithReport works in the same way as Clone and returns the report of the clone.
+// All memory allocated by a in the clone is counted, including memory allocated in custom funcs.
+func (a *Allocator) CloneWithReport(val reflect.Value) (reflect.Value, Report) {
+	return a.cloneWithReport(val, false, true)
+}
+
+// CloneSlowlyWithReport works in the same way as CloneSlowly and returns the report of the clone.
+func (a *Allocator) CloneSlowlyWithReport(val reflect.Value) (reflect.Value, Report) {
+	return a.cloneWithReport(val, true, true)
+}
+
+func (a *Allocator) cloneWithReport(val reflect.Value, slowly, inCustomFunc bool) (cloned reflect.Value, report Report) {
+	types := map[reflect.Type]*TypeStats{}
+	cloned, report.Stats = a.cloneWithTypeStats(val, types, slowly, inCustomFunc)
+
+	if len(types) == 0 {
+		return
+	}
+
+	report.Types = make([]TypeStats, 0, len(types))
+
+	for _, ts := range types {
+		report.Types = append(report.Types, *ts)
+	}
+
+	sort.Slice(report.Types, func(i, j int) bool {
+		ti, tj := &report.Types[i], &report.Types[j]
+
+		if ti.Bytes != tj.Bytes {
+			return ti.Bytes > tj.Bytes
+		}
+
+		if ti.Objects != tj.Objects {
+			return ti.Objects > tj.Objects
+		}
+
+		return ti.Type.String() < tj.Type.String()
+	})
+	return
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type reportRecord struct {
+	Nodes []*statsNode
+	Index map[string]int
+}
+
+func TestCloneWithReport(t *testing.T) {
+	a := assert.New(t)
+
+	cloned, report := CloneWithReport(nil)
+	a.Equal(cloned, nil)
+	a.Equal(report, Report{})
+
+	orig := &reportRecord{
+		Nodes: []*statsNode{
+			{Value: 1},
+			{Value: 2, Children: []*statsNode{{Value: 3}}},
+		},
+		Index: map[string]int{"a": 1},
+	}
+	cloned, report = CloneWithReport(orig)
+	a.Equal(cloned, orig)
+
+	sizeOfNode := int(reflect.TypeOf(statsNode{}).Size())
+	sizeOfRecord := int(reflect.TypeOf(reportRecord{}).Size())
+	sizeOfPtr := int(reflect.TypeOf(orig).Size())
+	sizeOfEntry := int(reflect.TypeOf("").Size() + reflect.TypeOf(0).Size())
+
+	// Objects: 1 record, 3 nodes, 2 slices and 1 map.
+	a.Equal(report.Objects, 7)
+	a.Equal(report.Bytes, sizeOfRecord+3*sizeOfNode+3*sizeOfPtr+sizeOfEntry)
+	a.Equal(report.Types, []TypeStats{
+		{Type: reflect.TypeOf(statsNode{}), Objects: 3, Bytes: 3 * sizeOfNode},
+		{Type: reflect.TypeOf(reportRecord{}), Objects: 1, Bytes: sizeOfRecord},
+		{Type: reflect.TypeOf([]*statsNode{}), Objects: 2, Bytes: 3 * sizeOfPtr},
+		{Type: reflect.TypeOf(map[string]int{}), Objects: 1, Bytes: sizeOfEntry},
+	})
+
+	orig.Nodes[1].Children[0] = orig.Nodes[1]
+	cloned, report = SlowlyWithReport(orig)
+	a.Assert(cloned.(*reportRecord).Nodes[1].Children[0] == cloned.(*reportRecord).Nodes[1])
+	a.Equal(report.Types[0], TypeStats{Type: reflect.TypeOf(statsNode{}), Objects: 2, Bytes: 2 * sizeOfNode})
+
+	allocator := FromHeap()
+	val, report := allocator.CloneWithReport(reflect.ValueOf([]int{1, 2}))
+	a.Equal(val.Interface(), []int{1, 2})
+	a.Equal(report.Types, []TypeStats{{Type: reflect.TypeOf([]int{}), Objects: 1, Bytes: 2 * int(reflect.TypeOf(0).Size())}})
+
+	val, report = allocator.CloneSlowlyWithReport(reflect.ValueOf(123))
+	a.Equal(val.Interface(), 123)
+	a.Equal(report.Types, []TypeStats(nil))
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// SandboxLevel is how a value is isolated from the original value by Sandbox.
+type SandboxLevel int
+
+// All sandbox levels.
+const (
+	SandboxShared   SandboxLevel = iota + 1 // Share the value without any copy.
+	SandboxShield                           // Wrap the value by Wrap, so that changes on the value are not visible to the original value.
+	SandboxIsolate                          // Deep clone the value by Clone.
+	SandboxHardened                         // Deep clone the value by Slowly with redacted fields, funcs and chans dropped.
+)
+
+// SandboxProfile is the name of the profile to redact values in SandboxHardened level.
+// Register it by RegisterProfile to make fields visible in hardened clones.
+const SandboxProfile = "sandbox"
+
+var sandboxLevelNames = [...]string{
+	SandboxShared:   "shared",
+	SandboxShield:   "shield",
+	SandboxIsolate:  "isolate",
+	SandboxHardened: "hardened",
+}
+
+// String returns the name of level.
+func (level SandboxLevel) String() string {
+	if level > 0 && int(level) < len(sandboxLevelNames) {
+		return sandboxLevelNames[level]
+	}
+
+	return fmt.Sprintf("SandboxLevel(%d)", int(level))
+}
+
+var (
+	hardenedOnce      sync.Once
+	hardenedAllocator *Allocator
+)
+
+// Sandbox returns a value isolated from v in level, e.g. to hand v to a plugin.
+// It panics if level is not a valid level.
+//
+// Levels are designed to express the intent of host applications
+// instead of composing options one by one.
+//
+//   - SandboxShared returns v as it is.
+//   - SandboxShield returns Wrap(v), so that v can be restored by Unwrap or Undo.
+//     If v is not a pointer, v is returned as it is.
+//   - SandboxIsolate returns Clone(v).
+//   - SandboxHardened returns a clone of v made by Slowly, in which every func and chan is nil.
+//     If a profile named SandboxProfile is registered by RegisterProfile,
+//     all fields invisible in the profile are zeroed as CloneForProfile does.
+//
+// All levels respect registrations in heap allocator, e.g. custom funcs and scalar marks.
+func Sandbox(v interface{}, level SandboxLevel) interface{} {
+	switch level {
+	case SandboxShared:
+		return v
+	case SandboxShield:
+		return Wrap(v)
+	case SandboxIsolate:
+		return Clone(v)
+	case SandboxHardened:
+		if v == nil {
+			return nil
+		}
+
+		return sandboxHardened(reflect.ValueOf(v)).Interface()
+	}
+
+	panic(fmt.Errorf("go-clone: invalid sandbox level `%v`", level))
+}
+
+func sandboxHardened(val reflect.Value) reflect.Value {
+	hardenedOnce.Do(func() {
+		// The allocator follows all registrations in heap allocator.
+		hardenedAllocator = NewAllocator(nil, &AllocatorMethods{
+			Parent: defaultAllocator,
+		})
+		hardenedAllocator.SetChanPolicy(ChanNil)
+		hardenedAllocator.SetFuncStubFactory(func(t reflect.Type) reflect.Value {
+			return reflect.Value{}
+		})
+	})
+
+	if hardenedAllocator.loadConfig().lookupProfile(SandboxProfile) == nil {
+		return hardenedAllocator.cloneSlowly(val, false)
+	}
+
+	return hardenedAllocator.CloneForProfile(val, SandboxProfile)
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type sandboxPlugin struct {
+	Name     string
+	Token    string
+	Hosts    []string
+	Events   chan string
+	OnChange func()
+}
+
+func TestSandbox(t *testing.T) {
+	a := assert.New(t)
+	v := &sandboxPlugin{
+		Name:     "plugin",
+		Token:    "secret",
+		Hosts:    []string{"localhost"},
+		Events:   make(chan string),
+		OnChange: func() {},
+	}
+
+	a.Assert(Sandbox(v, SandboxShared).(*sandboxPlugin) == v)
+	a.Equal(Sandbox(nil, SandboxIsolate), nil)
+	a.Equal(Sandbox(nil, SandboxHardened), nil)
+
+	shield := Sandbox(v, SandboxShield).(*sandboxPlugin)
+	shield.Name = "changed"
+	a.Equal(v.Name, "plugin")
+	a.Assert(Unwrap(shield).(*sandboxPlugin) == v)
+
+	isolated := Sandbox(v, SandboxIsolate).(*sandboxPlugin)
+	a.Assert(isolated != v)
+	a.Assert(&isolated.Hosts[0] != &v.Hosts[0])
+	a.Assert(isolated.Events != nil && isolated.Events != v.Events)
+	a.Assert(isolated.OnChange != nil)
+
+	hardened := Sandbox(v, SandboxHardened).(*sandboxPlugin)
+	a.Assert(hardened != v)
+	a.Equal(hardened.Token, "secret")
+	a.Assert(&hardened.Hosts[0] != &v.Hosts[0])
+	a.Assert(hardened.Events == nil)
+	a.Assert(hardened.OnChange == nil)
+
+	// Fields invisible in the sandbox profile are redacted.
+	scope := Register(func(a *Allocator) {
+		a.RegisterProfile(SandboxProfile, Profile{
+			Fields: []string{".Name", ".Hosts", ".Events"},
+		})
+	})
+	defer scope.Close()
+
+	hardened = Sandbox(v, SandboxHardened).(*sandboxPlugin)
+	a.Equal(hardened.Name, "plugin")
+	a.Equal(hardened.Token, "")
+	a.Equal(hardened.Hosts, v.Hosts)
+	a.Assert(hardened.Events == nil)
+
+	a.Equal(SandboxHardened.String(), "hardened")
+	a.Equal(SandboxLevel(0).String(), "SandboxLevel(0)")
+
+	defer func() {
+		a.Assert(recover() != nil)
+	}()
+	Sandbox(v, 0)
+}
+
+type sandboxSecret struct {
+	Key string
+}
+
+func TestSandboxCustomFuncOfRoot(t *testing.T) {
+	a := assert.New(t)
+	scope := Register(func(a *Allocator) {
+		a.SetCustomFunc(reflect.TypeOf(sandboxSecret{}), func(allocator *Allocator, old, new reflect.Value) {
+			new.FieldByName("Key").SetString("redacted")
+		})
+	})
+	defer scope.Close()
+
+	// Custom funcs of root types apply to values passed by value.
+	v := sandboxSecret{
+		Key: "secret",
+	}
+	a.Equal(Sandbox(v, SandboxIsolate), sandboxSecret{Key: "redacted"})
+	a.Equal(Sandbox(v, SandboxHardened), sandboxSecret{Key: "redacted"})
+
+	profile := Register(func(a *Allocator) {
+		a.RegisterProfile(SandboxProfile, Profile{
+			Fields: []string{".Key"},
+		})
+	})
+	defer profile.Close()
+
+	a.Equal(Sandbox(v, SandboxHardened), sandboxSecret{Key: "redacted"})
+}
@@ -0,0 +1,311 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+var (
+	typeOfDocument     = reflect.TypeOf(map[string]interface{}(nil))
+	typeOfDocumentList = reflect.TypeOf([]interface{}(nil))
+)
+
+// Schema is the schema of dynamic documents of type map[string]interface{},
+// e.g. documents decoded from JSON.
+type Schema struct {
+	Fields map[string]SchemaField // Fields by keys in documents.
+	Strict bool                   // Reject keys not in Fields if true. Otherwise, such keys are cloned as they are.
+}
+
+// SchemaField is the schema of a value in documents.
+type SchemaField struct {
+	// Type is the expected type of the value or nil if the value can be any type.
+	// See CloneWithSchema for rules to coerce values.
+	Type reflect.Type
+
+	// Required is true if the key must exist in documents.
+	Required bool
+
+	// Schema is the schema of the value if the value is a nested document
+	// or a []interface{} of nested documents.
+	Schema *Schema
+}
+
+// SchemaError is the error of a value which doesn't match the schema in CloneWithSchema.
+type SchemaError struct {
+	Path   string       // The path to the value written like `["foo"][2]["bar"]`.
+	Type   reflect.Type // The expected type or nil if the value is not expected to exist.
+	Value  interface{}  // The value. It's nil if a required key is missing.
+	Reason string       // The reason why the value doesn't match.
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("go-clone: value at `%v` doesn't match schema: %v", e.Path, e.Reason)
+}
+
+// CloneWithSchema clones doc in heap like Clone and validates it against schema in the same pass.
+//
+// See Allocator.CloneWithSchema for more details.
+func CloneWithSchema(doc map[string]interface{}, schema *Schema) (map[string]interface{}, error) {
+	return defaultAllocator.CloneWithSchema(doc, schema)
+}
+
+// CloneWithSchema clones doc like Clone and validates every value against schema in the same pass,
+// so that it's not necessary to walk doc again to validate it.
+// It returns a *SchemaError on the first mismatch. If schema is nil, doc is cloned as it is.
+//
+// A value is coerced to the expected type in SchemaField if it's not the expected type.
+//
+//   - Numbers are converted to other number types, e.g. float64 to int, if no precision is lost.
+//   - Strings are parsed as numbers or bools by strconv, e.g. "42" to int.
+//   - Strings and named string types are converted to each other.
+//   - Values implementing an expected interface type are accepted as they are.
+//
+// Other values, e.g. 3.5 for int or "abc" for bool, are rejected.
+// A nil value is rejected unless the expected type is nil or nilable.
+func (a *Allocator) CloneWithSchema(doc map[string]interface{}, schema *Schema) (map[string]interface{}, error) {
+	if doc == nil {
+		return nil, nil
+	}
+
+	if schema == nil {
+		return a.clone(reflect.ValueOf(doc), false).Interface().(map[string]interface{}), nil
+	}
+
+	cloned, err := a.cloneDocument(reflect.ValueOf(doc), schema, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cloned.Interface().(map[string]interface{}), nil
+}
+
+func (a *Allocator) cloneDocument(doc reflect.Value, schema *Schema, path string) (reflect.Value, error) {
+	cloned := a.MakeMap(typeOfDocument, doc.Len())
+
+	for iter := doc.MapRange(); iter.Next(); {
+		key := iter.Key()
+		p := path + "[" + strconv.Quote(key.String()) + "]"
+		field, ok := schema.Fields[key.String()]
+
+		if !ok && schema.Strict {
+			return reflect.Value{}, &SchemaError{
+				Path:   p,
+				Value:  iter.Value().Interface(),
+				Reason: "unknown key",
+			}
+		}
+
+		v, err := a.cloneField(iter.Value().Elem(), &field, p)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if v.IsValid() {
+			cloned.SetMapIndex(key, v)
+		} else {
+			cloned.SetMapIndex(key, reflect.Zero(typeOfDocument.Elem()))
+		}
+	}
+
+	for key, field := range schema.Fields {
+		if !field.Required || doc.MapIndex(reflect.ValueOf(key)).IsValid() {
+			continue
+		}
+
+		return reflect.Value{}, &SchemaError{
+			Path:   path + "[" + strconv.Quote(key) + "]",
+			Type:   field.Type,
+			Reason: "required key is missing",
+		}
+	}
+
+	return cloned, nil
+}
+
+// cloneField clones v which is the dynamic value in a document.
+// The v is invalid if the value is nil.
+func (a *Allocator) cloneField(v reflect.Value, field *SchemaField, path string) (reflect.Value, error) {
+	if t := field.Type; t != nil {
+		coerced, ok := coerceValue(v, t)
+
+		if !ok {
+			var value interface{}
+			reason := fmt.Sprintf("nil cannot be coerced to `%v`", t)
+
+			if v.IsValid() {
+				value = v.Interface()
+				reason = fmt.Sprintf("value of type `%v` cannot be coerced to `%v`", v.Type(), t)
+			}
+
+			return reflect.Value{}, &SchemaError{
+				Path:   path,
+				Type:   t,
+				Value:  value,
+				Reason: reason,
+			}
+		}
+
+		// Coerced values are new values. It's not necessary to clone them.
+		if coerced.IsValid() && (!v.IsValid() || coerced.Type() != v.Type()) {
+			return coerced, nil
+		}
+	}
+
+	if !v.IsValid() {
+		return v, nil
+	}
+
+	if schema := field.Schema; schema != nil {
+		switch v.Type() {
+		case typeOfDocument:
+			if v.IsNil() {
+				return v, nil
+			}
+
+			return a.cloneDocument(v, schema, path)
+		case typeOfDocumentList:
+			if v.IsNil() {
+				return v, nil
+			}
+
+			list := a.MakeSlice(typeOfDocumentList, v.Len(), v.Len())
+
+			for i := 0; i < v.Len(); i++ {
+				elem := v.Index(i).Elem()
+				p := path + "[" + strconv.Itoa(i) + "]"
+
+				if elem.Type() != typeOfDocument {
+					return reflect.Value{}, &SchemaError{
+						Path:   p,
+						Value:  elem.Interface(),
+						Reason: fmt.Sprintf("value of type `%v` is not a document", elem.Type()),
+					}
+				}
+
+				cloned, err := a.cloneDocument(elem, schema, p)
+
+				if err != nil {
+					return reflect.Value{}, err
+				}
+
+				list.Index(i).Set(cloned)
+			}
+
+			return list, nil
+		}
+
+		return reflect.Value{}, &SchemaError{
+			Path:   path,
+			Value:  v.Interface(),
+			Reason: fmt.Sprintf("value of type `%v` is not a document", v.Type()),
+		}
+	}
+
+	return a.clone(v, false), nil
+}
+
+// coerceValue coerces v to type t.
+// The v is invalid if it's nil.
+func coerceValue(v reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if !v.IsValid() {
+		return v, isNilable(t.Kind())
+	}
+
+	vt := v.Type()
+
+	if vt == t || t.Kind() == reflect.Interface && vt.Implements(t) {
+		return v, true
+	}
+
+	switch {
+	case isNumberKind(vt.Kind()) && isNumberKind(t.Kind()):
+		return convertNumber(v, t)
+	case vt.Kind() == reflect.String && t.Kind() == reflect.String:
+		return v.Convert(t), true
+	case vt.Kind() == reflect.String:
+		return parseString(v.String(), t)
+	}
+
+	return reflect.Value{}, false
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return isIntegerKind(k)
+}
+
+func isUnsignedKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+
+	return false
+}
+
+// convertNumber converts number v to number type t if no precision is lost.
+func convertNumber(v reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if isUnsignedKind(t.Kind()) && !isUnsignedKind(v.Kind()) {
+		if isIntegerKind(v.Kind()) && v.Int() < 0 || !isIntegerKind(v.Kind()) && v.Float() < 0 {
+			return reflect.Value{}, false
+		}
+	}
+
+	converted := v.Convert(t)
+
+	// A large unsigned integer may overflow to a negative signed integer.
+	if isUnsignedKind(v.Kind()) && isIntegerKind(t.Kind()) && !isUnsignedKind(t.Kind()) && converted.Int() < 0 {
+		return reflect.Value{}, false
+	}
+
+	if converted.Convert(v.Type()).Interface() != v.Interface() {
+		return reflect.Value{}, false
+	}
+
+	return converted, true
+}
+
+// parseString parses s as a value of number or bool type t.
+func parseString(s string, t reflect.Type) (reflect.Value, bool) {
+	v := reflect.New(t).Elem()
+	bits := t.Bits
+	var err error
+
+	switch k := t.Kind(); {
+	case k == reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		v.SetBool(b)
+	case k == reflect.Float32 || k == reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(s, bits())
+		v.SetFloat(f)
+	case isUnsignedKind(k):
+		var u uint64
+		u, err = strconv.ParseUint(s, 10, bits())
+		v.SetUint(u)
+	case isIntegerKind(k):
+		var n int64
+		n, err = strconv.ParseInt(s, 10, bits())
+		v.SetInt(n)
+	default:
+		return reflect.Value{}, false
+	}
+
+	if err != nil {
+		return reflect.Value{}, false
+	}
+
+	return v, true
+}
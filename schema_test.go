@@ -0,0 +1,120 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type schemaLevel string
+
+func TestCloneWithSchema(t *testing.T) {
+	a := assert.New(t)
+	schema := &Schema{
+		Fields: map[string]SchemaField{
+			"id":    {Type: reflect.TypeOf(int64(0)), Required: true},
+			"level": {Type: reflect.TypeOf(schemaLevel(""))},
+			"score": {Type: reflect.TypeOf(float32(0))},
+			"ok":    {Type: reflect.TypeOf(true)},
+			"err":   {Type: reflect.TypeOf((*error)(nil)).Elem()},
+			"owner": {Schema: &Schema{
+				Fields: map[string]SchemaField{
+					"name": {Type: reflect.TypeOf(""), Required: true},
+				},
+				Strict: true,
+			}},
+			"items": {Schema: &Schema{
+				Fields: map[string]SchemaField{
+					"count": {Type: reflect.TypeOf(uint(0))},
+				},
+			}},
+		},
+	}
+	tags := []interface{}{"a", "b"}
+	doc := map[string]interface{}{
+		"id":    float64(42),
+		"level": "debug",
+		"score": "1.5",
+		"ok":    "true",
+		"err":   nil,
+		"owner": map[string]interface{}{"name": "foo"},
+		"items": []interface{}{
+			map[string]interface{}{"count": 3.0, "extra": tags},
+		},
+		"tags": tags,
+	}
+	cloned, err := CloneWithSchema(doc, schema)
+	a.NilError(err)
+	a.Equal(cloned, map[string]interface{}{
+		"id":    int64(42),
+		"level": schemaLevel("debug"),
+		"score": float32(1.5),
+		"ok":    true,
+		"err":   nil,
+		"owner": map[string]interface{}{"name": "foo"},
+		"items": []interface{}{
+			map[string]interface{}{"count": uint(3), "extra": tags},
+		},
+		"tags": tags,
+	})
+
+	// Values not coerced are cloned.
+	a.Assert(&cloned["tags"].([]interface{})[0] != &tags[0])
+	a.Assert(&cloned["items"].([]interface{})[0].(map[string]interface{})["extra"].([]interface{})[0] != &tags[0])
+
+	cloned, err = CloneWithSchema(nil, schema)
+	a.NilError(err)
+	a.Equal(cloned, map[string]interface{}(nil))
+
+	cloned, err = FromHeap().CloneWithSchema(doc, nil)
+	a.NilError(err)
+	a.Equal(cloned, doc)
+}
+
+func TestCloneWithSchemaErrors(t *testing.T) {
+	a := assert.New(t)
+	schema := &Schema{
+		Fields: map[string]SchemaField{
+			"id":   {Type: reflect.TypeOf(0), Required: true},
+			"size": {Type: reflect.TypeOf(uint8(0))},
+			"ok":   {Type: reflect.TypeOf(true)},
+			"sub": {Schema: &Schema{
+				Fields: map[string]SchemaField{},
+				Strict: true,
+			}},
+		},
+	}
+	cases := []struct {
+		doc      map[string]interface{}
+		path     string
+		expected string
+	}{
+		{map[string]interface{}{}, `["id"]`, "required key is missing"},
+		{map[string]interface{}{"id": 3.5}, `["id"]`, "value of type `float64` cannot be coerced to `int`"},
+		{map[string]interface{}{"id": nil}, `["id"]`, "nil cannot be coerced to `int`"},
+		{map[string]interface{}{"id": "abc"}, `["id"]`, "value of type `string` cannot be coerced to `int`"},
+		{map[string]interface{}{"id": uint64(math.MaxUint64)}, `["id"]`, "value of type `uint64` cannot be coerced to `int`"},
+		{map[string]interface{}{"id": 1, "size": -1}, `["size"]`, "value of type `int` cannot be coerced to `uint8`"},
+		{map[string]interface{}{"id": 1, "size": 256}, `["size"]`, "value of type `int` cannot be coerced to `uint8`"},
+		{map[string]interface{}{"id": 1, "ok": 1}, `["ok"]`, "value of type `int` cannot be coerced to `bool`"},
+		{map[string]interface{}{"id": 1, "sub": 1}, `["sub"]`, "value of type `int` is not a document"},
+		{map[string]interface{}{"id": 1, "sub": map[string]interface{}{"x": 1}}, `["sub"]["x"]`, "unknown key"},
+		{map[string]interface{}{"id": 1, "sub": []interface{}{map[string]interface{}{}, 1}}, `["sub"][1]`, "value of type `int` is not a document"},
+	}
+
+	for i, c := range cases {
+		a.Use(&i, &c)
+		_, err := CloneWithSchema(c.doc, schema)
+		se, ok := err.(*SchemaError)
+		a.Assert(ok)
+		a.Equal(se.Path, c.path)
+		a.Equal(se.Reason, c.expected)
+		a.Equal(se.Error(), fmt.Sprintf("go-clone: value at `%v` doesn't match schema: %v", c.path, c.expected))
+	}
+}
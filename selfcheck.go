@@ -0,0 +1,234 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+// Capabilities is the optional capabilities available in current build.
+type Capabilities struct {
+	// Arena is true if FromArena allocates memory in arena.
+	// It requires go1.20 or later with GOEXPERIMENT=arenas.
+	Arena bool
+
+	// ReusedMapKeys is true if keys of string-keyed maps are reused without allocating memory.
+	// It requires go1.18 or later.
+	ReusedMapKeys bool
+}
+
+// AvailableCapabilities returns optional capabilities available in current build.
+func AvailableCapabilities() Capabilities {
+	return Capabilities{
+		Arena:         arenaIsEnabled,
+		ReusedMapKeys: iterKeyIsReused,
+	}
+}
+
+// SelfCheckError is the error of failed checks in SelfCheck.
+type SelfCheckError struct {
+	GoVersion string           // The Go version of the running program.
+	Errors    map[string]error // Errors of failed checks indexed by check names.
+}
+
+func (e *SelfCheckError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	failures := make([]string, 0, len(names))
+
+	for _, name := range names {
+		failures = append(failures, name+": "+e.Errors[name].Error())
+	}
+
+	return fmt.Sprintf("go-clone: self check fails in %v: %v", e.GoVersion, strings.Join(failures, "; "))
+}
+
+// selfChecks is all checks run by SelfCheck.
+var selfChecks = []struct {
+	name  string
+	check func() error
+}{
+	{"ro-flag", checkROFlag},
+	{"interface-data", checkInterfaceData},
+	{"headers", checkHeaders},
+	{"map-keys", checkMapKeys},
+	{"wrapper", checkWrapper},
+}
+
+// SelfCheck checks whether the unsafe tricks used by this package work in the running Go runtime.
+// It returns a *SelfCheckError if any check fails.
+//
+// This package relies on internal layouts of reflect.Value, interfaces, strings, slices and maps,
+// which are not covered by the Go 1 compatibility promise.
+// A new Go release may break them silently and corrupts cloned values.
+// It's designed to be called on startup, e.g. in tests or health checks,
+// to fail fast after a Go upgrade.
+//
+// Call AvailableCapabilities to find optional capabilities available in current build.
+func SelfCheck() error {
+	var errs map[string]error
+
+	for _, c := range selfChecks {
+		if err := runSelfCheck(c.check); err != nil {
+			if errs == nil {
+				errs = map[string]error{}
+			}
+
+			errs[c.name] = err
+		}
+	}
+
+	if errs == nil {
+		return nil
+	}
+
+	return &SelfCheckError{
+		GoVersion: runtime.Version(),
+		Errors:    errs,
+	}
+}
+
+func runSelfCheck(check func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return check()
+}
+
+type selfCheckData struct {
+	n     int
+	s     string
+	p     *int
+	bytes []byte
+	i     interface{}
+	m     map[string]int
+	keys  map[*int]bool
+}
+
+func newSelfCheckData() *selfCheckData {
+	n := 123
+	return &selfCheckData{
+		n:     n,
+		s:     "self-check",
+		p:     &n,
+		bytes: []byte("bytes"),
+		i:     &n,
+		m:     map[string]int{"a": 1, "b": 2},
+		keys:  map[*int]bool{&n: true},
+	}
+}
+
+// checkROFlag checks reading unexported fields with forceClearROFlag.
+func checkROFlag() error {
+	data := newSelfCheckData()
+	field := reflect.ValueOf(data).Elem().FieldByName("p")
+	v := forceClearROFlag(field)
+
+	if !v.CanInterface() {
+		return errors.New("read-only flag is not cleared")
+	}
+
+	if p, ok := v.Interface().(*int); !ok || p != data.p {
+		return errors.New("unexpected value after clearing read-only flag")
+	}
+
+	return nil
+}
+
+// checkInterfaceData checks copying interface data with parseReflectValue.
+func checkInterfaceData() error {
+	data := newSelfCheckData()
+	var i interface{}
+	shadowCopy(reflect.ValueOf(data).Elem().FieldByName("i"), unsafe.Pointer(&i))
+
+	if p, ok := i.(*int); !ok || p != data.p {
+		return errors.New("unexpected interface value copied from reflect.Value")
+	}
+
+	return nil
+}
+
+// checkHeaders checks setting string and slice headers in clones.
+func checkHeaders() error {
+	data := newSelfCheckData()
+	allocator := NewAllocator(nil, &AllocatorMethods{
+		IsScalar: func(k reflect.Kind) bool {
+			return k != reflect.String && IsScalar(k)
+		},
+	})
+	cloned := allocator.Clone(reflect.ValueOf(data)).Interface().(*selfCheckData)
+
+	if cloned.n != data.n || cloned.s != data.s {
+		return errors.New("unexpected scalar values in clone")
+	}
+
+	if (*stringHeader)(unsafe.Pointer(&cloned.s)).Data == (*stringHeader)(unsafe.Pointer(&data.s)).Data {
+		return errors.New("string is not copied")
+	}
+
+	if string(cloned.bytes) != string(data.bytes) || len(cloned.bytes) != len(data.bytes) || cap(cloned.bytes) != cap(data.bytes) {
+		return errors.New("unexpected slice header in clone")
+	}
+
+	if &cloned.bytes[0] == &data.bytes[0] {
+		return errors.New("slice is not copied")
+	}
+
+	if cloned.p == data.p || *cloned.p != *data.p {
+		return errors.New("unexpected pointer in clone")
+	}
+
+	return nil
+}
+
+// checkMapKeys checks cloning map keys in unexported fields.
+func checkMapKeys() error {
+	data := newSelfCheckData()
+	allocator := FromHeap()
+	allocator.SetMapKeyPolicy(reflect.TypeOf(data.keys), MapKeyPolicyShare)
+	cloned := allocator.Clone(reflect.ValueOf(data)).Interface().(*selfCheckData)
+
+	if len(cloned.m) != len(data.m) || cloned.m["a"] != 1 || cloned.m["b"] != 2 {
+		return errors.New("unexpected string-keyed map in clone")
+	}
+
+	if len(cloned.keys) != 1 || !cloned.keys[data.p] {
+		return errors.New("unexpected shared map keys in clone")
+	}
+
+	return nil
+}
+
+// checkWrapper checks wrapping and restoring a value.
+func checkWrapper() error {
+	data := newSelfCheckData()
+	wrapped := Wrap(data).(*selfCheckData)
+
+	if wrapped == data || Unwrap(wrapped).(*selfCheckData) != data {
+		return errors.New("unexpected wrapped value")
+	}
+
+	wrapped.n = 456
+	Undo(wrapped)
+
+	if wrapped.n != data.n {
+		return errors.New("wrapped value is not restored")
+	}
+
+	return nil
+}
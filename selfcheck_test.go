@@ -0,0 +1,38 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestSelfCheck(t *testing.T) {
+	a := assert.New(t)
+	a.NilError(SelfCheck())
+
+	caps := AvailableCapabilities()
+	a.Equal(caps.Arena, arenaIsEnabled)
+	a.Equal(caps.ReusedMapKeys, iterKeyIsReused)
+}
+
+func TestSelfCheckError(t *testing.T) {
+	a := assert.New(t)
+	err := runSelfCheck(func() error {
+		panic("broken")
+	})
+	a.Equal(err.Error(), "panic: broken")
+
+	sce := &SelfCheckError{
+		GoVersion: "go1.99",
+		Errors: map[string]error{
+			"wrapper": errors.New("bar"),
+			"headers": err,
+		},
+	}
+	a.Assert(strings.HasSuffix(sce.Error(), "go1.99: headers: panic: broken; wrapper: bar"))
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// shapeFunc wraps a custom func set for a struct shape so that configs can tell whether it's changed.
+type shapeFunc struct {
+	fn Func
+}
+
+// SetShapeFunc sets a custom clone function for all struct types in the same shape as t in heap allocator.
+//
+// See Allocator.SetShapeFunc for more details.
+func SetShapeFunc(t reflect.Type, fn Func) {
+	defaultAllocator.SetShapeFunc(t, fn)
+}
+
+// SetShapeFunc sets a custom clone function for all struct types in the same shape as t.
+// If fn is nil, remove the custom clone function for the shape.
+// If t is not struct or pointer to struct, SetShapeFunc ignores t.
+//
+// Two struct types are in the same shape if they have the same fields in the same order,
+// i.e. same names, types, tags and embedding, no matter what the names of struct types are.
+// It's designed for types constructed at runtime by reflect.StructOf,
+// which may not exist yet when the allocator is set up.
+// A struct type built later with the same fields is cloned by fn.
+//
+// Custom functions set by SetCustomFunc, and marks set by MarkAsScalar, for a struct type win the shape func.
+func (a *Allocator) SetShapeFunc(t reflect.Type, fn Func) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	shape := StructShape(t)
+	var sf *shapeFunc
+
+	if fn != nil {
+		sf = &shapeFunc{
+			fn: fn,
+		}
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		shapes := make(map[string]*shapeFunc, len(cfg.shapes)+1)
+
+		for k, v := range cfg.shapes {
+			shapes[k] = v
+		}
+
+		shapes[shape] = sf
+		copied.shapes = shapes
+		return copied
+	})
+}
+
+// StructShape returns the shape of struct type t in Go syntax,
+// e.g. `struct { Name string "json:\"name\""; count int }`.
+// Struct types in the same shape have the same shape string.
+// If t is not a struct, StructShape returns an empty string.
+//
+// Names of field types are not resolved,
+// so that a struct with a field of a named struct type is not in the same shape
+// as a struct with a field of the underlying unnamed struct type.
+func StructShape(t reflect.Type) string {
+	if t.Kind() != reflect.Struct {
+		return ""
+	}
+
+	num := t.NumField()
+
+	if num == 0 {
+		return "struct {}"
+	}
+
+	buf := &strings.Builder{}
+	buf.WriteString("struct {")
+
+	for i := 0; i < num; i++ {
+		field := t.Field(i)
+
+		if i > 0 {
+			buf.WriteByte(';')
+		}
+
+		buf.WriteByte(' ')
+
+		if !field.Anonymous {
+			buf.WriteString(field.Name)
+			buf.WriteByte(' ')
+		}
+
+		buf.WriteString(field.Type.String())
+
+		if field.Tag != "" {
+			buf.WriteByte(' ')
+			buf.WriteString(strconv.Quote(string(field.Tag)))
+		}
+	}
+
+	buf.WriteString(" }")
+	return buf.String()
+}
+
+// lookupShapeFunc returns the nearest custom func set for the shape of struct type t.
+func (cfg *config) lookupShapeFunc(t reflect.Type) Func {
+	var shape string
+
+	for current := cfg; current != nil; current = current.parent {
+		if len(current.shapes) == 0 {
+			continue
+		}
+
+		if shape == "" {
+			shape = StructShape(t)
+		}
+
+		if sf, ok := current.shapes[shape]; ok {
+			if sf == nil {
+				return nil
+			}
+
+			return sf.fn
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+type shapeData struct {
+	Name  string `json:"name"`
+	Value *int
+	count int
+}
+
+// makeShapeType makes a struct type in the same shape as shapeData at runtime.
+func makeShapeType() reflect.Type {
+	return reflect.StructOf([]reflect.StructField{
+		{Name: "Name", Type: reflect.TypeOf(""), Tag: `json:"name"`},
+		{Name: "Value", Type: reflect.TypeOf((*int)(nil))},
+		{Name: "count", PkgPath: "github.com/huandu/go-clone", Type: reflect.TypeOf(0)},
+	})
+}
+
+func TestCloneDynamicTypes(t *testing.T) {
+	a := assert.New(t)
+	st := makeShapeType()
+	mt := reflect.MapOf(reflect.TypeOf(""), reflect.PtrTo(st))
+	slt := reflect.SliceOf(mt)
+	at := reflect.ArrayOf(2, st)
+
+	n := 1
+	elem := reflect.New(st)
+	elem.Elem().Field(0).SetString("foo")
+	elem.Elem().Field(1).Set(reflect.ValueOf(&n))
+	*(*int)(unsafe.Pointer(elem.Elem().Field(2).UnsafeAddr())) = 2
+	m := reflect.MakeMap(mt)
+	m.SetMapIndex(reflect.ValueOf("a"), elem)
+	sl := reflect.MakeSlice(slt, 1, 1)
+	sl.Index(0).Set(m)
+	arr := reflect.New(at).Elem()
+	arr.Index(1).Set(elem.Elem())
+
+	for _, fn := range []func(v interface{}) interface{}{Clone, Slowly} {
+		cloned := reflect.ValueOf(fn(sl.Interface()))
+		a.Equal(cloned.Interface(), sl.Interface())
+		a.Assert(cloned.Pointer() != sl.Pointer())
+
+		orig := sl.Index(0).MapIndex(reflect.ValueOf("a"))
+		ce := cloned.Index(0).MapIndex(reflect.ValueOf("a"))
+		a.Assert(ce.Pointer() != orig.Pointer())
+		a.Assert(ce.Elem().Field(1).Pointer() != orig.Elem().Field(1).Pointer())
+		a.Equal(ce.Elem().Field(2).Int(), int64(2))
+
+		cloned = reflect.ValueOf(fn(arr.Interface()))
+		a.Equal(cloned.Interface(), arr.Interface())
+		a.Assert(cloned.Index(1).Field(1).Pointer() != arr.Index(1).Field(1).Pointer())
+
+		// Values of dynamic types in interfaces.
+		values := []interface{}{elem.Interface(), elem.Elem().Interface()}
+		clonedValues := fn(values).([]interface{})
+		a.Equal(clonedValues, values)
+		a.Assert(reflect.ValueOf(clonedValues[0]).Pointer() != elem.Pointer())
+		a.Assert(reflect.ValueOf(clonedValues[1]).Field(1).Pointer() != elem.Elem().Field(1).Pointer())
+	}
+}
+
+func TestStructShape(t *testing.T) {
+	a := assert.New(t)
+	a.Equal(StructShape(reflect.TypeOf(shapeData{})), StructShape(makeShapeType()))
+	a.Equal(StructShape(makeShapeType()), makeShapeType().String())
+	a.Equal(StructShape(reflect.TypeOf(struct{}{})), "struct {}")
+	a.Equal(StructShape(reflect.TypeOf(0)), "")
+
+	type embedded struct {
+		shapeData
+		*testing.T
+	}
+	a.Equal(StructShape(reflect.TypeOf(embedded{})), reflect.TypeOf(struct {
+		shapeData
+		*testing.T
+	}{}).String())
+}
+
+func TestSetShapeFunc(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.SetShapeFunc(reflect.TypeOf(&shapeData{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.Field(0).SetString(old.Field(0).String() + "-cloned")
+	})
+
+	n := 1
+	data := &shapeData{
+		Name:  "foo",
+		Value: &n,
+		count: 2,
+	}
+	cloned := allocator.Clone(reflect.ValueOf(data)).Interface().(*shapeData)
+	a.Equal(cloned, &shapeData{Name: "foo-cloned"})
+
+	// Types built at runtime in the same shape.
+	st := makeShapeType()
+	v := reflect.New(st).Elem()
+	v.Field(0).SetString("bar")
+	v.Field(1).Set(reflect.ValueOf(&n))
+	nv := allocator.Clone(v.Addr()).Elem()
+	a.Equal(nv.Field(0).String(), "bar-cloned")
+	a.Assert(nv.Field(1).IsNil())
+
+	// Custom func set for the type wins.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	child.SetCustomFunc(st, func(allocator *Allocator, old, new reflect.Value) {
+		new.Field(0).SetString("custom")
+	})
+	a.Equal(child.Clone(v.Addr()).Elem().Field(0).String(), "custom")
+	a.Equal(child.Clone(reflect.ValueOf(data)).Interface().(*shapeData).Name, "foo-cloned")
+
+	// Remove shape func in child.
+	child.SetShapeFunc(reflect.TypeOf(shapeData{}), nil)
+	a.Equal(child.Clone(reflect.ValueOf(data)).Interface(), data)
+	a.Equal(allocator.Clone(reflect.ValueOf(data)).Interface().(*shapeData).Name, "foo-cloned")
+
+	// Non-struct types are ignored.
+	allocator.SetShapeFunc(reflect.TypeOf(0), emptyCloneFunc)
+	a.Equal(len(allocator.loadConfig().shapes), 1)
+}
+
+func TestShapeFuncRegistration(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	fn := func(allocator *Allocator, old, new reflect.Value) {
+		new.Field(0).SetString("shape")
+	}
+	scope := allocator.Register(func(allocator *Allocator) {
+		allocator.SetShapeFunc(reflect.TypeOf(shapeData{}), fn)
+	})
+	data := &shapeData{Name: "foo"}
+	a.Equal(allocator.Clone(reflect.ValueOf(data)).Interface().(*shapeData).Name, "shape")
+
+	exported := allocator.ExportConfig()
+	scope.Close()
+	a.Equal(allocator.Clone(reflect.ValueOf(data)).Interface(), data)
+
+	other := FromHeap()
+	other.ApplyConfig(exported)
+	a.Equal(other.Clone(reflect.ValueOf(data)).Interface().(*shapeData).Name, "shape")
+}
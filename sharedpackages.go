@@ -0,0 +1,119 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"strings"
+)
+
+// sharedPackagesOption is all package patterns registered by ShareTypesFromPackages in one allocator.
+type sharedPackagesOption struct {
+	patterns []string
+}
+
+// ShareTypesFromPackages shares values of all types defined in packages matching patterns in heap allocator.
+//
+// See Allocator.ShareTypesFromPackages for more details.
+func ShareTypesFromPackages(patterns ...string) {
+	defaultAllocator.ShareTypesFromPackages(patterns...)
+}
+
+// ShareTypesFromPackages shares values of all types defined in packages matching patterns with clones,
+// as if all struct types defined in these packages are marked by MarkAsScalar
+// and all pointers to types defined in these packages are marked by MarkAsOpaquePointer.
+// It's designed for packages of immutable types, so that new types are shared without registrations.
+//
+// A pattern is a package path, e.g. "github.com/myorg/immutabletypes",
+// or a package path followed by "/..." to match the package and all its sub-packages,
+// e.g. "github.com/myorg/immutabletypes/...".
+// Empty patterns are ignored.
+//
+// Patterns are added to patterns registered in a and a's parents.
+// Custom funcs and marks set for a type win the patterns, so that a type can be excluded by SetCustomFunc.
+// UnmarkAsScalar and UnmarkAsOpaquePointer don't remove patterns.
+func (a *Allocator) ShareTypesFromPackages(patterns ...string) {
+	added := make([]string, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		if pattern != "" {
+			added = append(added, pattern)
+		}
+	}
+
+	if len(added) == 0 {
+		return
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.sharedPackages = cfg.sharedPackages.merge(added)
+		return copied
+	})
+}
+
+// merge returns a new option with patterns in opt and patterns.
+func (opt *sharedPackagesOption) merge(patterns []string) *sharedPackagesOption {
+	if opt == nil {
+		return &sharedPackagesOption{
+			patterns: patterns,
+		}
+	}
+
+	merged := make([]string, 0, len(opt.patterns)+len(patterns))
+	merged = append(merged, opt.patterns...)
+	merged = append(merged, patterns...)
+	return &sharedPackagesOption{
+		patterns: merged,
+	}
+}
+
+// match returns true if pkg matches any pattern in opt.
+func (opt *sharedPackagesOption) match(pkg string) bool {
+	for _, pattern := range opt.patterns {
+		if matchPackagePattern(pattern, pkg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchPackagePattern(pattern, pkg string) bool {
+	if prefix := strings.TrimSuffix(pattern, "/..."); prefix != pattern {
+		return pkg == prefix || strings.HasPrefix(pkg, prefix+"/")
+	}
+
+	return pkg == pattern
+}
+
+// isSharedPackageType returns true if t is defined in a package registered by ShareTypesFromPackages.
+func (cfg *config) isSharedPackageType(t reflect.Type) bool {
+	var pkg string
+
+	for current := cfg; current != nil; current = current.parent {
+		if current.sharedPackages == nil {
+			continue
+		}
+
+		if pkg == "" {
+			pkg = t.PkgPath()
+
+			if pkg == "" {
+				return false
+			}
+		}
+
+		if current.sharedPackages.match(pkg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSharedPackagePointer returns true if pointer type t or its elem type is defined in a shared package.
+func (cfg *config) isSharedPackagePointer(t reflect.Type) bool {
+	return cfg.isSharedPackageType(t) || cfg.isSharedPackageType(t.Elem())
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type sharedPackageModel struct {
+	Names []string
+	Next  *sharedPackageModel
+}
+
+type sharedPackageHolder struct {
+	Value  sharedPackageModel
+	Ptr    *sharedPackageModel
+	Models []*sharedPackageModel
+	Items  []int
+}
+
+func TestShareTypesFromPackages(t *testing.T) {
+	a := assert.New(t)
+	model := &sharedPackageModel{
+		Names: []string{"foo"},
+		Next:  &sharedPackageModel{},
+	}
+	v := &sharedPackageHolder{
+		Value:  *model,
+		Ptr:    model,
+		Models: []*sharedPackageModel{model},
+		Items:  []int{1},
+	}
+
+	parent := FromHeap()
+	parent.ShareTypesFromPackages("", "github.com/huandu/go-clone/...")
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: parent,
+	})
+	child.ShareTypesFromPackages("github.com/huandu/go-clone/walk")
+
+	for _, allocator := range []*Allocator{parent, child} {
+		a.Assert(allocator.IsMarkedAsScalar(reflect.TypeOf(sharedPackageModel{})))
+		a.Assert(allocator.IsOpaquePointer(reflect.TypeOf(model)))
+
+		cloned := allocator.Clone(reflect.ValueOf(v)).Interface().(*sharedPackageHolder)
+		a.Assert(cloned == v)
+
+		// Unnamed types are cloned as usual.
+		models := allocator.Clone(reflect.ValueOf(v.Models)).Interface().([]*sharedPackageModel)
+		a.Assert(&models[0] != &v.Models[0])
+		a.Assert(models[0] == model)
+
+		value := allocator.Clone(reflect.ValueOf(*v)).Interface().(sharedPackageHolder)
+		a.Assert(&value.Value.Names[0] == &v.Value.Names[0])
+		a.Assert(value.Ptr == model)
+		a.Assert(&value.Items[0] == &v.Items[0])
+	}
+
+	// Patterns don't match other packages.
+	allocator := FromHeap()
+	allocator.ShareTypesFromPackages("github.com/huandu/go-clone/walk/...", "github.com/huandu")
+	cloned := allocator.Clone(reflect.ValueOf(v)).Interface().(*sharedPackageHolder)
+	a.Assert(cloned != v)
+	a.Assert(cloned.Ptr != model)
+
+	// Custom funcs win patterns.
+	child.SetCustomFunc(reflect.TypeOf(sharedPackageModel{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.Set(old)
+	})
+	a.Assert(!child.IsMarkedAsScalar(reflect.TypeOf(sharedPackageModel{})))
+	wrapped := child.Clone(reflect.ValueOf([]sharedPackageModel{*model})).Interface().([]sharedPackageModel)
+	a.Assert(&wrapped[0].Names[0] == &model.Names[0])
+
+	// Patterns are exported and undone as other registrations.
+	exported := FromHeap()
+	exported.ApplyConfig(parent.ExportConfig())
+	a.Assert(exported.IsOpaquePointer(reflect.TypeOf(model)))
+
+	scoped := FromHeap()
+	scope := scoped.Register(func(a *Allocator) {
+		a.ShareTypesFromPackages("github.com/huandu/go-clone")
+	})
+	a.Assert(scoped.IsOpaquePointer(reflect.TypeOf(model)))
+	scope.Close()
+	a.Assert(!scoped.IsOpaquePointer(reflect.TypeOf(model)))
+}
+
+func TestMatchPackagePattern(t *testing.T) {
+	a := assert.New(t)
+
+	a.Assert(matchPackagePattern("github.com/foo/bar", "github.com/foo/bar"))
+	a.Assert(!matchPackagePattern("github.com/foo/bar", "github.com/foo/bar/baz"))
+	a.Assert(matchPackagePattern("github.com/foo/bar/...", "github.com/foo/bar"))
+	a.Assert(matchPackagePattern("github.com/foo/bar/...", "github.com/foo/bar/baz"))
+	a.Assert(!matchPackagePattern("github.com/foo/bar/...", "github.com/foo/barbaz"))
+}
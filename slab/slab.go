@@ -0,0 +1,186 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Package slab provides a slab allocator for clones of many small values of the same types,
+// e.g. nodes of linked lists or trees.
+//
+// Values of registered types are allocated from per-type slabs,
+// each of which is an array of fixed number of values,
+// and are reused across clone sessions after Reset.
+// Values of other types are allocated in heap.
+//
+//	slabs := slab.New(0)
+//	slabs.Register(reflect.TypeOf(Node{}))
+//	allocator := slabs.Allocator()
+//	cloned := allocator.Clone(reflect.ValueOf(root)).Interface().(*Node)
+//
+//	// Reuse all nodes when cloned is not used any more.
+//	allocator.Reset()
+package slab
+
+import (
+	"reflect"
+	"sync"
+	"unsafe"
+
+	"github.com/huandu/go-clone"
+)
+
+// DefaultSlabSize is the default number of values in a slab.
+const DefaultSlabSize = 1024
+
+// Slabs allocates values of registered types from per-type slabs.
+// All methods of Slabs are safe for concurrent use.
+type Slabs struct {
+	mu    sync.Mutex
+	size  int
+	slabs map[reflect.Type]*slabs
+}
+
+// slabs are all slabs of a type.
+type slabs struct {
+	arrayType reflect.Type
+	chunks    []reflect.Value // Pointers to arrays of values.
+	current   int             // Index of current chunk.
+	next      int             // Index of next value in current chunk.
+	free      []reflect.Value // Values returned by Free.
+}
+
+// New creates a Slabs with size values in every slab.
+// If size is not positive, DefaultSlabSize is used.
+func New(size int) *Slabs {
+	if size <= 0 {
+		size = DefaultSlabSize
+	}
+
+	return &Slabs{
+		size:  size,
+		slabs: map[reflect.Type]*slabs{},
+	}
+}
+
+// Register registers types so that their values are allocated from slabs.
+// Registering a type more than once is a no-op.
+func (s *Slabs) Register(types ...reflect.Type) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range types {
+		if _, ok := s.slabs[t]; ok {
+			continue
+		}
+
+		s.slabs[t] = &slabs{
+			arrayType: reflect.ArrayOf(s.size, t),
+		}
+	}
+}
+
+// Methods returns allocator methods allocating values of registered types from slabs.
+// The pool passed to methods is ignored.
+//
+// Only New allocates memory from slabs. Slices, maps and chans are allocated in heap.
+// Reset makes all values reusable, and Free makes a value reusable.
+// Release drops all slabs.
+func (s *Slabs) Methods() *clone.AllocatorMethods {
+	return &clone.AllocatorMethods{
+		New: func(pool unsafe.Pointer, t reflect.Type) reflect.Value {
+			return s.New(t)
+		},
+		Free: func(pool unsafe.Pointer, v reflect.Value) {
+			s.Free(v)
+		},
+		Reset: func(pool unsafe.Pointer) {
+			s.Reset()
+		},
+		Release: func(pool unsafe.Pointer) {
+			s.Release()
+		},
+	}
+}
+
+// Allocator creates an allocator using methods returned by Methods.
+func (s *Slabs) Allocator() *clone.Allocator {
+	return clone.NewAllocator(unsafe.Pointer(s), s.Methods())
+}
+
+// New returns a pointer to a new zero value of t.
+// If t is registered, the value is allocated from slabs of t.
+// Otherwise, it's allocated in heap.
+func (s *Slabs) New(t reflect.Type) reflect.Value {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sl, ok := s.slabs[t]
+
+	if !ok {
+		return reflect.New(t)
+	}
+
+	if n := len(sl.free); n != 0 {
+		v := sl.free[n-1]
+		sl.free = sl.free[:n-1]
+		v.Elem().Set(reflect.Zero(t))
+		return v
+	}
+
+	if sl.next == s.size {
+		sl.current++
+		sl.next = 0
+	}
+
+	if sl.current == len(sl.chunks) {
+		sl.chunks = append(sl.chunks, reflect.New(sl.arrayType))
+	}
+
+	v := sl.chunks[sl.current].Elem().Index(sl.next).Addr()
+	sl.next++
+	return v
+}
+
+// Free makes v, a pointer returned by New, reusable by New.
+// Values which are not allocated from slabs are ignored.
+func (s *Slabs) Free(v reflect.Value) {
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sl, ok := s.slabs[v.Type().Elem()]; ok && len(sl.chunks) != 0 {
+		sl.free = append(sl.free, v)
+	}
+}
+
+// Reset makes all values allocated from slabs reusable.
+// All values allocated before Reset must not be used after Reset.
+func (s *Slabs) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sl := range s.slabs {
+		for i := 0; i <= sl.current && i < len(sl.chunks); i++ {
+			chunk := sl.chunks[i].Elem()
+			chunk.Set(reflect.Zero(sl.arrayType))
+		}
+
+		sl.current = 0
+		sl.next = 0
+		sl.free = nil
+	}
+}
+
+// Release drops all slabs.
+// Registered types are kept.
+func (s *Slabs) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sl := range s.slabs {
+		sl.chunks = nil
+		sl.current = 0
+		sl.next = 0
+		sl.free = nil
+	}
+}
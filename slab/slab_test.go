@@ -0,0 +1,109 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package slab
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type node struct {
+	Value int
+	Name  string
+	Next  *node
+}
+
+func newList(n int) *node {
+	var head *node
+
+	for i := n; i > 0; i-- {
+		head = &node{
+			Value: i,
+			Name:  "node",
+			Next:  head,
+		}
+	}
+
+	return head
+}
+
+func TestSlabsClone(t *testing.T) {
+	a := assert.New(t)
+	slabs := New(4)
+	slabs.Register(reflect.TypeOf(node{}))
+	allocator := slabs.Allocator()
+
+	list := newList(10)
+	cloned := allocator.Clone(reflect.ValueOf(list)).Interface().(*node)
+	a.Equal(cloned, list)
+	a.Assert(cloned != list)
+
+	sl := slabs.slabs[reflect.TypeOf(node{})]
+	a.Equal(len(sl.chunks), 3)
+
+	chunk := sl.chunks[0].Interface().(*[4]node)
+	found := false
+
+	for p := cloned; p != nil; p = p.Next {
+		if p == &chunk[0] || p == &chunk[1] || p == &chunk[2] || p == &chunk[3] {
+			found = true
+		}
+	}
+
+	a.Assert(found)
+}
+
+func TestSlabsReset(t *testing.T) {
+	a := assert.New(t)
+	slabs := New(4)
+	slabs.Register(reflect.TypeOf(node{}))
+	allocator := slabs.Allocator()
+
+	list := newList(6)
+	cloned := allocator.Clone(reflect.ValueOf(list)).Interface().(*node)
+	a.Equal(cloned, list)
+
+	sl := slabs.slabs[reflect.TypeOf(node{})]
+	chunks := append([]reflect.Value{}, sl.chunks...)
+	a.Equal(len(chunks), 2)
+
+	allocator.Reset()
+	a.Equal(chunks[0].Elem().Index(0).Interface(), node{})
+
+	// Slabs are reused after reset.
+	list = newList(8)
+	cloned = allocator.Clone(reflect.ValueOf(list)).Interface().(*node)
+	a.Equal(cloned, list)
+	a.Equal(len(sl.chunks), 2)
+	a.Equal(sl.chunks[0].Pointer(), chunks[0].Pointer())
+	a.Equal(sl.chunks[1].Pointer(), chunks[1].Pointer())
+
+	allocator.Release()
+	a.Equal(len(sl.chunks), 0)
+	a.Equal(slabs.New(reflect.TypeOf(node{})).Elem().Interface(), node{})
+	a.Equal(len(sl.chunks), 1)
+}
+
+func TestSlabsFree(t *testing.T) {
+	a := assert.New(t)
+	slabs := New(0)
+	slabs.Register(reflect.TypeOf(node{}), reflect.TypeOf(node{}))
+	typ := reflect.TypeOf(node{})
+
+	v := slabs.New(typ)
+	v.Elem().Field(0).SetInt(42)
+	slabs.Free(v)
+
+	reused := slabs.New(typ)
+	a.Equal(reused.Pointer(), v.Pointer())
+	a.Equal(reused.Elem().Interface(), node{})
+
+	// Values of unregistered types are allocated in heap and never reused.
+	n := slabs.New(reflect.TypeOf(0))
+	slabs.Free(n)
+	a.Equal(len(slabs.slabs), 1)
+	a.Equal(n.Elem().Interface(), 0)
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+// Stats is the footprint of a clone.
+type Stats struct {
+	// Objects is the number of objects allocated by allocator methods,
+	// including values created by New, slices, maps and chans.
+	Objects int
+
+	// Bytes is the number of bytes allocated by allocator methods.
+	// The internal data structure of maps is not counted,
+	// thus the bytes of a map is estimated as the size of n keys and n values.
+	Bytes int
+
+	// MaxDepth is the max depth of values reached from the root value.
+	// Struct fields and array elements cloned in place are in the same depth as their owners.
+	MaxDepth int
+
+	// Nodes is the number of values visited.
+	// Struct and array values cloned in place are counted as a part of their owners.
+	Nodes int
+
+	// Duration is the time spent in the clone.
+	Duration time.Duration
+}
+
+// CloneWithStats clones v in heap like Clone and returns the stats of the clone.
+func CloneWithStats(v interface{}) (interface{}, Stats) {
+	return cloneWithStats(defaultAllocator, v, false)
+}
+
+// SlowlyWithStats clones v in heap like Slowly and returns the stats of the clone.
+func SlowlyWithStats(v interface{}) (interface{}, Stats) {
+	return cloneWithStats(defaultAllocator, v, true)
+}
+
+// Compare clones v with every allocator in allocators like Clone and returns stats of clones by names of allocators.
+// It's designed to evaluate allocation strategies, e.g. arena, pool and heap, with a real workload.
+// If an allocator is nil, the heap allocator is used.
+//
+// Every allocator clones v once before measuring to warm up type caches,
+// so that the first allocator is not penalized.
+// Both clones allocate memory from allocators, so pools with limited memory must be large enough for two clones.
+func Compare(allocators map[string]*Allocator, v interface{}) map[string]Stats {
+	return compare(allocators, v, false)
+}
+
+// CompareSlowly works in the same way as Compare except it clones v like Slowly.
+func CompareSlowly(allocators map[string]*Allocator, v interface{}) map[string]Stats {
+	return compare(allocators, v, true)
+}
+
+func compare(allocators map[string]*Allocator, v interface{}, slowly bool) map[string]Stats {
+	results := make(map[string]Stats, len(allocators))
+
+	for name, allocator := range allocators {
+		if allocator == nil {
+			allocator = defaultAllocator
+		}
+
+		cloneWithStats(allocator, v, slowly)
+		_, stats := cloneWithStats(allocator, v, slowly)
+		results[name] = stats
+	}
+
+	return results
+}
+
+func cloneWithStats(allocator *Allocator, v interface{}, slowly bool) (interface{}, Stats) {
+	if v == nil {
+		return nil, Stats{}
+	}
+
+	cloned, stats := allocator.cloneWithStats(reflect.ValueOf(v), slowly, false)
+	return cloned.Interface(), stats
+}
+
+// CloneWithStats works in the same way as Clone and returns the stats of the clone.
+// All memory allocated by a in the clone is counted, including memory allocated in custom funcs.
+func (a *Allocator) CloneWithStats(val reflect.Value) (reflect.Value, Stats) {
+	return a.cloneWithStats(val, false, true)
+}
+
+// CloneSlowlyWithStats works in the same way as CloneSlowly and returns the stats of the clone.
+func (a *Allocator) CloneSlowlyWithStats(val reflect.Value) (reflect.Value, Stats) {
+	return a.cloneWithStats(val, true, true)
+}
+
+func (a *Allocator) cloneWithStats(val reflect.Value, slowly, inCustomFunc bool) (cloned reflect.Value, stats Stats) {
+	return a.cloneWithTypeStats(val, nil, slowly, inCustomFunc)
+}
+
+func (a *Allocator) cloneWithTypeStats(val reflect.Value, types map[reflect.Type]*TypeStats, slowly, inCustomFunc bool) (cloned reflect.Value, stats Stats) {
+	if !val.IsValid() {
+		return val, stats
+	}
+
+	start := time.Now()
+	state := &cloneState{}
+	a.withStats(&stats, types).initCloneState(state, slowly)
+	state.stats = &stats
+
+	if inCustomFunc {
+		state.skipCustomFuncValue = val
+	}
+
+	cloned = state.cloneRoot(val)
+	stats.Duration = time.Since(start)
+	return
+}
+
+// withStats returns a frozen allocator which counts all memory allocated in stats.
+// If types is not nil, memory is counted by types in types as well.
+// It shares pool, methods and config with a.
+func (a *Allocator) withStats(stats *Stats, types map[reflect.Type]*TypeStats) *Allocator {
+	count := func(t reflect.Type, bytes int) {
+		stats.Objects++
+		stats.Bytes += bytes
+
+		if types == nil {
+			return
+		}
+
+		ts := types[t]
+
+		if ts == nil {
+			ts = &TypeStats{
+				Type: t,
+			}
+			types[t] = ts
+		}
+
+		ts.Objects++
+		ts.Bytes += bytes
+	}
+
+	return a.derive(func(primary, derived *Allocator) {
+		derived.new = func(pool unsafe.Pointer, t reflect.Type) reflect.Value {
+			v := primary.new(pool, t)
+
+			if v.IsValid() {
+				count(t, int(t.Size()))
+			}
+
+			return v
+		}
+		derived.makeSlice = func(pool unsafe.Pointer, t reflect.Type, len, cap int) reflect.Value {
+			v := primary.makeSlice(pool, t, len, cap)
+
+			if v.IsValid() {
+				count(t, int(t.Elem().Size())*cap)
+			}
+
+			return v
+		}
+		derived.makeMap = func(pool unsafe.Pointer, t reflect.Type, n int) reflect.Value {
+			v := primary.makeMap(pool, t, n)
+
+			if v.IsValid() {
+				count(t, int(t.Key().Size()+t.Elem().Size())*n)
+			}
+
+			return v
+		}
+		derived.makeChan = func(pool unsafe.Pointer, t reflect.Type, buffer int) reflect.Value {
+			v := primary.makeChan(pool, t, buffer)
+
+			if v.IsValid() {
+				count(t, int(t.Elem().Size())*buffer)
+			}
+
+			return v
+		}
+	})
+}
+
+// cloneAndCount clones v, tracks the depth of v and counts it in stats if necessary.
+func (state *cloneState) cloneAndCount(v reflect.Value) reflect.Value {
+	if state.maxDepth > 0 && state.depth >= state.maxDepth {
+		return state.cloneBeyondMaxDepth(v)
+	}
+
+	if state.autoDepth > 0 && state.depth >= state.autoDepth {
+		panic(autoFallback{})
+	}
+
+	state.depth++
+
+	if stats := state.stats; stats != nil {
+		stats.Nodes++
+
+		if state.depth > stats.MaxDepth {
+			stats.MaxDepth = state.depth
+		}
+	}
+
+	cloned := state.cloneValue(v)
+	state.depth--
+	return cloned
+}
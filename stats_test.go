@@ -0,0 +1,107 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type statsNode struct {
+	Value    int
+	Children []*statsNode
+}
+
+func TestCloneWithStats(t *testing.T) {
+	a := assert.New(t)
+
+	cloned, stats := CloneWithStats(nil)
+	a.Equal(cloned, nil)
+	a.Equal(stats, Stats{})
+
+	orig := &statsNode{
+		Value: 1,
+		Children: []*statsNode{
+			{Value: 2},
+			{Value: 3, Children: []*statsNode{{Value: 4}}},
+		},
+	}
+	cloned, stats = CloneWithStats(orig)
+	a.Equal(cloned, orig)
+
+	// Objects: 4 nodes and 2 slices.
+	sizeOfNode := int(reflect.TypeOf(statsNode{}).Size())
+	sizeOfPtr := int(reflect.TypeOf(orig).Size())
+	a.Equal(stats.Objects, 6)
+	a.Equal(stats.Bytes, 4*sizeOfNode+3*sizeOfPtr)
+
+	// Nodes: 4 pointers, 2 non-nil slices and 2 nil slices.
+	a.Equal(stats.Nodes, 8)
+
+	// Depth: ptr -> slice -> ptr -> slice -> ptr -> slice.
+	a.Equal(stats.MaxDepth, 6)
+	a.Assert(stats.Duration > 0)
+
+	orig.Children[1].Children[0] = orig
+	cloned, stats = SlowlyWithStats(orig)
+	a.Assert(cloned.(*statsNode).Children[1].Children[0] == cloned)
+	a.Equal(stats.Objects, 5)
+}
+
+func TestAllocatorCloneWithStats(t *testing.T) {
+	a := assert.New(t)
+	typeOfNode := reflect.TypeOf(statsNode{})
+	allocator := NewAllocator(nil, nil)
+
+	// Memory allocated in custom funcs is counted.
+	allocator.SetCustomFunc(typeOfNode, func(allocator *Allocator, old, new reflect.Value) {
+		new.Field(0).Set(old.Field(0))
+		new.Field(1).Set(allocator.MakeSlice(old.Field(1).Type(), 0, 10))
+	})
+
+	orig := []statsNode{{Value: 1}}
+	cloned, stats := allocator.CloneWithStats(reflect.ValueOf(orig))
+	a.Equal(cloned.Interface(), []statsNode{{Value: 1, Children: []*statsNode{}}})
+	a.Equal(stats.Objects, 2)
+	a.Equal(stats.Bytes, int(typeOfNode.Size())+10*int(reflect.TypeOf(&statsNode{}).Size()))
+
+	_, stats = allocator.CloneSlowlyWithStats(reflect.Value{})
+	a.Equal(stats, Stats{})
+}
+
+func TestCompare(t *testing.T) {
+	a := assert.New(t)
+	type node struct {
+		Values []int
+		Next   *node
+	}
+	v := &node{
+		Values: []int{1, 2, 3},
+		Next: &node{
+			Values: []int{4},
+		},
+	}
+	v.Next.Next = v
+
+	region := FromRegion(NewRegion(0))
+	results := CompareSlowly(map[string]*Allocator{
+		"heap":   nil,
+		"region": region,
+	}, v)
+	a.Equal(len(results), 2)
+	a.Equal(results["heap"].Objects, 4)
+	a.Equal(results["heap"].Bytes, results["region"].Bytes)
+	a.Equal(results["heap"].Objects, results["region"].Objects)
+	a.Equal(results["heap"].Nodes, results["region"].Nodes)
+
+	// Nil value is not cloned.
+	results = Compare(map[string]*Allocator{
+		"heap": FromHeap(),
+	}, nil)
+	a.Equal(results, map[string]Stats{
+		"heap": {},
+	})
+}
@@ -16,7 +16,34 @@ import (
 type structType struct {
 	ZeroFields    []structFieldSize
 	PointerFields []structFieldType
+	ParentFields  []structFieldType
+
+	// GenerationFields are integer fields tagged with `clone:"generation"`.
+	GenerationFields []structFieldType
+
+	// FuncFields are fields tagged with `clone:"func=name"`.
+	FuncFields []structFieldFunc
+
+	// InitMethods are indexes of methods of pointer to this struct type
+	// named by fields tagged with `clone:"init=methodName"`.
+	InitMethods []int
+
+	// TrackAncestors is true if any type reachable from this struct type
+	// has fields tagged with `clone:"parent"`.
+	TrackAncestors bool
+
+	// Guard is the lock field to hold while cloning this struct.
+	Guard *structGuard
+
 	fn            Func
+	rebind        RebindFunc
+
+	// validated is true if a validator is registered for this struct type.
+	// Such a struct must be cloned by copyStruct, even if it's a shadow copy, so that it's validated in strict mode.
+	validated bool
+
+	// plan is the precompiled plan to clone this struct type or nil if it cannot be planned.
+	plan *clonePlan
 }
 
 type structFieldSize struct {
@@ -27,6 +54,10 @@ type structFieldSize struct {
 type structFieldType struct {
 	Offset uintptr // The offset from the beginning of the struct.
 	Index  int     // The index of the field.
+	Deep   bool    // The field is tagged with `clone:"deep"`.
+
+	// Pointee is the type referenced by an unsafe.Pointer field set by SetUnsafePointerType.
+	Pointee reflect.Type
 }
 
 var zeroStructType = structType{}
@@ -79,10 +110,6 @@ func init() {
 		new.FieldByName("New").Set(newFn)
 	})
 	SetCustomFunc(reflect.TypeOf(sync.Map{}), func(allocator *Allocator, old, new reflect.Value) {
-		if !old.CanAddr() {
-			return
-		}
-
 		// Clone all values inside sync.Map.
 		oldMap := old.Addr().Interface().(*sync.Map)
 		newMap := new.Addr().Interface().(*sync.Map)
@@ -94,10 +121,6 @@ func init() {
 		})
 	})
 	SetCustomFunc(reflect.TypeOf(atomic.Value{}), func(allocator *Allocator, old, new reflect.Value) {
-		if !old.CanAddr() {
-			return
-		}
-
 		// Clone value inside atomic.Value.
 		oldValue := old.Addr().Interface().(*atomic.Value)
 		newValue := new.Addr().Interface().(*atomic.Value)
@@ -132,25 +155,57 @@ func MarkAsOpaquePointer(t reflect.Type) {
 	defaultAllocator.MarkAsOpaquePointer(t)
 }
 
+// UnmarkAsScalar removes the scalar mark of t in heap allocator.
+//
+// See Allocator.UnmarkAsScalar for more details.
+func UnmarkAsScalar(t reflect.Type) {
+	defaultAllocator.UnmarkAsScalar(t)
+}
+
+// UnmarkAsOpaquePointer removes the opaque pointer mark of t in heap allocator.
+//
+// See Allocator.UnmarkAsOpaquePointer for more details.
+func UnmarkAsOpaquePointer(t reflect.Type) {
+	defaultAllocator.UnmarkAsOpaquePointer(t)
+}
+
 // Func is a custom func to clone value from old to new.
 // The new is a zero value
 // which `new.CanSet()` and `new.CanAddr()` is guaranteed to be true.
+// The `old.CanAddr()` is guaranteed to be true as well.
+// If the original value is not addressable, e.g. a value inside a map or an interface,
+// the old is a shadow copy of it in an addressable temporary value.
 //
 // Func must update the new to return result.
 type Func func(allocator *Allocator, old, new reflect.Value)
 
+// RebindFunc is a func to populate fields tagged with `clone:"rebind"` after cloning.
+// The root is the cloned root value and the owner is a cloned struct value
+// which `owner.CanAddr()` is guaranteed to be true.
+//
+// It's useful to connect a cloned value to something outside the value,
+// e.g. register the clone in a manager or reconnect pointers maintained outside.
+type RebindFunc func(root, owner reflect.Value)
+
 // emptyCloneFunc is used to disable shadow copy.
 // It's useful when cloning sync.Mutex as cloned value must be a zero value.
 func emptyCloneFunc(allocator *Allocator, old, new reflect.Value) {}
 
 // SetCustomFunc sets a custom clone function for type t in heap allocator.
-// If t is not struct or pointer to struct, SetCustomFunc ignores t.
 //
-// If fn is nil, remove the custom clone function for type t.
+// See Allocator.SetCustomFunc for more details.
 func SetCustomFunc(t reflect.Type, fn Func) {
 	defaultAllocator.SetCustomFunc(t, fn)
 }
 
+// SetRebindFunc sets a rebind function for struct type t in heap allocator.
+// If t is not struct or pointer to struct, SetRebindFunc ignores t.
+//
+// See Allocator.SetRebindFunc for more details.
+func SetRebindFunc(t reflect.Type, fn RebindFunc) {
+	defaultAllocator.SetRebindFunc(t, fn)
+}
+
 // Init creates a new value of src.Type() and shadow copies all content from src.
 // If noCustomFunc is set to true, custom clone function will be ignored.
 //
@@ -164,6 +219,15 @@ func (st *structType) Init(allocator *Allocator, src, nv reflect.Value, noCustom
 			src = forceClearROFlag(src)
 		}
 
+		// Values inside maps or interfaces are not addressable.
+		// Copy them to an addressable temporary value,
+		// so that custom funcs can access src through pointers.
+		if !src.CanAddr() {
+			tmp := reflect.New(src.Type()).Elem()
+			tmp.Set(src)
+			src = tmp
+		}
+
 		st.fn(allocator, src, dst)
 		done = true
 		return
@@ -171,12 +235,14 @@ func (st *structType) Init(allocator *Allocator, src, nv reflect.Value, noCustom
 
 	ptr := unsafe.Pointer(nv.Pointer())
 	shadowCopy(src, ptr)
-	done = len(st.PointerFields) == 0
+	done = len(st.PointerFields) == 0 && len(st.ZeroFields) == 0 && len(st.ParentFields) == 0 && len(st.FuncFields) == 0
 	return
 }
 
 func (st *structType) CanShadowCopy() bool {
-	return len(st.PointerFields) == 0 && st.fn == nil
+	return len(st.PointerFields) == 0 && len(st.ZeroFields) == 0 && len(st.ParentFields) == 0 &&
+		len(st.FuncFields) == 0 && len(st.GenerationFields) == 0 && st.fn == nil && st.rebind == nil && st.Guard == nil &&
+		!st.validated
 }
 
 // IsScalar returns true if k should be considered as a scalar type.
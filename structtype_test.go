@@ -31,22 +31,24 @@ func TestMarkAsScalar(t *testing.T) {
 	newCnt := 0
 	a.Use(&oldCnt, &newCnt)
 
-	// Count cache.
-	defaultAllocator.cachedStructTypes.Range(func(key, value interface{}) bool {
-		oldCnt++
-		return true
-	})
+	// Count scalar types.
+	for _, tc := range defaultAllocator.loadConfig().types {
+		if tc.scalar {
+			oldCnt++
+		}
+	}
 
 	// Add 2 valid types.
 	MarkAsScalar(reflect.TypeOf(new(NoPointer)))
 	MarkAsScalar(reflect.TypeOf(new(WithPointer)))
 	MarkAsScalar(reflect.TypeOf(new(int))) // Should be ignored.
 
-	// Count cache against.
-	defaultAllocator.cachedStructTypes.Range(func(key, value interface{}) bool {
-		newCnt++
-		return true
-	})
+	// Count scalar types against.
+	for _, tc := range defaultAllocator.loadConfig().types {
+		if tc.scalar {
+			newCnt++
+		}
+	}
 
 	a.Assert(oldCnt+2 == newCnt)
 
@@ -240,3 +242,96 @@ func TestMarkAsOpaquePointer(t *testing.T) {
 	a.Assert(&opaque != cloned)
 	a.Assert(opaque == *cloned)
 }
+
+func TestCustomFuncWithUnaddressableValue(t *testing.T) {
+	a := assert.New(t)
+
+	type counter struct {
+		N int
+	}
+
+	allocator := FromHeap()
+	allocator.SetCustomFunc(reflect.TypeOf(counter{}), func(allocator *Allocator, old, new reflect.Value) {
+		a.Assert(old.CanAddr())
+
+		oldValue := old.Addr().Interface().(*counter)
+		newValue := new.Addr().Interface().(*counter)
+		newValue.N = oldValue.N + 1
+	})
+
+	// Values inside maps and interfaces are not addressable.
+	m := map[string]counter{
+		"foo": {N: 1},
+	}
+	cloned := allocator.Clone(reflect.ValueOf(m)).Interface().(map[string]counter)
+	a.Equal(cloned["foo"].N, 2)
+
+	values := []interface{}{counter{N: 10}}
+	clonedValues := allocator.Clone(reflect.ValueOf(values)).Interface().([]interface{})
+	a.Equal(clonedValues[0].(counter).N, 11)
+}
+
+type customDuration int64
+type customSet map[string]struct{}
+
+func TestCustomFuncForNamedTypes(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+
+	// Round durations to second.
+	allocator.SetCustomFunc(reflect.TypeOf(customDuration(0)), func(allocator *Allocator, old, new reflect.Value) {
+		new.SetInt(old.Int() / 1000 * 1000)
+	})
+
+	// Drop empty keys in sets.
+	allocator.SetCustomFunc(reflect.TypeOf(customSet{}), func(allocator *Allocator, old, new reflect.Value) {
+		if old.IsNil() {
+			return
+		}
+
+		cloned := allocator.Clone(old)
+		cloned.SetMapIndex(reflect.ValueOf(""), reflect.Value{})
+		new.Set(cloned)
+	})
+
+	type config struct {
+		Timeout   customDuration
+		Durations [2]customDuration
+		Retries   []customDuration
+		Tags      customSet
+		count     int
+	}
+
+	orig := &config{
+		Timeout:   1234,
+		Durations: [2]customDuration{2345, 3456},
+		Retries:   []customDuration{4567},
+		Tags:      customSet{"": {}, "foo": {}},
+		count:     1,
+	}
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*config)
+	a.Equal(cloned, &config{
+		Timeout:   1000,
+		Durations: [2]customDuration{2000, 3000},
+		Retries:   []customDuration{4000},
+		Tags:      customSet{"foo": {}},
+		count:     1,
+	})
+	a.Equal(len(orig.Tags), 2)
+
+	// Values in maps and interfaces.
+	m := map[string]interface{}{"d": customDuration(5678)}
+	a.Equal(allocator.Clone(reflect.ValueOf(m)).Interface(), map[string]interface{}{"d": customDuration(5000)})
+
+	// Heap allocator is not affected.
+	a.Equal(Clone(orig), orig)
+
+	// Unnamed types are ignored.
+	allocator.SetCustomFunc(reflect.TypeOf([]int{}), func(allocator *Allocator, old, new reflect.Value) {})
+	a.Equal(allocator.Clone(reflect.ValueOf([]int{1})).Interface(), []int{1})
+
+	// Remove custom func.
+	allocator.SetCustomFunc(reflect.TypeOf(customDuration(0)), nil)
+	cloned = allocator.Clone(reflect.ValueOf(orig)).Interface().(*config)
+	a.Equal(cloned.Timeout, orig.Timeout)
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// syncPools is the pool of allocators created by FromSyncPools.
+type syncPools struct {
+	pools map[reflect.Type]*sync.Pool
+}
+
+// FromSyncPools creates an allocator which allocates values of types in pools from their sync.Pool.
+// Values of other types, slices, maps and chans are allocated in heap.
+//
+// The New func of every pool must return a pointer to a new value of the related type.
+// A value got from a pool is zeroed before it's used by clone.
+// If the pool returns nil or a value of any other type, the value is allocated in heap instead.
+//
+// A finalizer is set on each value got from pools to put the value back to its pool
+// when the value is unreachable. The finalizer is cleared when the value is freed
+// in transactional mode, and the value is put back to its pool immediately.
+// It's safe to put cloned values back to pools manually.
+// See Allocator.SetTransactional for details.
+//
+// The pools is copied, so changing it after calling FromSyncPools doesn't affect the allocator.
+func FromSyncPools(pools map[reflect.Type]*sync.Pool) *Allocator {
+	sp := &syncPools{
+		pools: make(map[reflect.Type]*sync.Pool, len(pools)),
+	}
+
+	for t, p := range pools {
+		if p != nil {
+			sp.pools[t] = p
+		}
+	}
+
+	return NewAllocator(unsafe.Pointer(sp), &AllocatorMethods{
+		New: func(pool unsafe.Pointer, t reflect.Type) reflect.Value {
+			return (*syncPools)(pool).new(t)
+		},
+		Free: func(pool unsafe.Pointer, v reflect.Value) {
+			(*syncPools)(pool).free(v)
+		},
+	})
+}
+
+func (sp *syncPools) new(t reflect.Type) reflect.Value {
+	p := sp.pools[t]
+
+	if p == nil {
+		return reflect.New(t)
+	}
+
+	x := p.Get()
+
+	if x == nil || reflect.TypeOf(x) != reflect.PtrTo(t) {
+		return reflect.New(t)
+	}
+
+	v := reflect.ValueOf(x)
+
+	if v.IsNil() {
+		return reflect.New(t)
+	}
+
+	v.Elem().Set(reflect.Zero(t))
+
+	// The value may be put back to pool by caller with its finalizer set.
+	// Setting a finalizer twice is a fatal error.
+	runtime.SetFinalizer(x, nil)
+	runtime.SetFinalizer(x, func(x interface{}) {
+		p.Put(x)
+	})
+	return v
+}
+
+func (sp *syncPools) free(v reflect.Value) {
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return
+	}
+
+	p := sp.pools[v.Type().Elem()]
+
+	if p == nil {
+		return
+	}
+
+	x := v.Interface()
+	runtime.SetFinalizer(x, nil)
+	p.Put(x)
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type syncPoolNode struct {
+	Value int
+	Next  *syncPoolNode
+}
+
+func TestFromSyncPools(t *testing.T) {
+	a := assert.New(t)
+	created := 0
+	pools := map[reflect.Type]*sync.Pool{
+		reflect.TypeOf(syncPoolNode{}): {
+			New: func() interface{} {
+				created++
+				return &syncPoolNode{Value: -1}
+			},
+		},
+		reflect.TypeOf(0): {
+			New: func() interface{} {
+				return "not an *int"
+			},
+		},
+		reflect.TypeOf(""): nil,
+	}
+	allocator := FromSyncPools(pools)
+	delete(pools, reflect.TypeOf(syncPoolNode{}))
+
+	list := &syncPoolNode{
+		Value: 1,
+		Next: &syncPoolNode{
+			Value: 2,
+		},
+	}
+	cloned := allocator.Clone(reflect.ValueOf(list)).Interface().(*syncPoolNode)
+	a.Equal(cloned, list)
+	a.Equal(created, 2)
+
+	// Pools returning values of wrong types are ignored.
+	p := allocator.New(reflect.TypeOf(0)).Interface().(*int)
+	a.Equal(*p, 0)
+
+	s := allocator.New(reflect.TypeOf("")).Interface().(*string)
+	a.Equal(*s, "")
+}
+
+func TestFromSyncPoolsTransactional(t *testing.T) {
+	a := assert.New(t)
+	typ := reflect.TypeOf(syncPoolNode{})
+	var got []*syncPoolNode
+	pool := &sync.Pool{
+		New: func() interface{} {
+			n := &syncPoolNode{}
+			got = append(got, n)
+			return n
+		},
+	}
+	allocator := FromSyncPools(map[reflect.Type]*sync.Pool{
+		typ: pool,
+	})
+	allocator.SetTransactional(true)
+	errFailed := errors.New("failed")
+	allocator.SetCustomFunc(reflect.TypeOf(syncPoolNode{}), func(allocator *Allocator, old, new reflect.Value) {
+		if old.FieldByName("Value").Int() == 2 {
+			panic(errFailed)
+		}
+
+		new.Set(old)
+	})
+
+	list := &syncPoolNode{
+		Value: 1,
+		Next: &syncPoolNode{
+			Value: 2,
+		},
+	}
+	a.Assert(func() (ok bool) {
+		defer func() {
+			ok = recover() != nil
+		}()
+
+		allocator.Clone(reflect.ValueOf([]*syncPoolNode{list, list.Next}))
+		return
+	}())
+	a.Assert(len(got) != 0)
+
+	// Freed value is put back to pool and zeroed before reuse.
+	v := allocator.New(typ).Interface().(*syncPoolNode)
+	a.Equal(*v, syncPoolNode{})
+	a.Equal(len(got), 2)
+	a.Assert(v == got[0] || v == got[1])
+}
+
+func TestFromSyncPoolsPutManually(t *testing.T) {
+	a := assert.New(t)
+	typ := reflect.TypeOf(syncPoolNode{})
+	pool := &sync.Pool{
+		New: func() interface{} {
+			return &syncPoolNode{}
+		},
+	}
+	allocator := FromSyncPools(map[reflect.Type]*sync.Pool{
+		typ: pool,
+	})
+	node := &syncPoolNode{
+		Value: 1,
+	}
+
+	// Values put back to pool by caller still have finalizers.
+	for i := 0; i < 10; i++ {
+		cloned := allocator.Clone(reflect.ValueOf(node)).Interface().(*syncPoolNode)
+		a.Equal(cloned, node)
+		pool.Put(cloned)
+	}
+}
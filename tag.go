@@ -0,0 +1,164 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Struct tag name and verbs recognized by this package.
+// Verbs TagInit and TagFunc require an argument, e.g. `clone:"init=methodName"` and `clone:"func=name"`.
+//
+// A tag on an embedded field applies to the whole embedded struct including all promoted fields,
+// e.g. `clone:"opaque"` on an embedded field shares the embedded struct or pointer as it is.
+// If fields of the embedded struct have their own tags, the tag on the embedded field wins,
+// as the embedded struct is not cloned field by field.
+// The only exception is TagDeep, which changes how strings and scalar types are copied in the subtree
+// and keeps tags of fields in the embedded struct.
+const (
+	TagName       = "clone"
+	TagSkip       = "skip"
+	TagSkipAlias  = "-"
+	TagZero       = "zero"
+	TagShadowCopy = "shadowcopy"
+	TagOpaque     = "opaque"
+	TagRebind     = "rebind"
+	TagParent     = "parent"
+	TagGeneration = "generation"
+	TagDeep       = "deep"
+	TagInit       = "init"
+	TagFunc       = "func"
+)
+
+// FieldAction is how a struct field is cloned according to its `clone:` tag.
+type FieldAction int
+
+// All actions of struct fields.
+const (
+	FieldDeepClone  FieldAction = iota // Field is not tagged and cloned in depth as usual.
+	FieldSkip                          // Field is tagged with TagSkip or TagSkipAlias and is zero in clones.
+	FieldZero                          // Field is tagged with TagZero and is zero in clones.
+	FieldShadowCopy                    // Field is tagged with TagShadowCopy or TagOpaque and is shadow copied.
+	FieldRebind                        // Field is tagged with TagRebind and is zero in clones until rebound.
+	FieldParent                        // Field is tagged with TagParent and points to the parent struct in clones.
+	FieldGeneration                    // Field is tagged with TagGeneration and is increased in clones.
+	FieldDeep                          // Field is tagged with TagDeep and is copied in depth including strings.
+	FieldInit                          // Field is tagged with TagInit and populated by the init method in clones.
+	FieldFunc                          // Field is tagged with TagFunc and cloned by the func registered by RegisterNamedFunc.
+	FieldHandler                       // Field is tagged with a verb which can be handled by a TagHandler.
+)
+
+var fieldActionNames = [...]string{
+	FieldDeepClone:  "deepclone",
+	FieldSkip:       "skip",
+	FieldZero:       "zero",
+	FieldShadowCopy: "shadowcopy",
+	FieldRebind:     "rebind",
+	FieldParent:     "parent",
+	FieldGeneration: "generation",
+	FieldDeep:       "deep",
+	FieldInit:       "init",
+	FieldFunc:       "func",
+	FieldHandler:    "handler",
+}
+
+// String returns the name of action.
+func (action FieldAction) String() string {
+	if action >= 0 && int(action) < len(fieldActionNames) {
+		return fieldActionNames[action]
+	}
+
+	return fmt.Sprintf("FieldAction(%d)", int(action))
+}
+
+// FieldPolicy is the policy of a struct field parsed from its `clone:` tag.
+type FieldPolicy struct {
+	Action FieldAction
+
+	// Verb is the tag verb, e.g. "skip" or "init".
+	// It's empty if the field is not tagged.
+	Verb string
+
+	// Arg is the text after "=" in the tag, e.g. the method name in `clone:"init=methodName"`.
+	// It's empty if the tag doesn't have an argument.
+	Arg string
+}
+
+// ParseFieldTag parses the `clone:` tag in tag in the same way as clone methods do.
+// It's designed for code generators and linters to interpret tags consistently with this package.
+//
+// Verbs not handled by this package are parsed as FieldHandler,
+// as they can be handled by a TagHandler registered by RegisterTagHandler.
+// Fields tagged with these verbs are cloned as usual if no handler is registered.
+//
+// Some actions depend on field types, which are not checked by ParseFieldTag.
+// FieldParent applies to pointer fields only, and FieldGeneration applies to integer fields only.
+// Fields of other types are cloned as usual.
+//
+// ParseFieldTag returns an error if the tag is malformed,
+// e.g. a verb is empty, TagInit or TagFunc doesn't have an argument or other builtin verbs have an argument.
+func ParseFieldTag(tag reflect.StructTag) (policy FieldPolicy, err error) {
+	value, ok := tag.Lookup(TagName)
+
+	if !ok || value == "" {
+		return
+	}
+
+	verb := value
+	arg := ""
+	hasArg := false
+
+	if i := strings.IndexByte(value, '='); i >= 0 {
+		verb = value[:i]
+		arg = value[i+1:]
+		hasArg = true
+	}
+
+	if verb == "" {
+		err = fmt.Errorf("go-clone: tag verb is empty in `%v`", value)
+		return
+	}
+
+	policy.Verb = verb
+	policy.Arg = arg
+
+	switch verb {
+	case TagSkip, TagSkipAlias:
+		policy.Action = FieldSkip
+	case TagZero:
+		policy.Action = FieldZero
+	case TagShadowCopy, TagOpaque:
+		policy.Action = FieldShadowCopy
+	case TagRebind:
+		policy.Action = FieldRebind
+	case TagParent:
+		policy.Action = FieldParent
+	case TagGeneration:
+		policy.Action = FieldGeneration
+	case TagDeep:
+		policy.Action = FieldDeep
+	case TagInit:
+		policy.Action = FieldInit
+	case TagFunc:
+		policy.Action = FieldFunc
+	default:
+		policy.Action = FieldHandler
+		return
+	}
+
+	switch policy.Action {
+	case FieldInit, FieldFunc:
+		if arg == "" {
+			err = fmt.Errorf("go-clone: tag verb `%v` requires an argument in `%v`", verb, value)
+		}
+	default:
+		if hasArg {
+			err = fmt.Errorf("go-clone: tag verb `%v` doesn't take any argument in `%v`", verb, value)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestParseFieldTag(t *testing.T) {
+	a := assert.New(t)
+	cases := []struct {
+		tag    reflect.StructTag
+		policy FieldPolicy
+		failed bool
+	}{
+		{``, FieldPolicy{}, false},
+		{`json:"name"`, FieldPolicy{}, false},
+		{`clone:""`, FieldPolicy{}, false},
+		{`clone:"skip"`, FieldPolicy{Action: FieldSkip, Verb: TagSkip}, false},
+		{`clone:"-"`, FieldPolicy{Action: FieldSkip, Verb: TagSkipAlias}, false},
+		{`clone:"zero"`, FieldPolicy{Action: FieldZero, Verb: TagZero}, false},
+		{`clone:"shadowcopy"`, FieldPolicy{Action: FieldShadowCopy, Verb: TagShadowCopy}, false},
+		{`clone:"opaque"`, FieldPolicy{Action: FieldShadowCopy, Verb: TagOpaque}, false},
+		{`clone:"rebind"`, FieldPolicy{Action: FieldRebind, Verb: TagRebind}, false},
+		{`clone:"parent"`, FieldPolicy{Action: FieldParent, Verb: TagParent}, false},
+		{`clone:"generation"`, FieldPolicy{Action: FieldGeneration, Verb: TagGeneration}, false},
+		{`clone:"deep"`, FieldPolicy{Action: FieldDeep, Verb: TagDeep}, false},
+		{`clone:"init=Build"`, FieldPolicy{Action: FieldInit, Verb: TagInit, Arg: "Build"}, false},
+		{`clone:"func=redact"`, FieldPolicy{Action: FieldFunc, Verb: TagFunc, Arg: "redact"}, false},
+		{`clone:"trim=64"`, FieldPolicy{Action: FieldHandler, Verb: "trim", Arg: "64"}, false},
+		{`clone:"intern"`, FieldPolicy{Action: FieldHandler, Verb: "intern"}, false},
+		{`clone:"init="`, FieldPolicy{Action: FieldInit, Verb: TagInit}, true},
+		{`clone:"func"`, FieldPolicy{Action: FieldFunc, Verb: TagFunc}, true},
+		{`clone:"skip=1"`, FieldPolicy{Action: FieldSkip, Verb: TagSkip, Arg: "1"}, true},
+		{`clone:"=1"`, FieldPolicy{}, true},
+	}
+
+	for _, c := range cases {
+		policy, err := ParseFieldTag(c.tag)
+		a.Use(&c, &policy, &err)
+		a.Equal(policy, c.policy)
+		a.Equal(err != nil, c.failed)
+	}
+
+	a.Equal(FieldInit.String(), "init")
+	a.Equal(FieldAction(100).String(), "FieldAction(100)")
+}
+
+func TestParseFieldTagConsistency(t *testing.T) {
+	a := assert.New(t)
+	type T struct {
+		Skipped []int `clone:"skip"`
+		Zeroed  []int `clone:"zero"`
+		Shared  []int `clone:"shadowcopy"`
+		Cloned  []int
+	}
+	orig := &T{
+		Skipped: []int{1},
+		Zeroed:  []int{2},
+		Shared:  []int{3},
+		Cloned:  []int{4},
+	}
+	cloned := Clone(orig).(*T)
+	v := reflect.ValueOf(orig).Elem()
+	nv := reflect.ValueOf(cloned).Elem()
+
+	for i := 0; i < v.NumField(); i++ {
+		policy, err := ParseFieldTag(v.Type().Field(i).Tag)
+		a.NilError(err)
+		a.Use(&i, &policy)
+
+		switch policy.Action {
+		case FieldSkip, FieldZero:
+			a.Assert(nv.Field(i).IsNil())
+		case FieldShadowCopy:
+			a.Equal(nv.Field(i).Pointer(), v.Field(i).Pointer())
+		default:
+			a.Assert(nv.Field(i).Pointer() != v.Field(i).Pointer())
+			a.Equal(nv.Field(i).Interface(), v.Field(i).Interface())
+		}
+	}
+}
+
+type embeddedBase struct {
+	List   []int
+	Skip   []int `clone:"skip"`
+	Shared []int `clone:"shadowcopy"`
+}
+
+func TestEmbeddedFieldTags(t *testing.T) {
+	a := assert.New(t)
+	type Opaque struct {
+		embeddedBase `clone:"opaque"`
+	}
+	type OpaquePtr struct {
+		*embeddedBase `clone:"opaque"`
+	}
+	type Zero struct {
+		embeddedBase `clone:"zero"`
+	}
+	type Deep struct {
+		embeddedBase `clone:"deep"`
+	}
+	base := embeddedBase{
+		List:   []int{1},
+		Skip:   []int{2},
+		Shared: []int{3},
+	}
+
+	// Tags on embedded fields win tags of promoted fields.
+	opaque := Clone(&Opaque{base}).(*Opaque)
+	a.Equal(opaque.embeddedBase, base)
+	a.Assert(&opaque.List[0] == &base.List[0])
+
+	opaquePtr := &OpaquePtr{&base}
+	a.Assert(Clone(opaquePtr).(*OpaquePtr).embeddedBase == &base)
+
+	zero := Clone(&Zero{base}).(*Zero)
+	a.Equal(zero.embeddedBase, embeddedBase{})
+
+	// Tags of promoted fields are kept in deep clones.
+	deep := Clone(&Deep{base}).(*Deep)
+	a.Equal(deep.List, base.List)
+	a.Assert(&deep.List[0] != &base.List[0])
+	a.Assert(deep.Skip == nil)
+	a.Assert(&deep.Shared[0] == &base.Shared[0])
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// tagFunc wraps a func registered by RegisterNamedFunc so that configs can tell whether it's changed.
+type tagFunc struct {
+	fn Func
+}
+
+// structFieldFunc is a field tagged with `clone:"func=name"`.
+type structFieldFunc struct {
+	Offset uintptr // The offset from the beginning of the struct.
+	Index  int     // The index of the field.
+	Func   Func    // The func registered with the name.
+}
+
+// RegisterNamedFunc registers fn with name in heap allocator.
+//
+// See Allocator.RegisterNamedFunc for more details.
+func RegisterNamedFunc(name string, fn Func) {
+	defaultAllocator.RegisterNamedFunc(name, fn)
+}
+
+// RegisterNamedFunc registers fn with name in a,
+// so that fn clones all struct fields tagged with `clone:"func=name"`.
+// If fn is nil, remove the func registered with name in a.
+// If name is not registered, a inherits it from parent allocator.
+//
+// The fn works in the same way as a custom func set by SetCustomFunc,
+// except that the old and the new are values of a field rather than values of a type.
+// It's designed to attach the same behavior to many fields across many structs declaratively,
+// e.g. a func to redact secrets.
+//
+//	clone.RegisterNamedFunc("redactSecret", func(allocator *clone.Allocator, old, new reflect.Value) {
+//	    new.SetString("<redacted>")
+//	})
+//
+//	type Credential struct {
+//	    User     string
+//	    Password string `clone:"func=redactSecret"`
+//	}
+//
+// Cloning a struct with a field tagged with a name not registered panics.
+func (a *Allocator) RegisterNamedFunc(name string, fn Func) {
+	var tf *tagFunc
+
+	if fn != nil {
+		tf = &tagFunc{
+			fn: fn,
+		}
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		tagFuncs := make(map[string]*tagFunc, len(cfg.tagFuncs)+1)
+
+		for k, v := range cfg.tagFuncs {
+			tagFuncs[k] = v
+		}
+
+		// Removing the func in a makes a inherit the func registered in parent allocator.
+		if tf == nil {
+			delete(tagFuncs, name)
+		} else {
+			tagFuncs[name] = tf
+		}
+
+		copied.tagFuncs = tagFuncs
+		return copied
+	})
+}
+
+// parseFuncTag returns the func name in tag `clone:"func=name"`.
+func parseFuncTag(tag string) (name string, ok bool) {
+	if !strings.HasPrefix(tag, fieldTagValueFuncPrefix) {
+		return
+	}
+
+	name = tag[len(fieldTagValueFuncPrefix):]
+	ok = true
+	return
+}
+
+// lookupTagFunc returns the nearest func registered with name.
+// It panics if name is not registered, as the struct type t cannot be cloned as expected.
+func (cfg *config) lookupTagFunc(t reflect.Type, name string) Func {
+	for current := cfg; current != nil; current = current.parent {
+		if tf, ok := current.tagFuncs[name]; ok {
+			return tf.fn
+		}
+	}
+
+	panic(fmt.Errorf("go-clone: func `%v` used by `%v` is not registered", name, t))
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type tagFuncCredential struct {
+	User     string
+	Password string `clone:"func=redactSecret"`
+	Token    []byte `clone:"func=redactSecret"`
+	Note     *string
+}
+
+type tagFuncConfig struct {
+	Creds  []tagFuncCredential
+	APIKey string `clone:"func=redactSecret"`
+	Upper  string `clone:"func=upper"`
+}
+
+func redactSecret(allocator *Allocator, old, new reflect.Value) {
+	switch new.Kind() {
+	case reflect.String:
+		new.SetString("<redacted>")
+	case reflect.Slice:
+		new.Set(reflect.MakeSlice(new.Type(), 0, 0))
+	}
+}
+
+func TestRegisterNamedFunc(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.RegisterNamedFunc("redactSecret", redactSecret)
+	allocator.RegisterNamedFunc("upper", func(allocator *Allocator, old, new reflect.Value) {
+		new.SetString(strings.ToUpper(old.String()))
+	})
+
+	note := "note"
+	orig := &tagFuncConfig{
+		Creds: []tagFuncCredential{
+			{
+				User:     "foo",
+				Password: "secret",
+				Token:    []byte("token"),
+				Note:     &note,
+			},
+		},
+		APIKey: "key",
+		Upper:  "upper",
+	}
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*tagFuncConfig)
+	a.Equal(cloned, &tagFuncConfig{
+		Creds: []tagFuncCredential{
+			{
+				User:     "foo",
+				Password: "<redacted>",
+				Token:    []byte{},
+				Note:     &note,
+			},
+		},
+		APIKey: "<redacted>",
+		Upper:  "UPPER",
+	})
+	a.Assert(cloned.Creds[0].Note != orig.Creds[0].Note)
+	a.Equal(orig.Creds[0].Password, "secret")
+
+	// Values in maps are not addressable.
+	m := map[string]tagFuncCredential{
+		"a": orig.Creds[0],
+	}
+	clonedMap := allocator.Clone(reflect.ValueOf(m)).Interface().(map[string]tagFuncCredential)
+	a.Equal(clonedMap["a"].Password, "<redacted>")
+
+	// Child inherits named funcs.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	child.RegisterNamedFunc("upper", func(allocator *Allocator, old, new reflect.Value) {
+		new.SetString(strings.ToLower(old.String()))
+	})
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().(*tagFuncConfig)
+	a.Equal(cloned.APIKey, "<redacted>")
+	a.Equal(cloned.Upper, "upper")
+
+	// Removing the override in child falls back to the func in parent.
+	child.RegisterNamedFunc("upper", nil)
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().(*tagFuncConfig)
+	a.Equal(cloned.Upper, "UPPER")
+	child.RegisterNamedFunc("redactSecret", nil)
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().(*tagFuncConfig)
+	a.Equal(cloned.APIKey, "<redacted>")
+}
+
+func TestRegisterNamedFuncNotFound(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.RegisterNamedFunc("redactSecret", redactSecret)
+	allocator.RegisterNamedFunc("upper", nil)
+
+	_, err := allocator.TryClone(reflect.ValueOf(&tagFuncConfig{}))
+	a.Assert(err != nil)
+	a.Equal(err.(*PanicError).Value.(error).Error(), "go-clone: func `upper` used by `clone.tagFuncConfig` is not registered")
+}
+
+func TestNamedFuncRegistration(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.RegisterNamedFunc("upper", emptyCloneFunc)
+	scope := allocator.Register(func(allocator *Allocator) {
+		allocator.RegisterNamedFunc("redactSecret", redactSecret)
+		allocator.RegisterNamedFunc("upper", nil)
+	})
+	a.Equal(len(allocator.loadConfig().tagFuncs), 1)
+
+	exported := allocator.ExportConfig()
+	scope.Close()
+	tagFuncs := allocator.loadConfig().tagFuncs
+	a.Equal(len(tagFuncs), 1)
+	a.Assert(tagFuncs["upper"] != nil)
+
+	other := FromHeap()
+	other.ApplyConfig(exported)
+	cred := &tagFuncCredential{Password: "secret"}
+	a.Equal(other.Clone(reflect.ValueOf(cred)).Interface().(*tagFuncCredential).Password, "<redacted>")
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TagHandler handles struct fields tagged with a tag verb registered by RegisterTagHandler.
+// The field is the struct field tagged with `clone:"verb"` or `clone:"verb=arg"`,
+// and the arg is the text after "=" in the tag or an empty string.
+//
+// A TagHandler is called once for every tagged field when a struct type is analyzed.
+// It returns a func to clone the field, which works in the same way as a func registered by RegisterNamedFunc.
+// If it returns nil, the field is cloned as if it's not tagged.
+type TagHandler func(field reflect.StructField, arg string) Func
+
+// tagHandler wraps a TagHandler so that configs can tell whether it's changed.
+type tagHandler struct {
+	handler TagHandler
+}
+
+// builtinTagVerbs are tag verbs handled by this package.
+var builtinTagVerbs = map[string]struct{}{
+	fieldTagValueSkip:       {},
+	fieldTagValueSkipAlias:  {},
+	fieldTagValueZero:       {},
+	fieldTagValueShadowCopy: {},
+	fieldTagValueOpaque:     {},
+	fieldTagValueRebind:     {},
+	fieldTagValueParent:     {},
+	fieldTagValueGeneration: {},
+	fieldTagValueDeep:       {},
+	TagInit:                 {},
+	TagFunc:                 {},
+}
+
+// RegisterTagHandler registers a handler of tag verb name in heap allocator.
+//
+// See Allocator.RegisterTagHandler for more details.
+func RegisterTagHandler(name string, handler TagHandler) {
+	defaultAllocator.RegisterTagHandler(name, handler)
+}
+
+// RegisterTagHandler registers a handler of tag verb name in a,
+// so that struct fields tagged with `clone:"name"` or `clone:"name=arg"` are cloned by funcs returned by handler.
+// If handler is nil, remove the handler registered with name in a.
+// If name is not registered, a inherits it from parent allocator.
+// Fields tagged with verbs not registered are cloned as usual.
+//
+// It's designed to define new tag verbs, e.g. `clone:"intern"` or `clone:"trim=64"`.
+// The name must not contain "=" and must not be a verb handled by this package, e.g. "skip" or "init".
+// Otherwise, RegisterTagHandler panics.
+//
+//	clone.RegisterTagHandler("trim", func(field reflect.StructField, arg string) clone.Func {
+//	    n, _ := strconv.Atoi(arg)
+//
+//	    return func(allocator *clone.Allocator, old, new reflect.Value) {
+//	        if s := old.String(); len(s) > n {
+//	            new.SetString(s[:n])
+//	        } else {
+//	            new.SetString(s)
+//	        }
+//	    }
+//	})
+func (a *Allocator) RegisterTagHandler(name string, handler TagHandler) {
+	if name == "" || strings.Contains(name, "=") {
+		panic(fmt.Errorf("go-clone: invalid tag verb `%v`", name))
+	}
+
+	if _, ok := builtinTagVerbs[name]; ok {
+		panic(fmt.Errorf("go-clone: tag verb `%v` is handled by go-clone", name))
+	}
+
+	var th *tagHandler
+
+	if handler != nil {
+		th = &tagHandler{
+			handler: handler,
+		}
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		tagHandlers := make(map[string]*tagHandler, len(cfg.tagHandlers)+1)
+
+		for k, v := range cfg.tagHandlers {
+			tagHandlers[k] = v
+		}
+
+		tagHandlers[name] = th
+		copied.tagHandlers = tagHandlers
+		return copied
+	})
+}
+
+// handleTag returns the func made by the nearest handler of the verb in tag of field.
+// It returns nil if the verb is not registered.
+func (cfg *config) handleTag(field reflect.StructField, tag string) Func {
+	name := tag
+	arg := ""
+
+	if i := strings.IndexByte(tag, '='); i >= 0 {
+		name = tag[:i]
+		arg = tag[i+1:]
+	}
+
+	for current := cfg; current != nil; current = current.parent {
+		if th, ok := current.tagHandlers[name]; ok {
+			if th == nil {
+				return nil
+			}
+
+			return th.handler(field, arg)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type tagHandlerData struct {
+	Title   string   `clone:"trim=3"`
+	Summary string   `clone:"trim"`
+	Names   []string `clone:"intern"`
+	Count   int      `clone:"trim=3"`
+	Other   string   `clone:"unknown"`
+}
+
+func trimHandler(field reflect.StructField, arg string) Func {
+	if field.Type.Kind() != reflect.String {
+		return nil
+	}
+
+	n, err := strconv.Atoi(arg)
+
+	if err != nil {
+		n = 1
+	}
+
+	return func(allocator *Allocator, old, new reflect.Value) {
+		if s := old.String(); len(s) > n {
+			new.SetString(s[:n])
+		} else {
+			new.SetString(s)
+		}
+	}
+}
+
+func TestRegisterTagHandler(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	var fields []string
+	allocator.RegisterTagHandler("trim", func(field reflect.StructField, arg string) Func {
+		fields = append(fields, field.Name+"="+arg)
+		return trimHandler(field, arg)
+	})
+	interned := map[string]string{}
+	allocator.RegisterTagHandler("intern", func(field reflect.StructField, arg string) Func {
+		return func(allocator *Allocator, old, new reflect.Value) {
+			names := make([]string, old.Len())
+
+			for i := range names {
+				s := old.Index(i).String()
+
+				if v, ok := interned[s]; ok {
+					s = v
+				} else {
+					interned[s] = s
+				}
+
+				names[i] = s
+			}
+
+			new.Set(reflect.ValueOf(names))
+		}
+	})
+
+	orig := &tagHandlerData{
+		Title:   "foobar",
+		Summary: "summary",
+		Names:   []string{"a", "b"},
+		Count:   12345,
+		Other:   "other",
+	}
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*tagHandlerData)
+	a.Equal(cloned, &tagHandlerData{
+		Title:   "foo",
+		Summary: "s",
+		Names:   []string{"a", "b"},
+		Count:   12345,
+		Other:   "other",
+	})
+	a.Equal(len(interned), 2)
+
+	// Handlers are called once when analyzing the struct type.
+	allocator.Clone(reflect.ValueOf(orig))
+	a.Equal(fields, []string{"Title=3", "Summary=", "Count=3"})
+
+	// Child removes a handler.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	child.RegisterTagHandler("trim", nil)
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().(*tagHandlerData)
+	a.Equal(cloned.Title, "foobar")
+	a.Equal(cloned.Names, orig.Names)
+}
+
+func TestRegisterTagHandlerInvalidName(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+
+	for _, name := range []string{"", "a=b", "skip", "-", "shadowcopy", "init", "func", "deep"} {
+		a.Assert(func() (ok bool) {
+			defer func() {
+				ok = recover() != nil
+			}()
+			allocator.RegisterTagHandler(name, trimHandler)
+			return
+		}())
+	}
+
+	a.Equal(len(allocator.loadConfig().tagHandlers), 0)
+}
+
+func TestTagHandlerRegistration(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	scope := allocator.Register(func(allocator *Allocator) {
+		allocator.RegisterTagHandler("trim", trimHandler)
+	})
+	orig := &tagHandlerData{
+		Title: "foobar",
+	}
+	a.Equal(allocator.Clone(reflect.ValueOf(orig)).Interface().(*tagHandlerData).Title, "foo")
+
+	exported := allocator.ExportConfig()
+	scope.Close()
+	a.Equal(allocator.Clone(reflect.ValueOf(orig)).Interface(), orig)
+
+	other := FromHeap()
+	other.ApplyConfig(exported)
+	a.Equal(other.Clone(reflect.ValueOf(orig)).Interface().(*tagHandlerData).Title, "foo")
+}
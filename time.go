@@ -0,0 +1,24 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"time"
+)
+
+// StripMonotonic is a custom func for time.Time to strip the monotonic clock reading
+// from cloned time values, so that cloned values are the same as values
+// decoded from any serialization format.
+// The wall clock reading and the location are copied as they are.
+//
+// As time.Time is marked as scalar in heap allocator,
+// StripMonotonic must be set in a new allocator like following.
+//
+//	allocator := clone.NewAllocator(nil, nil)
+//	allocator.SetCustomFunc(reflect.TypeOf(time.Time{}), clone.StripMonotonic)
+func StripMonotonic(allocator *Allocator, old, new reflect.Value) {
+	t := old.Interface().(time.Time)
+	new.Set(reflect.ValueOf(t.Round(0)))
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestStripMonotonic(t *testing.T) {
+	a := assert.New(t)
+
+	type event struct {
+		At   time.Time
+		Name string
+	}
+
+	// time.Now() contains monotonic clock reading which is printed as "m=...".
+	now := time.Now()
+	orig := &event{
+		At:   now,
+		Name: "foo",
+	}
+	a.Assert(strings.Contains(Clone(orig).(*event).At.String(), "m="))
+
+	allocator := NewAllocator(nil, nil)
+	allocator.SetCustomFunc(reflect.TypeOf(time.Time{}), StripMonotonic)
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*event)
+	a.Assert(!strings.Contains(cloned.At.String(), "m="))
+	a.Assert(cloned.At.Equal(now))
+	a.Assert(cloned.At.Location() == now.Location())
+	a.Equal(cloned.Name, orig.Name)
+
+	times := []time.Time{now}
+	clonedTimes := allocator.Clone(reflect.ValueOf(times)).Interface().([]time.Time)
+	a.Equal(clonedTimes[0], now.Round(0))
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/huandu/go-clone/walk"
+)
+
+// TraceHooks are hooks called around values cloned by an allocator.
+// Both hooks are optional.
+type TraceHooks struct {
+	// OnEnter is called with a value before it's cloned.
+	OnEnter func(path string, v reflect.Value)
+
+	// OnLeave is called with a value and its clone after it's cloned.
+	OnLeave func(path string, v, cloned reflect.Value)
+}
+
+type traceHooksOption struct {
+	hooks *TraceHooks
+}
+
+// SetTraceHooks sets trace hooks of clones made by heap allocator.
+//
+// See Allocator.SetTraceHooks for more details.
+func SetTraceHooks(hooks *TraceHooks) {
+	defaultAllocator.SetTraceHooks(hooks)
+}
+
+// SetTraceHooks sets hooks to be called around values cloned by clone methods in a,
+// e.g. to log clones, audit values of sensitive types or build a heat map of cloned types.
+// If hooks is nil, remove trace hooks in a.
+// If trace hooks are not set, a inherits them from parent allocator.
+//
+// Hooks are called with every value cloned separately, i.e. values counted in Stats.Nodes,
+// including the root value, values pointed by pointers or held by interfaces,
+// and non-scalar fields, elements, keys and values in maps.
+// Scalar values copied together with their owners are not traced.
+// The path of a value is written in Go selector syntax relative to root like `.Foo[2]["key"]`,
+// in the same way as package walk.
+// A pointed value or a value held by an interface has the same path as its owner,
+// and a map key has the same path as its entry.
+//
+// Values cloned by custom funcs with allocator methods, e.g. Allocator.Clone, are in new clones.
+// Their paths start from the root path "".
+// Values pointed by too deep pointers are cloned in the work stack after their owners,
+// so that a value may not be left before hooks enter values after it.
+//
+// Hooks must not modify values.
+// Tracing disables clone plans and slows down clones. Don't set hooks in production unless necessary.
+func (a *Allocator) SetTraceHooks(hooks *TraceHooks) {
+	var opt *traceHooksOption
+
+	if hooks != nil {
+		copied := *hooks
+		opt = &traceHooksOption{
+			hooks: &copied,
+		}
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.traceHooks = opt
+		return copied
+	})
+}
+
+// lookupTraceHooks returns the nearest trace hooks or nil if clone is not traced.
+func (cfg *config) lookupTraceHooks() *TraceHooks {
+	for current := cfg; current != nil; current = current.parent {
+		if current.traceHooks != nil {
+			return current.traceHooks.hooks
+		}
+	}
+
+	return nil
+}
+
+// cloneAndTrace clones v and calls trace hooks around it.
+func (state *cloneState) cloneAndTrace(v reflect.Value) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+
+	trace := state.trace
+	path := state.path
+
+	// Hooks can read unexported fields.
+	// The v itself is left as it is, as some clone methods compare it with values they know.
+	// In read-only memory mode, hooks read a copy of v, so that v is never written by hooks.
+	readable := v
+
+	if state.readOnlyMem && readable.CanAddr() {
+		readable = detachValue(readable)
+	} else if !readable.CanInterface() {
+		readable = forceClearROFlag(readable)
+	}
+
+	if trace.OnEnter != nil {
+		trace.OnEnter(path, readable)
+	}
+
+	var cloned reflect.Value
+
+	if state.stats != nil || state.maxDepth > 0 || state.autoDepth > 0 {
+		cloned = state.cloneAndCount(v)
+	} else {
+		cloned = state.cloneValue(v)
+	}
+
+	if trace.OnLeave != nil {
+		trace.OnLeave(path, readable, cloned)
+	}
+
+	return cloned
+}
+
+// traceField sets the path of the value being cloned to the field of the struct at path.
+// It does nothing if clone is not traced.
+func (state *cloneState) traceField(path string, t reflect.Type, i int) {
+	if state.trace == nil {
+		return
+	}
+
+	state.path = path + "." + t.Field(i).Name
+}
+
+// traceIndex sets the path of the value being cloned to the i-th element at path.
+// It does nothing if clone is not traced.
+func (state *cloneState) traceIndex(path string, i int) {
+	if state.trace == nil {
+		return
+	}
+
+	state.path = path + "[" + strconv.Itoa(i) + "]"
+}
+
+// traceKey sets the path of the value being cloned to the entry of key in the map at path.
+// It does nothing if clone is not traced.
+func (state *cloneState) traceKey(path string, key reflect.Value) {
+	if state.trace == nil {
+		return
+	}
+
+	if !key.CanInterface() {
+		key = forceClearROFlag(key)
+	}
+
+	state.path = path + "[" + walk.FormatMapKey(key) + "]"
+}
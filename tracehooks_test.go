@@ -0,0 +1,151 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type traceSecret struct {
+	Token string
+}
+
+type traceNode struct {
+	Name   string
+	Next   *traceNode
+	Tags   []string
+	Attrs  map[string]interface{}
+	Pair   [2]*traceSecret
+	secret *traceSecret
+}
+
+func TestTraceHooks(t *testing.T) {
+	a := assert.New(t)
+	v := &traceNode{
+		Name: "root",
+		Next: &traceNode{
+			Name: "next",
+		},
+		Tags: []string{"a"},
+		Attrs: map[string]interface{}{
+			"n": 1,
+		},
+		Pair: [2]*traceSecret{nil, {Token: "pair"}},
+		secret: &traceSecret{
+			Token: "unexported",
+		},
+	}
+
+	var entered, left []string
+	secrets := map[string]string{}
+	allocator := FromHeap()
+	allocator.SetTraceHooks(&TraceHooks{
+		OnEnter: func(path string, v reflect.Value) {
+			entered = append(entered, fmt.Sprintf("%v %v", path, v.Type()))
+
+			if s, ok := v.Interface().(*traceSecret); ok && s != nil {
+				secrets[path] = s.Token
+			}
+		},
+		OnLeave: func(path string, v, cloned reflect.Value) {
+			left = append(left, path)
+		},
+	})
+	cloned := allocator.Clone(reflect.ValueOf(v)).Interface().(*traceNode)
+
+	a.Equal(cloned, v)
+	a.Equal(entered, []string{
+		" *clone.traceNode",
+		".Next *clone.traceNode",
+		".Next.Next *clone.traceNode",
+		".Next.Tags []string",
+		".Next.Attrs map[string]interface {}",
+		".Next.Pair[0] *clone.traceSecret",
+		".Next.Pair[1] *clone.traceSecret",
+		".Next.secret *clone.traceSecret",
+		".Tags []string",
+		".Attrs map[string]interface {}",
+		`.Attrs["n"] interface {}`,
+		`.Attrs["n"] int`,
+		".Pair[0] *clone.traceSecret",
+		".Pair[1] *clone.traceSecret",
+		".secret *clone.traceSecret",
+	})
+	a.Equal(len(left), len(entered))
+	a.Equal(left[0], `.Next.Next`)
+	a.Equal(left[len(left)-1], "")
+
+	// Traced values can be read even if they are unexported.
+	a.Equal(secrets, map[string]string{
+		".Pair[1]": "pair",
+		".secret":  "unexported",
+	})
+
+	// Trace hooks are inherited.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	entered = nil
+	child.Clone(reflect.ValueOf(v.Pair))
+	a.Equal(entered, []string{
+		" [2]*clone.traceSecret",
+		"[0] *clone.traceSecret",
+		"[1] *clone.traceSecret",
+	})
+
+	// Paths are traced in the same way in Slowly.
+	entered = nil
+	child.CloneSlowly(reflect.ValueOf(v.Next))
+	a.Equal(len(entered), 7)
+	a.Equal(entered[6], ".secret *clone.traceSecret")
+
+	// Hooks are removed by nil.
+	child.SetTraceHooks(nil)
+	entered = nil
+	child.Clone(reflect.ValueOf(v))
+	a.Equal(len(entered), 15)
+	allocator.SetTraceHooks(nil)
+	entered = nil
+	child.Clone(reflect.ValueOf(v))
+	a.Equal(len(entered), 0)
+	a.Equal(allocator.loadConfig().lookupTraceHooks(), (*TraceHooks)(nil))
+}
+
+func TestTraceHooksInWorkStack(t *testing.T) {
+	a := assert.New(t)
+	const n = maxPtrDepth * 2
+	var head *traceNode
+
+	for i := 0; i < n; i++ {
+		head = &traceNode{
+			Next: head,
+		}
+	}
+
+	var paths []string
+	allocator := FromHeap()
+	allocator.SetTraceHooks(&TraceHooks{
+		OnLeave: func(path string, v, cloned reflect.Value) {
+			if v.Type() == reflect.TypeOf(head) && !v.IsNil() && v.Elem().Field(1).IsNil() {
+				paths = append(paths, path)
+			}
+		},
+	})
+	cloned := allocator.Clone(reflect.ValueOf(head)).Interface().(*traceNode)
+
+	a.Equal(cloned, head)
+	a.Equal(len(paths), 1)
+
+	expected := ""
+
+	for i := 0; i < n-1; i++ {
+		expected += ".Next"
+	}
+
+	a.Equal(paths[0], expected)
+}
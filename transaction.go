@@ -0,0 +1,121 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// SetTransactional enables or disables transactional mode in heap allocator.
+//
+// See Allocator.SetTransactional for more details.
+func SetTransactional(transactional bool) {
+	defaultAllocator.SetTransactional(transactional)
+}
+
+// SetTransactional enables or disables transactional mode in a.
+// If transactional mode is not set, a inherits it from parent allocator.
+// Transactional mode is disabled in the default allocator.
+//
+// In transactional mode, a clone is all or nothing.
+// All memory allocated in a clone is tracked,
+// and returned to the pool by AllocatorMethods.Free in the reverse order of allocation
+// if the clone panics, e.g. a custom func panics or the pool runs out of memory without fallback.
+// It's designed for pools or arenas with limits, so that failed clones leave no residue in pools.
+// Memory allocated by fallback allocator is freed by the fallback allocator in the same way.
+//
+// Allocations are tracked only if Free is set.
+// Custom funcs receive an allocator tracking memory in the same transaction,
+// and customizing the allocator in custom funcs panics.
+func (a *Allocator) SetTransactional(transactional bool) {
+	option := optionDisabled
+
+	if transactional {
+		option = optionEnabled
+	}
+
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.transactional = option
+		return copied
+	})
+}
+
+func (cfg *config) isTransactional() bool {
+	for current := cfg; current != nil; current = current.parent {
+		switch current.transactional {
+		case optionEnabled:
+			return true
+		case optionDisabled:
+			return false
+		}
+	}
+
+	return false
+}
+
+// transaction tracks all memory allocated in a clone.
+type transaction struct {
+	allocations []allocation
+	committed   bool
+}
+
+type allocation struct {
+	owner *Allocator
+	v     reflect.Value
+}
+
+// record tracks v allocated by owner if owner can free it.
+func (tx *transaction) record(owner *Allocator, v reflect.Value) reflect.Value {
+	if owner.free != nil && v.IsValid() {
+		tx.allocations = append(tx.allocations, allocation{
+			owner: owner,
+			v:     v,
+		})
+	}
+
+	return v
+}
+
+// commit keeps all memory allocated in tx.
+func (tx *transaction) commit() {
+	tx.committed = true
+	tx.allocations = nil
+}
+
+// end frees all memory allocated in tx in the reverse order of allocation
+// if tx is not committed.
+func (tx *transaction) end() {
+	if tx.committed {
+		return
+	}
+
+	for i := len(tx.allocations) - 1; i >= 0; i-- {
+		alloc := tx.allocations[i]
+		alloc.owner.free(alloc.owner.pool, alloc.v)
+	}
+
+	tx.allocations = nil
+}
+
+// withTransaction returns a frozen allocator which tracks all memory allocated in tx.
+// It shares pool, methods and config with a.
+func (a *Allocator) withTransaction(tx *transaction) *Allocator {
+	return a.derive(func(primary, derived *Allocator) {
+		derived.new = func(pool unsafe.Pointer, t reflect.Type) reflect.Value {
+			return tx.record(primary, primary.new(pool, t))
+		}
+		derived.makeSlice = func(pool unsafe.Pointer, t reflect.Type, len, cap int) reflect.Value {
+			return tx.record(primary, primary.makeSlice(pool, t, len, cap))
+		}
+		derived.makeMap = func(pool unsafe.Pointer, t reflect.Type, n int) reflect.Value {
+			return tx.record(primary, primary.makeMap(pool, t, n))
+		}
+		derived.makeChan = func(pool unsafe.Pointer, t reflect.Type, buffer int) reflect.Value {
+			return tx.record(primary, primary.makeChan(pool, t, buffer))
+		}
+		derived.tx = tx
+	})
+}
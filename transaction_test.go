@@ -0,0 +1,115 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+func newFreeableQuotaAllocator(qp *quotaPool, fallback *Allocator, freed *[]string) *Allocator {
+	return NewAllocator(unsafe.Pointer(qp), &AllocatorMethods{
+		Parent:   newQuotaAllocator(qp, nil),
+		Fallback: fallback,
+		Free: func(pool unsafe.Pointer, v reflect.Value) {
+			(*quotaPool)(pool).quota++
+			*freed = append(*freed, v.Type().String())
+		},
+	})
+}
+
+func TestTransactional(t *testing.T) {
+	a := assert.New(t)
+	type T struct {
+		Name  *string
+		List  []int
+		Attrs map[string]int
+		Next  *T
+	}
+	name := "foo"
+	orig := &T{
+		Name:  &name,
+		List:  []int{1, 2, 3},
+		Attrs: map[string]int{"a": 1},
+		Next: &T{
+			List: []int{4},
+		},
+	}
+	exhausted := func() reflect.Value {
+		panic(fmt.Errorf("quota pool: %w", ErrExhausted))
+	}
+
+	// Memory allocated in a failed clone is freed in the reverse order of allocation.
+	var freed []string
+	qp := &quotaPool{
+		quota:     5,
+		exhausted: exhausted,
+	}
+	allocator := newFreeableQuotaAllocator(qp, nil, &freed)
+	allocator.SetTransactional(true)
+	quota := qp.quota
+	a.Assert(func() (ok bool) {
+		defer func() { ok = recover() != nil }()
+		allocator.Clone(reflect.ValueOf(orig))
+		return
+	}())
+	a.Equal(qp.quota, quota)
+	a.Equal(freed, []string{"[]int", "*string", "*clone.T"})
+
+	// Nothing is freed in a successful clone.
+	freed = nil
+	qp.quota = 100
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*T)
+	a.Equal(cloned, orig)
+	a.Equal(qp.quota, 100-6)
+	a.Equal(len(freed), 0)
+
+	// Memory is not freed if transactional mode is disabled.
+	qp.quota = 3
+	allocator.SetTransactional(false)
+	a.Assert(func() (ok bool) {
+		defer func() { ok = recover() != nil }()
+		allocator.Clone(reflect.ValueOf(orig))
+		return
+	}())
+	a.Equal(qp.quota, 0)
+	a.Equal(len(freed), 0)
+
+	// Memory allocated by fallback allocator is freed by fallback allocator.
+	var fallbackFreed []string
+	fallbackPool := &quotaPool{
+		quota:     100,
+		exhausted: exhausted,
+	}
+	fallback := newFreeableQuotaAllocator(fallbackPool, nil, &fallbackFreed)
+	freed = nil
+	qp = &quotaPool{
+		quota:     100,
+		exhausted: exhausted,
+	}
+	allocator = newFreeableQuotaAllocator(qp, fallback, &freed)
+	allocator.SetTransactional(true)
+	allocator.SetCustomFunc(reflect.TypeOf(T{}), func(allocator *Allocator, old, new reflect.Value) {
+		if old.FieldByName("Next").IsNil() {
+			panic("custom func fails")
+		}
+
+		new.Set(allocator.Clone(old))
+	})
+	quota, fallbackQuota := 2, fallbackPool.quota
+	qp.quota = quota
+	a.Assert(func() (ok bool) {
+		defer func() { ok = recover() != nil }()
+		allocator.Clone(reflect.ValueOf(orig))
+		return
+	}())
+	a.Equal(qp.quota, quota)
+	a.Equal(fallbackPool.quota, fallbackQuota)
+	a.Equal(freed, []string{"*clone.T", "*clone.T"})
+	a.Equal(fallbackFreed, []string{"*clone.T", "map[string]int", "[]int", "*string"})
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+)
+
+// UnsupportedTypeError is the error of a value which kind is not supported by clone methods.
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("go-clone: <bug> unsupported type `%v`", e.Type)
+}
+
+// PanicError is the error of a panic recovered by TryClone,
+// e.g. a panic in a custom func or an internal bug.
+type PanicError struct {
+	Value interface{} // The value passed to panic.
+	Stack []byte      // The stack trace of the goroutine when the panic is recovered.
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("go-clone: panic while cloning: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it's an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// TryClone recursively deep clones v to a new value in heap like Clone,
+// but returns an error instead of panicking.
+//
+// See Allocator.TryClone for more details.
+func TryClone(v interface{}) (cloned interface{}, err error) {
+	if v == nil {
+		return
+	}
+
+	val, err := defaultAllocator.tryClone(reflect.ValueOf(v), false)
+
+	if err != nil {
+		return
+	}
+
+	cloned = val.Interface()
+	return
+}
+
+// TryClone works in the same way as Clone, except it returns an error instead of panicking.
+//
+// The errors reported by clone methods are returned as they are,
+// e.g. *ConflictError, *InterfaceError, *ValidationError, *UnsupportedTypeError, *DepthError, *NodeLimitError, *NaNKeyError and *InvalidValueError.
+// The other panics, e.g. panics in custom funcs or internal bugs, are returned as *PanicError.
+// If err is not nil, the cloned value is invalid.
+func (a *Allocator) TryClone(val reflect.Value) (reflect.Value, error) {
+	return a.tryClone(val, true)
+}
+
+func (a *Allocator) tryClone(val reflect.Value, inCustomFunc bool) (cloned reflect.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			cloned = reflect.Value{}
+			err = recoveredError(r)
+		}
+	}()
+
+	cloned = a.clone(val, inCustomFunc)
+	return
+}
+
+// recoveredError converts a recovered panic value r to an error.
+func recoveredError(r interface{}) error {
+	switch err := r.(type) {
+	case *ConflictError:
+		return err
+	case *InterfaceError:
+		return err
+	case *ValidationError:
+		return err
+	case *UnsupportedTypeError:
+		return err
+	case *DepthError:
+		return err
+	case *NodeLimitError:
+		return err
+	case *NaNKeyError:
+		return err
+	case *InvalidValueError:
+		return err
+	}
+
+	return &PanicError{
+		Value: r,
+		Stack: debug.Stack(),
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type tryCloneData struct {
+	Values []int
+}
+
+func TestTryClone(t *testing.T) {
+	a := assert.New(t)
+	orig := &tryCloneData{Values: []int{1, 2}}
+
+	cloned, err := TryClone(orig)
+	a.NilError(err)
+	a.Equal(cloned, orig)
+
+	cloned, err = TryClone(nil)
+	a.NilError(err)
+	a.Equal(cloned, nil)
+
+	// Panics in custom funcs are returned as *PanicError.
+	errBroken := errors.New("broken")
+	allocator := NewAllocator(nil, nil)
+	allocator.SetCustomFunc(reflect.TypeOf(tryCloneData{}), func(allocator *Allocator, old, new reflect.Value) {
+		panic(errBroken)
+	})
+	val, err := allocator.TryClone(reflect.ValueOf([]tryCloneData{{}}))
+	a.Assert(!val.IsValid())
+	pe, ok := err.(*PanicError)
+	a.Assert(ok)
+	a.Assert(pe.Value == errBroken)
+	a.Assert(pe.Unwrap() == errBroken)
+	a.Assert(len(pe.Stack) != 0)
+	a.Equal(pe.Error(), "go-clone: panic while cloning: broken")
+
+	// Custom func of root value is not called like Clone.
+	val, err = allocator.TryClone(reflect.ValueOf(tryCloneData{}))
+	a.NilError(err)
+	a.Equal(val.Interface(), tryCloneData{})
+
+	// Errors reported by clone methods are returned as they are.
+	s := "foo"
+	allocator = NewAllocator(nil, nil)
+	allocator.SetInterfacePolicy(reflect.TypeOf((*fmt.Stringer)(nil)).Elem(), InterfacePolicyError)
+	_, err = allocator.TryClone(reflect.ValueOf(&interfaceHolder{
+		Stringer: &unexportedStringer{s: &s},
+	}))
+	_, ok = err.(*InterfaceError)
+	a.Assert(ok)
+
+	pe = &PanicError{Value: "oops"}
+	a.Assert(pe.Unwrap() == nil)
+
+	ute := &UnsupportedTypeError{Type: reflect.TypeOf(0)}
+	a.Equal(ute.Error(), "go-clone: <bug> unsupported type `int`")
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+)
+
+// CachePolicy is a func to decide whether the analysis of struct type t should be cached.
+type CachePolicy func(t reflect.Type) bool
+
+// cachePolicyOption wraps a CachePolicy so that configs can tell whether it's changed.
+type cachePolicyOption struct {
+	policy CachePolicy
+}
+
+type cacheSizeOption struct {
+	n int
+}
+
+// SetCachePolicy sets the cache policy of struct types in heap allocator.
+//
+// See Allocator.SetCachePolicy for more details.
+func SetCachePolicy(policy CachePolicy) {
+	defaultAllocator.SetCachePolicy(policy)
+}
+
+// SetCachePolicy sets a policy to decide which struct types are cached by a.
+// If policy is nil, all struct types are cached.
+// If cache policy is not set, a inherits it from parent allocator.
+//
+// An allocator analyzes every struct type it clones and caches the result until its registrations change.
+// Struct types created at runtime by reflect.StructOf may never be seen again,
+// and caching them grows the cache without bound.
+// The policy is called with such a struct type before caching it.
+// If it returns false, the type is analyzed again every time it's cloned.
+//
+// Other types are cached in the same way when they are analyzed, e.g. for floats to canonicalize or cloner methods,
+// so that the policy may be called with types of other kinds, e.g. arrays created by reflect.ArrayOf.
+func (a *Allocator) SetCachePolicy(policy CachePolicy) {
+	opt := &cachePolicyOption{
+		policy: policy,
+	}
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.cachePolicy = opt
+		return copied
+	})
+}
+
+// SetCacheSize sets the max number of struct types cached by heap allocator.
+//
+// See Allocator.SetCacheSize for more details.
+func SetCacheSize(n int) {
+	defaultAllocator.SetCacheSize(n)
+}
+
+// SetCacheSize sets the max number of struct types cached by a.
+// If n is not positive, the cache is unbounded.
+// If cache size is not set, a inherits it from parent allocator.
+//
+// When the cache is full, the least recently used struct type is evicted.
+// Other analyses of types, e.g. for floats to canonicalize or cloner methods, are cached separately with the same size.
+// A bounded cache is guarded by a mutex, which is slower than the default lock-free cache
+// when many goroutines clone values concurrently.
+func (a *Allocator) SetCacheSize(n int) {
+	if n < 0 {
+		n = 0
+	}
+
+	opt := &cacheSizeOption{
+		n: n,
+	}
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.cacheSize = opt
+		return copied
+	})
+}
+
+func (cfg *config) lookupCachePolicy() CachePolicy {
+	for current := cfg; current != nil; current = current.parent {
+		if current.cachePolicy != nil {
+			return current.cachePolicy.policy
+		}
+	}
+
+	return nil
+}
+
+// lookupCacheSize returns the nearest cache size or 0 if the cache is unbounded.
+func (cfg *config) lookupCacheSize() int {
+	for current := cfg; current != nil; current = current.parent {
+		if current.cacheSize != nil {
+			return current.cacheSize.n
+		}
+	}
+
+	return 0
+}
+
+// structTypeCache is the cache of struct types analyzed with a config.
+// Options are looked up on first use, as the config is not published before it.
+//
+// Other analyses of types, which are cheaper but still worth caching, are cached along with struct types,
+// so that they follow the same cache policy and cache size and are dropped with the config.
+type structTypeCache struct {
+	once sync.Once
+
+	structs        typeCache // Cache of structType.
+	inlineFloats   typeCache // Cache of hasInlineFloat.
+	clonerMethods  typeCache // Cache of cloner method indexes. A type without cloner method is stored with -1.
+	visibilityTags typeCache // Cache of hasVisibilityTag.
+	pointerFree    typeCache // Cache of isPointerFree.
+	opaquePointers typeCache // Cache of isOpaquePointer.
+}
+
+// typeCache caches analyses of types with a cache policy and cache size.
+type typeCache struct {
+	policy CachePolicy
+	size   int
+
+	// types is the cache if size is 0.
+	types sync.Map
+
+	// Bounded cache in LRU order.
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[reflect.Type]*list.Element
+}
+
+type typeCacheEntry struct {
+	t reflect.Type
+	v interface{}
+}
+
+// cache returns the struct type cache of cfg.
+func (cfg *config) cache() *structTypeCache {
+	c := cfg.structTypes
+	c.once.Do(func() {
+		policy := cfg.lookupCachePolicy()
+		size := cfg.lookupCacheSize()
+
+		for _, tc := range []*typeCache{&c.structs, &c.inlineFloats, &c.clonerMethods, &c.visibilityTags, &c.pointerFree, &c.opaquePointers} {
+			tc.init(policy, size)
+		}
+	})
+	return c
+}
+
+func (c *structTypeCache) load(t reflect.Type) (st *structType, ok bool) {
+	v, ok := c.structs.load(t)
+
+	if !ok {
+		return
+	}
+
+	return v.(*structType), true
+}
+
+func (c *structTypeCache) store(t reflect.Type, st *structType) {
+	c.structs.store(t, st)
+}
+
+// len returns the number of cached struct types.
+func (c *structTypeCache) len() int {
+	return c.structs.len()
+}
+
+func (c *typeCache) init(policy CachePolicy, size int) {
+	c.policy = policy
+	c.size = size
+
+	if size > 0 {
+		c.lru = list.New()
+		c.entries = map[reflect.Type]*list.Element{}
+	}
+}
+
+func (c *typeCache) load(t reflect.Type) (v interface{}, ok bool) {
+	if c.size == 0 {
+		return c.types.Load(t)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[t]
+
+	if !ok {
+		return
+	}
+
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*typeCacheEntry).v, true
+}
+
+func (c *typeCache) store(t reflect.Type, v interface{}) {
+	if c.policy != nil && !c.policy(t) {
+		return
+	}
+
+	if c.size == 0 {
+		c.types.LoadOrStore(t, v)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[t]; ok {
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[t] = c.lru.PushFront(&typeCacheEntry{
+		t: t,
+		v: v,
+	})
+
+	if c.lru.Len() > c.size {
+		elem := c.lru.Back()
+		c.lru.Remove(elem)
+		delete(c.entries, elem.Value.(*typeCacheEntry).t)
+	}
+}
+
+// len returns the number of cached types.
+func (c *typeCache) len() (n int) {
+	if c.size != 0 {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.lru.Len()
+	}
+
+	c.types.Range(func(key, value interface{}) bool {
+		n++
+		return true
+	})
+	return
+}
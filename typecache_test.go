@@ -0,0 +1,141 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type cachedStruct struct {
+	Name  string
+	Value *int
+}
+
+// makeDynamicStruct makes a new struct type with a field named by i.
+func makeDynamicStruct(i int) reflect.Value {
+	t := reflect.StructOf([]reflect.StructField{
+		{
+			Name: fmt.Sprintf("Field%v", i),
+			Type: reflect.TypeOf([]int{}),
+		},
+	})
+	v := reflect.New(t).Elem()
+	v.Field(0).Set(reflect.ValueOf([]int{i}))
+	return v
+}
+
+func TestSetCachePolicy(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.SetCachePolicy(func(t reflect.Type) bool {
+		return t.Name() != ""
+	})
+
+	for i := 0; i < 10; i++ {
+		v := makeDynamicStruct(i)
+		cloned := allocator.Clone(v)
+		a.Equal(cloned.Interface(), v.Interface())
+	}
+
+	n := 1
+	orig := &cachedStruct{
+		Name:  "foo",
+		Value: &n,
+	}
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(*cachedStruct)
+	a.Equal(cloned, orig)
+	a.Assert(cloned.Value != orig.Value)
+
+	cache := allocator.loadConfig().cache()
+	a.Equal(cache.len(), 1)
+	_, ok := cache.load(reflect.TypeOf(cachedStruct{}))
+	a.Assert(ok)
+
+	// Child inherits the cache policy.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	child.Clone(makeDynamicStruct(0))
+	a.Equal(child.loadConfig().cache().len(), 0)
+
+	// Remove the policy.
+	child.SetCachePolicy(nil)
+	child.Clone(makeDynamicStruct(0))
+	a.Equal(child.loadConfig().cache().len(), 1)
+}
+
+func TestSetCacheSize(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.SetCacheSize(3)
+
+	for i := 0; i < 10; i++ {
+		v := makeDynamicStruct(i)
+		cloned := allocator.Clone(v)
+		a.Equal(cloned.Interface(), v.Interface())
+	}
+
+	cache := allocator.loadConfig().cache()
+	a.Equal(cache.len(), 3)
+
+	// The least recently used type is evicted.
+	t7 := makeDynamicStruct(7).Type()
+	t8 := makeDynamicStruct(8).Type()
+	allocator.Clone(makeDynamicStruct(7))
+	allocator.Clone(makeDynamicStruct(10))
+	_, ok := cache.load(t7)
+	a.Assert(ok)
+	_, ok = cache.load(t8)
+	a.Assert(!ok)
+	a.Equal(cache.len(), 3)
+
+	// Unbounded cache.
+	allocator.SetCacheSize(0)
+
+	for i := 0; i < 10; i++ {
+		allocator.Clone(makeDynamicStruct(i))
+	}
+
+	a.Equal(allocator.loadConfig().cache().len(), 10)
+}
+
+func TestCacheOfTypeAnalyses(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.SetCachePolicy(func(t reflect.Type) bool {
+		return t.Name() != ""
+	})
+	analyze := func(cfg *config) {
+		for i := 0; i < 10; i++ {
+			t := reflect.ArrayOf(i+1, reflect.TypeOf(0.0))
+			a.Assert(cfg.hasInlineFloat(t))
+			a.Assert(cfg.isPointerFree(t))
+			a.Assert(!cfg.hasVisibilityTag(t))
+			_, ok := cfg.lookupClonerMethod(t)
+			a.Assert(!ok)
+		}
+	}
+	lens := func(c *structTypeCache) []int {
+		return []int{c.inlineFloats.len(), c.pointerFree.len(), c.visibilityTags.len(), c.clonerMethods.len()}
+	}
+
+	// Types created at runtime are not cached by policy.
+	cfg := allocator.loadConfig()
+	analyze(cfg)
+	a.Equal(lens(cfg.cache()), []int{0, 0, 0, 0})
+
+	cfg.hasInlineFloat(reflect.TypeOf(cachedStruct{}))
+	a.Equal(cfg.cache().inlineFloats.len(), 1)
+
+	// Caches are bounded by cache size.
+	allocator.SetCachePolicy(nil)
+	allocator.SetCacheSize(3)
+	cfg = allocator.loadConfig()
+	analyze(cfg)
+	a.Equal(lens(cfg.cache()), []int{3, 3, 3, 3})
+}
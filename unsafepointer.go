@@ -0,0 +1,90 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// SetUnsafePointerType sets the type of values referenced by an unsafe.Pointer field in heap allocator.
+//
+// See Allocator.SetUnsafePointerType for more details.
+func SetUnsafePointerType(owner reflect.Type, fieldName string, t reflect.Type) {
+	defaultAllocator.SetUnsafePointerType(owner, fieldName, t)
+}
+
+// SetUnsafePointerType sets t as the type of values referenced by the unsafe.Pointer field named fieldName
+// in struct type owner, so that the field is cloned as a pointer to t rather than copied by value.
+// If t is nil, remove the type of the field, and the field is copied by value again.
+// If owner is not struct or pointer to struct, or owner doesn't have an unsafe.Pointer field named fieldName,
+// SetUnsafePointerType ignores it.
+//
+// By default, there is no way to know what an unsafe.Pointer references,
+// so an unsafe.Pointer is always copied by value and shares memory with the source.
+// Once the type is set, the referenced value is cloned like the field were a `*T`,
+// including handling of pointer cycles through the field in Slowly.
+// Callers must make sure that the field is either nil or a pointer to the beginning of a value of t;
+// otherwise, the clone may read or write memory out of bounds.
+func (a *Allocator) SetUnsafePointerType(owner reflect.Type, fieldName string, t reflect.Type) {
+	for owner.Kind() == reflect.Ptr {
+		owner = owner.Elem()
+	}
+
+	if owner.Kind() != reflect.Struct {
+		return
+	}
+
+	field, ok := owner.FieldByName(fieldName)
+
+	if !ok || len(field.Index) != 1 || field.Type.Kind() != reflect.UnsafePointer {
+		return
+	}
+
+	a.updateTypeConfig(owner, func(tc *typeConfig) {
+		unsafePointers := make(map[string]reflect.Type, len(tc.unsafePointers)+1)
+
+		for k, v := range tc.unsafePointers {
+			unsafePointers[k] = v
+		}
+
+		unsafePointers[fieldName] = t
+		tc.unsafePointers = unsafePointers
+	})
+}
+
+// lookupUnsafePointerType returns the nearest type set for the unsafe.Pointer field named fieldName in owner.
+func (cfg *config) lookupUnsafePointerType(owner reflect.Type, fieldName string) reflect.Type {
+	for current := cfg; current != nil; current = current.parent {
+		if tc, ok := current.types[owner]; ok {
+			if t, ok := tc.unsafePointers[fieldName]; ok {
+				return t
+			}
+		}
+	}
+
+	return nil
+}
+
+// field returns the field of struct v described by pf.
+// If the field is an unsafe.Pointer with a type set by SetUnsafePointerType,
+// it's returned as a pointer to the type.
+func (pf structFieldType) field(v reflect.Value) reflect.Value {
+	field := v.Field(pf.Index)
+
+	if pf.Pointee == nil {
+		return field
+	}
+
+	return reflect.NewAt(pf.Pointee, unsafe.Pointer(field.Pointer()))
+}
+
+// fieldType returns the type of the field of struct type t described by pf.
+func (pf structFieldType) fieldType(t reflect.Type) reflect.Type {
+	if pf.Pointee == nil {
+		return t.Field(pf.Index).Type
+	}
+
+	return reflect.PtrTo(pf.Pointee)
+}
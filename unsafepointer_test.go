@@ -0,0 +1,89 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/huandu/go-assert"
+)
+
+type unsafeNode struct {
+	Value int
+	Next  unsafe.Pointer // Always points to an unsafeNode.
+	data  unsafe.Pointer // Always points to an unsafePayload.
+	Raw   unsafe.Pointer
+}
+
+type unsafePayload struct {
+	Values []int
+}
+
+func TestSetUnsafePointerType(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.SetUnsafePointerType(reflect.TypeOf(&unsafeNode{}), "Next", reflect.TypeOf(unsafeNode{}))
+	allocator.SetUnsafePointerType(reflect.TypeOf(unsafeNode{}), "data", reflect.TypeOf(unsafePayload{}))
+
+	raw := new(int)
+	payload := &unsafePayload{Values: []int{1, 2}}
+	n2 := &unsafeNode{Value: 2, Raw: unsafe.Pointer(raw)}
+	n1 := &unsafeNode{
+		Value: 1,
+		Next:  unsafe.Pointer(n2),
+		data:  unsafe.Pointer(payload),
+		Raw:   unsafe.Pointer(raw),
+	}
+
+	cloned := allocator.Clone(reflect.ValueOf(n1)).Interface().(*unsafeNode)
+	a.Equal(cloned.Value, 1)
+	a.Assert(cloned.Next != n1.Next)
+	a.Equal((*unsafeNode)(cloned.Next).Value, 2)
+	a.Assert((*unsafeNode)(cloned.Next).Next == nil)
+	a.Assert(cloned.data != n1.data)
+	a.Equal((*unsafePayload)(cloned.data).Values, payload.Values)
+	a.Assert(&(*unsafePayload)(cloned.data).Values[0] != &payload.Values[0])
+
+	// Fields without types are copied by value.
+	a.Assert(cloned.Raw == unsafe.Pointer(raw))
+	a.Assert((*unsafeNode)(cloned.Next).Raw == unsafe.Pointer(raw))
+
+	// Cycles through unsafe.Pointer are handled in Slowly.
+	n2.Next = unsafe.Pointer(n1)
+	cloned = allocator.CloneSlowly(reflect.ValueOf(n1)).Interface().(*unsafeNode)
+	next := (*unsafeNode)(cloned.Next)
+	a.Equal(next.Value, 2)
+	a.Assert(next != n2)
+	a.Assert((*unsafeNode)(next.Next) == cloned)
+
+	// Remove the type.
+	allocator.SetUnsafePointerType(reflect.TypeOf(unsafeNode{}), "Next", nil)
+	cloned = allocator.CloneSlowly(reflect.ValueOf(n1)).Interface().(*unsafeNode)
+	a.Assert(cloned.Next == unsafe.Pointer(n2))
+	a.Assert(cloned.data != n1.data)
+}
+
+func TestSetUnsafePointerTypeInvalid(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.SetUnsafePointerType(reflect.TypeOf(0), "Next", reflect.TypeOf(unsafeNode{}))
+	allocator.SetUnsafePointerType(reflect.TypeOf(unsafeNode{}), "Value", reflect.TypeOf(unsafeNode{}))
+	allocator.SetUnsafePointerType(reflect.TypeOf(unsafeNode{}), "NotExist", reflect.TypeOf(unsafeNode{}))
+	a.Equal(len(allocator.loadConfig().types), 0)
+
+	// Types are inherited from parent.
+	parent := FromHeap()
+	parent.SetUnsafePointerType(reflect.TypeOf(unsafeNode{}), "Next", reflect.TypeOf(unsafeNode{}))
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: parent,
+	})
+	child.SetUnsafePointerType(reflect.TypeOf(unsafeNode{}), "data", reflect.TypeOf(unsafePayload{}))
+	a.Equal(len(child.loadStructType(reflect.TypeOf(unsafeNode{})).PointerFields), 2)
+
+	other := FromHeap()
+	other.ApplyConfig(child.ExportConfig())
+	a.Equal(len(other.loadStructType(reflect.TypeOf(unsafeNode{})).PointerFields), 2)
+}
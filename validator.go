@@ -0,0 +1,83 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ValidateFunc is a func to check invariants of a cloned value.
+// It returns an error if v is invalid.
+type ValidateFunc func(v reflect.Value) error
+
+// ValidationError is the error of a cloned value rejected by a validator in strict mode.
+type ValidationError struct {
+	Type reflect.Type // The type of the cloned value.
+	Err  error        // The error returned by the validator.
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("go-clone: cloned value of type `%v` is invalid: %v", e.Type, e.Err)
+}
+
+// Unwrap returns the error returned by the validator.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// RegisterValidator registers a validator for struct type t in heap allocator.
+// If t is not struct or pointer to struct, RegisterValidator ignores t.
+//
+// See Allocator.RegisterValidator for more details.
+func RegisterValidator(t reflect.Type, fn ValidateFunc) {
+	defaultAllocator.RegisterValidator(t, fn)
+}
+
+// SetStrictMode enables or disables strict mode in heap allocator.
+//
+// See Allocator.SetStrictMode for more details.
+func SetStrictMode(strict bool) {
+	defaultAllocator.SetStrictMode(strict)
+}
+
+// RegisterValidator registers a validator for struct type t.
+// If t is not struct or pointer to struct, RegisterValidator ignores t.
+//
+// Validators work in strict mode only.
+// The fn is called with every cloned value of t right after the value is cloned,
+// including values cloned by custom funcs.
+// If fn returns an error, clone methods panic with a *ValidationError.
+//
+// If fn is nil, remove the validator for type t.
+func (a *Allocator) RegisterValidator(t reflect.Type, fn ValidateFunc) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	a.updateTypeConfig(t, func(tc *typeConfig) {
+		tc.validator = fn
+	})
+}
+
+// validate checks v with the validator of v's type.
+func (state *cloneState) validate(v reflect.Value) {
+	t := v.Type()
+	fn := state.config.lookupValidator(t)
+
+	if fn == nil {
+		return
+	}
+
+	if err := fn(v); err != nil {
+		panic(&ValidationError{
+			Type: t,
+			Err:  err,
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type validatedIndex struct {
+	Keys  []string
+	Index map[string]int
+}
+
+func TestRegisterValidator(t *testing.T) {
+	a := assert.New(t)
+	parent := FromHeap()
+	allocator := NewAllocator(nil, &AllocatorMethods{
+		Parent: parent,
+	})
+	errInconsistent := errors.New("inconsistent index")
+	validated := 0
+	parent.RegisterValidator(reflect.TypeOf(&validatedIndex{}), func(v reflect.Value) error {
+		validated++
+		idx := v.Addr().Interface().(*validatedIndex)
+
+		if len(idx.Keys) != len(idx.Index) {
+			return errInconsistent
+		}
+
+		return nil
+	})
+
+	// A buggy custom func drops the index.
+	allocator.SetCustomFunc(reflect.TypeOf(validatedIndex{}), func(allocator *Allocator, old, new reflect.Value) {
+		new.FieldByName("Keys").Set(allocator.Clone(old.FieldByName("Keys")))
+	})
+
+	value := []validatedIndex{
+		{
+			Keys:  []string{"a"},
+			Index: map[string]int{"a": 0},
+		},
+	}
+
+	// Validators are not called unless strict mode is enabled.
+	allocator.Clone(reflect.ValueOf(value))
+	a.Equal(validated, 0)
+
+	parent.SetStrictMode(true)
+	a.Assert(allocator.loadConfig().isStrictMode())
+
+	var err error
+
+	func() {
+		defer func() {
+			err = recover().(error)
+		}()
+
+		allocator.Clone(reflect.ValueOf(value))
+	}()
+
+	a.Equal(validated, 1)
+	a.Assert(errors.Is(err, errInconsistent))
+
+	var ve *ValidationError
+	a.Assert(errors.As(err, &ve))
+	a.Equal(ve.Type, reflect.TypeOf(validatedIndex{}))
+
+	// Strict mode can be disabled in child allocator.
+	allocator.SetStrictMode(false)
+	a.Assert(!allocator.loadConfig().isStrictMode())
+	allocator.Clone(reflect.ValueOf(value))
+	a.Equal(validated, 1)
+
+	// Valid values pass.
+	parent.Clone(reflect.ValueOf(value))
+	a.Equal(validated, 2)
+
+	parent.RegisterValidator(reflect.TypeOf(validatedIndex{}), nil)
+	parent.Clone(reflect.ValueOf(value))
+	a.Equal(validated, 2)
+}
+
+type validatedPoint struct {
+	X, Y int
+}
+
+type validatedShape struct {
+	Name   string
+	Center validatedPoint
+	Points [2]validatedPoint
+}
+
+func TestRegisterValidatorInlineValues(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.SetStrictMode(true)
+	value := &validatedShape{
+		Name:   "line",
+		Center: validatedPoint{1, 1},
+		Points: [2]validatedPoint{{0, 0}, {2, 2}},
+	}
+
+	// Struct types are cached before any validator is registered.
+	cloned := allocator.Clone(reflect.ValueOf(value)).Interface().(*validatedShape)
+	a.Equal(cloned, value)
+
+	var validated []validatedPoint
+	allocator.RegisterValidator(reflect.TypeOf(validatedPoint{}), func(v reflect.Value) error {
+		validated = append(validated, v.Interface().(validatedPoint))
+		return nil
+	})
+
+	// Inline fields and array elements are validated even if they can be shadow copied.
+	cloned = allocator.Clone(reflect.ValueOf(value)).Interface().(*validatedShape)
+	a.Equal(cloned, value)
+	a.Equal(validated, []validatedPoint{{1, 1}, {0, 0}, {2, 2}})
+
+	validated = nil
+	allocator.Clone(reflect.ValueOf(value.Points))
+	a.Equal(validated, []validatedPoint{{0, 0}, {2, 2}})
+}
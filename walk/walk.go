@@ -0,0 +1,194 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Package walk walks all values inside a value in depth-first order.
+// It's able to walk unexported fields and values with pointer cycles.
+// Inspections in go-clone, e.g. FindPaths and checks of read-only sources, are built on it,
+// while clone methods traverse values by themselves.
+// It's designed as a building block to diff, hash, dump or redact values.
+//
+//	walk.Walk(v, walk.VisitorFunc(func(path string, v reflect.Value) bool {
+//		fmt.Println(path, v.Kind())
+//		return true
+//	}))
+package walk
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"unsafe"
+)
+
+// Visitor visits values walked by Walk.
+type Visitor interface {
+	// Visit is called with every value walked by Walk.
+	// The path is written in Go selector syntax relative to root like `.Foo[2]["key"]`.
+	// The empty path means root itself.
+	//
+	// The v can always be used with v.Interface(), even if it's an unexported field.
+	// Visitor must not modify v.
+	//
+	// If Visit returns false, values inside v are not walked.
+	Visit(path string, v reflect.Value) (descend bool)
+}
+
+// VisitorFunc is a func implementing Visitor.
+type VisitorFunc func(path string, v reflect.Value) (descend bool)
+
+// Visit calls fn(path, v).
+func (fn VisitorFunc) Visit(path string, v reflect.Value) bool {
+	return fn(path, v)
+}
+
+// Walk walks v and all values inside v in depth-first order and calls visitor with every value.
+//
+// Following rules apply to different kinds of values.
+//
+//   - Array and slice: Elements are walked in order of index.
+//   - Interface: The interface value is visited and then the dynamic value with the same path.
+//   - Map: Keys and values are visited with the same path like `["key"]` in random order.
+//   - Pointer: The pointed value is walked with the same path.
+//   - Struct: Fields are walked in order of declaration including unexported fields.
+//
+// Walk can walk values with pointer cycles.
+// Every pointer, map and slice is visited every time it's reached,
+// but values inside it are walked only the first time.
+func Walk(v interface{}, visitor Visitor) {
+	if v == nil {
+		return
+	}
+
+	WalkValue(reflect.ValueOf(v), visitor)
+}
+
+// WalkValue walks v in the same way as Walk.
+// If v is an unexported field, v must be addressable.
+func WalkValue(v reflect.Value, visitor Visitor) {
+	if !v.IsValid() {
+		return
+	}
+
+	// Make v addressable and readable, so that all values inside v can be made readable.
+	if !v.CanInterface() {
+		v = reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+	} else if !v.CanAddr() {
+		nv := reflect.New(v.Type()).Elem()
+		nv.Set(v)
+		v = nv
+	}
+
+	w := &walker{
+		visitor: visitor,
+		visited: map[visit]struct{}{},
+	}
+	w.walk(v, "")
+}
+
+type visit struct {
+	p     uintptr
+	extra int
+	t     reflect.Type
+}
+
+type walker struct {
+	visitor Visitor
+	visited map[visit]struct{}
+}
+
+func (w *walker) walk(v reflect.Value, path string) {
+	if !w.visitor.Visit(path, v) {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			w.walk(v.Index(i), path+"["+strconv.Itoa(i)+"]")
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			w.walk(v.Elem(), path)
+		}
+	case reflect.Map:
+		if v.IsNil() || !w.visit(v, 0) {
+			return
+		}
+
+		for iter := v.MapRange(); iter.Next(); {
+			key := iter.Key()
+			p := path + "[" + FormatMapKey(key) + "]"
+			w.walk(key, p)
+			w.walk(iter.Value(), p)
+		}
+	case reflect.Ptr:
+		if v.IsNil() || !w.visit(v, 0) {
+			return
+		}
+
+		w.walk(v.Elem(), path)
+	case reflect.Slice:
+		if v.IsNil() || !w.visit(v, v.Len()) {
+			return
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			w.walk(v.Index(i), path+"["+strconv.Itoa(i)+"]")
+		}
+	case reflect.Struct:
+		t := v.Type()
+		addressable := v.CanAddr()
+
+		// Structs inside maps and interfaces are not addressable.
+		// Copy them to read unexported fields.
+		if !addressable {
+			nv := reflect.New(t).Elem()
+			nv.Set(v)
+			v = nv
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+
+			// Unexported fields are read-only. Clear the flag by a new value at the same address.
+			if !field.CanInterface() {
+				field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+			}
+
+			// Fields of a copy must not be addressable,
+			// so that visitors never take addresses of the copy.
+			if !addressable {
+				field = field.Convert(field.Type())
+			}
+
+			w.walk(field, path+"."+t.Field(i).Name)
+		}
+	}
+}
+
+// visit records v as visited and reports whether v is visited at the first time.
+func (w *walker) visit(v reflect.Value, extra int) bool {
+	vst := visit{
+		p:     v.Pointer(),
+		extra: extra,
+		t:     v.Type(),
+	}
+
+	if _, ok := w.visited[vst]; ok {
+		return false
+	}
+
+	w.visited[vst] = struct{}{}
+	return true
+}
+
+// FormatMapKey formats a map key in a path.
+// A string key is quoted and other keys are formatted by fmt.
+// The key must be readable by key.Interface().
+func FormatMapKey(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return strconv.Quote(key.String())
+	}
+
+	return fmt.Sprintf("%v", key.Interface())
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package walk
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type node struct {
+	Name     string
+	Children []*node
+	attrs    map[string]interface{}
+	parent   *node
+}
+
+func TestWalk(t *testing.T) {
+	a := assert.New(t)
+	root := &node{
+		Name: "root",
+		attrs: map[string]interface{}{
+			"id": 1,
+		},
+	}
+	child := &node{
+		Name:   "child",
+		parent: root,
+	}
+	root.Children = []*node{child, child}
+
+	var paths []string
+	var names []string
+	Walk(root, VisitorFunc(func(path string, v reflect.Value) bool {
+		paths = append(paths, path)
+
+		// Unexported fields are readable.
+		if n, ok := v.Interface().(node); ok {
+			names = append(names, n.Name)
+		}
+
+		return true
+	}))
+	a.Equal(paths, []string{
+		"",
+		"",
+		".Name",
+		".Children",
+		".Children[0]",
+		".Children[0]",
+		".Children[0].Name",
+		".Children[0].Children",
+		".Children[0].attrs",
+		".Children[0].parent",
+		".Children[1]",
+		".attrs",
+		`.attrs["id"]`,
+		`.attrs["id"]`,
+		`.attrs["id"]`,
+		".parent",
+	})
+
+	// Every node is walked once even if it's reached several times.
+	a.Equal(names, []string{"root", "child"})
+}
+
+func TestWalkSkip(t *testing.T) {
+	a := assert.New(t)
+	v := struct {
+		A map[string]int
+		B []int
+	}{
+		A: map[string]int{"foo": 1, "bar": 2},
+		B: []int{1, 2},
+	}
+
+	var paths []string
+	Walk(v, VisitorFunc(func(path string, v reflect.Value) bool {
+		paths = append(paths, path)
+		return v.Kind() != reflect.Slice
+	}))
+	sort.Strings(paths)
+	a.Equal(paths, []string{"", ".A", `.A["bar"]`, `.A["bar"]`, `.A["foo"]`, `.A["foo"]`, ".B"})
+
+	// Nothing to walk.
+	Walk(nil, VisitorFunc(func(path string, v reflect.Value) bool {
+		a.Fatalf("unexpected visit")
+		return true
+	}))
+}
+
+func TestWalkUnaddressable(t *testing.T) {
+	a := assert.New(t)
+	type point struct {
+		x, y int
+	}
+	v := map[string]interface{}{
+		"point": point{x: 1, y: 2},
+		"nil":   nil,
+	}
+
+	var paths []string
+	var values []interface{}
+	Walk(v, VisitorFunc(func(path string, v reflect.Value) bool {
+		// Fields of structs inside maps are readable but not addressable.
+		if v.Kind() == reflect.Int {
+			a.Assert(!v.CanAddr())
+			paths = append(paths, path)
+			values = append(values, v.Interface())
+		}
+
+		return true
+	}))
+	a.Equal(paths, []string{`["point"].x`, `["point"].y`})
+	a.Equal(values, []interface{}{1, 2})
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Warm analyzes all struct types reachable from types in heap allocator.
+//
+// See Allocator.Warm for more details.
+func Warm(types ...reflect.Type) {
+	defaultAllocator.Warm(types...)
+}
+
+// WarmFromTypeNames resolves names to types by resolver and warms them up in heap allocator.
+//
+// See Allocator.WarmFromTypeNames for more details.
+func WarmFromTypeNames(names []string, resolver func(name string) reflect.Type) error {
+	return defaultAllocator.WarmFromTypeNames(names, resolver)
+}
+
+// Warm analyzes all struct types reachable from types ahead of time,
+// so that the first clone of these types doesn't pay for the analysis.
+// It's designed to be called before serving traffic
+// to avoid latency spikes of first requests.
+//
+// Struct types are analyzed with current registrations in a and cached in a's config.
+// Any registration made in a after Warm drops the cache, so call Warm after all registrations.
+// Allocators created with a frozen parent share the cache of the parent until customized,
+// thus it's recommended to warm up a frozen base allocator.
+//
+// Warm panics if a struct type cannot be cloned as expected, e.g. an init method is not found,
+// in the same way as clone methods do.
+func (a *Allocator) Warm(types ...reflect.Type) {
+	cfg := a.loadConfig()
+	visited := map[reflect.Type]struct{}{}
+
+	for _, t := range types {
+		cfg.warm(t, visited)
+	}
+}
+
+// WarmFromTypeNames resolves names to types by resolver and warms them up by Warm.
+// It's designed to warm up types listed in a manifest, e.g. a file generated by code generators
+// and embedded by go:embed, as types cannot be looked up by names at runtime in Go.
+//
+// If resolver returns nil for any name, the name is skipped and reported in the returned error
+// after all other types are warmed up.
+func (a *Allocator) WarmFromTypeNames(names []string, resolver func(name string) reflect.Type) error {
+	types := make([]reflect.Type, 0, len(names))
+	var unresolved []string
+
+	for _, name := range names {
+		if t := resolver(name); t != nil {
+			types = append(types, t)
+		} else {
+			unresolved = append(unresolved, name)
+		}
+	}
+
+	a.Warm(types...)
+
+	if len(unresolved) != 0 {
+		return fmt.Errorf("go-clone: cannot resolve types %v", strings.Join(unresolved, ", "))
+	}
+
+	return nil
+}
+
+// warm analyzes all struct types reachable from t in cfg.
+func (cfg *config) warm(t reflect.Type, visited map[reflect.Type]struct{}) {
+	if _, ok := visited[t]; ok {
+		return
+	}
+
+	visited[t] = struct{}{}
+
+	switch t.Kind() {
+	case reflect.Array, reflect.Chan, reflect.Ptr, reflect.Slice:
+		cfg.warm(t.Elem(), visited)
+	case reflect.Map:
+		cfg.warm(t.Key(), visited)
+		cfg.warm(t.Elem(), visited)
+	case reflect.Struct:
+		cfg.loadStructType(t)
+
+		for i := 0; i < t.NumField(); i++ {
+			cfg.warm(t.Field(i).Type, visited)
+		}
+	}
+}
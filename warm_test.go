@@ -0,0 +1,62 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type warmLeaf struct {
+	Value *int
+}
+
+type warmNode struct {
+	Name     string
+	Children []*warmNode
+	Leaves   map[string][2]warmLeaf
+}
+
+type warmMissingInit struct {
+	Index map[string]int `clone:"init=Missing"`
+}
+
+func TestWarm(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.Warm(reflect.TypeOf(&warmNode{}), reflect.TypeOf(0))
+
+	cache := allocator.loadConfig().cache()
+	a.Equal(cache.len(), 2)
+	_, ok := cache.load(reflect.TypeOf(warmNode{}))
+	a.Assert(ok)
+	_, ok = cache.load(reflect.TypeOf(warmLeaf{}))
+	a.Assert(ok)
+
+	// Errors in struct types are reported by Warm.
+	a.Assert(func() (ok bool) {
+		defer func() { ok = recover() != nil }()
+		allocator.Warm(reflect.TypeOf(warmMissingInit{}))
+		return
+	}())
+}
+
+func TestWarmFromTypeNames(t *testing.T) {
+	a := assert.New(t)
+	manifest := map[string]reflect.Type{
+		"clone.warmNode": reflect.TypeOf(warmNode{}),
+	}
+	allocator := FromHeap()
+	err := allocator.WarmFromTypeNames([]string{"clone.warmNode", "clone.unknown", "clone.missing"}, func(name string) reflect.Type {
+		return manifest[name]
+	})
+	a.Equal(err.Error(), "go-clone: cannot resolve types clone.unknown, clone.missing")
+
+	cache := allocator.loadConfig().cache()
+	a.Equal(cache.len(), 2)
+
+	a.NilError(allocator.WarmFromTypeNames(nil, nil))
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// maxPtrDepth is the max number of nested pointed values cloned recursively.
+// Deeper values, e.g. nodes in a long linked list, are cloned in the work stack,
+// so that the goroutine stack doesn't grow with the depth of a value.
+const maxPtrDepth = 64
+
+type nodeLimitOption struct {
+	n int
+}
+
+// NodeLimitError is the error of a clone which has more pointed values than the node limit.
+type NodeLimitError struct {
+	NodeLimit int          // The node limit.
+	Type      reflect.Type // The type of the pointer beyond node limit.
+}
+
+func (e *NodeLimitError) Error() string {
+	return fmt.Sprintf("go-clone: pointer of type `%v` is beyond node limit %v", e.Type, e.NodeLimit)
+}
+
+// cloneJob is a pending work in the work stack.
+// If finish is false, the job is to clone src to the value pointed by nv.
+// Otherwise, the job is to finish the cloned struct nv after all values inside it are cloned.
+type cloneJob struct {
+	src         reflect.Value
+	nv          reflect.Value
+	initMethods []int
+	finish      bool
+
+	depth     int
+	ancestors *ancestorValue
+	config    *config
+	path      string
+}
+
+// SetNodeLimit sets the node limit of clones made by heap allocator.
+//
+// See Allocator.SetNodeLimit for more details.
+func SetNodeLimit(n int) {
+	defaultAllocator.SetNodeLimit(n)
+}
+
+// SetNodeLimit sets the max number of values pointed by pointers, a.k.a. nodes, cloned in a clone made by a.
+// If n is not positive, the number of nodes is unlimited.
+// If node limit is not set, a inherits it from parent allocator.
+//
+// A clone with more nodes than the limit panics with a *NodeLimitError.
+// It protects a program from running out of memory when cloning an unexpectedly large value,
+// e.g. a linked list which has a cycle by mistake.
+// Nil pointers and pointers visited again in Slowly are not counted.
+//
+// Deep pointed values are not limited by the goroutine stack size.
+// Nested pointed values deeper than a fixed threshold are cloned in an explicit work stack allocated in heap,
+// so that a linked list of millions of nodes can be cloned by Clone.
+// Values in the work stack are cloned after their owners,
+// so custom funcs of owners cannot see these values in clones.
+// Init methods and validation of a struct still run after all values inside the struct are cloned.
+// Values inside a struct guarded by MarkAsGuardedBy are always cloned recursively while the lock is held,
+// so that the goroutine stack grows with the depth of a guarded value.
+func (a *Allocator) SetNodeLimit(n int) {
+	if n < 0 {
+		n = 0
+	}
+
+	opt := &nodeLimitOption{
+		n: n,
+	}
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.nodeLimit = opt
+		return copied
+	})
+}
+
+// lookupNodeLimit returns the nearest node limit or 0 if the number of nodes is unlimited.
+func (cfg *config) lookupNodeLimit() int {
+	for current := cfg; current != nil; current = current.parent {
+		if current.nodeLimit != nil {
+			return current.nodeLimit.n
+		}
+	}
+
+	return 0
+}
+
+// countNode counts a pointed value of pointer type t and panics if it's beyond node limit.
+func (state *cloneState) countNode(t reflect.Type) {
+	state.nodes++
+
+	if state.nodes > state.nodeLimit {
+		panic(&NodeLimitError{
+			NodeLimit: state.nodeLimit,
+			Type:      t,
+		})
+	}
+}
+
+// pushJob pushes a job to clone src to the value pointed by nv later.
+func (state *cloneState) pushJob(src, nv reflect.Value) {
+	state.jobs = append(state.jobs, cloneJob{
+		src:       src,
+		nv:        nv,
+		depth:     state.depth,
+		ancestors: state.ancestors,
+		config:    state.config,
+		path:      state.path,
+	})
+}
+
+// insertJob inserts job at index i of the work stack,
+// so that the job runs after all jobs above i.
+func (state *cloneState) insertJob(i int, job cloneJob) {
+	state.jobs = append(state.jobs, cloneJob{})
+	copy(state.jobs[i+1:], state.jobs[i:])
+	state.jobs[i] = job
+}
+
+// drain runs all jobs in the work stack until it's empty.
+func (state *cloneState) drain() {
+	if len(state.jobs) == 0 {
+		return
+	}
+
+	depth := state.depth
+	ancestors := state.ancestors
+	cfg := state.config
+	path := state.path
+
+	for n := len(state.jobs); n > 0; n = len(state.jobs) {
+		job := state.jobs[n-1]
+		state.jobs[n-1] = cloneJob{}
+		state.jobs = state.jobs[:n-1]
+
+		if job.finish {
+			state.finishStruct(job.nv, job.initMethods)
+			continue
+		}
+
+		state.depth = job.depth
+		state.ancestors = job.ancestors
+		state.config = job.config
+		state.path = job.path
+		state.copyElem(job.src, job.nv)
+	}
+
+	state.depth = depth
+	state.ancestors = ancestors
+	state.config = cfg
+	state.path = path
+	state.jobs = nil
+}
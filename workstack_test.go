@@ -0,0 +1,149 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type workStackNode struct {
+	Value  int
+	Next   *workStackNode
+	Parent *workStackNode `clone:"parent"`
+	length int            `clone:"init=Length"`
+}
+
+func (n *workStackNode) Length() {
+	// Init methods of deep nodes must be called before their owners.
+	n.length = 1
+
+	if n.Next != nil {
+		n.length += n.Next.length
+	}
+}
+
+func newWorkStackList(n int) *workStackNode {
+	head := &workStackNode{}
+	curr := head
+
+	for i := 1; i < n; i++ {
+		curr.Next = &workStackNode{
+			Value:  i,
+			Parent: curr,
+		}
+		curr = curr.Next
+	}
+
+	return head
+}
+
+type workStackListNode struct {
+	Value int
+	Next  *workStackListNode
+}
+
+func TestCloneLongList(t *testing.T) {
+	a := assert.New(t)
+	const n = 1000000
+	head := &workStackListNode{}
+	last := head
+
+	for i := 1; i < n; i++ {
+		last.Next = &workStackListNode{Value: i}
+		last = last.Next
+	}
+
+	cloned := Clone(head).(*workStackListNode)
+	i := 0
+
+	for orig, curr := head, cloned; orig != nil; orig, curr = orig.Next, curr.Next {
+		if curr == orig || curr.Value != i {
+			t.Fatalf("invalid node #%v.", i)
+		}
+
+		i++
+	}
+
+	a.Equal(i, n)
+
+	// Make the list a cycle.
+	last.Next = head
+	cloned = Slowly(head).(*workStackListNode)
+	curr := cloned
+
+	for i := 1; i < n; i++ {
+		curr = curr.Next
+	}
+
+	a.Assert(curr != last)
+	a.Assert(curr.Next == cloned)
+}
+
+func TestWorkStackParentAndInit(t *testing.T) {
+	a := assert.New(t)
+	const n = 1000
+	head := newWorkStackList(n)
+
+	for _, cloned := range []*workStackNode{Clone(head).(*workStackNode), Slowly(head).(*workStackNode)} {
+		i := 0
+
+		for orig, curr := head, cloned; orig != nil; orig, curr = orig.Next, curr.Next {
+			a.Assert(curr != orig)
+			a.Equal(curr.Value, i)
+			a.Equal(curr.length, n-i)
+
+			if i != 0 {
+				a.Assert(curr.Parent.Next == curr)
+			}
+
+			i++
+		}
+
+		a.Equal(i, n)
+	}
+}
+
+func TestWorkStackMaxDepth(t *testing.T) {
+	a := assert.New(t)
+	head := newWorkStackList(200)
+	cloned := CloneWithMaxDepth(head, 100).(*workStackNode)
+	orig := head
+
+	for i := 1; i < 100; i++ {
+		a.Assert(cloned != orig)
+		cloned, orig = cloned.Next, orig.Next
+	}
+
+	// Pointers beyond max depth are shadow copied.
+	a.Assert(cloned != orig)
+	a.Assert(cloned.Next == orig.Next)
+}
+
+func TestSetNodeLimit(t *testing.T) {
+	a := assert.New(t)
+	allocator := FromHeap()
+	allocator.SetNodeLimit(10)
+
+	// The root value passed to Allocator.Clone is not counted as it's not a pointed value.
+	orig := *newWorkStackList(11)
+	cloned := allocator.Clone(reflect.ValueOf(orig)).Interface().(workStackNode)
+	a.Equal(cloned.length, 11)
+
+	orig = *newWorkStackList(12)
+	_, err := allocator.TryClone(reflect.ValueOf(orig))
+	nle, ok := err.(*NodeLimitError)
+	a.Assert(ok)
+	a.Equal(nle.NodeLimit, 10)
+	a.Equal(nle.Type, reflect.TypeOf(&orig))
+
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	child.SetNodeLimit(0)
+	cloned = child.Clone(reflect.ValueOf(orig)).Interface().(workStackNode)
+	a.Equal(cloned.length, 12)
+}
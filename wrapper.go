@@ -4,18 +4,15 @@
 package clone
 
 import (
-	"encoding/binary"
-	"hash/crc64"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"unsafe"
 )
 
 var (
 	sizeOfChecksum = unsafe.Sizeof(uint64(0))
 
-	crc64Table = crc64.MakeTable(crc64.ECMA)
-
 	cachedWrapperTypes sync.Map
 )
 
@@ -76,7 +73,7 @@ func Wrap(v interface{}) interface{} {
 
 	// Equivalent code: wrapper.T = Clone(v)
 	field := wrapper.Field(0)
-	field.Set(heapCloneState.clone(elem))
+	field.Set(defaultAllocator.clone(elem, false))
 
 	// Equivalent code: wrapper.Checksum = makeChecksum(v)
 	checksumPtr := unsafe.Pointer((uintptr(wrapperPtr) + t.Size()))
@@ -86,6 +83,10 @@ func Wrap(v interface{}) interface{} {
 	originPtr := unsafe.Pointer((uintptr(wrapperPtr) + t.Size() + sizeOfChecksum))
 	*(*uintptr)(originPtr) = uintptr(ptr)
 
+	if atomic.LoadInt32(&wrapTracking) != 0 {
+		trackWrapper(pw, t)
+	}
+
 	return field.Addr().Interface()
 }
 
@@ -98,11 +99,14 @@ func validateChecksum(t reflect.Type, ptr unsafe.Pointer) bool {
 	return checksum == expected
 }
 
+// makeChecksum mixes the address of a wrapper and its origin.
+// It's called by every Unwrap, so it must be cheap and free of any shared state.
+// The mixer is the finalizer of SplitMix64, which spreads every input bit to all output bits.
 func makeChecksum(t reflect.Type, pw uintptr, orig uintptr) uint64 {
-	var data [binary.MaxVarintLen64 * 2]byte
-	binary.PutUvarint(data[:binary.MaxVarintLen64], uint64(pw))
-	binary.PutUvarint(data[binary.MaxVarintLen64:], uint64(orig))
-	return crc64.Checksum(data[:], crc64Table)
+	h := uint64(pw)*0x9e3779b97f4a7c15 ^ uint64(orig)
+	h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+	h = (h ^ h>>27) * 0x94d049bb133111eb
+	return h ^ h>>31
 }
 
 func getOrigin(t reflect.Type, ptr unsafe.Pointer) unsafe.Pointer {
@@ -111,19 +115,35 @@ func getOrigin(t reflect.Type, ptr unsafe.Pointer) unsafe.Pointer {
 
 // Unwrap returns v's original value if v is a wrapped value.
 // Otherwise, simply returns v itself.
+//
+// The original pointer is stored right after the wrapped value in the same memory block.
+// Unwrap reads it without any lock or lookup in shared data.
 func Unwrap(v interface{}) interface{} {
 	if v == nil {
 		return v
 	}
 
-	val := reflect.ValueOf(v)
+	pt := reflect.TypeOf(v)
 
-	if !isWrapped(val) {
+	if pt.Kind() != reflect.Ptr {
 		return v
 	}
 
-	origVal := origin(val)
-	return origVal.Interface()
+	// The data word of an interface holding a pointer is the pointer itself.
+	data := &(*[2]unsafe.Pointer)(unsafe.Pointer(&v))[1]
+	t := pt.Elem()
+	ptr := *data
+
+	if ptr == nil || !validateChecksum(t, ptr) {
+		return v
+	}
+
+	if atomic.LoadInt32(&wrapTracking) != 0 {
+		releaseWrapper(ptr)
+	}
+
+	*data = getOrigin(t, ptr)
+	return v
 }
 
 func origin(val reflect.Value) reflect.Value {
@@ -148,9 +168,13 @@ func Undo(v interface{}) {
 		return
 	}
 
+	if atomic.LoadInt32(&wrapTracking) != 0 {
+		releaseWrapper(unsafe.Pointer(val.Pointer()))
+	}
+
 	origVal := origin(val)
 	elem := val.Elem()
-	elem.Set(heapCloneState.clone(origVal.Elem()))
+	elem.Set(defaultAllocator.clone(origVal.Elem(), false))
 }
 
 func isWrapped(val reflect.Value) bool {
@@ -162,5 +186,5 @@ func isWrapped(val reflect.Value) bool {
 
 	t := pt.Elem()
 	ptr := unsafe.Pointer(val.Pointer())
-	return validateChecksum(t, ptr)
+	return ptr != nil && validateChecksum(t, ptr)
 }
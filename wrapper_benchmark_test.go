@@ -24,6 +24,33 @@ func BenchmarkUnwrap(b *testing.B) {
 	}
 }
 
+func BenchmarkUnwrapParallel(b *testing.B) {
+	orig := &testSimple{
+		Foo: 123,
+		Bar: "abcd",
+	}
+	wrapped := Wrap(orig)
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			Unwrap(wrapped)
+		}
+	})
+}
+
+func BenchmarkUnwrapNotWrapped(b *testing.B) {
+	var v interface{} = &testSimple{
+		Foo: 123,
+		Bar: "abcd",
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Unwrap(v)
+	}
+}
+
 func BenchmarkSimpleWrap(b *testing.B) {
 	orig := &testSimple{
 		Foo: 123,
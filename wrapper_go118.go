@@ -0,0 +1,69 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package clone
+
+import (
+	"reflect"
+	"sync/atomic"
+	"unsafe"
+)
+
+// WrapT creates a wrapper of v like Wrap.
+// The wrapper has the same type as v, so no type assertion is required.
+//
+//	t := &T{Foo: 123}
+//	v := WrapT(t)  // v is a *T.
+//	v.Foo = 456    // t.Foo doesn't change.
+//	UnwrapT(v) == t
+//	UndoT(v)       // v.Foo == t.Foo again.
+func WrapT[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+
+	return Wrap(v).(*T)
+}
+
+// UnwrapT returns the original value of wrapped if wrapped is created by Wrap or WrapT.
+// Otherwise, simply returns wrapped itself.
+//
+// The original value always has the same type as wrapped,
+// so that unwrapping as a wrong type is a compile error rather than a failed type assertion.
+func UnwrapT[T any](wrapped *T) *T {
+	if wrapped == nil {
+		return nil
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	ptr := unsafe.Pointer(wrapped)
+
+	if !validateChecksum(t, ptr) {
+		return wrapped
+	}
+
+	if atomic.LoadInt32(&wrapTracking) != 0 {
+		releaseWrapper(ptr)
+	}
+
+	return (*T)(getOrigin(t, ptr))
+}
+
+// UndoT discards any change made in wrapped like Undo.
+// If wrapped is not a wrapped value, nothing happens.
+func UndoT[T any](wrapped *T) {
+	if wrapped == nil {
+		return
+	}
+
+	orig := UnwrapT(wrapped)
+
+	if orig == wrapped {
+		return
+	}
+
+	*wrapped = *cloneNew(orig)
+}
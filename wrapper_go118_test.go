@@ -0,0 +1,46 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package clone
+
+import (
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestWrapT(t *testing.T) {
+	a := assert.New(t)
+	orig := &testType{
+		Foo: "abcd",
+		Bar: map[string]interface{}{
+			"def": 123,
+		},
+		Player: []float64{12.3},
+	}
+	wrapped := WrapT(orig)
+	a.Equal(wrapped, orig)
+	a.Assert(wrapped != orig)
+	a.Assert(UnwrapT(wrapped) == orig)
+	a.Assert(Unwrap(wrapped).(*testType) == orig)
+
+	wrapped.Foo = "xyz"
+	wrapped.Player[0] = 45.6
+	a.Equal(orig.Foo, "abcd")
+	UndoT(wrapped)
+	a.Equal(wrapped, orig)
+
+	// Values not wrapped are returned as they are.
+	plain := &testSimple{Foo: 1}
+	a.Assert(UnwrapT(plain) == plain)
+	UndoT(plain)
+	a.Equal(plain.Foo, 1)
+
+	var nilValue *testSimple
+	a.Assert(WrapT(nilValue) == nil)
+	a.Assert(UnwrapT(nilValue) == nil)
+	UndoT(nilValue)
+}
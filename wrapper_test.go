@@ -98,7 +98,7 @@ func TestUnwrapPlainValueWhichIsNotWrapped(t *testing.T) {
 	a := assert.New(t)
 	i := 0
 	cases := []interface{}{
-		123, "abc", nil, &i,
+		123, "abc", nil, &i, (*testSimple)(nil),
 	}
 
 	for _, c := range cases {
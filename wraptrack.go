@@ -0,0 +1,129 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// wrapTracking is 1 if wrappers are tracked.
+var wrapTracking int32
+
+// wrapTracker records call sites of tracked wrappers which are not unwrapped or undone yet.
+var wrapTracker struct {
+	mu       sync.Mutex
+	wrappers map[uintptr]string
+}
+
+// Functions which create wrappers on behalf of their callers.
+// The call site of a wrapper is the first caller outside of them.
+var wrapFuncPrefixes = []string{
+	"github.com/huandu/go-clone.Wrap",
+	"github.com/huandu/go-clone.Sandbox",
+	"github.com/huandu/go-clone/generic.Wrap",
+}
+
+// SetWrapTracking enables or disables tracking of wrappers created by Wrap.
+//
+// When tracking is enabled, Wrap records the call site of every new wrapper.
+// If a wrapper is garbage-collected without any call of Unwrap, Undo
+// or Transaction's UndoAll and CommitAll,
+// a warning is reported by the warning func of heap allocator with the call site.
+// Such a wrapper is a protective copy which silently becomes the source of truth,
+// as changes in it are never discarded and the original value is never read again.
+//
+// Wrappers are checked by finalizers, so warnings are reported after GC in another goroutine.
+// Disabling tracking discards all records. Wrappers created before are not reported.
+// It's designed for debugging and tests, as it slows down both Wrap and Unwrap.
+func SetWrapTracking(enabled bool) {
+	wrapTracker.mu.Lock()
+	defer wrapTracker.mu.Unlock()
+
+	if enabled {
+		if wrapTracker.wrappers == nil {
+			wrapTracker.wrappers = map[uintptr]string{}
+		}
+
+		atomic.StoreInt32(&wrapTracking, 1)
+		return
+	}
+
+	atomic.StoreInt32(&wrapTracking, 0)
+	wrapTracker.wrappers = nil
+}
+
+// trackWrapper records the call site of the wrapper pw and checks it when it's garbage-collected.
+func trackWrapper(pw reflect.Value, t reflect.Type) {
+	p := pw.Pointer()
+	caller := wrapCaller()
+
+	wrapTracker.mu.Lock()
+
+	if wrapTracker.wrappers == nil {
+		wrapTracker.mu.Unlock()
+		return
+	}
+
+	wrapTracker.wrappers[p] = caller
+	wrapTracker.mu.Unlock()
+
+	runtime.SetFinalizer(pw.Interface(), func(interface{}) {
+		wrapTracker.mu.Lock()
+		caller, leaked := wrapTracker.wrappers[p]
+		delete(wrapTracker.wrappers, p)
+		wrapTracker.mu.Unlock()
+
+		if !leaked {
+			return
+		}
+
+		warning := fmt.Sprintf("wrapped value is garbage-collected without Unwrap or Undo, wrapped at %v", caller)
+
+		if fn := defaultAllocator.loadConfig().lookupWarning(); fn != nil {
+			fn(t, warning)
+			return
+		}
+
+		log.Printf("go-clone: warning: %v: %v", t, warning)
+	})
+}
+
+// releaseWrapper stops tracking the wrapper at ptr, as it's unwrapped or undone.
+func releaseWrapper(ptr unsafe.Pointer) {
+	wrapTracker.mu.Lock()
+	delete(wrapTracker.wrappers, uintptr(ptr))
+	wrapTracker.mu.Unlock()
+}
+
+// wrapCaller returns the call site of a wrapper in the format of `file:line`.
+func wrapCaller() string {
+	var pcs [16]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+
+		if !more || !isWrapFunc(frame.Function) {
+			return fmt.Sprintf("%v:%v", frame.File, frame.Line)
+		}
+	}
+}
+
+func isWrapFunc(name string) bool {
+	for _, prefix := range wrapFuncPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+type wrapTrackValue struct {
+	N int
+	P *int
+}
+
+func TestWrapTracking(t *testing.T) {
+	a := assert.New(t)
+	warnings := make(chan string, 10)
+	scope := Register(func(allocator *Allocator) {
+		allocator.SetWarningFunc(func(t reflect.Type, warning string) {
+			if t == reflect.TypeOf(wrapTrackValue{}) {
+				warnings <- warning
+			}
+		})
+	})
+	defer scope.Close()
+
+	SetWrapTracking(true)
+	defer SetWrapTracking(false)
+
+	// Wrappers unwrapped or undone are not reported.
+	func() {
+		n := 1
+		w1 := Wrap(&wrapTrackValue{N: 1, P: &n}).(*wrapTrackValue)
+		Unwrap(w1)
+		w2 := Wrap(&wrapTrackValue{N: 2}).(*wrapTrackValue)
+		Undo(w2)
+	}()
+
+	// The wrapper is leaked.
+	func() {
+		w := Wrap(&wrapTrackValue{N: 3}).(*wrapTrackValue)
+		w.N = 4
+	}()
+
+	var warning string
+	deadline := time.Now().Add(5 * time.Second)
+
+	for warning == "" && time.Now().Before(deadline) {
+		runtime.GC()
+
+		select {
+		case warning = <-warnings:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	a.Assert(strings.HasPrefix(warning, "wrapped value is garbage-collected without Unwrap or Undo, wrapped at "))
+	a.Assert(strings.Contains(warning, "wraptrack_test.go:"))
+
+	// Only one wrapper is leaked.
+	for i := 0; i < 3; i++ {
+		runtime.GC()
+	}
+
+	select {
+	case warning = <-warnings:
+		t.Fatalf("unexpected warning: %v", warning)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Transaction is a set of wrapped values created by Wrap,
+// which are undone or committed together to keep them consistent with each other.
+//
+// All methods are safe to be called concurrently.
+type Transaction struct {
+	mu       sync.Mutex
+	wrappers []reflect.Value
+}
+
+// NewTransaction creates a new empty transaction.
+func NewTransaction() *Transaction {
+	return &Transaction{}
+}
+
+// Wrap creates a wrapper of v by Wrap and adds it to tx.
+// If v is not a pointer, Wrap simply returns v and do nothing.
+func (tx *Transaction) Wrap(v interface{}) interface{} {
+	wrapped := Wrap(v)
+
+	// Values like slices and maps are not comparable. Don't compare wrapped with v.
+	if reflect.ValueOf(v).Kind() == reflect.Ptr {
+		tx.Add(wrapped)
+	}
+
+	return wrapped
+}
+
+// Add adds wrapped values created by Wrap to tx.
+// A value added more than once is added only once.
+// It panics if any value is not a wrapped value.
+func (tx *Transaction) Add(wrapped ...interface{}) {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	for _, w := range wrapped {
+		val := reflect.ValueOf(w)
+
+		if !val.IsValid() || !isWrapped(val) {
+			panic(fmt.Errorf("go-clone: value of type `%T` is not wrapped by Wrap", w))
+		}
+
+		if !tx.contains(val) {
+			tx.wrappers = append(tx.wrappers, val)
+		}
+	}
+}
+
+func (tx *Transaction) contains(val reflect.Value) bool {
+	for _, w := range tx.wrappers {
+		if w.Pointer() == val.Pointer() && w.Type() == val.Type() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Len returns the number of wrapped values in tx.
+func (tx *Transaction) Len() int {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	return len(tx.wrappers)
+}
+
+// UndoAll discards all changes made in wrapped values in tx like Undo.
+// Wrapped values are removed from tx once they are undone.
+//
+// It's all-or-nothing. All original values are cloned before any wrapped value is changed,
+// so that, if cloning panics, e.g. in a custom func, no wrapped value is changed.
+func (tx *Transaction) UndoAll() {
+	tx.apply(false)
+}
+
+// CommitAll copies all changes made in wrapped values in tx to their original values.
+// After the commit, every original value is a deep clone of its wrapped value,
+// and wrapped values can be unwrapped or undone as usual.
+// Wrapped values are removed from tx once they are committed.
+//
+// It's all-or-nothing. All wrapped values are cloned before any original value is changed,
+// so that, if cloning panics, e.g. in a custom func, no original value is changed.
+// It panics if several wrapped values in tx wrap the same original value,
+// as it's impossible to commit all of them.
+func (tx *Transaction) CommitAll() {
+	tx.apply(true)
+}
+
+// apply clones original values to wrapped values, or wrapped values to original values if commit is true,
+// in two phases.
+func (tx *Transaction) apply(commit bool) {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	dsts := make([]reflect.Value, 0, len(tx.wrappers))
+	clones := make([]reflect.Value, 0, len(tx.wrappers))
+	var origins map[uintptr]struct{}
+
+	if commit {
+		origins = make(map[uintptr]struct{}, len(tx.wrappers))
+	}
+
+	// Phase 1: Clone all values without changing any of them.
+	for _, wrapper := range tx.wrappers {
+		dst, src := wrapper, origin(wrapper)
+
+		if commit {
+			if _, ok := origins[src.Pointer()]; ok {
+				panic(fmt.Errorf("go-clone: several wrapped values of type `%v` wrap the same original value", wrapper.Type()))
+			}
+
+			origins[src.Pointer()] = struct{}{}
+			dst, src = src, dst
+		}
+
+		dsts = append(dsts, dst.Elem())
+		clones = append(clones, defaultAllocator.clone(src.Elem(), false))
+	}
+
+	// Phase 2: Set all values. It never panics as types of values always match.
+	for i, dst := range dsts {
+		dst.Set(clones[i])
+	}
+
+	if atomic.LoadInt32(&wrapTracking) != 0 {
+		for _, wrapper := range tx.wrappers {
+			releaseWrapper(unsafe.Pointer(wrapper.Pointer()))
+		}
+	}
+
+	tx.wrappers = nil
+}
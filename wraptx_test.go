@@ -0,0 +1,130 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+type txFlaky struct {
+	N int
+}
+
+type txAccount struct {
+	Balance int
+	Tags    []string
+	Flaky   *txFlaky
+}
+
+func TestTransaction(t *testing.T) {
+	a := assert.New(t)
+	from := &txAccount{Balance: 100, Tags: []string{"from"}}
+	to := &txAccount{Balance: 10}
+	tx := NewTransaction()
+	wfrom := tx.Wrap(from).(*txAccount)
+	wto := tx.Wrap(to).(*txAccount)
+	tx.Add(wfrom)
+	a.Equal(tx.Len(), 2)
+	a.Equal(tx.Wrap(123), 123)
+	a.Equal(tx.Len(), 2)
+
+	wfrom.Balance -= 50
+	wto.Balance += 50
+	wfrom.Tags[0] = "changed"
+	tx.UndoAll()
+	a.Equal(tx.Len(), 0)
+	a.Equal(wfrom, from)
+	a.Equal(wto, to)
+
+	tx.Add(wfrom, wto)
+	wfrom.Balance -= 50
+	wto.Balance += 50
+	tx.CommitAll()
+	a.Equal(from.Balance, 50)
+	a.Equal(to.Balance, 60)
+	a.Equal(from.Tags, []string{"from"})
+	a.Assert(&from.Tags[0] != &wfrom.Tags[0])
+	a.Assert(Unwrap(wfrom).(*txAccount) == from)
+	a.Equal(tx.Len(), 0)
+
+	a.Equal(func() (err interface{}) {
+		defer func() {
+			err = recover()
+		}()
+		tx.Add(from)
+		return
+	}().(error).Error(), "go-clone: value of type `*clone.txAccount` is not wrapped by Wrap")
+
+	// Several wrapped values of the same original value cannot be committed.
+	tx.Add(Wrap(from), Wrap(from))
+	a.Equal(func() (err interface{}) {
+		defer func() {
+			err = recover()
+		}()
+		tx.CommitAll()
+		return
+	}().(error).Error(), "go-clone: several wrapped values of type `*clone.txAccount` wrap the same original value")
+	tx.UndoAll()
+}
+
+func TestTransactionAllOrNothing(t *testing.T) {
+	a := assert.New(t)
+	scope := Register(func(allocator *Allocator) {
+		allocator.SetCustomFunc(reflect.TypeOf(txFlaky{}), func(allocator *Allocator, old, new reflect.Value) {
+			if old.Field(0).Int() < 0 {
+				panic("flaky")
+			}
+
+			new.Set(old)
+		})
+	})
+	defer scope.Close()
+
+	from := &txAccount{Balance: 100}
+	to := &txAccount{Balance: 10, Flaky: &txFlaky{}}
+	tx := NewTransaction()
+	wfrom := tx.Wrap(from).(*txAccount)
+	wto := tx.Wrap(to).(*txAccount)
+	wfrom.Balance -= 50
+	wto.Balance += 50
+	wto.Flaky.N = -1
+
+	func() {
+		defer func() {
+			a.Equal(recover(), "flaky")
+		}()
+		tx.CommitAll()
+	}()
+
+	// No original value is changed.
+	a.Equal(from.Balance, 100)
+	a.Equal(to.Balance, 10)
+	a.Equal(tx.Len(), 2)
+
+	wto.Flaky.N = 1
+	tx.CommitAll()
+	a.Equal(from.Balance, 50)
+	a.Equal(to.Balance, 60)
+	a.Equal(to.Flaky.N, 1)
+}
+
+func TestTransactionWrapNonPointer(t *testing.T) {
+	a := assert.New(t)
+	tx := NewTransaction()
+
+	// Values which are not pointers are returned as they are, even if they are not comparable.
+	s := []int{1, 2}
+	a.Equal(tx.Wrap(s), s)
+	m := map[string]int{"a": 1}
+	a.Equal(tx.Wrap(m), m)
+	a.Equal(tx.Wrap(1), 1)
+	a.Equal(tx.Wrap(nil), nil)
+	a.Equal(tx.Len(), 0)
+
+	tx.Wrap(&txAccount{})
+	a.Equal(tx.Len(), 1)
+}
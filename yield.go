@@ -0,0 +1,74 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"runtime"
+)
+
+type yieldOption struct {
+	n  int
+	fn func()
+}
+
+// SetYieldInterval makes clone methods in heap allocator call fn every n values cloned.
+//
+// See Allocator.SetYieldInterval for more details.
+func SetYieldInterval(n int, fn func()) {
+	defaultAllocator.SetYieldInterval(n, fn)
+}
+
+// SetYieldInterval makes clone methods in a call fn every n values cloned.
+// If fn is nil, runtime.Gosched is called.
+// If n is not positive, clone methods in a never yield.
+// If yield interval is not set, a inherits it from parent allocator.
+//
+// It's designed to clone very large values without monopolizing a P,
+// so that latency-critical goroutines are not starved during a long clone.
+// The fn is called synchronously in the goroutine cloning values.
+func (a *Allocator) SetYieldInterval(n int, fn func()) {
+	if n < 0 {
+		n = 0
+	}
+
+	if fn == nil {
+		fn = runtime.Gosched
+	}
+
+	opt := &yieldOption{
+		n:  n,
+		fn: fn,
+	}
+	a.updateConfig(func(cfg *config) *config {
+		copied := cfg.copy()
+		copied.yield = opt
+		return copied
+	})
+}
+
+// lookupYield returns the nearest yield option or nil if clone methods should not yield.
+func (cfg *config) lookupYield() *yieldOption {
+	for current := cfg; current != nil; current = current.parent {
+		if current.yield == nil {
+			continue
+		}
+
+		if current.yield.n == 0 {
+			return nil
+		}
+
+		return current.yield
+	}
+
+	return nil
+}
+
+// tick counts a cloned value and yields if necessary.
+func (state *cloneState) tick() {
+	state.ticks++
+
+	if state.ticks%state.yield.n == 0 {
+		state.yield.fn()
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package clone
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestSetYieldInterval(t *testing.T) {
+	a := assert.New(t)
+	v := make([]*int, 10)
+
+	for i := range v {
+		n := i
+		v[i] = &n
+	}
+
+	yields := 0
+	allocator := FromHeap()
+	allocator.SetYieldInterval(4, func() {
+		yields++
+	})
+
+	// The slice, 10 pointers and 10 ints are cloned.
+	cloned := allocator.Clone(reflect.ValueOf(v)).Interface().([]*int)
+	a.Equal(cloned, v)
+	a.Equal(yields, 5)
+
+	// Child inherits yield interval.
+	child := NewAllocator(nil, &AllocatorMethods{
+		Parent: allocator,
+	})
+	yields = 0
+	child.CloneSlowly(reflect.ValueOf(v))
+	a.Equal(yields, 5)
+
+	// Disable yield in child.
+	child.SetYieldInterval(0, nil)
+	yields = 0
+	child.Clone(reflect.ValueOf(v))
+	a.Equal(yields, 0)
+
+	// Use runtime.Gosched by default.
+	allocator.SetYieldInterval(1, nil)
+	a.Equal(allocator.Clone(reflect.ValueOf(v)).Interface(), v)
+}